@@ -37,24 +37,68 @@ func main() {
 
 	// 创建Repository实例
 	userRepo := repository.NewUserRepository(repository.GetDB())
-	elementRepo := repository.NewContextElementRepository(repository.GetDB())
+	elementRepo := repository.NewContextElementRepository(repository.GetDB(), cfg.Search)
+	versionRepo := repository.NewContextElementVersionRepository(repository.GetDB())
+	variableRepo := repository.NewContextElementVariableRepository(repository.GetDB())
+	usageRepo := repository.NewContextElementUsageRepository(repository.GetDB())
+	tagRepo := repository.NewTagRepository(repository.GetDB())
+	folderRepo := repository.NewFolderRepository(repository.GetDB())
+	templateRepo := repository.NewTemplateRepository(repository.GetDB())
+	generationRecordRepo := repository.NewGenerationRecordRepository(repository.GetDB())
+	providerConfigRepo := repository.NewProviderConfigRepository(repository.GetDB())
+	comparisonRepo := repository.NewComparisonRepository(repository.GetDB())
+	evalRepo := repository.NewEvalRepository(repository.GetDB())
+	catalogRepo := repository.NewCatalogRepository(repository.GetDB())
 
 	// 创建Service实例
 	userService := service.NewUserService(userRepo, cfg)
-	elementService := service.NewContextElementService(elementRepo, cfg)
+	elementService := service.NewContextElementService(elementRepo, versionRepo, variableRepo, folderRepo, usageRepo, cfg)
+	tagService := service.NewTagService(tagRepo)
+	folderService := service.NewFolderService(folderRepo)
+	templateService := service.NewTemplateService(templateRepo, elementRepo, folderRepo, variableRepo, cfg)
+	generationService := service.NewGenerationService(elementService, generationRecordRepo, comparisonRepo, evalRepo, cfg)
+	providerConfigService := service.NewProviderConfigService(providerConfigRepo, cfg)
+	catalogService := service.NewCatalogService(catalogRepo)
+
+	// 用当前主密钥重新加密轮换前保存的 API Key
+	if rotated, err := providerConfigService.RotateKeys(); err != nil {
+		logger.GetLogger().Errorf("重新加密API Key失败: %v", err)
+	} else if rotated > 0 {
+		logger.GetLogger().Infof("已用当前主密钥重新加密 %d 个API Key", rotated)
+	}
+
+	// 导入内置的角色与交付格式目录
+	if seeded, err := catalogService.Seed(); err != nil {
+		logger.GetLogger().Errorf("导入推荐目录失败: %v", err)
+	} else if seeded > 0 {
+		logger.GetLogger().Infof("已导入 %d 个推荐目录项", seeded)
+	}
+
+	// 上次退出时仍在执行的评估运行无法继续，标记为已取消
+	if canceled, err := generationService.CancelInterruptedEvalRuns(); err != nil {
+		logger.GetLogger().Errorf("清理中断的评估运行失败: %v", err)
+	} else if canceled > 0 {
+		logger.GetLogger().Infof("已将 %d 个中断的评估运行标记为已取消", canceled)
+	}
+
+	// 启动回收站自动清理
+	trashSweeper := service.NewTrashSweeper(elementRepo, cfg)
+	trashSweeper.Start()
 
-	// 创建Hertz服务器
-	h := server.Default(server.WithHostPorts(cfg.GetServerAddr()))
+	// 创建Hertz服务器，客户端断开连接时取消请求的 context，以便停止流式生成
+	h := server.Default(
+		server.WithHostPorts(cfg.GetServerAddr()),
+		server.WithSenseClientDisconnection(true),
+		server.WithMaxRequestBodySize(handler.MaxRequestBodySize),
+	)
 
 	// 设置路由
-	handler.SetupRoutes(h, cfg, userService, elementService)
+	handler.SetupRoutes(h, cfg, userService, elementService, tagService, folderService, templateService, generationService, providerConfigService, catalogService)
 
 	// 启动服务器
 	go func() {
 		logger.GetLogger().Infof("服务器启动在 %s", cfg.GetServerAddr())
-		if err := h.Spin(); err != nil {
-			logger.GetLogger().Fatalf("服务器启动失败: %v", err)
-		}
+		h.Spin()
 	}()
 
 	// 等待中断信号
@@ -64,6 +108,12 @@ func main() {
 
 	logger.GetLogger().Info("正在关闭服务器...")
 
+	// 停止回收站自动清理
+	trashSweeper.Stop()
+
+	// 停止后台执行的评估运行
+	generationService.Close()
+
 	// 关闭数据库连接
 	if err := repository.CloseDatabase(); err != nil {
 		logger.GetLogger().Errorf("关闭数据库连接失败: %v", err)
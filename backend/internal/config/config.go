@@ -2,21 +2,36 @@ package config
 
 import (
 	"fmt"
+	"net/url"
+	"os"
 	"time"
 
+	"cese-backend/pkg/lint"
+	"cese-backend/pkg/provider"
+	"cese-backend/pkg/secret"
+	"cese-backend/pkg/tokenizer"
+
 	"github.com/spf13/viper"
 )
 
 // Config 应用配置结构
 type Config struct {
-	Server     ServerConfig     `mapstructure:"server"`
-	Database   DatabaseConfig   `mapstructure:"database"`
-	JWT        JWTConfig        `mapstructure:"jwt"`
-	Log        LogConfig        `mapstructure:"log"`
-	Pagination PaginationConfig `mapstructure:"pagination"`
-	Password   PasswordConfig   `mapstructure:"password"`
-	RateLimit  RateLimitConfig  `mapstructure:"rate_limit"`
-	Security   SecurityConfig   `mapstructure:"security"`
+	Server      ServerConfig      `mapstructure:"server"`
+	Database    DatabaseConfig    `mapstructure:"database"`
+	JWT         JWTConfig         `mapstructure:"jwt"`
+	Log         LogConfig         `mapstructure:"log"`
+	Pagination  PaginationConfig  `mapstructure:"pagination"`
+	Password    PasswordConfig    `mapstructure:"password"`
+	RateLimit   RateLimitConfig   `mapstructure:"rate_limit"`
+	Security    SecurityConfig    `mapstructure:"security"`
+	Search      SearchConfig      `mapstructure:"search"`
+	Concurrency ConcurrencyConfig `mapstructure:"concurrency"`
+	Trash       TrashConfig       `mapstructure:"trash"`
+	Lint        LintConfig        `mapstructure:"lint"`
+	Tokenizer   TokenizerConfig   `mapstructure:"tokenizer"`
+	LLM         LLMConfig         `mapstructure:"llm"`
+	Encryption  EncryptionConfig  `mapstructure:"encryption"`
+	Admin       AdminConfig       `mapstructure:"admin"`
 }
 
 // ServerConfig 服务器配置
@@ -77,6 +92,96 @@ type PasswordConfig struct {
 	RequireSpecial bool `mapstructure:"require_special"`
 }
 
+// SearchConfig 全文搜索配置
+type SearchConfig struct {
+	Engine         string        `mapstructure:"engine"`          // fulltext（MySQL FULLTEXT ngram索引）或 like
+	Weights        SearchWeights `mapstructure:"weights"`         // 各字段的相关度权重
+	SnippetContext int           `mapstructure:"snippet_context"` // 高亮片段中命中位置前后保留的字符数
+	MaxSnippets    int           `mapstructure:"max_snippets"`    // 每个字段最多返回的高亮片段数
+}
+
+// SearchWeights 相关度权重
+type SearchWeights struct {
+	Subject  float64 `mapstructure:"subject"`
+	TaskGoal float64 `mapstructure:"task_goal"`
+	Others   float64 `mapstructure:"others"`
+}
+
+// 搜索引擎类型
+const (
+	SearchEngineFullText = "fulltext"
+	SearchEngineLike     = "like"
+)
+
+// ConcurrencyConfig 并发控制配置
+type ConcurrencyConfig struct {
+	IfMatch string `mapstructure:"if_match"` // strict：更新和删除必须携带If-Match；lenient：携带时才校验
+}
+
+// If-Match 校验模式
+const (
+	IfMatchStrict  = "strict"
+	IfMatchLenient = "lenient"
+)
+
+// TrashConfig 回收站配置
+type TrashConfig struct {
+	RetentionDays int    `mapstructure:"retention_days"` // 保留天数，超过后自动彻底删除；0 表示不自动清理
+	SweepInterval string `mapstructure:"sweep_interval"` // 自动清理的执行间隔，如 1h、30m
+}
+
+// LintConfig 提示词质量检查配置
+type LintConfig struct {
+	Rules map[string]bool `mapstructure:"rules"` // 按规则ID启用或禁用，未列出的规则默认启用
+}
+
+// TokenizerConfig 令牌计数与费用估算配置
+type TokenizerConfig struct {
+	VocabDir     string         `mapstructure:"vocab_dir"`     // tiktoken 词表文件目录，为空或缺少文件时使用内置词表
+	DefaultModel string         `mapstructure:"default_model"` // 未指定模型时使用
+	Models       []ModelPricing `mapstructure:"models"`
+}
+
+// ModelPricing 模型的编码方式和价格
+type ModelPricing struct {
+	Name          string  `mapstructure:"name"`
+	Encoding      string  `mapstructure:"encoding"`       // cl100k_base, o200k_base, cjk_approx
+	InputPrice    float64 `mapstructure:"input_price"`    // 每百万输入令牌的价格
+	Currency      string  `mapstructure:"currency"`       // 价格的币种，如 USD、CNY
+	ContextWindow int     `mapstructure:"context_window"` // 上下文窗口的令牌数，0 表示不限制
+}
+
+// LLMConfig 大模型生成配置
+type LLMConfig struct {
+	DefaultProvider      string              `mapstructure:"default_provider"`        // 未指定提供商时使用
+	Timeout              string              `mapstructure:"timeout"`                 // 单次生成的超时时间，如 120s
+	MaxTokens            int                 `mapstructure:"max_tokens"`              // 单次生成允许的最大输出令牌数，0 表示不限制
+	DefaultMaxTokens     int                 `mapstructure:"default_max_tokens"`      // 未指定 max_tokens 时使用，0 表示由模型服务决定
+	MaxConcurrentPerUser int                 `mapstructure:"max_concurrent_per_user"` // 每个用户同时进行的生成数，0 表示不限制
+	Providers            []LLMProviderConfig `mapstructure:"providers"`
+}
+
+// LLMProviderConfig 模型服务配置
+type LLMProviderConfig struct {
+	Name    string   `mapstructure:"name"`
+	Type    string   `mapstructure:"type"` // openai, baidu, alibaba, tencent, bytedance, ollama
+	BaseURL string   `mapstructure:"base_url"`
+	APIKey  string   `mapstructure:"api_key"`
+	Models  []string `mapstructure:"models"` // 可用模型，第一个为默认模型
+	Enabled bool     `mapstructure:"enabled"`
+}
+
+// EncryptionConfig 敏感信息加密配置（用户保存的模型服务 API Key）
+type EncryptionConfig struct {
+	MasterKey    string   `mapstructure:"master_key"`    // Base64 编码的 32 字节 AES-256 密钥，为空时不能保存 API Key
+	PreviousKeys []string `mapstructure:"previous_keys"` // 轮换前使用的旧密钥，仅用于解密；启动时自动用主密钥重新加密
+}
+
+// AdminConfig 管理员配置
+type AdminConfig struct {
+	Phones []string `mapstructure:"phones"` // 管理员的手机号，可维护角色与交付格式推荐目录
+}
+
 var GlobalConfig *Config
 
 // LoadConfig 加载配置文件
@@ -125,9 +230,124 @@ func validateConfig(config *Config) error {
 		return fmt.Errorf("JWT过期时间必须大于0")
 	}
 
+	if engine := config.Search.Engine; engine != "" && engine != SearchEngineFullText && engine != SearchEngineLike {
+		return fmt.Errorf("搜索引擎配置错误: %s", engine)
+	}
+
+	if mode := config.Concurrency.IfMatch; mode != "" && mode != IfMatchStrict && mode != IfMatchLenient {
+		return fmt.Errorf("If-Match校验模式配置错误: %s", mode)
+	}
+
+	if config.Trash.RetentionDays < 0 {
+		return fmt.Errorf("回收站保留天数不能为负数: %d", config.Trash.RetentionDays)
+	}
+	if interval := config.Trash.SweepInterval; interval != "" {
+		if d, err := time.ParseDuration(interval); err != nil || d <= 0 {
+			return fmt.Errorf("回收站清理间隔配置错误: %s", interval)
+		}
+	}
+
+	if _, err := lint.New(config.Lint.Rules); err != nil {
+		return fmt.Errorf("提示词检查配置错误: %w", err)
+	}
+
+	if err := validateTokenizer(&config.Tokenizer); err != nil {
+		return fmt.Errorf("令牌计数配置错误: %w", err)
+	}
+
+	if err := validateLLM(&config.LLM); err != nil {
+		return fmt.Errorf("大模型生成配置错误: %w", err)
+	}
+
+	if err := validateEncryption(&config.Encryption); err != nil {
+		return fmt.Errorf("加密配置错误: %w", err)
+	}
+
+	return nil
+}
+
+// validateTokenizer 验证令牌计数配置
+func validateTokenizer(config *TokenizerConfig) error {
+	if dir := config.VocabDir; dir != "" {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return fmt.Errorf("词表目录不存在: %s", dir)
+		}
+	}
+
+	seen := make(map[string]bool, len(config.Models))
+	for _, model := range config.Models {
+		if model.Name == "" {
+			return fmt.Errorf("模型名称不能为空")
+		}
+		if seen[model.Name] {
+			return fmt.Errorf("模型名称重复: %s", model.Name)
+		}
+		seen[model.Name] = true
+
+		if !tokenizer.IsEncoding(model.Encoding) {
+			return fmt.Errorf("模型 %s 的编码方式错误: %s", model.Name, model.Encoding)
+		}
+		if model.InputPrice < 0 || model.ContextWindow < 0 {
+			return fmt.Errorf("模型 %s 的价格和上下文窗口不能为负数", model.Name)
+		}
+	}
+
+	if config.DefaultModel != "" && !seen[config.DefaultModel] {
+		return fmt.Errorf("默认模型不在模型列表中: %s", config.DefaultModel)
+	}
+	return nil
+}
+
+// validateLLM 验证大模型生成配置
+func validateLLM(config *LLMConfig) error {
+	if timeout := config.Timeout; timeout != "" {
+		if d, err := time.ParseDuration(timeout); err != nil || d <= 0 {
+			return fmt.Errorf("超时时间配置错误: %s", timeout)
+		}
+	}
+	if config.MaxTokens < 0 || config.DefaultMaxTokens < 0 || config.MaxConcurrentPerUser < 0 {
+		return fmt.Errorf("令牌数和并发数限制不能为负数")
+	}
+	if config.MaxTokens > 0 && config.DefaultMaxTokens > config.MaxTokens {
+		return fmt.Errorf("默认最大输出令牌数不能超过 max_tokens")
+	}
+
+	seen := make(map[string]bool, len(config.Providers))
+	for _, p := range config.Providers {
+		if p.Name == "" {
+			return fmt.Errorf("模型服务名称不能为空")
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("模型服务名称重复: %s", p.Name)
+		}
+		seen[p.Name] = true
+
+		if !provider.IsType(p.Type) {
+			return fmt.Errorf("模型服务 %s 的类型错误: %s", p.Name, p.Type)
+		}
+		if u, err := url.Parse(p.BaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("模型服务 %s 的地址错误: %s", p.Name, p.BaseURL)
+		}
+	}
+
+	if config.DefaultProvider != "" && !seen[config.DefaultProvider] {
+		return fmt.Errorf("默认模型服务不在列表中: %s", config.DefaultProvider)
+	}
 	return nil
 }
 
+// validateEncryption 验证加密配置，未配置主密钥时不能配置旧密钥
+func validateEncryption(config *EncryptionConfig) error {
+	if config.MasterKey == "" {
+		if len(config.PreviousKeys) > 0 {
+			return fmt.Errorf("配置了旧密钥但未配置主密钥")
+		}
+		return nil
+	}
+	_, err := secret.NewKeyring(config.MasterKey, config.PreviousKeys...)
+	return err
+}
+
 // GetServerAddr 获取服务器地址
 func (c *Config) GetServerAddr() string {
 	return fmt.Sprintf("%s:%d", c.Server.Host, c.Server.Port)
@@ -186,3 +406,81 @@ type CORSConfig struct {
 func (c *Config) GetRefreshExpireDuration() time.Duration {
 	return time.Duration(c.JWT.RefreshExpireHours) * time.Hour
 }
+
+// UseFullText 是否使用 MySQL FULLTEXT 索引搜索（未配置时默认使用）
+func (c SearchConfig) UseFullText() bool {
+	return c.Engine != SearchEngineLike
+}
+
+// GetWeights 获取相关度权重，未配置时使用 主题 5、任务目标 3、其他字段 1
+func (c SearchConfig) GetWeights() SearchWeights {
+	if c.Weights.Subject <= 0 && c.Weights.TaskGoal <= 0 && c.Weights.Others <= 0 {
+		return SearchWeights{Subject: 5, TaskGoal: 3, Others: 1}
+	}
+	return c.Weights
+}
+
+// RequireIfMatch 更新和删除是否必须携带If-Match请求头（未配置时为lenient）
+func (c ConcurrencyConfig) RequireIfMatch() bool {
+	return c.IfMatch == IfMatchStrict
+}
+
+// GetRetention 获取回收站保留时长，为0表示不自动清理
+func (c TrashConfig) GetRetention() time.Duration {
+	return time.Duration(c.RetentionDays) * 24 * time.Hour
+}
+
+// GetSweepInterval 获取自动清理的执行间隔，未配置时为1小时
+func (c TrashConfig) GetSweepInterval() time.Duration {
+	if d, err := time.ParseDuration(c.SweepInterval); err == nil && d > 0 {
+		return d
+	}
+	return time.Hour
+}
+
+// FindModel 根据名称查找模型，名称为空时返回默认模型
+func (c TokenizerConfig) FindModel(name string) *ModelPricing {
+	if name == "" {
+		name = c.DefaultModel
+	}
+	for i := range c.Models {
+		if c.Models[i].Name == name {
+			return &c.Models[i]
+		}
+	}
+	return nil
+}
+
+// GetTimeout 获取单次生成的超时时间，未配置时为 2 分钟
+func (c LLMConfig) GetTimeout() time.Duration {
+	if d, err := time.ParseDuration(c.Timeout); err == nil && d > 0 {
+		return d
+	}
+	return 2 * time.Minute
+}
+
+// FindProvider 根据名称查找模型服务，名称为空时返回默认模型服务
+func (c LLMConfig) FindProvider(name string) *LLMProviderConfig {
+	if name == "" {
+		name = c.DefaultProvider
+	}
+	for i := range c.Providers {
+		if c.Providers[i].Name == name {
+			return &c.Providers[i]
+		}
+	}
+	return nil
+}
+
+// IsAdmin 判断手机号是否为管理员
+func (c AdminConfig) IsAdmin(phone string) bool {
+	if phone == "" {
+		return false
+	}
+	for _, p := range c.Phones {
+		if p == phone {
+			return true
+		}
+	}
+	return false
+}
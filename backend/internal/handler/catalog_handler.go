@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"context"
+
+	"cese-backend/internal/middleware"
+	"cese-backend/internal/model"
+	"cese-backend/internal/service"
+	"cese-backend/pkg/response"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// CatalogHandler 角色与交付格式推荐处理器
+type CatalogHandler struct {
+	catalogService service.CatalogService
+}
+
+// NewCatalogHandler 创建推荐处理器实例
+func NewCatalogHandler(catalogService service.CatalogService) *CatalogHandler {
+	return &CatalogHandler{
+		catalogService: catalogService,
+	}
+}
+
+// GetSuggestions 获取推荐
+// @Summary 获取角色与交付格式推荐
+// @Description 返回当前用户最常用的AI角色、我的角色和交付格式，以及推荐目录中的预设项，可按名称或内容前缀搜索
+// @Tags 推荐目录
+// @Produce json
+// @Security BearerAuth
+// @Param field query string false "六要素字段，为空时查询全部" Enums(ai_role, my_role, delivery_format)
+// @Param q query string false "名称或内容的前缀"
+// @Param limit query int false "个人推荐和目录各自返回的最大数量" default(10)
+// @Success 200 {object} response.Response{data=model.SuggestionsResponse} "查询成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /api/v1/suggestions [get]
+func (h *CatalogHandler) GetSuggestions(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	var req model.SuggestionQueryRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	suggestions, err := h.catalogService.GetSuggestions(userID, &req)
+	if err != nil {
+		handleCatalogError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "查询成功", suggestions)
+}
+
+// GetList 获取推荐目录
+// @Summary 获取推荐目录
+// @Description 获取全部角色与交付格式目录项，按字段和排序值排列
+// @Tags 推荐目录
+// @Produce json
+// @Security BearerAuth
+// @Param field query string false "六要素字段" Enums(ai_role, my_role, delivery_format)
+// @Param category query string false "分类"
+// @Success 200 {object} response.Response{data=[]model.CatalogItemResponse} "查询成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /api/v1/catalog [get]
+func (h *CatalogHandler) GetList(ctx context.Context, c *app.RequestContext) {
+	var req model.CatalogQueryRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	items, err := h.catalogService.GetList(&req)
+	if err != nil {
+		handleCatalogError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "查询成功", items)
+}
+
+// Create 新增目录项
+// @Summary 新增目录项
+// @Description 新增角色或交付格式目录项（仅管理员），同一字段下名称唯一，与已删除的目录项同名时恢复该目录项
+// @Tags 推荐目录
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.CatalogItemRequest true "目录项"
+// @Success 200 {object} response.Response{data=model.CatalogItemResponse} "创建成功"
+// @Failure 400 {object} response.Response "参数错误或目录项已存在"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "不是管理员"
+// @Router /api/v1/catalog [post]
+func (h *CatalogHandler) Create(ctx context.Context, c *app.RequestContext) {
+	var req model.CatalogItemRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	item, err := h.catalogService.Create(&req)
+	if err != nil {
+		handleCatalogError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "创建成功", item)
+}
+
+// Update 修改目录项
+// @Summary 修改目录项
+// @Description 修改角色或交付格式目录项（仅管理员），内置项修改后重启服务不会被覆盖
+// @Tags 推荐目录
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "目录项ID"
+// @Param request body model.CatalogItemRequest true "目录项"
+// @Success 200 {object} response.Response{data=model.CatalogItemResponse} "更新成功"
+// @Failure 400 {object} response.Response "参数错误或目录项已存在"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "不是管理员"
+// @Failure 404 {object} response.Response "目录项不存在"
+// @Router /api/v1/catalog/{id} [put]
+func (h *CatalogHandler) Update(ctx context.Context, c *app.RequestContext) {
+	itemID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	var req model.CatalogItemRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	item, err := h.catalogService.Update(itemID, &req)
+	if err != nil {
+		handleCatalogError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "更新成功", item)
+}
+
+// Delete 删除目录项
+// @Summary 删除目录项
+// @Description 删除角色或交付格式目录项（仅管理员），内置项删除后重启服务不会重新导入
+// @Tags 推荐目录
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "目录项ID"
+// @Success 200 {object} response.Response "删除成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "不是管理员"
+// @Failure 404 {object} response.Response "目录项不存在"
+// @Router /api/v1/catalog/{id} [delete]
+func (h *CatalogHandler) Delete(ctx context.Context, c *app.RequestContext) {
+	itemID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	if err := h.catalogService.Delete(itemID); err != nil {
+		handleCatalogError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// handleCatalogError 将推荐服务错误转换为响应
+func handleCatalogError(c *app.RequestContext, err error) {
+	switch err.Error() {
+	case "目录项不存在":
+		response.Error(c, response.CodeCatalogItemNotFound)
+	case "目录项已存在":
+		response.Error(c, response.CodeCatalogItemExists)
+	case "参数验证失败":
+		response.ErrorWithMessage(c, response.CodeInvalidParams, err.Error())
+	default:
+		response.ErrorWithMessage(c, response.CodeInternalError, err.Error())
+	}
+}
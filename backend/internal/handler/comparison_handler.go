@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"context"
+
+	"cese-backend/internal/middleware"
+	"cese-backend/internal/model"
+	"cese-backend/pkg/response"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// Compare 运行提示词对比
+// @Summary 运行提示词对比
+// @Description 多个六要素（或同一六要素覆盖部分字段）使用相同的模型服务、采样参数和变量各执行 repeat 次，在剩余的生成并发名额内并发执行，保存全部结果并返回对比视图
+// @Tags 提示词对比
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.ComparisonCreateRequest true "对比参数"
+// @Success 200 {object} response.Response{data=model.ComparisonResponse} "对比完成"
+// @Failure 400 {object} response.Response "参数错误或变量校验失败"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "六要素不存在"
+// @Failure 429 {object} response.Response "生成任务过多"
+// @Router /api/v1/comparisons [post]
+func (h *GenerationHandler) Compare(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	var req model.ComparisonCreateRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	run, err := h.generationService.Compare(ctx, userID, &req)
+	if err != nil {
+		handleComparisonError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "对比完成", run)
+}
+
+// GetComparisons 查询对比运行
+// @Summary 查询对比运行
+// @Description 分页查询当前用户的对比运行，按创建时间倒序；列表只返回各变体的统计，不返回生成的内容
+// @Tags 提示词对比
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(15)
+// @Param element_id query int false "包含该六要素的对比运行"
+// @Success 200 {object} response.PageResponse{data=[]model.ComparisonResponse} "查询成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /api/v1/comparisons [get]
+func (h *GenerationHandler) GetComparisons(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	var req model.ComparisonQueryRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	runs, total, err := h.generationService.GetComparisons(userID, &req)
+	if err != nil {
+		handleComparisonError(c, err)
+		return
+	}
+
+	response.PageSuccessWithMessage(c, "查询成功", runs, total, req.Page, req.Size)
+}
+
+// GetComparison 获取对比视图
+// @Summary 获取对比视图
+// @Description 获取对比运行，包括每个变体的提示词快照、全部生成结果，以及长度、耗时和令牌用量的统计
+// @Tags 提示词对比
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "对比运行ID"
+// @Success 200 {object} response.Response{data=model.ComparisonResponse} "获取成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "对比运行不存在"
+// @Router /api/v1/comparisons/{id} [get]
+func (h *GenerationHandler) GetComparison(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	runID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	run, err := h.generationService.GetComparison(userID, runID)
+	if err != nil {
+		handleComparisonError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "获取成功", run)
+}
+
+// UpdateComparison 记录胜出变体
+// @Summary 记录胜出变体
+// @Description 记录对比的胜出变体和备注，winner_variant_id 为 0 时清除，省略的字段保持不变
+// @Tags 提示词对比
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "对比运行ID"
+// @Param request body model.ComparisonUpdateRequest true "胜出变体和备注"
+// @Success 200 {object} response.Response{data=model.ComparisonResponse} "更新成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "对比运行不存在"
+// @Router /api/v1/comparisons/{id} [patch]
+func (h *GenerationHandler) UpdateComparison(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	runID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	var req model.ComparisonUpdateRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	run, err := h.generationService.UpdateComparison(userID, runID, &req)
+	if err != nil {
+		handleComparisonError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "更新成功", run)
+}
+
+// DeleteComparison 删除对比运行
+// @Summary 删除对比运行
+// @Description 删除对比运行及其全部生成结果
+// @Tags 提示词对比
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "对比运行ID"
+// @Success 200 {object} response.Response "删除成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "对比运行不存在"
+// @Router /api/v1/comparisons/{id} [delete]
+func (h *GenerationHandler) DeleteComparison(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	runID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	if err := h.generationService.DeleteComparison(userID, runID); err != nil {
+		handleComparisonError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// handleComparisonError 将对比运行错误转换为响应
+func handleComparisonError(c *app.RequestContext, err error) {
+	switch err.Error() {
+	case "对比运行不存在":
+		response.Error(c, response.CodeComparisonNotFound)
+	default:
+		handleGenerationError(c, err)
+	}
+}
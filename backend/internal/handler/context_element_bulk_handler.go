@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"context"
+	"strings"
+
+	"cese-backend/internal/middleware"
+	"cese-backend/internal/model"
+	"cese-backend/pkg/response"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// BulkCreate 批量创建六要素
+// @Summary 批量创建六要素
+// @Description 在同一事务中创建多条六要素（最多1000条），返回每条记录的处理结果；atomic为true时任一记录失败则全部不写入
+// @Tags 六要素管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.ContextElementBulkCreateRequest true "待创建的六要素"
+// @Success 200 {object} response.Response{data=model.ContextElementBulkResult} "处理完成"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /api/v1/context-elements/bulk/create [post]
+func (h *ContextElementHandler) BulkCreate(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	var req model.ContextElementBulkCreateRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	result, err := h.elementService.BulkCreate(userID, &req)
+	if err != nil {
+		handleBulkError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "处理完成", result)
+}
+
+// BulkDelete 批量删除六要素
+// @Summary 批量删除六要素
+// @Description 按ID列表或过滤条件在同一事务中删除六要素（进入回收站，最多1000条），返回每条记录的处理结果；atomic为true时任一记录失败则全部不删除
+// @Tags 六要素管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.ContextElementBulkDeleteRequest true "ids与filter二选一"
+// @Success 200 {object} response.Response{data=model.ContextElementBulkResult} "处理完成"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /api/v1/context-elements/bulk/delete [post]
+func (h *ContextElementHandler) BulkDelete(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	var req model.ContextElementBulkDeleteRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	result, err := h.elementService.BulkDelete(userID, &req)
+	if err != nil {
+		handleBulkError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "处理完成", result)
+}
+
+// BulkPatch 批量修改六要素
+// @Summary 批量修改六要素
+// @Description 按ID列表或过滤条件在同一事务中修改六要素的字段（set替换或append追加）和标签（add_tags/remove_tags），每条修改都会保存历史版本；atomic为true时任一记录失败则全部不修改
+// @Tags 六要素管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.ContextElementBulkPatchRequest true "ids与filter二选一"
+// @Success 200 {object} response.Response{data=model.ContextElementBulkResult} "处理完成"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /api/v1/context-elements/bulk/patch [post]
+func (h *ContextElementHandler) BulkPatch(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	var req model.ContextElementBulkPatchRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	result, err := h.elementService.BulkPatch(userID, &req)
+	if err != nil {
+		handleBulkError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "处理完成", result)
+}
+
+// handleBulkError 将批量操作错误转换为响应，选择条件和修改内容的校验问题按参数错误处理
+func handleBulkError(c *app.RequestContext, err error) {
+	if strings.HasPrefix(err.Error(), "参数验证失败: ") {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, err.Error())
+		return
+	}
+	handleElementError(c, err)
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"context"
+
+	"cese-backend/internal/middleware"
+	"cese-backend/internal/model"
+	"cese-backend/pkg/response"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// GetListByCursor 游标分页获取六要素列表
+// @Summary 游标分页获取六要素列表
+// @Description 按排序字段和ID进行键集分页，翻页期间新增或删除记录不会导致重复或遗漏。首次请求不带cursor，之后传入响应中的next_cursor或prev_cursor
+// @Tags 六要素管理
+// @Produce json
+// @Security BearerAuth
+// @Param cursor query string false "翻页游标"
+// @Param size query int false "每页数量" default(15)
+// @Param keyword query string false "关键词搜索"
+// @Param subject query string false "主题过滤"
+// @Param ai_role query string false "AI角色过滤"
+// @Param my_role query string false "我的角色过滤"
+// @Param tags query string false "标签过滤（逗号分隔）"
+// @Param tag_mode query string false "标签匹配方式" Enums(any, all) default(any)
+// @Param folder_id query int false "文件夹过滤（0表示根目录）"
+// @Param recursive query bool false "是否包含子文件夹" default(false)
+// @Param sort_by query string false "排序字段（带cursor时以游标为准）" Enums(created_at, updated_at, subject)
+// @Param sort_desc query bool false "是否倒序" default(true)
+// @Param with_total query bool false "是否返回总数" default(false)
+// @Success 200 {object} response.CursorPageResponse{data=[]model.ContextElementResponse} "查询成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /api/v1/context-elements/cursor [get]
+func (h *ContextElementHandler) GetListByCursor(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	var req model.ContextElementCursorRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	page, err := h.elementService.GetListByCursor(userID, &req)
+	if err != nil {
+		switch err.Error() {
+		case "参数验证失败", "无效的游标":
+			response.ErrorWithMessage(c, response.CodeInvalidParams, err.Error())
+		default:
+			response.ErrorWithMessage(c, response.CodeInternalError, err.Error())
+		}
+		return
+	}
+
+	response.CursorPageSuccessWithMessage(c, "查询成功", page.Items, page.NextCursor, page.PrevCursor, req.Size, page.Total)
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"context"
+
+	"cese-backend/internal/middleware"
+	"cese-backend/internal/model"
+	"cese-backend/pkg/response"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// Duplicate 复制六要素
+// @Summary 复制六要素
+// @Description 以现有六要素为基础创建新记录（含标签和变量定义），可覆盖任意字段；未指定主题时自动追加"(副本)"后缀，新记录的cloned_from_id为原记录ID
+// @Tags 六要素管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "六要素ID"
+// @Param request body model.ContextElementDuplicateRequest false "覆盖的字段"
+// @Success 200 {object} response.Response{data=model.ContextElementResponse} "复制成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "记录不存在"
+// @Router /api/v1/context-elements/{id}/duplicate [post]
+func (h *ContextElementHandler) Duplicate(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	elementID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	var req model.ContextElementDuplicateRequest
+	if len(c.Request.Body()) > 0 {
+		if err := c.BindAndValidate(&req); err != nil {
+			response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+			return
+		}
+	}
+
+	element, err := h.elementService.Duplicate(userID, elementID, &req)
+	if err != nil {
+		handleElementError(c, err)
+		return
+	}
+
+	c.Header("ETag", element.ETag)
+
+	response.SuccessWithMessage(c, "复制成功", element)
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"context"
+
+	"cese-backend/internal/middleware"
+	"cese-backend/internal/model"
+	"cese-backend/pkg/response"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// MoveToFolder 移动单个六要素到文件夹
+// @Summary 移动六要素到文件夹
+// @Description 将六要素移动到指定文件夹，folder_id为空表示移动到根目录
+// @Tags 文件夹管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "六要素ID"
+// @Param request body model.ContextElementMoveRequest true "移动请求（忽略ids）"
+// @Success 200 {object} response.Response "移动成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "记录或文件夹不存在"
+// @Router /api/v1/context-elements/{id}/folder [put]
+func (h *ContextElementHandler) MoveToFolder(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	elementID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	var req model.ContextElementMoveRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+	req.IDs = []uint64{elementID}
+
+	moved, err := h.elementService.MoveToFolder(userID, &req)
+	if err != nil {
+		handleElementError(c, err)
+		return
+	}
+	if moved == 0 {
+		response.Error(c, response.CodeElementNotFound)
+		return
+	}
+
+	response.SuccessWithMessage(c, "移动成功", nil)
+}
+
+// BatchMoveToFolder 批量移动六要素到文件夹
+// @Summary 批量移动六要素到文件夹
+// @Description 将多个六要素移动到指定文件夹，folder_id为空表示移动到根目录，不属于当前用户的ID会被忽略
+// @Tags 文件夹管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.ContextElementMoveRequest true "移动请求"
+// @Success 200 {object} response.Response{data=map[string]int64} "移动成功，返回实际移动数量"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "文件夹不存在"
+// @Router /api/v1/context-elements/move [post]
+func (h *ContextElementHandler) BatchMoveToFolder(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	var req model.ContextElementMoveRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	moved, err := h.elementService.MoveToFolder(userID, &req)
+	if err != nil {
+		handleElementError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "移动成功", map[string]int64{"moved": moved})
+}
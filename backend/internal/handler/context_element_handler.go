@@ -7,6 +7,7 @@ import (
 	"cese-backend/internal/middleware"
 	"cese-backend/internal/model"
 	"cese-backend/internal/service"
+	"cese-backend/pkg/logger"
 	"cese-backend/pkg/response"
 
 	"github.com/cloudwego/hertz/pkg/app"
@@ -66,10 +67,14 @@ func (h *ContextElementHandler) Create(ctx context.Context, c *app.RequestContex
 // @Security BearerAuth
 // @Param page query int false "页码" default(1)
 // @Param size query int false "每页数量" default(15)
-// @Param keyword query string false "关键词搜索"
+// @Param keyword query string false "关键词搜索（空格分隔且需全部命中，支持\"短语\"和-排除词）"
 // @Param subject query string false "主题过滤"
 // @Param ai_role query string false "AI角色过滤"
 // @Param my_role query string false "我的角色过滤"
+// @Param tags query string false "标签过滤（逗号分隔）"
+// @Param tag_mode query string false "标签匹配方式" Enums(any, all) default(any)
+// @Param folder_id query int false "文件夹过滤（0表示根目录）"
+// @Param recursive query bool false "是否包含子文件夹" default(false)
 // @Param sort_by query string false "排序字段" Enums(created_at, updated_at, subject)
 // @Param sort_desc query bool false "是否倒序" default(true)
 // @Success 200 {object} response.PageResponse{data=[]model.ContextElementResponse} "查询成功"
@@ -137,23 +142,34 @@ func (h *ContextElementHandler) GetByID(ctx context.Context, c *app.RequestConte
 		return
 	}
 
+	// 记录打开并返回记录后的使用情况，统计失败不影响请求结果
+	if usage, err := h.elementService.RecordUsage(userID, elementID, model.UsageOpen); err != nil {
+		logger.GetLogger().Warnf("记录六要素使用情况失败: element_id=%d event=%s err=%v", elementID, model.UsageOpen, err)
+	} else {
+		element.Usage = usage
+	}
+
+	c.Header("ETag", element.ETag)
 	response.SuccessWithMessage(c, "获取成功", element)
 }
 
 // Update 更新六要素
 // @Summary 更新六要素
-// @Description 更新六要素记录
+// @Description 整体替换六要素内容和标签，未提供的字段和标签会被清空；局部更新请使用PATCH
 // @Tags 六要素管理
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "六要素ID"
+// @Param If-Match header string false "获取记录时返回的ETag，strict模式下必填"
 // @Param request body model.ContextElementUpdateRequest true "更新请求"
 // @Success 200 {object} response.Response{data=model.ContextElementResponse} "更新成功"
 // @Failure 400 {object} response.Response "参数错误"
 // @Failure 401 {object} response.Response "未授权"
 // @Failure 403 {object} response.Response "无权限"
 // @Failure 404 {object} response.Response "记录不存在"
+// @Failure 412 {object} response.Response{data=model.ContextElementResponse} "记录已被修改，返回服务器上的最新内容"
+// @Failure 428 {object} response.Response "缺少If-Match请求头"
 // @Router /api/v1/context-elements/{id} [put]
 func (h *ContextElementHandler) Update(ctx context.Context, c *app.RequestContext) {
 	userID := middleware.GetUserID(c)
@@ -176,19 +192,14 @@ func (h *ContextElementHandler) Update(ctx context.Context, c *app.RequestContex
 		return
 	}
 
-	element, err := h.elementService.Update(userID, elementID, &req)
+	element, err := h.elementService.Update(userID, elementID, &req, string(c.GetHeader("If-Match")))
 	if err != nil {
-		switch err.Error() {
-		case "六要素记录不存在":
-			response.Error(c, response.CodeElementNotFound)
-		case "无权更新该记录":
-			response.Error(c, response.CodeForbidden)
-		default:
-			response.ErrorWithMessage(c, response.CodeInternalError, err.Error())
-		}
+		h.handleWriteError(c, userID, elementID, err, handleElementError)
 		return
 	}
 
+	c.Header("ETag", element.ETag)
+
 	response.SuccessWithMessage(c, "更新成功", element)
 }
 
@@ -199,11 +210,14 @@ func (h *ContextElementHandler) Update(ctx context.Context, c *app.RequestContex
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "六要素ID"
+// @Param If-Match header string false "获取记录时返回的ETag，strict模式下必填"
 // @Success 200 {object} response.Response "删除成功"
 // @Failure 400 {object} response.Response "参数错误"
 // @Failure 401 {object} response.Response "未授权"
 // @Failure 403 {object} response.Response "无权限"
 // @Failure 404 {object} response.Response "记录不存在"
+// @Failure 412 {object} response.Response{data=model.ContextElementResponse} "记录已被修改，返回服务器上的最新内容"
+// @Failure 428 {object} response.Response "缺少If-Match请求头"
 // @Router /api/v1/context-elements/{id} [delete]
 func (h *ContextElementHandler) Delete(ctx context.Context, c *app.RequestContext) {
 	userID := middleware.GetUserID(c)
@@ -220,18 +234,57 @@ func (h *ContextElementHandler) Delete(ctx context.Context, c *app.RequestContex
 		return
 	}
 
-	err = h.elementService.Delete(userID, elementID)
+	err = h.elementService.Delete(userID, elementID, string(c.GetHeader("If-Match")))
 	if err != nil {
-		switch err.Error() {
-		case "六要素记录不存在":
-			response.Error(c, response.CodeElementNotFound)
-		case "无权删除该记录":
-			response.Error(c, response.CodeForbidden)
-		default:
-			response.ErrorWithMessage(c, response.CodeInternalError, err.Error())
-		}
+		h.handleWriteError(c, userID, elementID, err, handleElementError)
 		return
 	}
 
 	response.SuccessWithMessage(c, "删除成功", nil)
 }
+
+// parseUintParam 解析路径中的无符号整数参数
+func parseUintParam(c *app.RequestContext, name string) (uint64, bool) {
+	value, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+// handleWriteError 处理更新和删除的错误：版本冲突时返回412及服务器上的最新内容，其他错误交由 fallback 处理
+func (h *ContextElementHandler) handleWriteError(c *app.RequestContext, userID, elementID uint64, err error, fallback func(*app.RequestContext, error)) {
+	if err.Error() != "版本冲突" {
+		fallback(c, err)
+		return
+	}
+
+	current, getErr := h.elementService.GetByID(userID, elementID)
+	if getErr != nil {
+		// 记录已被删除等情况
+		fallback(c, getErr)
+		return
+	}
+	c.Header("ETag", current.ETag)
+	response.ErrorWithData(c, response.CodeVersionConflict, current)
+}
+
+// handleElementError 将六要素服务错误转换为响应
+func handleElementError(c *app.RequestContext, err error) {
+	switch err.Error() {
+	case "六要素记录不存在":
+		response.Error(c, response.CodeElementNotFound)
+	case "历史版本不存在":
+		response.Error(c, response.CodeVersionNotFound)
+	case "文件夹不存在":
+		response.Error(c, response.CodeFolderNotFound)
+	case "无权访问该记录", "无权更新该记录", "无权删除该记录":
+		response.Error(c, response.CodeForbidden)
+	case "参数验证失败":
+		response.ErrorWithMessage(c, response.CodeInvalidParams, err.Error())
+	case "缺少If-Match请求头":
+		response.Error(c, response.CodeIfMatchRequired)
+	default:
+		response.ErrorWithMessage(c, response.CodeInternalError, err.Error())
+	}
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"context"
+
+	"cese-backend/internal/middleware"
+	"cese-backend/internal/model"
+	"cese-backend/pkg/response"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// Lint 检查六要素草稿
+// @Summary 检查六要素草稿
+// @Description 按规则检查未保存的六要素，返回0-100的评分和逐字段的问题（error/warning/info）及改进建议；提供variables时同时检查未定义的变量
+// @Tags 六要素管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.ContextElementLintRequest true "六要素草稿"
+// @Success 200 {object} response.Response{data=model.ContextElementLintResponse} "检查完成"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /api/v1/context-elements/lint [post]
+func (h *ContextElementHandler) Lint(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	var req model.ContextElementLintRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	result, err := h.elementService.Lint(&req)
+	if err != nil {
+		handleElementError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "检查完成", result)
+}
+
+// LintElement 检查已保存的六要素
+// @Summary 检查已保存的六要素
+// @Description 按规则检查六要素，返回0-100的评分和逐字段的问题及改进建议，并结合已定义的变量检查未定义或未使用的变量
+// @Tags 六要素管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "六要素ID"
+// @Success 200 {object} response.Response{data=model.ContextElementLintResponse} "检查完成"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "记录不存在"
+// @Router /api/v1/context-elements/{id}/lint [get]
+func (h *ContextElementHandler) LintElement(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	elementID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	result, err := h.elementService.LintElement(userID, elementID)
+	if err != nil {
+		handleElementError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "检查完成", result)
+}
+
+// ListLintRules 获取已启用的检查规则
+// @Summary 获取已启用的检查规则
+// @Description 获取当前配置中启用的提示词检查规则
+// @Tags 六要素管理
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]model.LintRuleResponse} "获取成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /api/v1/context-elements/lint/rules [get]
+func (h *ContextElementHandler) ListLintRules(ctx context.Context, c *app.RequestContext) {
+	response.SuccessWithMessage(c, "获取成功", h.elementService.ListLintRules())
+}
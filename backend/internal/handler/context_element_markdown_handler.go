@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"cese-backend/internal/middleware"
+	"cese-backend/internal/model"
+	"cese-backend/pkg/response"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// UploadMarkdown 上传六要素 Markdown 文档
+// @Summary 上传Markdown文档
+// @Description 解析按“## 任务目标”等二级标题分段的Markdown文档（支持英文标题别名和YAML元数据subject/tags）并创建六要素；文件可通过multipart表单字段file上传，也可直接作为请求体
+// @Tags 导入导出
+// @Accept multipart/form-data,text/markdown
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file false "Markdown文件（最大10MB）"
+// @Param dry_run query bool false "是否只解析不保存" default(false)
+// @Param folder_id query int false "所在文件夹"
+// @Success 200 {object} response.Response{data=model.ContextElementMarkdownImportResponse} "上传成功"
+// @Failure 400 {object} response.Response "参数错误或文档无法解析"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "文件夹不存在"
+// @Router /api/v1/context-elements/markdown [post]
+func (h *ContextElementHandler) UploadMarkdown(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	var req model.ContextElementMarkdownImportRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	var filename string
+	var data []byte
+	if fileHeader, err := c.FormFile("file"); err == nil {
+		if fileHeader.Size > maxImportFileSize {
+			response.ErrorWithMessage(c, response.CodeInvalidParams, "导入文件不能超过10MB")
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			response.ErrorWithMessage(c, response.CodeInvalidParams, "读取导入文件失败")
+			return
+		}
+		defer file.Close()
+		if data, err = io.ReadAll(io.LimitReader(file, maxImportFileSize)); err != nil {
+			response.ErrorWithMessage(c, response.CodeInvalidParams, "读取导入文件失败")
+			return
+		}
+		filename = fileHeader.Filename
+	} else {
+		data = c.Request.Body()
+		if len(data) > maxImportFileSize {
+			response.ErrorWithMessage(c, response.CodeInvalidParams, "导入文件不能超过10MB")
+			return
+		}
+	}
+	if len(data) == 0 {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "请上传Markdown文档")
+		return
+	}
+
+	result, err := h.elementService.ImportMarkdown(userID, filename, data, &req)
+	if err != nil {
+		handleImportError(c, err)
+		return
+	}
+
+	message := "上传成功"
+	if req.DryRun {
+		message = "解析成功"
+	}
+	response.SuccessWithMessage(c, message, result)
+}
+
+// DownloadMarkdown 下载六要素 Markdown 文档
+// @Summary 下载Markdown文档
+// @Description 将六要素导出为与六要素提示词模板格式一致的Markdown文档，主题和标签写入YAML元数据
+// @Tags 导入导出
+// @Produce text/markdown
+// @Security BearerAuth
+// @Param id path int true "六要素ID"
+// @Success 200 {file} file "Markdown文档"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "记录不存在"
+// @Router /api/v1/context-elements/{id}/markdown [get]
+func (h *ContextElementHandler) DownloadMarkdown(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	elementID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	file, err := h.elementService.ExportMarkdown(userID, elementID)
+	if err != nil {
+		handleElementError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", contentDisposition(file.Filename))
+	c.Data(http.StatusOK, file.ContentType, file.Data)
+}
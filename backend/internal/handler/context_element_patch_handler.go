@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"context"
+	"strings"
+
+	"cese-backend/internal/middleware"
+	"cese-backend/internal/model"
+	"cese-backend/pkg/response"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// Patch 局部更新六要素
+// @Summary 局部更新六要素
+// @Description 支持 JSON Merge Patch（application/merge-patch+json，null表示清空字段）和 JSON Patch（application/json-patch+json，remove表示清空字段），未出现的字段保持不变；Content-Type为application/json时请求体为数组按JSON Patch处理，否则按Merge Patch处理
+// @Tags 六要素管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "六要素ID"
+// @Param If-Match header string false "获取记录时返回的ETag，strict模式下必填"
+// @Param request body object true "补丁内容"
+// @Success 200 {object} response.Response{data=model.ContextElementResponse} "更新成功"
+// @Failure 400 {object} response.Response "参数错误或补丁无法应用"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "记录不存在"
+// @Failure 412 {object} response.Response{data=model.ContextElementResponse} "记录已被修改，返回服务器上的最新内容"
+// @Failure 428 {object} response.Response "缺少If-Match请求头"
+// @Router /api/v1/context-elements/{id} [patch]
+func (h *ContextElementHandler) Patch(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	elementID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	body := c.Request.Body()
+	format := model.PatchFormatFromContentType(string(c.ContentType()), body)
+	if format == "" {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "不支持的补丁格式")
+		return
+	}
+
+	element, err := h.elementService.Patch(userID, elementID, format, body, string(c.GetHeader("If-Match")))
+	if err != nil {
+		h.handleWriteError(c, userID, elementID, err, handlePatchError)
+		return
+	}
+
+	c.Header("ETag", element.ETag)
+
+	response.SuccessWithMessage(c, "更新成功", element)
+}
+
+// handlePatchError 将补丁错误转换为响应，补丁格式和内容校验问题按参数错误处理
+func handlePatchError(c *app.RequestContext, err error) {
+	message := err.Error()
+	if message == "不支持的补丁格式" ||
+		strings.HasPrefix(message, "补丁应用失败") ||
+		strings.HasPrefix(message, "参数验证失败: ") {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, message)
+		return
+	}
+	handleElementError(c, err)
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"cese-backend/internal/middleware"
+	"cese-backend/internal/model"
+	"cese-backend/pkg/prompt"
+	"cese-backend/pkg/response"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// Render 渲染提示词
+// @Summary 渲染提示词
+// @Description 将六要素组装为Markdown、纯文本、XML或JSON格式的提示词
+// @Tags 六要素管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "六要素ID"
+// @Param format query string false "输出格式" Enums(markdown, text, xml, json) default(markdown)
+// @Param order query string false "段落顺序，逗号分隔的字段名"
+// @Param omit_empty query bool false "是否省略空段落"
+// @Param raw query bool false "是否直接返回提示词文本"
+// @Success 200 {object} response.Response{data=model.ContextElementRenderResponse} "渲染成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "记录不存在"
+// @Router /api/v1/context-elements/{id}/render [get]
+func (h *ContextElementHandler) Render(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	elementID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	var req model.ContextElementRenderRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	result, err := h.elementService.Render(userID, elementID, &req)
+	if err != nil {
+		handleElementError(c, err)
+		return
+	}
+
+	if req.Raw {
+		c.Data(http.StatusOK, prompt.ContentType(result.Format), []byte(result.Content))
+		return
+	}
+
+	response.SuccessWithMessage(c, "渲染成功", result)
+}
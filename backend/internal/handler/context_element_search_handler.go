@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"context"
+
+	"cese-backend/internal/middleware"
+	"cese-backend/internal/model"
+	"cese-backend/pkg/response"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// Search 全文搜索六要素
+// @Summary 全文搜索六要素
+// @Description 在主题和六个要素中搜索关键词，按相关度排序（主题 > 任务目标 > 其他字段），返回每个字段的高亮片段。关键词以空格分隔且需全部命中，支持"双引号短语"和 -排除词
+// @Tags 六要素管理
+// @Produce json
+// @Security BearerAuth
+// @Param keyword query string true "搜索表达式，如：代码审查 \"单元测试\" -Java"
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(15)
+// @Param tags query string false "标签过滤（逗号分隔）"
+// @Param tag_mode query string false "标签匹配方式" Enums(any, all) default(any)
+// @Param folder_id query int false "文件夹过滤（0表示根目录）"
+// @Param recursive query bool false "是否包含子文件夹" default(false)
+// @Param sort_by query string false "排序字段" Enums(relevance, created_at, updated_at, subject) default(relevance)
+// @Param sort_desc query bool false "是否倒序（按相关度排序时忽略）" default(false)
+// @Success 200 {object} response.PageResponse{data=[]model.ContextElementSearchResult} "搜索成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /api/v1/context-elements/search [get]
+func (h *ContextElementHandler) Search(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	var req model.ContextElementQueryRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	results, total, err := h.elementService.Search(userID, &req)
+	if err != nil {
+		switch err.Error() {
+		case "参数验证失败", "搜索关键词不能为空":
+			response.ErrorWithMessage(c, response.CodeInvalidParams, err.Error())
+		default:
+			response.ErrorWithMessage(c, response.CodeInternalError, err.Error())
+		}
+		return
+	}
+
+	response.PageSuccessWithMessage(c, "搜索成功", results, total, req.Page, req.Size)
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"context"
+	"strings"
+
+	"cese-backend/internal/middleware"
+	"cese-backend/internal/model"
+	"cese-backend/pkg/response"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// CountTokens 统计六要素的令牌数
+// @Summary 统计六要素的令牌数
+// @Description 统计六要素各字段及渲染后完整提示词的令牌数，并按模型价格估算输入费用；近似估算的编码方式（cjk_approx）exact为false
+// @Tags 六要素管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "六要素ID"
+// @Param model query string false "模型名称，为空时使用默认模型"
+// @Param format query string false "渲染格式 (markdown, text, xml, json)" default(markdown)
+// @Param omit_empty query bool false "是否省略内容为空的段落"
+// @Success 200 {object} response.Response{data=model.ContextElementTokenResponse} "统计成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "记录不存在"
+// @Router /api/v1/context-elements/{id}/tokens [get]
+func (h *ContextElementHandler) CountTokens(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	elementID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	var req model.ContextElementTokenQuery
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	result, err := h.elementService.CountTokens(userID, elementID, &req)
+	if err != nil {
+		handleTokenError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "统计成功", result)
+}
+
+// CountDraftTokens 统计六要素草稿或文本的令牌数
+// @Summary 统计六要素草稿或文本的令牌数
+// @Description 统计未保存的六要素草稿各字段及渲染后提示词的令牌数；指定text时只统计该文本。同时按模型价格估算输入费用
+// @Tags 六要素管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.ContextElementTokenRequest true "六要素草稿或文本"
+// @Success 200 {object} response.Response{data=model.ContextElementTokenResponse} "统计成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /api/v1/context-elements/tokens [post]
+func (h *ContextElementHandler) CountDraftTokens(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	var req model.ContextElementTokenRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	result, err := h.elementService.CountDraftTokens(&req)
+	if err != nil {
+		handleTokenError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "统计成功", result)
+}
+
+// ListTokenModels 获取可用的模型及价格
+// @Summary 获取可用的模型及价格
+// @Description 获取统计令牌数可选的模型、编码方式、每百万输入令牌的价格和上下文窗口
+// @Tags 六要素管理
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]model.TokenModelResponse} "获取成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /api/v1/context-elements/tokens/models [get]
+func (h *ContextElementHandler) ListTokenModels(ctx context.Context, c *app.RequestContext) {
+	response.SuccessWithMessage(c, "获取成功", h.elementService.ListTokenModels())
+}
+
+// handleTokenError 将令牌统计错误转换为响应，模型不存在按参数错误处理
+func handleTokenError(c *app.RequestContext, err error) {
+	message := err.Error()
+	if message == "未配置默认模型" || strings.HasPrefix(message, "不支持的模型: ") {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, message)
+		return
+	}
+	handleElementError(c, err)
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"unicode"
+
+	"cese-backend/internal/middleware"
+	"cese-backend/internal/model"
+	"cese-backend/pkg/response"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// maxImportFileSize 导入文件的最大字节数
+const maxImportFileSize = 10 << 20
+
+// MaxRequestBodySize 服务器允许的最大请求体字节数：导入文件上限加上 multipart 表单的额外开销，
+// 创建服务器时需通过 server.WithMaxRequestBodySize 设置，否则超过框架默认的4MB即被拒绝
+const MaxRequestBodySize = maxImportFileSize + 1<<20
+
+// Export 导出六要素
+// @Summary 导出六要素
+// @Description 按ID列表、过滤条件或全部导出六要素，支持JSON、CSV和ZIP（每条六要素一个JSON文件）格式
+// @Tags 导入导出
+// @Accept json
+// @Produce application/json,text/csv,application/zip
+// @Security BearerAuth
+// @Param request body model.ContextElementExportRequest true "导出请求"
+// @Success 200 {file} file "导出文件"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /api/v1/context-elements/export [post]
+func (h *ContextElementHandler) Export(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	var req model.ContextElementExportRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	file, err := h.elementService.Export(userID, &req)
+	if err != nil {
+		handleElementError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", contentDisposition(file.Filename))
+	c.Data(http.StatusOK, file.ContentType, file.Data)
+}
+
+// Import 导入六要素
+// @Summary 导入六要素
+// @Description 上传JSON、CSV、Markdown或ZIP（可包含JSON和Markdown文件）导入六要素，逐条校验并返回每条记录的结果；按主题判断冲突，支持跳过或覆盖，dry_run时只校验不写入
+// @Tags 导入导出
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "导入文件（最大10MB）"
+// @Param format formData string false "文件格式，默认按扩展名判断" Enums(json, csv, zip, markdown)
+// @Param conflict formData string false "主题冲突处理方式" Enums(skip, overwrite) default(skip)
+// @Param dry_run formData bool false "是否只校验不写入" default(false)
+// @Param folder_id formData int false "新建记录所在文件夹"
+// @Success 200 {object} response.Response{data=model.ContextElementImportResult} "导入完成"
+// @Failure 400 {object} response.Response "参数错误或文件无法解析"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "文件夹不存在"
+// @Router /api/v1/context-elements/import [post]
+func (h *ContextElementHandler) Import(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	var req model.ContextElementImportRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "请上传导入文件")
+		return
+	}
+	if fileHeader.Size > maxImportFileSize {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "导入文件不能超过10MB")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "读取导入文件失败")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxImportFileSize))
+	if err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "读取导入文件失败")
+		return
+	}
+
+	result, err := h.elementService.Import(userID, fileHeader.Filename, data, &req)
+	if err != nil {
+		handleImportError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "导入完成", result)
+}
+
+// contentDisposition 生成附件下载头，非ASCII文件名通过 filename* 传递
+func contentDisposition(filename string) string {
+	fallback := filename
+	for _, r := range filename {
+		if r > unicode.MaxASCII || r == '"' {
+			fallback = "download" + path.Ext(filename)
+			break
+		}
+	}
+	return fmt.Sprintf(`attachment; filename="%s"; filename*=UTF-8''%s`, fallback, url.PathEscape(filename))
+}
+
+// handleImportError 将导入错误转换为响应，文件格式和内容校验问题按参数错误处理
+func handleImportError(c *app.RequestContext, err error) {
+	message := err.Error()
+	if message == "不支持的导入文件格式" ||
+		strings.HasPrefix(message, "导入文件解析失败") ||
+		strings.HasPrefix(message, "导入记录数不能超过") ||
+		strings.HasPrefix(message, "Markdown解析失败") ||
+		strings.HasPrefix(message, "参数验证失败: ") {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, message)
+		return
+	}
+	handleElementError(c, err)
+}
@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"context"
+
+	"cese-backend/internal/middleware"
+	"cese-backend/internal/model"
+	"cese-backend/pkg/response"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// ListTrash 获取回收站列表
+// @Summary 获取回收站列表
+// @Description 获取当前用户已删除的六要素，按删除时间倒序，包含预计自动彻底删除的时间
+// @Tags 回收站
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(15)
+// @Param keyword query string false "按主题搜索"
+// @Success 200 {object} response.PageResponse{data=[]model.ContextElementTrashResponse} "查询成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /api/v1/context-elements/trash [get]
+func (h *ContextElementHandler) ListTrash(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	var req model.ContextElementTrashQueryRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	elements, total, err := h.elementService.ListTrash(userID, &req)
+	if err != nil {
+		handleElementError(c, err)
+		return
+	}
+
+	response.PageSuccessWithMessage(c, "查询成功", elements, total, req.Page, req.Size)
+}
+
+// RestoreTrash 批量恢复六要素
+// @Summary 批量恢复六要素
+// @Description 将回收站中的六要素恢复到原文件夹，原文件夹已删除时恢复到根目录
+// @Tags 回收站
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.ContextElementTrashRequest true "六要素ID列表"
+// @Success 200 {object} response.Response{data=model.ContextElementTrashResult} "恢复成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /api/v1/context-elements/trash/restore [post]
+func (h *ContextElementHandler) RestoreTrash(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	var req model.ContextElementTrashRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	result, err := h.elementService.RestoreTrash(userID, &req)
+	if err != nil {
+		handleElementError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "恢复成功", result)
+}
+
+// RestoreTrashOne 恢复单个六要素
+// @Summary 恢复单个六要素
+// @Description 将回收站中的六要素恢复到原文件夹，原文件夹已删除时恢复到根目录
+// @Tags 回收站
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "六要素ID"
+// @Success 200 {object} response.Response{data=model.ContextElementTrashResult} "恢复成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "回收站中不存在该记录"
+// @Router /api/v1/context-elements/trash/{id}/restore [post]
+func (h *ContextElementHandler) RestoreTrashOne(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	elementID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	result, err := h.elementService.RestoreTrash(userID, &model.ContextElementTrashRequest{IDs: []uint64{elementID}})
+	if err != nil {
+		handleElementError(c, err)
+		return
+	}
+	if result.Affected == 0 {
+		response.Error(c, response.CodeElementNotFound)
+		return
+	}
+
+	response.SuccessWithMessage(c, "恢复成功", result)
+}
+
+// PurgeTrash 批量彻底删除六要素
+// @Summary 批量彻底删除六要素
+// @Description 彻底删除回收站中的六要素及其历史版本、变量定义、标签关联、生成记录、评估数据、对比变体和发布的模板，不可恢复
+// @Tags 回收站
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.ContextElementTrashRequest true "六要素ID列表"
+// @Success 200 {object} response.Response{data=model.ContextElementTrashResult} "删除成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /api/v1/context-elements/trash/purge [post]
+func (h *ContextElementHandler) PurgeTrash(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	var req model.ContextElementTrashRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	result, err := h.elementService.PurgeTrash(userID, &req)
+	if err != nil {
+		handleElementError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "删除成功", result)
+}
+
+// PurgeTrashOne 彻底删除单个六要素
+// @Summary 彻底删除单个六要素
+// @Description 彻底删除回收站中的六要素及其历史版本、变量定义、标签关联、生成记录、评估数据、对比变体和发布的模板，不可恢复
+// @Tags 回收站
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "六要素ID"
+// @Success 200 {object} response.Response{data=model.ContextElementTrashResult} "删除成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "回收站中不存在该记录"
+// @Router /api/v1/context-elements/trash/{id} [delete]
+func (h *ContextElementHandler) PurgeTrashOne(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	elementID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	result, err := h.elementService.PurgeTrash(userID, &model.ContextElementTrashRequest{IDs: []uint64{elementID}})
+	if err != nil {
+		handleElementError(c, err)
+		return
+	}
+	if result.Affected == 0 {
+		response.Error(c, response.CodeElementNotFound)
+		return
+	}
+
+	response.SuccessWithMessage(c, "删除成功", result)
+}
+
+// EmptyTrash 清空回收站
+// @Summary 清空回收站
+// @Description 彻底删除回收站中的全部六要素，不可恢复
+// @Tags 回收站
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=model.ContextElementTrashResult} "清空成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /api/v1/context-elements/trash [delete]
+func (h *ContextElementHandler) EmptyTrash(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	result, err := h.elementService.PurgeTrash(userID, nil)
+	if err != nil {
+		handleElementError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "清空成功", result)
+}
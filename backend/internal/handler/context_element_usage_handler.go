@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"cese-backend/internal/middleware"
+	"cese-backend/internal/model"
+	"cese-backend/pkg/logger"
+	"cese-backend/pkg/response"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// TrackUsage 使用统计中间件：请求成功后为路径中的六要素记录一次渲染或生成
+func (h *ContextElementHandler) TrackUsage(event string) app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		c.Next(ctx)
+
+		if c.Response.StatusCode() != http.StatusOK {
+			return
+		}
+		userID := middleware.GetUserID(c)
+		elementID, ok := parseUintParam(c, "id")
+		if userID == 0 || !ok {
+			return
+		}
+
+		// 统计失败不影响请求结果
+		if _, err := h.elementService.RecordUsage(userID, elementID, event); err != nil {
+			logger.GetLogger().Warnf("记录六要素使用情况失败: element_id=%d event=%s err=%v", elementID, event, err)
+		}
+	}
+}
+
+// RecordUsage 上报使用事件
+// @Summary 上报使用事件
+// @Description 客户端复制提示词后上报，计入复制次数和使用次数；打开、渲染和生成由服务端自动记录
+// @Tags 六要素管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "六要素ID"
+// @Param request body model.ContextElementUsageRequest true "使用事件"
+// @Success 200 {object} response.Response{data=model.ContextElementUsageResponse} "记录成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "记录不存在"
+// @Router /api/v1/context-elements/{id}/usage [post]
+func (h *ContextElementHandler) RecordUsage(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	elementID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	var req model.ContextElementUsageRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	usage, err := h.elementService.RecordUsage(userID, elementID, req.Event)
+	if err != nil {
+		handleElementError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "记录成功", usage)
+}
+
+// Favorite 收藏六要素
+// @Summary 收藏六要素
+// @Tags 六要素管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "六要素ID"
+// @Success 200 {object} response.Response{data=model.ContextElementUsageResponse} "收藏成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "记录不存在"
+// @Router /api/v1/context-elements/{id}/favorite [put]
+func (h *ContextElementHandler) Favorite(ctx context.Context, c *app.RequestContext) {
+	h.setUsageFlag(c, "收藏成功", func(userID, elementID uint64) (*model.ContextElementUsageResponse, error) {
+		return h.elementService.SetFavorite(userID, elementID, true)
+	})
+}
+
+// Unfavorite 取消收藏六要素
+// @Summary 取消收藏六要素
+// @Tags 六要素管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "六要素ID"
+// @Success 200 {object} response.Response{data=model.ContextElementUsageResponse} "已取消收藏"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "记录不存在"
+// @Router /api/v1/context-elements/{id}/favorite [delete]
+func (h *ContextElementHandler) Unfavorite(ctx context.Context, c *app.RequestContext) {
+	h.setUsageFlag(c, "已取消收藏", func(userID, elementID uint64) (*model.ContextElementUsageResponse, error) {
+		return h.elementService.SetFavorite(userID, elementID, false)
+	})
+}
+
+// Pin 置顶六要素
+// @Summary 置顶六要素
+// @Description 置顶后可在列表中通过 pinned_first 排在最前；已置顶时保持原置顶时间
+// @Tags 六要素管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "六要素ID"
+// @Success 200 {object} response.Response{data=model.ContextElementUsageResponse} "置顶成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "记录不存在"
+// @Router /api/v1/context-elements/{id}/pin [put]
+func (h *ContextElementHandler) Pin(ctx context.Context, c *app.RequestContext) {
+	h.setUsageFlag(c, "置顶成功", func(userID, elementID uint64) (*model.ContextElementUsageResponse, error) {
+		return h.elementService.SetPinned(userID, elementID, true)
+	})
+}
+
+// Unpin 取消置顶六要素
+// @Summary 取消置顶六要素
+// @Tags 六要素管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "六要素ID"
+// @Success 200 {object} response.Response{data=model.ContextElementUsageResponse} "已取消置顶"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "记录不存在"
+// @Router /api/v1/context-elements/{id}/pin [delete]
+func (h *ContextElementHandler) Unpin(ctx context.Context, c *app.RequestContext) {
+	h.setUsageFlag(c, "已取消置顶", func(userID, elementID uint64) (*model.ContextElementUsageResponse, error) {
+		return h.elementService.SetPinned(userID, elementID, false)
+	})
+}
+
+// GetRecent 获取最近使用的六要素
+// @Summary 获取最近使用的六要素
+// @Description 按最近打开或使用（渲染、生成、复制）的时间倒序返回，包含使用统计
+// @Tags 六要素管理
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "数量" default(10)
+// @Success 200 {object} response.Response{data=[]model.ContextElementResponse} "查询成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /api/v1/context-elements/recent [get]
+func (h *ContextElementHandler) GetRecent(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	var req model.ContextElementRecentRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	elements, err := h.elementService.GetRecent(userID, &req)
+	if err != nil {
+		handleElementError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "查询成功", elements)
+}
+
+// setUsageFlag 处理收藏和置顶请求
+func (h *ContextElementHandler) setUsageFlag(c *app.RequestContext, message string, set func(userID, elementID uint64) (*model.ContextElementUsageResponse, error)) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	elementID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	usage, err := set(userID, elementID)
+	if err != nil {
+		handleElementError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, message, usage)
+}
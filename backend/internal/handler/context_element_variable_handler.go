@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"context"
+
+	"cese-backend/internal/middleware"
+	"cese-backend/internal/model"
+	"cese-backend/pkg/response"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// GetVariables 获取模板变量
+// @Summary 获取模板变量
+// @Description 获取六要素中检测到的变量及已定义的变量类型
+// @Tags 六要素变量
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "六要素ID"
+// @Success 200 {object} response.Response{data=model.ContextElementVariablesResponse} "获取成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "记录不存在"
+// @Router /api/v1/context-elements/{id}/variables [get]
+func (h *ContextElementHandler) GetVariables(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	elementID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	result, err := h.elementService.GetVariables(userID, elementID)
+	if err != nil {
+		handleElementError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "获取成功", result)
+}
+
+// UpdateVariables 设置模板变量
+// @Summary 设置模板变量
+// @Description 整体替换六要素的变量定义（名称、类型、默认值、是否必填、说明）
+// @Tags 六要素变量
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "六要素ID"
+// @Param request body model.ContextElementVariablesUpdateRequest true "变量定义"
+// @Success 200 {object} response.Response{data=model.ContextElementVariablesResponse} "保存成功"
+// @Failure 400 {object} response.Response{data=[]prompt.VariableError} "变量定义不合法"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "记录不存在"
+// @Router /api/v1/context-elements/{id}/variables [put]
+func (h *ContextElementHandler) UpdateVariables(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	elementID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	var req model.ContextElementVariablesUpdateRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	result, err := h.elementService.UpdateVariables(userID, elementID, &req)
+	if err != nil {
+		handleElementError(c, err)
+		return
+	}
+	if len(result.Errors) > 0 {
+		response.ErrorWithData(c, response.CodeInvalidVariable, result.Errors)
+		return
+	}
+
+	response.SuccessWithMessage(c, "保存成功", result)
+}
+
+// RenderWithVariables 代入变量渲染提示词
+// @Summary 代入变量渲染提示词
+// @Description 校验变量值并代入六要素后渲染提示词，校验失败时返回逐个变量的错误；overrides 中的字段只用于本次渲染，不修改六要素
+// @Tags 六要素变量
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "六要素ID"
+// @Param request body model.ContextElementRenderVariablesRequest true "渲染请求"
+// @Success 200 {object} response.Response{data=model.ContextElementRenderResponse} "渲染成功"
+// @Failure 400 {object} response.Response{data=[]prompt.VariableError} "变量校验失败"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "记录不存在"
+// @Router /api/v1/context-elements/{id}/render [post]
+func (h *ContextElementHandler) RenderWithVariables(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	elementID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	var req model.ContextElementRenderVariablesRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	result, err := h.elementService.RenderWithVariables(userID, elementID, &req)
+	if err != nil {
+		handleElementError(c, err)
+		return
+	}
+	if len(result.Errors) > 0 {
+		response.ErrorWithData(c, response.CodeInvalidVariable, result.Errors)
+		return
+	}
+
+	response.SuccessWithMessage(c, "渲染成功", result)
+}
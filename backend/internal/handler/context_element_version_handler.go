@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"context"
+	"strconv"
+
+	"cese-backend/internal/middleware"
+	"cese-backend/internal/model"
+	"cese-backend/pkg/response"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// ListVersions 获取历史版本列表
+// @Summary 获取历史版本列表
+// @Description 获取六要素的历史版本列表（按版本号倒序）
+// @Tags 六要素版本
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "六要素ID"
+// @Success 200 {object} response.Response{data=[]model.ContextElementVersionResponse} "获取成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "记录不存在"
+// @Router /api/v1/context-elements/{id}/versions [get]
+func (h *ContextElementHandler) ListVersions(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	elementID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	versions, err := h.elementService.ListVersions(userID, elementID)
+	if err != nil {
+		handleElementError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "获取成功", versions)
+}
+
+// GetVersion 获取指定历史版本
+// @Summary 获取指定历史版本
+// @Description 根据版本号获取六要素历史版本详情
+// @Tags 六要素版本
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "六要素ID"
+// @Param version path int true "版本号"
+// @Success 200 {object} response.Response{data=model.ContextElementVersionResponse} "获取成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "版本不存在"
+// @Router /api/v1/context-elements/{id}/versions/{version} [get]
+func (h *ContextElementHandler) GetVersion(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	elementID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	versionNo, err := strconv.Atoi(c.Param("version"))
+	if err != nil || versionNo <= 0 {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的版本号")
+		return
+	}
+
+	version, err := h.elementService.GetVersion(userID, elementID, versionNo)
+	if err != nil {
+		handleElementError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "获取成功", version)
+}
+
+// DiffVersions 对比历史版本
+// @Summary 对比历史版本
+// @Description 逐字段对比两个历史版本，to为空时与当前版本对比
+// @Tags 六要素版本
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "六要素ID"
+// @Param from query int true "起始版本号"
+// @Param to query int false "目标版本号（默认当前版本）"
+// @Success 200 {object} response.Response{data=model.ContextElementVersionDiffResponse} "对比成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "版本不存在"
+// @Router /api/v1/context-elements/{id}/versions/diff [get]
+func (h *ContextElementHandler) DiffVersions(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	elementID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	var req model.ContextElementVersionDiffRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	result, err := h.elementService.DiffVersions(userID, elementID, &req)
+	if err != nil {
+		handleElementError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "对比成功", result)
+}
+
+// RestoreVersion 恢复历史版本
+// @Summary 恢复历史版本
+// @Description 将指定历史版本恢复为当前版本，恢复前的内容会保存为新的历史版本
+// @Tags 六要素版本
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "六要素ID"
+// @Param version path int true "版本号"
+// @Param If-Match header string false "获取记录时返回的ETag，strict模式下必填"
+// @Success 200 {object} response.Response{data=model.ContextElementResponse} "恢复成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "版本不存在"
+// @Failure 412 {object} response.Response{data=model.ContextElementResponse} "记录已被修改，返回服务器上的最新内容"
+// @Failure 428 {object} response.Response "缺少If-Match请求头"
+// @Router /api/v1/context-elements/{id}/versions/{version}/restore [post]
+func (h *ContextElementHandler) RestoreVersion(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	elementID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	versionNo, err := strconv.Atoi(c.Param("version"))
+	if err != nil || versionNo <= 0 {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的版本号")
+		return
+	}
+
+	element, err := h.elementService.RestoreVersion(userID, elementID, versionNo, string(c.GetHeader("If-Match")))
+	if err != nil {
+		h.handleWriteError(c, userID, elementID, err, handleElementError)
+		return
+	}
+
+	c.Header("ETag", element.ETag)
+	response.SuccessWithMessage(c, "恢复成功", element)
+}
@@ -0,0 +1,394 @@
+package handler
+
+import (
+	"context"
+
+	"cese-backend/internal/middleware"
+	"cese-backend/internal/model"
+	"cese-backend/pkg/response"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// GetEvalDatasets 获取评估数据集列表
+// @Summary 获取评估数据集列表
+// @Description 获取当前用户的评估数据集，按更新时间倒序，列表不返回用例
+// @Tags 提示词评估
+// @Produce json
+// @Security BearerAuth
+// @Param element_id query int false "六要素ID"
+// @Success 200 {object} response.Response{data=[]model.EvalDatasetResponse} "查询成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /api/v1/eval-datasets [get]
+func (h *GenerationHandler) GetEvalDatasets(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	var req model.EvalDatasetQueryRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	datasets, err := h.generationService.GetEvalDatasets(userID, &req)
+	if err != nil {
+		handleEvalError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "查询成功", datasets)
+}
+
+// GetEvalDataset 获取评估数据集
+// @Summary 获取评估数据集
+// @Description 获取评估数据集及其用例
+// @Tags 提示词评估
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "评估数据集ID"
+// @Success 200 {object} response.Response{data=model.EvalDatasetResponse} "获取成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "评估数据集不存在"
+// @Router /api/v1/eval-datasets/{id} [get]
+func (h *GenerationHandler) GetEvalDataset(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	datasetID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	dataset, err := h.generationService.GetEvalDataset(userID, datasetID)
+	if err != nil {
+		handleEvalError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "获取成功", dataset)
+}
+
+// CreateEvalDataset 创建评估数据集
+// @Summary 创建评估数据集
+// @Description 为六要素创建评估数据集，每个用例包括输入变量和对输出的断言（contains、not_contains、regex、json_schema、max_length、llm_judge）
+// @Tags 提示词评估
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.EvalDatasetCreateRequest true "评估数据集"
+// @Success 200 {object} response.Response{data=model.EvalDatasetResponse} "创建成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "六要素不存在"
+// @Router /api/v1/eval-datasets [post]
+func (h *GenerationHandler) CreateEvalDataset(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	var req model.EvalDatasetCreateRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	dataset, err := h.generationService.CreateEvalDataset(userID, &req)
+	if err != nil {
+		handleEvalError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "创建成功", dataset)
+}
+
+// UpdateEvalDataset 更新评估数据集
+// @Summary 更新评估数据集
+// @Description 修改名称和描述，并整体替换用例
+// @Tags 提示词评估
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "评估数据集ID"
+// @Param request body model.EvalDatasetUpdateRequest true "评估数据集"
+// @Success 200 {object} response.Response{data=model.EvalDatasetResponse} "更新成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "评估数据集不存在"
+// @Router /api/v1/eval-datasets/{id} [put]
+func (h *GenerationHandler) UpdateEvalDataset(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	datasetID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	var req model.EvalDatasetUpdateRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	dataset, err := h.generationService.UpdateEvalDataset(userID, datasetID, &req)
+	if err != nil {
+		handleEvalError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "更新成功", dataset)
+}
+
+// DeleteEvalDataset 删除评估数据集
+// @Summary 删除评估数据集
+// @Description 删除评估数据集、用例及其全部评估运行
+// @Tags 提示词评估
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "评估数据集ID"
+// @Success 200 {object} response.Response "删除成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "评估数据集不存在"
+// @Router /api/v1/eval-datasets/{id} [delete]
+func (h *GenerationHandler) DeleteEvalDataset(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	datasetID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	if err := h.generationService.DeleteEvalDataset(userID, datasetID); err != nil {
+		handleEvalError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// RunEval 运行评估
+// @Summary 运行评估
+// @Description 创建状态为 running 的评估运行并立即返回，在后台对数据集的每个用例渲染并执行六要素、按断言评估输出；通过 GET /api/v1/eval-runs/{id} 查询进度和结果
+// @Tags 提示词评估
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "评估数据集ID"
+// @Param request body model.EvalRunCreateRequest true "模型服务和采样参数"
+// @Success 200 {object} response.Response{data=model.EvalRunResponse} "评估已开始"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "评估数据集不存在"
+// @Failure 429 {object} response.Response "生成任务过多"
+// @Router /api/v1/eval-datasets/{id}/runs [post]
+func (h *GenerationHandler) RunEval(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	datasetID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	var req model.EvalRunCreateRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	run, err := h.generationService.RunEval(userID, datasetID, &req)
+	if err != nil {
+		handleEvalError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "评估已开始", run)
+}
+
+// GetEvalRuns 查询评估运行
+// @Summary 查询评估运行
+// @Description 分页查询评估运行的通过情况，按创建时间倒序，列表不返回用例结果
+// @Tags 提示词评估
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(15)
+// @Param dataset_id query int false "评估数据集ID"
+// @Param element_id query int false "六要素ID"
+// @Success 200 {object} response.PageResponse{data=[]model.EvalRunResponse} "查询成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /api/v1/eval-runs [get]
+func (h *GenerationHandler) GetEvalRuns(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	var req model.EvalRunQueryRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	h.respondEvalRuns(c, userID, &req)
+}
+
+// GetElementEvalRuns 查询六要素的评估历史
+// @Summary 查询六要素的评估历史
+// @Description 分页查询指定六要素的评估运行，每条记录包括运行时的六要素版本和通过情况
+// @Tags 提示词评估
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "六要素ID"
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(15)
+// @Param dataset_id query int false "评估数据集ID"
+// @Success 200 {object} response.PageResponse{data=[]model.EvalRunResponse} "查询成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /api/v1/context-elements/{id}/eval-runs [get]
+func (h *GenerationHandler) GetElementEvalRuns(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	elementID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	var req model.EvalRunQueryRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+	req.ElementID = elementID
+
+	h.respondEvalRuns(c, userID, &req)
+}
+
+// respondEvalRuns 查询评估运行并返回分页结果
+func (h *GenerationHandler) respondEvalRuns(c *app.RequestContext, userID uint64, req *model.EvalRunQueryRequest) {
+	runs, total, err := h.generationService.GetEvalRuns(userID, req)
+	if err != nil {
+		handleEvalError(c, err)
+		return
+	}
+
+	response.PageSuccessWithMessage(c, "查询成功", runs, total, req.Page, req.Size)
+}
+
+// GetEvalRun 获取评估运行详情
+// @Summary 获取评估运行详情
+// @Description 获取评估运行及每个用例的提示词、输出和断言结果
+// @Tags 提示词评估
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "评估运行ID"
+// @Success 200 {object} response.Response{data=model.EvalRunResponse} "获取成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "评估运行不存在"
+// @Router /api/v1/eval-runs/{id} [get]
+func (h *GenerationHandler) GetEvalRun(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	runID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	run, err := h.generationService.GetEvalRun(userID, runID)
+	if err != nil {
+		handleEvalError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "获取成功", run)
+}
+
+// DeleteEvalRun 删除评估运行
+// @Summary 删除评估运行
+// @Description 删除评估运行及其用例结果，执行中的评估运行在当前用例结束后停止
+// @Tags 提示词评估
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "评估运行ID"
+// @Success 200 {object} response.Response "删除成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "评估运行不存在"
+// @Router /api/v1/eval-runs/{id} [delete]
+func (h *GenerationHandler) DeleteEvalRun(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	runID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	if err := h.generationService.DeleteEvalRun(userID, runID); err != nil {
+		handleEvalError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// handleEvalError 将评估错误转换为响应
+func handleEvalError(c *app.RequestContext, err error) {
+	switch err.Error() {
+	case "评估数据集不存在":
+		response.Error(c, response.CodeEvalDatasetNotFound)
+	case "评估运行不存在":
+		response.Error(c, response.CodeEvalRunNotFound)
+	default:
+		handleGenerationError(c, err)
+	}
+}
@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"context"
+	"strings"
+
+	"cese-backend/internal/middleware"
+	"cese-backend/internal/model"
+	"cese-backend/internal/service"
+	"cese-backend/pkg/response"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// FolderHandler 文件夹处理器
+type FolderHandler struct {
+	folderService service.FolderService
+}
+
+// NewFolderHandler 创建文件夹处理器实例
+func NewFolderHandler(folderService service.FolderService) *FolderHandler {
+	return &FolderHandler{
+		folderService: folderService,
+	}
+}
+
+// GetTree 获取文件夹树
+// @Summary 获取文件夹树
+// @Description 获取当前用户的文件夹树及每个文件夹直接包含的六要素数量
+// @Tags 文件夹管理
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]model.FolderResponse} "查询成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /api/v1/folders [get]
+func (h *FolderHandler) GetTree(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	folders, err := h.folderService.GetTree(userID)
+	if err != nil {
+		response.ErrorWithMessage(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "查询成功", folders)
+}
+
+// Create 创建文件夹
+// @Summary 创建文件夹
+// @Description 在根目录或指定父文件夹下创建文件夹
+// @Tags 文件夹管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.FolderCreateRequest true "创建请求"
+// @Success 200 {object} response.Response{data=model.FolderResponse} "创建成功"
+// @Failure 400 {object} response.Response "参数错误或文件夹已存在"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "父文件夹不存在"
+// @Router /api/v1/folders [post]
+func (h *FolderHandler) Create(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	var req model.FolderCreateRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	folder, err := h.folderService.Create(userID, &req)
+	if err != nil {
+		handleFolderError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "创建成功", folder)
+}
+
+// Rename 重命名文件夹
+// @Summary 重命名文件夹
+// @Description 重命名文件夹
+// @Tags 文件夹管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "文件夹ID"
+// @Param request body model.FolderRenameRequest true "重命名请求"
+// @Success 200 {object} response.Response{data=model.FolderResponse} "重命名成功"
+// @Failure 400 {object} response.Response "参数错误或文件夹已存在"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "文件夹不存在"
+// @Router /api/v1/folders/{id} [put]
+func (h *FolderHandler) Rename(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	folderID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	var req model.FolderRenameRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	folder, err := h.folderService.Rename(userID, folderID, &req)
+	if err != nil {
+		handleFolderError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "重命名成功", folder)
+}
+
+// Move 移动文件夹
+// @Summary 移动文件夹
+// @Description 将文件夹移动到新的父文件夹下，parent_id为空表示移动到根目录
+// @Tags 文件夹管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "文件夹ID"
+// @Param request body model.FolderMoveRequest true "移动请求"
+// @Success 200 {object} response.Response{data=model.FolderResponse} "移动成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "文件夹不存在"
+// @Router /api/v1/folders/{id}/move [post]
+func (h *FolderHandler) Move(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	folderID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	var req model.FolderMoveRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	folder, err := h.folderService.Move(userID, folderID, &req)
+	if err != nil {
+		handleFolderError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "移动成功", folder)
+}
+
+// Delete 删除文件夹
+// @Summary 删除文件夹
+// @Description 删除文件夹，mode=cascade 同时删除子文件夹及其中的六要素，mode=reparent（默认）将其移动到上级文件夹
+// @Tags 文件夹管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "文件夹ID"
+// @Param mode query string false "删除方式" Enums(cascade, reparent) default(reparent)
+// @Success 200 {object} response.Response "删除成功"
+// @Failure 400 {object} response.Response "参数错误或子文件夹与上级文件夹中的文件夹重名"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "文件夹不存在"
+// @Router /api/v1/folders/{id} [delete]
+func (h *FolderHandler) Delete(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	folderID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	var req model.FolderDeleteRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	if err := h.folderService.Delete(userID, folderID, &req); err != nil {
+		handleFolderError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// handleFolderError 将文件夹服务错误转换为响应
+func handleFolderError(c *app.RequestContext, err error) {
+	switch err.Error() {
+	case "文件夹不存在":
+		response.Error(c, response.CodeFolderNotFound)
+	case "文件夹已存在":
+		response.Error(c, response.CodeFolderExists)
+	case "参数验证失败", "不能移动到自身或子文件夹下":
+		response.ErrorWithMessage(c, response.CodeInvalidParams, err.Error())
+	default:
+		if strings.HasPrefix(err.Error(), "上级文件夹中已有同名文件夹") {
+			response.ErrorWithMessage(c, response.CodeFolderExists, err.Error())
+			return
+		}
+		response.ErrorWithMessage(c, response.CodeInternalError, err.Error())
+	}
+}
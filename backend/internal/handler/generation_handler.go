@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"context"
+	"strings"
+
+	"cese-backend/internal/middleware"
+	"cese-backend/internal/model"
+	"cese-backend/internal/service"
+	"cese-backend/pkg/response"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// GenerationHandler 大模型生成处理器
+type GenerationHandler struct {
+	generationService service.GenerationService
+}
+
+// NewGenerationHandler 创建大模型生成处理器实例
+func NewGenerationHandler(generationService service.GenerationService) *GenerationHandler {
+	return &GenerationHandler{
+		generationService: generationService,
+	}
+}
+
+// Generate 调用大模型生成内容
+// @Summary 调用大模型生成内容
+// @Description 代入变量渲染六要素提示词，通过服务端配置的模型服务生成内容，API Key 不会下发到浏览器
+// @Tags 大模型生成
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "六要素ID"
+// @Param request body model.GenerateRequest true "生成参数"
+// @Success 200 {object} response.Response{data=model.GenerateResponse} "生成成功"
+// @Failure 400 {object} response.Response "参数错误或变量校验失败"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "记录不存在"
+// @Failure 429 {object} response.Response "生成任务过多"
+// @Failure 502 {object} response.Response "模型服务调用失败"
+// @Failure 504 {object} response.Response "模型服务响应超时"
+// @Router /api/v1/context-elements/{id}/generate [post]
+func (h *GenerationHandler) Generate(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	elementID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	var req model.GenerateRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	result, err := h.generationService.Generate(ctx, userID, elementID, &req)
+	if err != nil {
+		handleGenerationError(c, err)
+		return
+	}
+	if len(result.Errors) > 0 {
+		response.ErrorWithData(c, response.CodeInvalidVariable, result.Errors)
+		return
+	}
+
+	response.SuccessWithMessage(c, "生成成功", result)
+}
+
+// ListProviders 获取可用的模型服务
+// @Summary 获取可用的模型服务
+// @Description 获取服务端已启用的模型服务及其可用模型，不包含 API Key
+// @Tags 大模型生成
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]model.ProviderResponse} "获取成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /api/v1/providers [get]
+func (h *GenerationHandler) ListProviders(ctx context.Context, c *app.RequestContext) {
+	response.SuccessWithMessage(c, "获取成功", h.generationService.ListProviders())
+}
+
+// handleGenerationError 将生成错误转换为响应
+func handleGenerationError(c *app.RequestContext, err error) {
+	message := err.Error()
+	switch {
+	case message == "模型服务不存在或未启用":
+		response.Error(c, response.CodeProviderNotFound)
+	case message == "模型服务响应超时":
+		response.Error(c, response.CodeProviderTimeout)
+	case message == "生成任务过多，请稍后再试":
+		response.ErrorWithMessage(c, 429, message)
+	case strings.HasPrefix(message, "调用模型服务失败: "):
+		response.ErrorWithMessage(c, response.CodeProviderError, message)
+	case strings.HasPrefix(message, "参数验证失败: "):
+		response.ErrorWithMessage(c, response.CodeInvalidParams, message)
+	default:
+		handleElementError(c, err)
+	}
+}
@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"context"
+
+	"cese-backend/internal/middleware"
+	"cese-backend/internal/model"
+	"cese-backend/pkg/response"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// GetRecords 查询生成记录
+// @Summary 查询生成记录
+// @Description 分页查询当前用户的生成记录，可按六要素、模型服务、模型、状态、收藏和创建日期过滤，默认按生成时间倒序
+// @Tags 大模型生成
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(15)
+// @Param element_id query int false "六要素ID"
+// @Param provider query string false "模型服务名称"
+// @Param model query string false "模型名称"
+// @Param status query string false "状态" Enums(completed, failed, canceled)
+// @Param starred query bool false "是否收藏"
+// @Param start_date query string false "创建日期起（含），格式 2006-01-02"
+// @Param end_date query string false "创建日期止（含），格式 2006-01-02"
+// @Param sort_by query string false "排序字段" Enums(created_at, duration_ms, total_tokens)
+// @Param sort_desc query bool false "是否倒序" default(true)
+// @Success 200 {object} response.PageResponse{data=[]model.GenerationRecordResponse} "查询成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /api/v1/generations [get]
+func (h *GenerationHandler) GetRecords(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	var req model.GenerationRecordQueryRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	h.respondRecords(c, userID, &req)
+}
+
+// GetElementRecords 查询六要素的生成记录
+// @Summary 查询六要素的生成记录
+// @Description 分页查询指定六要素的生成记录，过滤和排序参数同生成记录列表
+// @Tags 大模型生成
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "六要素ID"
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(15)
+// @Param provider query string false "模型服务名称"
+// @Param model query string false "模型名称"
+// @Param status query string false "状态" Enums(completed, failed, canceled)
+// @Param starred query bool false "是否收藏"
+// @Param start_date query string false "创建日期起（含），格式 2006-01-02"
+// @Param end_date query string false "创建日期止（含），格式 2006-01-02"
+// @Param sort_by query string false "排序字段" Enums(created_at, duration_ms, total_tokens)
+// @Param sort_desc query bool false "是否倒序" default(true)
+// @Success 200 {object} response.PageResponse{data=[]model.GenerationRecordResponse} "查询成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /api/v1/context-elements/{id}/generations [get]
+func (h *GenerationHandler) GetElementRecords(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	elementID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	var req model.GenerationRecordQueryRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+	req.ElementID = elementID
+
+	h.respondRecords(c, userID, &req)
+}
+
+// respondRecords 查询生成记录并返回分页结果
+func (h *GenerationHandler) respondRecords(c *app.RequestContext, userID uint64, req *model.GenerationRecordQueryRequest) {
+	records, total, err := h.generationService.GetRecords(userID, req)
+	if err != nil {
+		handleRecordError(c, err)
+		return
+	}
+
+	response.PageSuccessWithMessage(c, "查询成功", records, total, req.Page, req.Size)
+}
+
+// GetRecord 获取生成记录详情
+// @Summary 获取生成记录详情
+// @Description 获取生成记录，包括发送给模型的提示词快照、变量、采样参数和生成的内容
+// @Tags 大模型生成
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "生成记录ID"
+// @Success 200 {object} response.Response{data=model.GenerationRecordResponse} "获取成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "生成记录不存在"
+// @Router /api/v1/generations/{id} [get]
+func (h *GenerationHandler) GetRecord(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	recordID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	record, err := h.generationService.GetRecord(userID, recordID)
+	if err != nil {
+		handleRecordError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "获取成功", record)
+}
+
+// UpdateRecord 收藏和备注生成记录
+// @Summary 收藏和备注生成记录
+// @Description 收藏或取消收藏生成记录、修改备注，省略的字段保持不变
+// @Tags 大模型生成
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "生成记录ID"
+// @Param request body model.GenerationRecordUpdateRequest true "收藏状态和备注"
+// @Success 200 {object} response.Response{data=model.GenerationRecordResponse} "更新成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "生成记录不存在"
+// @Router /api/v1/generations/{id} [patch]
+func (h *GenerationHandler) UpdateRecord(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	recordID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	var req model.GenerationRecordUpdateRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	record, err := h.generationService.UpdateRecord(userID, recordID, &req)
+	if err != nil {
+		handleRecordError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "更新成功", record)
+}
+
+// DeleteRecord 删除生成记录
+// @Summary 删除生成记录
+// @Description 删除生成记录
+// @Tags 大模型生成
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "生成记录ID"
+// @Success 200 {object} response.Response "删除成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "生成记录不存在"
+// @Router /api/v1/generations/{id} [delete]
+func (h *GenerationHandler) DeleteRecord(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	recordID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	if err := h.generationService.DeleteRecord(userID, recordID); err != nil {
+		handleRecordError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// handleRecordError 将生成记录错误转换为响应
+func handleRecordError(c *app.RequestContext, err error) {
+	switch err.Error() {
+	case "生成记录不存在":
+		response.Error(c, response.CodeGenerationNotFound)
+	default:
+		handleElementError(c, err)
+	}
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"cese-backend/internal/middleware"
+	"cese-backend/internal/model"
+	"cese-backend/pkg/response"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/network"
+	"github.com/cloudwego/hertz/pkg/protocol/http1/resp"
+)
+
+// GenerateStream 以流式方式调用大模型生成内容
+// @Summary 以流式方式调用大模型生成内容
+// @Description 与生成接口参数相同，通过 Server-Sent Events 逐段返回生成的内容。事件依次为若干 delta、一个 usage 和 done（数据为完整的生成结果）；
+// @Description 生成中途失败或超时发送 error 事件。客户端断开连接时（包括等待上游输出期间）立即取消上游请求。开始输出前的错误（参数、变量校验等）仍以 JSON 返回
+// @Tags 大模型生成
+// @Accept json
+// @Produce text/event-stream
+// @Security BearerAuth
+// @Param id path int true "六要素ID"
+// @Param request body model.GenerateRequest true "生成参数"
+// @Success 200 {string} string "SSE 事件流"
+// @Failure 400 {object} response.Response "参数错误或变量校验失败"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "记录不存在"
+// @Failure 429 {object} response.Response "生成任务过多"
+// @Failure 502 {object} response.Response "模型服务调用失败"
+// @Failure 504 {object} response.Response "模型服务响应超时"
+// @Router /api/v1/context-elements/{id}/generate/stream [post]
+func (h *GenerationHandler) GenerateStream(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	elementID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	var req model.GenerateRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	stream := newEventStream(ctx, c)
+	result, err := h.generationService.GenerateStream(ctx, userID, elementID, &req, func(delta string) error {
+		if err := stream.send(model.GenerateEventDelta, &model.GenerateDeltaEvent{Content: delta}); err != nil {
+			// 客户端已断开，取消上游请求
+			return context.Canceled
+		}
+		return nil
+	})
+
+	// 尚未开始输出时按普通接口返回错误
+	if !stream.started {
+		switch {
+		case err != nil:
+			handleGenerationError(c, err)
+		case len(result.Errors) > 0:
+			response.ErrorWithData(c, response.CodeInvalidVariable, result.Errors)
+		default:
+			_ = stream.send(model.GenerateEventUsage, result.Usage)
+			_ = stream.send(model.GenerateEventDone, result)
+		}
+		return
+	}
+
+	if err != nil {
+		code := response.CodeProviderError
+		if err.Error() == "模型服务响应超时" {
+			code = response.CodeProviderTimeout
+		}
+		_ = stream.send(model.GenerateEventError, &model.GenerateErrorEvent{Code: code, Message: err.Error()})
+		return
+	}
+	_ = stream.send(model.GenerateEventUsage, result.Usage)
+	_ = stream.send(model.GenerateEventDone, result)
+}
+
+// eventStream Server-Sent Events 输出，发送第一个事件时才写入响应头
+type eventStream struct {
+	ctx     context.Context // 客户端断开连接时取消
+	c       *app.RequestContext
+	writer  network.ExtWriter
+	started bool
+}
+
+// newEventStream 创建 SSE 输出
+func newEventStream(ctx context.Context, c *app.RequestContext) *eventStream {
+	return &eventStream{ctx: ctx, c: c}
+}
+
+// send 发送一个事件并立即刷新，客户端断开时返回错误
+func (s *eventStream) send(event string, data interface{}) error {
+	select {
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	default:
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	if !s.started {
+		s.c.SetStatusCode(http.StatusOK)
+		s.c.SetContentType("text/event-stream; charset=utf-8")
+		s.c.Response.Header.Set("Cache-Control", "no-cache")
+		s.c.Response.Header.Set("X-Accel-Buffering", "no") // 禁止反向代理缓冲
+		s.writer = resp.NewChunkedBodyWriter(&s.c.Response, s.c.GetWriter())
+		s.c.Response.HijackWriter(s.writer)
+		s.started = true
+	}
+
+	if _, err := s.writer.Write([]byte(fmt.Sprintf("event: %s\ndata: %s\n\n", event, payload))); err != nil {
+		return err
+	}
+	return s.writer.Flush()
+}
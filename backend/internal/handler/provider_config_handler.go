@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"context"
+
+	"cese-backend/internal/middleware"
+	"cese-backend/internal/model"
+	"cese-backend/internal/service"
+	"cese-backend/pkg/response"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// ProviderConfigHandler 用户模型服务配置处理器
+type ProviderConfigHandler struct {
+	configService service.ProviderConfigService
+}
+
+// NewProviderConfigHandler 创建用户模型服务配置处理器实例
+func NewProviderConfigHandler(configService service.ProviderConfigService) *ProviderConfigHandler {
+	return &ProviderConfigHandler{
+		configService: configService,
+	}
+}
+
+// GetList 获取模型服务配置列表
+// @Summary 获取模型服务配置列表
+// @Description 获取当前用户保存的模型服务配置，API Key 只返回掩码
+// @Tags 模型服务配置
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]model.ProviderConfigResponse} "查询成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /api/v1/provider-configs [get]
+func (h *ProviderConfigHandler) GetList(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	configs, err := h.configService.GetList(userID)
+	if err != nil {
+		handleProviderConfigError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "查询成功", configs)
+}
+
+// GetByID 获取模型服务配置
+// @Summary 获取模型服务配置
+// @Description 根据ID获取模型服务配置，API Key 只返回掩码
+// @Tags 模型服务配置
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "模型服务配置ID"
+// @Success 200 {object} response.Response{data=model.ProviderConfigResponse} "获取成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "模型服务配置不存在"
+// @Router /api/v1/provider-configs/{id} [get]
+func (h *ProviderConfigHandler) GetByID(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	configID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	config, err := h.configService.GetByID(userID, configID)
+	if err != nil {
+		handleProviderConfigError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "获取成功", config)
+}
+
+// Create 创建模型服务配置
+// @Summary 创建模型服务配置
+// @Description 保存模型服务的地址、模型和 API Key，API Key 使用 AES-GCM 加密存储，响应中只返回掩码
+// @Tags 模型服务配置
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.ProviderConfigCreateRequest true "创建请求"
+// @Success 200 {object} response.Response{data=model.ProviderConfigResponse} "创建成功"
+// @Failure 400 {object} response.Response "参数错误或名称已存在"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "未配置加密主密钥"
+// @Router /api/v1/provider-configs [post]
+func (h *ProviderConfigHandler) Create(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	var req model.ProviderConfigCreateRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	config, err := h.configService.Create(userID, &req)
+	if err != nil {
+		handleProviderConfigError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "创建成功", config)
+}
+
+// Update 更新模型服务配置
+// @Summary 更新模型服务配置
+// @Description 整体替换模型服务配置；api_key 省略时保持不变，为空字符串时清除
+// @Tags 模型服务配置
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "模型服务配置ID"
+// @Param request body model.ProviderConfigUpdateRequest true "更新请求"
+// @Success 200 {object} response.Response{data=model.ProviderConfigResponse} "更新成功"
+// @Failure 400 {object} response.Response "参数错误或名称已存在"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "模型服务配置不存在"
+// @Router /api/v1/provider-configs/{id} [put]
+func (h *ProviderConfigHandler) Update(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	configID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	var req model.ProviderConfigUpdateRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	config, err := h.configService.Update(userID, configID, &req)
+	if err != nil {
+		handleProviderConfigError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "更新成功", config)
+}
+
+// Delete 删除模型服务配置
+// @Summary 删除模型服务配置
+// @Description 删除模型服务配置及其加密保存的 API Key
+// @Tags 模型服务配置
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "模型服务配置ID"
+// @Success 200 {object} response.Response "删除成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "模型服务配置不存在"
+// @Router /api/v1/provider-configs/{id} [delete]
+func (h *ProviderConfigHandler) Delete(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	configID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	if err := h.configService.Delete(userID, configID); err != nil {
+		handleProviderConfigError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// handleProviderConfigError 将模型服务配置错误转换为响应
+func handleProviderConfigError(c *app.RequestContext, err error) {
+	switch err.Error() {
+	case "模型服务配置不存在":
+		response.Error(c, response.CodeProviderConfigNotFound)
+	case "模型服务配置已存在":
+		response.Error(c, response.CodeProviderConfigExists)
+	case "无权访问该记录":
+		response.Error(c, response.CodeForbidden)
+	case "参数验证失败":
+		response.ErrorWithMessage(c, response.CodeInvalidParams, err.Error())
+	default:
+		response.ErrorWithMessage(c, response.CodeInternalError, err.Error())
+	}
+}
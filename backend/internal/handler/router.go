@@ -3,6 +3,7 @@ package handler
 import (
 	"cese-backend/internal/config"
 	"cese-backend/internal/middleware"
+	"cese-backend/internal/model"
 	"cese-backend/internal/service"
 	"context"
 
@@ -16,10 +17,22 @@ func SetupRoutes(
 	cfg *config.Config,
 	userService service.UserService,
 	elementService service.ContextElementService,
+	tagService service.TagService,
+	folderService service.FolderService,
+	templateService service.TemplateService,
+	generationService service.GenerationService,
+	providerConfigService service.ProviderConfigService,
+	catalogService service.CatalogService,
 ) {
 	// 创建处理器实例
 	userHandler := NewUserHandler(userService)
 	elementHandler := NewContextElementHandler(elementService)
+	tagHandler := NewTagHandler(tagService)
+	folderHandler := NewFolderHandler(folderService)
+	templateHandler := NewTemplateHandler(templateService)
+	generationHandler := NewGenerationHandler(generationService)
+	providerConfigHandler := NewProviderConfigHandler(providerConfigService)
+	catalogHandler := NewCatalogHandler(catalogService)
 
 	// 添加全局中间件
 	h.Use(middleware.ErrorLoggerMiddleware())
@@ -52,9 +65,182 @@ func SetupRoutes(
 	{
 		elementGroup.POST("/", elementHandler.Create)
 		elementGroup.GET("/", elementHandler.GetList)
+		elementGroup.GET("/cursor", elementHandler.GetListByCursor)
+		elementGroup.GET("/recent", elementHandler.GetRecent)
+		elementGroup.GET("/search", elementHandler.Search)
+		elementGroup.POST("/move", elementHandler.BatchMoveToFolder)
+		elementGroup.POST("/export", elementHandler.Export)
+		elementGroup.POST("/import", elementHandler.Import)
+		elementGroup.POST("/markdown", elementHandler.UploadMarkdown)
+		elementGroup.POST("/bulk/create", elementHandler.BulkCreate)
+		elementGroup.POST("/bulk/delete", elementHandler.BulkDelete)
+		elementGroup.POST("/bulk/patch", elementHandler.BulkPatch)
+		elementGroup.POST("/lint", elementHandler.Lint)
+		elementGroup.GET("/lint/rules", elementHandler.ListLintRules)
+		elementGroup.POST("/tokens", elementHandler.CountDraftTokens)
+		elementGroup.GET("/tokens/models", elementHandler.ListTokenModels)
+
+		// 回收站
+		elementGroup.GET("/trash", elementHandler.ListTrash)
+		elementGroup.DELETE("/trash", elementHandler.EmptyTrash)
+		elementGroup.POST("/trash/restore", elementHandler.RestoreTrash)
+		elementGroup.POST("/trash/purge", elementHandler.PurgeTrash)
+		elementGroup.POST("/trash/:id/restore", elementHandler.RestoreTrashOne)
+		elementGroup.DELETE("/trash/:id", elementHandler.PurgeTrashOne)
+
 		elementGroup.GET("/:id", elementHandler.GetByID)
 		elementGroup.PUT("/:id", elementHandler.Update)
+		elementGroup.PATCH("/:id", elementHandler.Patch)
 		elementGroup.DELETE("/:id", elementHandler.Delete)
+		elementGroup.POST("/:id/duplicate", elementHandler.Duplicate)
+		elementGroup.GET("/:id/lint", elementHandler.LintElement)
+		elementGroup.GET("/:id/tokens", elementHandler.CountTokens)
+
+		// 文件夹
+		elementGroup.PUT("/:id/folder", elementHandler.MoveToFolder)
+
+		// 收藏、置顶和使用统计
+		elementGroup.PUT("/:id/favorite", elementHandler.Favorite)
+		elementGroup.DELETE("/:id/favorite", elementHandler.Unfavorite)
+		elementGroup.PUT("/:id/pin", elementHandler.Pin)
+		elementGroup.DELETE("/:id/pin", elementHandler.Unpin)
+		elementGroup.POST("/:id/usage", elementHandler.RecordUsage)
+
+		// 发布公共模板
+		elementGroup.POST("/:id/publish", templateHandler.Publish)
+		elementGroup.DELETE("/:id/publish", templateHandler.Unpublish)
+
+		// Markdown 文档
+		elementGroup.GET("/:id/markdown", elementHandler.DownloadMarkdown)
+
+		// 提示词渲染
+		elementGroup.GET("/:id/render", elementHandler.TrackUsage(model.UsageRender), elementHandler.Render)
+		elementGroup.POST("/:id/render", elementHandler.TrackUsage(model.UsageRender), elementHandler.RenderWithVariables)
+
+		// 大模型生成
+		elementGroup.POST("/:id/generate", elementHandler.TrackUsage(model.UsageGeneration), generationHandler.Generate)
+		elementGroup.POST("/:id/generate/stream", elementHandler.TrackUsage(model.UsageGeneration), generationHandler.GenerateStream)
+		elementGroup.GET("/:id/generations", generationHandler.GetElementRecords)
+		elementGroup.GET("/:id/eval-runs", generationHandler.GetElementEvalRuns)
+
+		// 模板变量
+		elementGroup.GET("/:id/variables", elementHandler.GetVariables)
+		elementGroup.PUT("/:id/variables", elementHandler.UpdateVariables)
+
+		// 历史版本
+		elementGroup.GET("/:id/versions", elementHandler.ListVersions)
+		elementGroup.GET("/:id/versions/diff", elementHandler.DiffVersions)
+		elementGroup.GET("/:id/versions/:version", elementHandler.GetVersion)
+		elementGroup.POST("/:id/versions/:version/restore", elementHandler.RestoreVersion)
+	}
+
+	// 标签相关路由（需要认证）
+	tagGroup := v1.Group("/tags")
+	tagGroup.Use(middleware.AuthMiddleware(cfg))
+	{
+		tagGroup.GET("/", tagHandler.GetList)
+		tagGroup.POST("/", tagHandler.Create)
+		tagGroup.POST("/merge", tagHandler.Merge)
+		tagGroup.PUT("/:id", tagHandler.Rename)
+		tagGroup.DELETE("/:id", tagHandler.Delete)
+	}
+
+	// 文件夹相关路由（需要认证）
+	folderGroup := v1.Group("/folders")
+	folderGroup.Use(middleware.AuthMiddleware(cfg))
+	{
+		folderGroup.GET("/", folderHandler.GetTree)
+		folderGroup.POST("/", folderHandler.Create)
+		folderGroup.PUT("/:id", folderHandler.Rename)
+		folderGroup.POST("/:id/move", folderHandler.Move)
+		folderGroup.DELETE("/:id", folderHandler.Delete)
+	}
+
+	// 公共模板路由（浏览无需认证，复刻需要认证）
+	templateGroup := v1.Group("/templates")
+	{
+		templateGroup.GET("/", middleware.OptionalAuthMiddleware(cfg), templateHandler.GetList)
+		templateGroup.GET("/categories", templateHandler.GetCategories)
+		templateGroup.GET("/:id", templateHandler.GetByID)
+		templateGroup.GET("/:id/lineage", templateHandler.GetLineage)
+		templateGroup.POST("/:id/fork", middleware.AuthMiddleware(cfg), templateHandler.Fork)
+	}
+
+	// 生成记录路由（需要认证）
+	generationGroup := v1.Group("/generations")
+	generationGroup.Use(middleware.AuthMiddleware(cfg))
+	{
+		generationGroup.GET("/", generationHandler.GetRecords)
+		generationGroup.GET("/:id", generationHandler.GetRecord)
+		generationGroup.PATCH("/:id", generationHandler.UpdateRecord)
+		generationGroup.DELETE("/:id", generationHandler.DeleteRecord)
+	}
+
+	// 提示词对比路由（需要认证）
+	comparisonGroup := v1.Group("/comparisons")
+	comparisonGroup.Use(middleware.AuthMiddleware(cfg))
+	{
+		comparisonGroup.GET("/", generationHandler.GetComparisons)
+		comparisonGroup.POST("/", generationHandler.Compare)
+		comparisonGroup.GET("/:id", generationHandler.GetComparison)
+		comparisonGroup.PATCH("/:id", generationHandler.UpdateComparison)
+		comparisonGroup.DELETE("/:id", generationHandler.DeleteComparison)
+	}
+
+	// 评估数据集路由（需要认证）
+	evalDatasetGroup := v1.Group("/eval-datasets")
+	evalDatasetGroup.Use(middleware.AuthMiddleware(cfg))
+	{
+		evalDatasetGroup.GET("/", generationHandler.GetEvalDatasets)
+		evalDatasetGroup.POST("/", generationHandler.CreateEvalDataset)
+		evalDatasetGroup.GET("/:id", generationHandler.GetEvalDataset)
+		evalDatasetGroup.PUT("/:id", generationHandler.UpdateEvalDataset)
+		evalDatasetGroup.DELETE("/:id", generationHandler.DeleteEvalDataset)
+		evalDatasetGroup.POST("/:id/runs", generationHandler.RunEval)
+	}
+
+	// 评估运行路由（需要认证）
+	evalRunGroup := v1.Group("/eval-runs")
+	evalRunGroup.Use(middleware.AuthMiddleware(cfg))
+	{
+		evalRunGroup.GET("/", generationHandler.GetEvalRuns)
+		evalRunGroup.GET("/:id", generationHandler.GetEvalRun)
+		evalRunGroup.DELETE("/:id", generationHandler.DeleteEvalRun)
+	}
+
+	// 模型服务路由（需要认证）
+	providerGroup := v1.Group("/providers")
+	providerGroup.Use(middleware.AuthMiddleware(cfg))
+	{
+		providerGroup.GET("/", generationHandler.ListProviders)
+	}
+
+	// 用户模型服务配置路由（需要认证）
+	providerConfigGroup := v1.Group("/provider-configs")
+	providerConfigGroup.Use(middleware.AuthMiddleware(cfg))
+	{
+		providerConfigGroup.GET("/", providerConfigHandler.GetList)
+		providerConfigGroup.POST("/", providerConfigHandler.Create)
+		providerConfigGroup.GET("/:id", providerConfigHandler.GetByID)
+		providerConfigGroup.PUT("/:id", providerConfigHandler.Update)
+		providerConfigGroup.DELETE("/:id", providerConfigHandler.Delete)
+	}
+
+	// 角色与交付格式推荐路由（需要认证）
+	suggestionGroup := v1.Group("/suggestions")
+	suggestionGroup.Use(middleware.AuthMiddleware(cfg))
+	{
+		suggestionGroup.GET("/", catalogHandler.GetSuggestions)
+	}
+
+	// 推荐目录路由（需要认证，维护目录需要管理员权限）
+	catalogGroup := v1.Group("/catalog")
+	catalogGroup.Use(middleware.AuthMiddleware(cfg))
+	{
+		catalogGroup.GET("/", catalogHandler.GetList)
+		catalogGroup.POST("/", middleware.AdminMiddleware(cfg), catalogHandler.Create)
+		catalogGroup.PUT("/:id", middleware.AdminMiddleware(cfg), catalogHandler.Update)
+		catalogGroup.DELETE("/:id", middleware.AdminMiddleware(cfg), catalogHandler.Delete)
 	}
 
 	// 健康检查路由
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"context"
+
+	"cese-backend/internal/middleware"
+	"cese-backend/internal/model"
+	"cese-backend/internal/service"
+	"cese-backend/pkg/response"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// TagHandler 标签处理器
+type TagHandler struct {
+	tagService service.TagService
+}
+
+// NewTagHandler 创建标签处理器实例
+func NewTagHandler(tagService service.TagService) *TagHandler {
+	return &TagHandler{
+		tagService: tagService,
+	}
+}
+
+// GetList 获取标签列表
+// @Summary 获取标签列表
+// @Description 获取当前用户的全部标签及关联的六要素数量
+// @Tags 标签管理
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]model.TagResponse} "查询成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /api/v1/tags [get]
+func (h *TagHandler) GetList(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	tags, err := h.tagService.GetList(userID)
+	if err != nil {
+		response.ErrorWithMessage(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "查询成功", tags)
+}
+
+// Create 创建标签
+// @Summary 创建标签
+// @Description 创建标签
+// @Tags 标签管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.TagCreateRequest true "创建请求"
+// @Success 200 {object} response.Response{data=model.TagResponse} "创建成功"
+// @Failure 400 {object} response.Response "参数错误或标签已存在"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /api/v1/tags [post]
+func (h *TagHandler) Create(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	var req model.TagCreateRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	tag, err := h.tagService.Create(userID, &req)
+	if err != nil {
+		handleTagError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "创建成功", tag)
+}
+
+// Rename 重命名标签
+// @Summary 重命名标签
+// @Description 重命名标签，新名称已存在时请使用合并接口
+// @Tags 标签管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "标签ID"
+// @Param request body model.TagRenameRequest true "重命名请求"
+// @Success 200 {object} response.Response{data=model.TagResponse} "重命名成功"
+// @Failure 400 {object} response.Response "参数错误或标签已存在"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "标签不存在"
+// @Router /api/v1/tags/{id} [put]
+func (h *TagHandler) Rename(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	tagID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	var req model.TagRenameRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	tag, err := h.tagService.Rename(userID, tagID, &req)
+	if err != nil {
+		handleTagError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "重命名成功", tag)
+}
+
+// Merge 合并标签
+// @Summary 合并标签
+// @Description 将源标签的关联关系迁移到目标标签并删除源标签
+// @Tags 标签管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.TagMergeRequest true "合并请求"
+// @Success 200 {object} response.Response{data=model.TagResponse} "合并成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "标签不存在"
+// @Router /api/v1/tags/merge [post]
+func (h *TagHandler) Merge(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	var req model.TagMergeRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	tag, err := h.tagService.Merge(userID, &req)
+	if err != nil {
+		handleTagError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "合并成功", tag)
+}
+
+// Delete 删除标签
+// @Summary 删除标签
+// @Description 删除标签并解除与六要素的关联
+// @Tags 标签管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "标签ID"
+// @Success 200 {object} response.Response "删除成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "标签不存在"
+// @Router /api/v1/tags/{id} [delete]
+func (h *TagHandler) Delete(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	tagID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	if err := h.tagService.Delete(userID, tagID); err != nil {
+		handleTagError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// handleTagError 将标签服务错误转换为响应
+func handleTagError(c *app.RequestContext, err error) {
+	switch err.Error() {
+	case "标签不存在":
+		response.Error(c, response.CodeTagNotFound)
+	case "标签已存在":
+		response.Error(c, response.CodeTagExists)
+	case "参数验证失败":
+		response.ErrorWithMessage(c, response.CodeInvalidParams, err.Error())
+	default:
+		response.ErrorWithMessage(c, response.CodeInternalError, err.Error())
+	}
+}
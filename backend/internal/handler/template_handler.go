@@ -0,0 +1,255 @@
+package handler
+
+import (
+	"context"
+
+	"cese-backend/internal/middleware"
+	"cese-backend/internal/model"
+	"cese-backend/internal/service"
+	"cese-backend/pkg/response"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// TemplateHandler 公共模板处理器
+type TemplateHandler struct {
+	templateService service.TemplateService
+}
+
+// NewTemplateHandler 创建公共模板处理器实例
+func NewTemplateHandler(templateService service.TemplateService) *TemplateHandler {
+	return &TemplateHandler{
+		templateService: templateService,
+	}
+}
+
+// Publish 发布六要素为公共模板
+// @Summary 发布公共模板
+// @Description 将六要素当前内容发布为公共模板，已发布时刷新模板内容、描述和分类
+// @Tags 公共模板
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "六要素ID"
+// @Param request body model.TemplatePublishRequest true "发布请求"
+// @Success 200 {object} response.Response{data=model.TemplateResponse} "发布成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "记录不存在"
+// @Router /api/v1/context-elements/{id}/publish [post]
+func (h *TemplateHandler) Publish(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	elementID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	var req model.TemplatePublishRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	template, err := h.templateService.Publish(userID, elementID, &req)
+	if err != nil {
+		handleTemplateError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "发布成功", template)
+}
+
+// Unpublish 取消发布公共模板
+// @Summary 取消发布公共模板
+// @Description 取消发布六要素对应的公共模板，已复刻的六要素不受影响
+// @Tags 公共模板
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "六要素ID"
+// @Success 200 {object} response.Response "取消发布成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "模板不存在"
+// @Router /api/v1/context-elements/{id}/publish [delete]
+func (h *TemplateHandler) Unpublish(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	elementID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	if err := h.templateService.Unpublish(userID, elementID); err != nil {
+		handleTemplateError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "取消发布成功", nil)
+}
+
+// GetList 浏览公共模板
+// @Summary 浏览公共模板
+// @Description 分页浏览和搜索已发布的公共模板，无需登录；mine=true 时仅返回当前用户发布的模板（需要登录）
+// @Tags 公共模板
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(15)
+// @Param keyword query string false "关键词搜索"
+// @Param category query string false "分类过滤"
+// @Param mine query bool false "仅查询自己发布的模板" default(false)
+// @Param sort_by query string false "排序字段" Enums(created_at, updated_at, fork_count)
+// @Param sort_desc query bool false "是否倒序" default(true)
+// @Success 200 {object} response.PageResponse{data=[]model.TemplateResponse} "查询成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /api/v1/templates [get]
+func (h *TemplateHandler) GetList(ctx context.Context, c *app.RequestContext) {
+	var req model.TemplateQueryRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	templates, total, err := h.templateService.GetList(middleware.GetUserID(c), &req)
+	if err != nil {
+		handleTemplateError(c, err)
+		return
+	}
+
+	response.PageSuccessWithMessage(c, "查询成功", templates, total, req.Page, req.Size)
+}
+
+// GetCategories 获取模板分类
+// @Summary 获取模板分类
+// @Description 获取已发布模板的分类及每个分类的模板数量，无需登录
+// @Tags 公共模板
+// @Produce json
+// @Success 200 {object} response.Response{data=[]model.TemplateCategoryResponse} "查询成功"
+// @Router /api/v1/templates/categories [get]
+func (h *TemplateHandler) GetCategories(ctx context.Context, c *app.RequestContext) {
+	categories, err := h.templateService.GetCategories()
+	if err != nil {
+		response.ErrorWithMessage(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "查询成功", categories)
+}
+
+// GetByID 获取公共模板详情
+// @Summary 获取公共模板详情
+// @Description 根据ID获取已发布的公共模板，无需登录
+// @Tags 公共模板
+// @Produce json
+// @Param id path int true "模板ID"
+// @Success 200 {object} response.Response{data=model.TemplateResponse} "获取成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 404 {object} response.Response "模板不存在"
+// @Router /api/v1/templates/{id} [get]
+func (h *TemplateHandler) GetByID(ctx context.Context, c *app.RequestContext) {
+	templateID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	template, err := h.templateService.GetByID(templateID)
+	if err != nil {
+		handleTemplateError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "获取成功", template)
+}
+
+// GetLineage 获取模板来源链
+// @Summary 获取模板来源链
+// @Description 从当前模板开始依次返回其复刻来源模板，来源模板已取消发布时到此为止，无需登录
+// @Tags 公共模板
+// @Produce json
+// @Param id path int true "模板ID"
+// @Success 200 {object} response.Response{data=[]model.TemplateResponse} "获取成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 404 {object} response.Response "模板不存在"
+// @Router /api/v1/templates/{id}/lineage [get]
+func (h *TemplateHandler) GetLineage(ctx context.Context, c *app.RequestContext) {
+	templateID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	lineage, err := h.templateService.GetLineage(templateID)
+	if err != nil {
+		handleTemplateError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "获取成功", lineage)
+}
+
+// Fork 复刻公共模板
+// @Summary 复刻公共模板
+// @Description 将公共模板复制为当前用户的六要素，新记录的 source_template_id 指向该模板
+// @Tags 公共模板
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "模板ID"
+// @Param request body model.TemplateForkRequest false "复刻请求"
+// @Success 200 {object} response.Response{data=model.ContextElementResponse} "复刻成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "模板或文件夹不存在"
+// @Router /api/v1/templates/{id}/fork [post]
+func (h *TemplateHandler) Fork(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		response.Error(c, response.CodeUnauthorized)
+		return
+	}
+
+	templateID, ok := parseUintParam(c, "id")
+	if !ok {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "无效的ID参数")
+		return
+	}
+
+	var req model.TemplateForkRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.ErrorWithMessage(c, response.CodeInvalidParams, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	element, err := h.templateService.Fork(userID, templateID, &req)
+	if err != nil {
+		handleTemplateError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "复刻成功", element)
+}
+
+// handleTemplateError 将公共模板服务错误转换为响应
+func handleTemplateError(c *app.RequestContext, err error) {
+	switch err.Error() {
+	case "模板不存在":
+		response.Error(c, response.CodeTemplateNotFound)
+	case "未登录":
+		response.Error(c, response.CodeUnauthorized)
+	default:
+		handleElementError(c, err)
+	}
+}
@@ -53,6 +53,35 @@ func AuthMiddleware(cfg *config.Config) app.HandlerFunc {
 	}
 }
 
+// OptionalAuthMiddleware 可选JWT认证中间件
+// 携带有效Token时写入用户信息，未携带或Token无效时按匿名用户继续处理
+func OptionalAuthMiddleware(cfg *config.Config) app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		authHeader := string(c.GetHeader("Authorization"))
+		if token := strings.TrimPrefix(authHeader, "Bearer "); token != "" && token != authHeader {
+			if claims, err := utils.ParseToken(token, cfg.JWT.Secret); err == nil {
+				c.Set("user_id", claims.UserID)
+				c.Set("phone", claims.Phone)
+			}
+		}
+
+		c.Next(ctx)
+	}
+}
+
+// AdminMiddleware 管理员权限中间件，需在 AuthMiddleware 之后使用
+func AdminMiddleware(cfg *config.Config) app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		if !cfg.Admin.IsAdmin(GetPhone(c)) {
+			response.Error(c, response.CodeForbidden)
+			c.Abort()
+			return
+		}
+
+		c.Next(ctx)
+	}
+}
+
 // GetUserID 从上下文中获取用户ID
 func GetUserID(c *app.RequestContext) uint64 {
 	if userID, exists := c.Get("user_id"); exists {
@@ -0,0 +1,174 @@
+package model
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// 目录项适用的六要素字段
+const (
+	CatalogFieldAIRole         = "ai_role"
+	CatalogFieldMyRole         = "my_role"
+	CatalogFieldDeliveryFormat = "delivery_format"
+)
+
+// CatalogFields 提供推荐的六要素字段
+var CatalogFields = []string{CatalogFieldAIRole, CatalogFieldMyRole, CatalogFieldDeliveryFormat}
+
+// CatalogItem 角色与交付格式推荐目录（全站共享，由管理员维护）
+type CatalogItem struct {
+	ID          uint64         `json:"id" gorm:"primaryKey;autoIncrement;comment:目录项ID"`
+	Field       string         `json:"field" gorm:"type:varchar(20);not null;index:idx_catalog_field_sort;uniqueIndex:idx_catalog_field_name;comment:适用的六要素字段"`
+	Category    string         `json:"category" gorm:"type:varchar(50);not null;default:'';comment:分类"`
+	Name        string         `json:"name" gorm:"type:varchar(100);not null;uniqueIndex:idx_catalog_field_name;comment:名称"`
+	Description string         `json:"description" gorm:"type:varchar(255);not null;default:'';comment:说明"`
+	Example     string         `json:"example" gorm:"type:text;comment:填入六要素的示例文本"`
+	SortOrder   int            `json:"sort_order" gorm:"not null;default:0;index:idx_catalog_field_sort;comment:排序"`
+	SeedKey     *string        `json:"-" gorm:"type:varchar(100);uniqueIndex;comment:内置数据的标识，管理员新增的为空"`
+	CreatedAt   time.Time      `json:"created_at" gorm:"comment:创建时间"`
+	UpdatedAt   time.Time      `json:"updated_at" gorm:"comment:更新时间"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index;comment:删除时间"`
+}
+
+// TableName 指定表名
+func (CatalogItem) TableName() string {
+	return "cese_catalog_item"
+}
+
+// CatalogSeed 内置目录数据文件中的一项
+type CatalogSeed struct {
+	Key         string `json:"key"`
+	Field       string `json:"field"`
+	Category    string `json:"category"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Example     string `json:"example"`
+}
+
+// FieldUsage 用户在某个六要素字段中使用过的内容及次数
+type FieldUsage struct {
+	Field      string    `json:"field"`
+	Value      string    `json:"value"`
+	Count      int64     `json:"count"`
+	LastUsedAt time.Time `json:"last_used_at"`
+}
+
+// CatalogItemRequest 创建或修改目录项请求
+type CatalogItemRequest struct {
+	Field       string `json:"field" binding:"required" validate:"required,oneof=ai_role my_role delivery_format"`
+	Category    string `json:"category" validate:"max=50"`
+	Name        string `json:"name" binding:"required" validate:"required,max=100"`
+	Description string `json:"description" validate:"max=255"`
+	Example     string `json:"example" binding:"required" validate:"required,max=5000"`
+	SortOrder   int    `json:"sort_order"`
+}
+
+// CatalogQueryRequest 查询目录请求
+type CatalogQueryRequest struct {
+	Field    string `form:"field" validate:"omitempty,oneof=ai_role my_role delivery_format"`
+	Category string `form:"category" validate:"max=50"`
+}
+
+// SuggestionQueryRequest 查询推荐请求
+type SuggestionQueryRequest struct {
+	Field string `form:"field" validate:"omitempty,oneof=ai_role my_role delivery_format"` // 为空时查询全部字段
+	Q     string `form:"q" validate:"max=100"`                                             // 前缀：个人推荐匹配字段内容，目录匹配名称或示例文本
+	Limit int    `form:"limit" validate:"min=0,max=50"`                                    // 个人推荐和目录各自返回的最大数量，默认10
+}
+
+// CatalogItemResponse 目录项响应
+type CatalogItemResponse struct {
+	ID          uint64    `json:"id"`
+	Field       string    `json:"field"`
+	Category    string    `json:"category"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Example     string    `json:"example"`
+	SortOrder   int       `json:"sort_order"`
+	Builtin     bool      `json:"builtin"` // 是否为内置数据
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// PersonalSuggestion 根据用户常用内容生成的推荐
+type PersonalSuggestion struct {
+	Field      string    `json:"field"`
+	Name       string    `json:"name"`  // 内容的第一行，最多30个字符
+	Value      string    `json:"value"` // 完整内容
+	Count      int64     `json:"count"` // 使用该内容的六要素数量
+	LastUsedAt time.Time `json:"last_used_at"`
+}
+
+// SuggestionsResponse 推荐响应
+type SuggestionsResponse struct {
+	Personal []*PersonalSuggestion  `json:"personal"` // 按使用次数排序
+	Catalog  []*CatalogItemResponse `json:"catalog"`  // 按目录顺序排序
+}
+
+// NewCatalogItem 根据内置数据创建目录项
+func (s *CatalogSeed) NewCatalogItem(sortOrder int) *CatalogItem {
+	key := s.Key
+	return &CatalogItem{
+		Field:       s.Field,
+		Category:    s.Category,
+		Name:        s.Name,
+		Description: s.Description,
+		Example:     s.Example,
+		SortOrder:   sortOrder,
+		SeedKey:     &key,
+	}
+}
+
+// Apply 将请求内容写入目录项
+func (ci *CatalogItem) Apply(req *CatalogItemRequest) {
+	ci.Field = req.Field
+	ci.Category = req.Category
+	ci.Name = req.Name
+	ci.Description = req.Description
+	ci.Example = req.Example
+	ci.SortOrder = req.SortOrder
+}
+
+// ToResponse 转换为响应格式
+func (ci *CatalogItem) ToResponse() *CatalogItemResponse {
+	return &CatalogItemResponse{
+		ID:          ci.ID,
+		Field:       ci.Field,
+		Category:    ci.Category,
+		Name:        ci.Name,
+		Description: ci.Description,
+		Example:     ci.Example,
+		SortOrder:   ci.SortOrder,
+		Builtin:     ci.SeedKey != nil,
+		UpdatedAt:   ci.UpdatedAt,
+	}
+}
+
+// ToSuggestion 转换为个人推荐
+func (u *FieldUsage) ToSuggestion() *PersonalSuggestion {
+	return &PersonalSuggestion{
+		Field:      u.Field,
+		Name:       suggestionName(u.Value),
+		Value:      u.Value,
+		Count:      u.Count,
+		LastUsedAt: u.LastUsedAt,
+	}
+}
+
+// suggestionName 取内容的第一个非空行作为名称，超过30个字符时截断
+func suggestionName(value string) string {
+	const maxRunes = 30
+	for _, line := range strings.Split(value, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if utf8.RuneCountInString(line) > maxRunes {
+			return string([]rune(line)[:maxRunes]) + "…"
+		}
+		return line
+	}
+	return ""
+}
@@ -0,0 +1,326 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+	"unicode/utf8"
+
+	"cese-backend/pkg/provider"
+)
+
+// ComparisonRun 提示词对比运行，多个变体使用相同的模型服务、采样参数和变量各执行若干次
+type ComparisonRun struct {
+	ID              uint64               `json:"id" gorm:"primaryKey;autoIncrement;comment:对比运行ID"`
+	UserID          uint64               `json:"user_id" gorm:"not null;index:idx_user_created;comment:用户ID"`
+	Name            string               `json:"name" gorm:"type:varchar(255);comment:名称"`
+	Provider        string               `json:"provider" gorm:"type:varchar(50);not null;comment:模型服务名称"`
+	Model           string               `json:"model" gorm:"type:varchar(100);not null;comment:模型名称"`
+	Format          string               `json:"format" gorm:"type:varchar(20);not null;comment:提示词格式"`
+	Variables       string               `json:"-" gorm:"type:text;comment:模板变量的值(JSON)"`
+	Config          string               `json:"-" gorm:"type:text;comment:采样参数(JSON)"`
+	Repeat          int                  `json:"repeat" gorm:"not null;default:1;comment:每个变体的执行次数"`
+	WinnerVariantID *uint64              `json:"winner_variant_id" gorm:"comment:胜出的变体ID"`
+	Note            string               `json:"note" gorm:"type:text;comment:备注"`
+	CreatedAt       time.Time            `json:"created_at" gorm:"index:idx_user_created;comment:创建时间"`
+	UpdatedAt       time.Time            `json:"updated_at" gorm:"comment:更新时间"`
+	Variants        []*ComparisonVariant `json:"variants,omitempty" gorm:"foreignKey:RunID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName 指定表名
+func (ComparisonRun) TableName() string {
+	return "cese_comparison_run"
+}
+
+// ComparisonVariant 对比运行中的一个提示词变体，保存发送给模型的提示词快照
+type ComparisonVariant struct {
+	ID             uint64              `json:"id" gorm:"primaryKey;autoIncrement;comment:变体ID"`
+	RunID          uint64              `json:"run_id" gorm:"not null;index;comment:对比运行ID"`
+	Position       int                 `json:"position" gorm:"not null;comment:变体序号"`
+	Label          string              `json:"label" gorm:"type:varchar(100);comment:变体名称"`
+	ElementID      uint64              `json:"element_id" gorm:"not null;index;comment:六要素ID"`
+	ElementVersion uint64              `json:"element_version" gorm:"not null;default:0;comment:运行时六要素的版本号"`
+	Overrides      string              `json:"-" gorm:"type:text;comment:覆盖的字段(JSON)"`
+	Prompt         string              `json:"prompt" gorm:"type:mediumtext;comment:发送给模型的提示词"`
+	CreatedAt      time.Time           `json:"created_at" gorm:"comment:创建时间"`
+	Outputs        []*ComparisonOutput `json:"outputs,omitempty" gorm:"foreignKey:VariantID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName 指定表名
+func (ComparisonVariant) TableName() string {
+	return "cese_comparison_variant"
+}
+
+// ComparisonOutput 变体的一次执行结果
+type ComparisonOutput struct {
+	ID               uint64    `json:"id" gorm:"primaryKey;autoIncrement;comment:执行结果ID"`
+	VariantID        uint64    `json:"variant_id" gorm:"not null;index;comment:变体ID"`
+	Attempt          int       `json:"attempt" gorm:"not null;comment:第几次执行"`
+	Content          string    `json:"content" gorm:"type:mediumtext;comment:生成的内容"`
+	FinishReason     string    `json:"finish_reason" gorm:"type:varchar(50);comment:结束原因"`
+	Status           string    `json:"status" gorm:"type:varchar(20);not null;comment:状态"`
+	Error            string    `json:"error" gorm:"type:text;comment:错误信息"`
+	PromptTokens     int       `json:"prompt_tokens" gorm:"not null;default:0;comment:输入令牌数"`
+	CompletionTokens int       `json:"completion_tokens" gorm:"not null;default:0;comment:输出令牌数"`
+	TotalTokens      int       `json:"total_tokens" gorm:"not null;default:0;comment:总令牌数"`
+	DurationMs       int64     `json:"duration_ms" gorm:"not null;default:0;comment:耗时(毫秒)"`
+	CreatedAt        time.Time `json:"created_at" gorm:"comment:创建时间"`
+}
+
+// TableName 指定表名
+func (ComparisonOutput) TableName() string {
+	return "cese_comparison_output"
+}
+
+// ComparisonCreateRequest 创建对比运行请求，模型服务、采样参数、格式和变量对所有变体相同
+type ComparisonCreateRequest struct {
+	GenerateRequest
+	Name     string                      `json:"name" validate:"max=255"`
+	Variants []*ComparisonVariantRequest `json:"variants" validate:"required,min=2,max=5,dive,required"`
+	Repeat   int                         `json:"repeat" validate:"min=0,max=5"` // 每个变体的执行次数，默认1
+}
+
+// ComparisonVariantRequest 对比变体，可以是不同的六要素，也可以是同一六要素覆盖部分字段
+type ComparisonVariantRequest struct {
+	ElementID uint64                   `json:"element_id" validate:"required"`
+	Label     string                   `json:"label" validate:"max=100"` // 为空时使用 A、B、C…
+	Overrides *ContextElementOverrides `json:"overrides"`
+}
+
+// ComparisonQueryRequest 查询对比运行请求
+type ComparisonQueryRequest struct {
+	Page      int    `form:"page" validate:"min=1"`
+	Size      int    `form:"size" validate:"min=1,max=100"`
+	ElementID uint64 `form:"element_id"` // 包含该六要素的对比运行
+}
+
+// ComparisonUpdateRequest 记录胜出变体和备注，字段为空时不修改
+type ComparisonUpdateRequest struct {
+	WinnerVariantID *uint64 `json:"winner_variant_id"` // 0 表示清除
+	Note            *string `json:"note" validate:"omitempty,max=5000"`
+}
+
+// ComparisonResponse 对比视图
+type ComparisonResponse struct {
+	ID              uint64                       `json:"id"`
+	Name            string                       `json:"name"`
+	Provider        string                       `json:"provider"`
+	Model           string                       `json:"model"`
+	Format          string                       `json:"format"`
+	Variables       map[string]interface{}       `json:"variables"`
+	Config          *GenerationConfig            `json:"config"`
+	Repeat          int                          `json:"repeat"`
+	WinnerVariantID *uint64                      `json:"winner_variant_id"`
+	Note            string                       `json:"note"`
+	Variants        []*ComparisonVariantResponse `json:"variants"`
+	CreatedAt       time.Time                    `json:"created_at"`
+	UpdatedAt       time.Time                    `json:"updated_at"`
+}
+
+// ComparisonVariantResponse 变体的提示词、执行结果和统计
+type ComparisonVariantResponse struct {
+	ID             uint64                      `json:"id"`
+	Position       int                         `json:"position"`
+	Label          string                      `json:"label"`
+	ElementID      uint64                      `json:"element_id"`
+	ElementVersion uint64                      `json:"element_version"`
+	Overrides      *ContextElementOverrides    `json:"overrides,omitempty"`
+	Prompt         string                      `json:"prompt"`
+	Winner         bool                        `json:"winner"`
+	Stats          ComparisonStats             `json:"stats"`
+	Outputs        []*ComparisonOutputResponse `json:"outputs,omitempty"` // 列表中不返回
+}
+
+// ComparisonOutputResponse 一次执行的结果
+type ComparisonOutputResponse struct {
+	ID           uint64         `json:"id"`
+	Attempt      int            `json:"attempt"`
+	Content      string         `json:"content"`
+	Length       int            `json:"length"` // 内容字符数
+	FinishReason string         `json:"finish_reason"`
+	Status       string         `json:"status"`
+	Error        string         `json:"error,omitempty"`
+	Usage        provider.Usage `json:"usage"`
+	DurationMs   int64          `json:"duration_ms"`
+}
+
+// ComparisonStats 变体成功执行结果的统计，长度为内容字符数
+type ComparisonStats struct {
+	Runs                int     `json:"runs"`
+	Completed           int     `json:"completed"`
+	AvgLength           float64 `json:"avg_length"`
+	MinLength           int     `json:"min_length"`
+	MaxLength           int     `json:"max_length"`
+	AvgDurationMs       float64 `json:"avg_duration_ms"`
+	MinDurationMs       int64   `json:"min_duration_ms"`
+	MaxDurationMs       int64   `json:"max_duration_ms"`
+	AvgPromptTokens     float64 `json:"avg_prompt_tokens"`
+	AvgCompletionTokens float64 `json:"avg_completion_tokens"`
+	TotalTokens         int     `json:"total_tokens"` // 全部执行（含失败）消耗的令牌数
+}
+
+// NewComparisonRun 根据创建请求创建对比运行（不含变体）
+func NewComparisonRun(userID uint64, req *ComparisonCreateRequest, providerName, modelName, format string, maxTokens int) *ComparisonRun {
+	variables, _ := json.Marshal(req.Variables)
+	config, _ := json.Marshal(req.SamplingConfig(maxTokens))
+
+	return &ComparisonRun{
+		UserID:    userID,
+		Name:      req.Name,
+		Provider:  providerName,
+		Model:     modelName,
+		Format:    format,
+		Variables: string(variables),
+		Config:    string(config),
+		Repeat:    req.Repeat,
+	}
+}
+
+// NewComparisonOutput 根据生成结果创建执行结果
+func NewComparisonOutput(attempt int, result *GenerateResponse) *ComparisonOutput {
+	return &ComparisonOutput{
+		Attempt:          attempt,
+		Content:          result.Content,
+		FinishReason:     result.FinishReason,
+		Status:           GenerationStatusCompleted,
+		PromptTokens:     result.Usage.PromptTokens,
+		CompletionTokens: result.Usage.CompletionTokens,
+		TotalTokens:      result.Usage.TotalTokens,
+		DurationMs:       result.DurationMs,
+	}
+}
+
+// SetOverrides 保存变体覆盖的字段
+func (v *ComparisonVariant) SetOverrides(overrides *ContextElementOverrides) {
+	if overrides == nil {
+		v.Overrides = ""
+		return
+	}
+	data, _ := json.Marshal(overrides)
+	v.Overrides = string(data)
+}
+
+// HasVariant 判断变体是否属于该对比运行
+func (r *ComparisonRun) HasVariant(variantID uint64) bool {
+	for _, v := range r.Variants {
+		if v.ID == variantID {
+			return true
+		}
+	}
+	return false
+}
+
+// ToResponse 转换为对比视图，withOutputs 为 false 时只返回统计
+func (r *ComparisonRun) ToResponse(withOutputs bool) *ComparisonResponse {
+	var variables map[string]interface{}
+	if r.Variables != "" {
+		_ = json.Unmarshal([]byte(r.Variables), &variables)
+	}
+	config := &GenerationConfig{}
+	if r.Config != "" {
+		_ = json.Unmarshal([]byte(r.Config), config)
+	}
+
+	variants := make([]*ComparisonVariantResponse, len(r.Variants))
+	for i, v := range r.Variants {
+		variants[i] = v.toResponse(withOutputs)
+		variants[i].Winner = r.WinnerVariantID != nil && *r.WinnerVariantID == v.ID
+	}
+
+	return &ComparisonResponse{
+		ID:              r.ID,
+		Name:            r.Name,
+		Provider:        r.Provider,
+		Model:           r.Model,
+		Format:          r.Format,
+		Variables:       variables,
+		Config:          config,
+		Repeat:          r.Repeat,
+		WinnerVariantID: r.WinnerVariantID,
+		Note:            r.Note,
+		Variants:        variants,
+		CreatedAt:       r.CreatedAt,
+		UpdatedAt:       r.UpdatedAt,
+	}
+}
+
+// toResponse 转换变体为响应格式并统计执行结果
+func (v *ComparisonVariant) toResponse(withOutputs bool) *ComparisonVariantResponse {
+	var overrides *ContextElementOverrides
+	if v.Overrides != "" {
+		overrides = &ContextElementOverrides{}
+		_ = json.Unmarshal([]byte(v.Overrides), overrides)
+	}
+
+	resp := &ComparisonVariantResponse{
+		ID:             v.ID,
+		Position:       v.Position,
+		Label:          v.Label,
+		ElementID:      v.ElementID,
+		ElementVersion: v.ElementVersion,
+		Overrides:      overrides,
+		Prompt:         v.Prompt,
+		Stats:          comparisonStats(v.Outputs),
+	}
+	if withOutputs {
+		resp.Outputs = make([]*ComparisonOutputResponse, len(v.Outputs))
+		for i, o := range v.Outputs {
+			resp.Outputs[i] = &ComparisonOutputResponse{
+				ID:           o.ID,
+				Attempt:      o.Attempt,
+				Content:      o.Content,
+				Length:       utf8.RuneCountInString(o.Content),
+				FinishReason: o.FinishReason,
+				Status:       o.Status,
+				Error:        o.Error,
+				Usage: provider.Usage{
+					PromptTokens:     o.PromptTokens,
+					CompletionTokens: o.CompletionTokens,
+					TotalTokens:      o.TotalTokens,
+				},
+				DurationMs: o.DurationMs,
+			}
+		}
+	}
+	return resp
+}
+
+// comparisonStats 统计成功执行结果的长度、耗时和令牌用量
+func comparisonStats(outputs []*ComparisonOutput) ComparisonStats {
+	stats := ComparisonStats{Runs: len(outputs)}
+	var totalLength, totalPrompt, totalCompletion int
+	var totalDuration int64
+	for _, o := range outputs {
+		stats.TotalTokens += o.TotalTokens
+		if o.Status != GenerationStatusCompleted {
+			continue
+		}
+
+		length := utf8.RuneCountInString(o.Content)
+		if stats.Completed == 0 || length < stats.MinLength {
+			stats.MinLength = length
+		}
+		if length > stats.MaxLength {
+			stats.MaxLength = length
+		}
+		if stats.Completed == 0 || o.DurationMs < stats.MinDurationMs {
+			stats.MinDurationMs = o.DurationMs
+		}
+		if o.DurationMs > stats.MaxDurationMs {
+			stats.MaxDurationMs = o.DurationMs
+		}
+		stats.Completed++
+		totalLength += length
+		totalDuration += o.DurationMs
+		totalPrompt += o.PromptTokens
+		totalCompletion += o.CompletionTokens
+	}
+
+	if stats.Completed > 0 {
+		n := float64(stats.Completed)
+		stats.AvgLength = float64(totalLength) / n
+		stats.AvgDurationMs = float64(totalDuration) / n
+		stats.AvgPromptTokens = float64(totalPrompt) / n
+		stats.AvgCompletionTokens = float64(totalCompletion) / n
+	}
+	return stats
+}
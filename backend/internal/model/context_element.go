@@ -8,21 +8,26 @@ import (
 
 // ContextElement 上下文工程六要素模型
 type ContextElement struct {
-	ID             uint64         `json:"id" gorm:"primaryKey;autoIncrement;comment:六要素ID"`
-	UserID         uint64         `json:"user_id" gorm:"not null;index;comment:用户ID"`
-	Subject        string         `json:"subject" gorm:"type:varchar(255);not null;index;comment:主题"`
-	TaskGoal       string         `json:"task_goal" gorm:"type:text;comment:任务目标"`
-	AIRole         string         `json:"ai_role" gorm:"type:text;comment:AI的角色"`
-	MyRole         string         `json:"my_role" gorm:"type:text;comment:我的角色"`
-	KeyInfo        string         `json:"key_info" gorm:"type:text;comment:关键信息"`
-	BehaviorRule   string         `json:"behavior_rule" gorm:"type:text;comment:行为规则"`
-	DeliveryFormat string         `json:"delivery_format" gorm:"type:text;comment:交付格式"`
-	CreatedAt      time.Time      `json:"created_at" gorm:"index;comment:创建时间"`
-	UpdatedAt      time.Time      `json:"updated_at" gorm:"comment:更新时间"`
-	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index;comment:删除时间"`
+	ID               uint64         `json:"id" gorm:"primaryKey;autoIncrement;comment:六要素ID"`
+	UserID           uint64         `json:"user_id" gorm:"not null;index;comment:用户ID"`
+	FolderID         *uint64        `json:"folder_id" gorm:"index;comment:所属文件夹ID"`
+	SourceTemplateID *uint64        `json:"source_template_id" gorm:"index;comment:复刻来源模板ID"`
+	ClonedFromID     *uint64        `json:"cloned_from_id" gorm:"index;comment:复制来源六要素ID"`
+	Subject          string         `json:"subject" gorm:"type:varchar(255);not null;index;comment:主题"`
+	TaskGoal         string         `json:"task_goal" gorm:"type:text;comment:任务目标"`
+	AIRole           string         `json:"ai_role" gorm:"type:text;comment:AI的角色"`
+	MyRole           string         `json:"my_role" gorm:"type:text;comment:我的角色"`
+	KeyInfo          string         `json:"key_info" gorm:"type:text;comment:关键信息"`
+	BehaviorRule     string         `json:"behavior_rule" gorm:"type:text;comment:行为规则"`
+	DeliveryFormat   string         `json:"delivery_format" gorm:"type:text;comment:交付格式"`
+	Version          uint64         `json:"version" gorm:"not null;default:1;comment:乐观锁版本号"`
+	CreatedAt        time.Time      `json:"created_at" gorm:"index;comment:创建时间"`
+	UpdatedAt        time.Time      `json:"updated_at" gorm:"index;comment:更新时间"`
+	DeletedAt        gorm.DeletedAt `json:"-" gorm:"index;comment:删除时间"`
 
 	// 关联关系
-	User User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	User User  `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Tags []Tag `json:"tags,omitempty" gorm:"many2many:cese_context_element_tag;"`
 }
 
 // TableName 指定表名
@@ -30,69 +35,157 @@ func (ContextElement) TableName() string {
 	return "cese_context_element"
 }
 
+// ElementField 六要素字段定义
+type ElementField struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+}
+
+// ElementFields 六要素字段（按模板顺序）
+var ElementFields = []ElementField{
+	{Key: "task_goal", Label: "任务目标"},
+	{Key: "ai_role", Label: "AI的角色"},
+	{Key: "my_role", Label: "我的角色"},
+	{Key: "key_info", Label: "关键信息"},
+	{Key: "behavior_rule", Label: "行为规则"},
+	{Key: "delivery_format", Label: "交付格式"},
+}
+
+// FieldValue 根据字段名获取六要素内容
+func (ce *ContextElement) FieldValue(key string) string {
+	switch key {
+	case "subject":
+		return ce.Subject
+	case "task_goal":
+		return ce.TaskGoal
+	case "ai_role":
+		return ce.AIRole
+	case "my_role":
+		return ce.MyRole
+	case "key_info":
+		return ce.KeyInfo
+	case "behavior_rule":
+		return ce.BehaviorRule
+	case "delivery_format":
+		return ce.DeliveryFormat
+	default:
+		return ""
+	}
+}
+
+// SetFieldValue 根据字段名设置六要素内容
+func (ce *ContextElement) SetFieldValue(key, value string) {
+	switch key {
+	case "subject":
+		ce.Subject = value
+	case "task_goal":
+		ce.TaskGoal = value
+	case "ai_role":
+		ce.AIRole = value
+	case "my_role":
+		ce.MyRole = value
+	case "key_info":
+		ce.KeyInfo = value
+	case "behavior_rule":
+		ce.BehaviorRule = value
+	case "delivery_format":
+		ce.DeliveryFormat = value
+	}
+}
+
 // ContextElementCreateRequest 创建六要素请求
 type ContextElementCreateRequest struct {
-	Subject        string `json:"subject" binding:"required" validate:"required,max=255"`
-	TaskGoal       string `json:"task_goal" validate:"max=5000"`
-	AIRole         string `json:"ai_role" validate:"max=5000"`
-	MyRole         string `json:"my_role" validate:"max=5000"`
-	KeyInfo        string `json:"key_info" validate:"max=5000"`
-	BehaviorRule   string `json:"behavior_rule" validate:"max=5000"`
-	DeliveryFormat string `json:"delivery_format" validate:"max=5000"`
+	Subject        string   `json:"subject" binding:"required" validate:"required,max=255"`
+	TaskGoal       string   `json:"task_goal" validate:"max=5000"`
+	AIRole         string   `json:"ai_role" validate:"max=5000"`
+	MyRole         string   `json:"my_role" validate:"max=5000"`
+	KeyInfo        string   `json:"key_info" validate:"max=5000"`
+	BehaviorRule   string   `json:"behavior_rule" validate:"max=5000"`
+	DeliveryFormat string   `json:"delivery_format" validate:"max=5000"`
+	Tags           []string `json:"tags" validate:"max=20,dive,max=50"`
+	FolderID       *uint64  `json:"folder_id"`
 }
 
-// ContextElementUpdateRequest 更新六要素请求
+// ContextElementUpdateRequest 更新六要素请求（整体替换，未提供的字段和标签会被清空）
 type ContextElementUpdateRequest struct {
-	Subject        string `json:"subject" validate:"max=255"`
-	TaskGoal       string `json:"task_goal" validate:"max=5000"`
-	AIRole         string `json:"ai_role" validate:"max=5000"`
-	MyRole         string `json:"my_role" validate:"max=5000"`
-	KeyInfo        string `json:"key_info" validate:"max=5000"`
-	BehaviorRule   string `json:"behavior_rule" validate:"max=5000"`
-	DeliveryFormat string `json:"delivery_format" validate:"max=5000"`
+	Subject        string   `json:"subject" validate:"required,max=255"`
+	TaskGoal       string   `json:"task_goal" validate:"max=5000"`
+	AIRole         string   `json:"ai_role" validate:"max=5000"`
+	MyRole         string   `json:"my_role" validate:"max=5000"`
+	KeyInfo        string   `json:"key_info" validate:"max=5000"`
+	BehaviorRule   string   `json:"behavior_rule" validate:"max=5000"`
+	DeliveryFormat string   `json:"delivery_format" validate:"max=5000"`
+	Tags           []string `json:"tags" validate:"max=20,dive,max=50"`
 }
 
 // ContextElementQueryRequest 查询六要素请求
 type ContextElementQueryRequest struct {
-	Page     int    `form:"page" validate:"min=1"`
-	Size     int    `form:"size" validate:"min=1,max=100"`
-	Keyword  string `form:"keyword" validate:"max=255"`
-	Subject  string `form:"subject" validate:"max=255"`
-	AIRole   string `form:"ai_role" validate:"max=255"`
-	MyRole   string `form:"my_role" validate:"max=255"`
-	SortBy   string `form:"sort_by" validate:"oneof=created_at updated_at subject"`
-	SortDesc bool   `form:"sort_desc"`
+	Page      int     `form:"page" validate:"min=1"`
+	Size      int     `form:"size" validate:"min=1,max=100"`
+	Keyword   string  `form:"keyword" validate:"max=255"`
+	Subject   string  `form:"subject" validate:"max=255"`
+	AIRole    string  `form:"ai_role" validate:"max=255"`
+	MyRole    string  `form:"my_role" validate:"max=255"`
+	FolderID  *uint64 `form:"folder_id"`                // 0 表示根目录
+	Recursive bool    `form:"recursive"`                // 是否包含子文件夹中的六要素
+	Tags      string  `form:"tags" validate:"max=1000"` // 逗号分隔的标签名
+	TagMode   string  `form:"tag_mode" validate:"omitempty,oneof=any all"`
+	Favorite  *bool   `form:"favorite"` // 仅收藏的（true）或未收藏的（false）
+	Pinned    *bool   `form:"pinned"`   // 仅置顶的（true）或未置顶的（false）
+	// relevance 仅对搜索生效；last_used 按最近打开或使用时间，use_count 按使用次数
+	SortBy      string `form:"sort_by" validate:"oneof=created_at updated_at subject relevance last_used use_count"`
+	SortDesc    bool   `form:"sort_desc"`
+	PinnedFirst bool   `form:"pinned_first"` // 置顶的排在最前（按置顶时间倒序），其余按 sort_by 排序
 }
 
 // ContextElementResponse 六要素响应
 type ContextElementResponse struct {
-	ID             uint64    `json:"id"`
-	UserID         uint64    `json:"user_id"`
-	Subject        string    `json:"subject"`
-	TaskGoal       string    `json:"task_goal"`
-	AIRole         string    `json:"ai_role"`
-	MyRole         string    `json:"my_role"`
-	KeyInfo        string    `json:"key_info"`
-	BehaviorRule   string    `json:"behavior_rule"`
-	DeliveryFormat string    `json:"delivery_format"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ID               uint64    `json:"id"`
+	UserID           uint64    `json:"user_id"`
+	FolderID         *uint64   `json:"folder_id"`
+	SourceTemplateID *uint64   `json:"source_template_id"`
+	ClonedFromID     *uint64   `json:"cloned_from_id"` // 由哪条六要素复制而来
+	Subject          string    `json:"subject"`
+	TaskGoal         string    `json:"task_goal"`
+	AIRole           string    `json:"ai_role"`
+	MyRole           string    `json:"my_role"`
+	KeyInfo          string    `json:"key_info"`
+	BehaviorRule     string    `json:"behavior_rule"`
+	DeliveryFormat   string    `json:"delivery_format"`
+	Tags             []string  `json:"tags"`
+	Version          uint64    `json:"version"` // 乐观锁版本号，每次修改内容后递增
+	ETag             string    `json:"etag"`    // 更新和删除时通过If-Match请求头传回
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+
+	Usage *ContextElementUsageResponse `json:"usage,omitempty"` // 收藏、置顶和使用统计，仅详情、列表和最近使用返回
 }
 
 // ToResponse 转换为响应格式
 func (ce *ContextElement) ToResponse() *ContextElementResponse {
+	tags := make([]string, len(ce.Tags))
+	for i, tag := range ce.Tags {
+		tags[i] = tag.Name
+	}
+
 	return &ContextElementResponse{
-		ID:             ce.ID,
-		UserID:         ce.UserID,
-		Subject:        ce.Subject,
-		TaskGoal:       ce.TaskGoal,
-		AIRole:         ce.AIRole,
-		MyRole:         ce.MyRole,
-		KeyInfo:        ce.KeyInfo,
-		BehaviorRule:   ce.BehaviorRule,
-		DeliveryFormat: ce.DeliveryFormat,
-		CreatedAt:      ce.CreatedAt,
-		UpdatedAt:      ce.UpdatedAt,
+		ID:               ce.ID,
+		UserID:           ce.UserID,
+		FolderID:         ce.FolderID,
+		SourceTemplateID: ce.SourceTemplateID,
+		ClonedFromID:     ce.ClonedFromID,
+		Subject:          ce.Subject,
+		TaskGoal:         ce.TaskGoal,
+		AIRole:           ce.AIRole,
+		MyRole:           ce.MyRole,
+		KeyInfo:          ce.KeyInfo,
+		BehaviorRule:     ce.BehaviorRule,
+		DeliveryFormat:   ce.DeliveryFormat,
+		Tags:             tags,
+		Version:          ce.Version,
+		ETag:             ce.ETag(),
+		CreatedAt:        ce.CreatedAt,
+		UpdatedAt:        ce.UpdatedAt,
 	}
 }
 
@@ -100,6 +193,7 @@ func (ce *ContextElement) ToResponse() *ContextElementResponse {
 func (req *ContextElementCreateRequest) ToContextElement(userID uint64) *ContextElement {
 	return &ContextElement{
 		UserID:         userID,
+		FolderID:       req.FolderID,
 		Subject:        req.Subject,
 		TaskGoal:       req.TaskGoal,
 		AIRole:         req.AIRole,
@@ -110,27 +204,13 @@ func (req *ContextElementCreateRequest) ToContextElement(userID uint64) *Context
 	}
 }
 
-// UpdateFromRequest 从更新请求更新模型
+// UpdateFromRequest 从更新请求整体替换六要素内容（空字符串表示清空该字段）
 func (ce *ContextElement) UpdateFromRequest(req *ContextElementUpdateRequest) {
-	if req.Subject != "" {
-		ce.Subject = req.Subject
-	}
-	if req.TaskGoal != "" {
-		ce.TaskGoal = req.TaskGoal
-	}
-	if req.AIRole != "" {
-		ce.AIRole = req.AIRole
-	}
-	if req.MyRole != "" {
-		ce.MyRole = req.MyRole
-	}
-	if req.KeyInfo != "" {
-		ce.KeyInfo = req.KeyInfo
-	}
-	if req.BehaviorRule != "" {
-		ce.BehaviorRule = req.BehaviorRule
-	}
-	if req.DeliveryFormat != "" {
-		ce.DeliveryFormat = req.DeliveryFormat
-	}
+	ce.Subject = req.Subject
+	ce.TaskGoal = req.TaskGoal
+	ce.AIRole = req.AIRole
+	ce.MyRole = req.MyRole
+	ce.KeyInfo = req.KeyInfo
+	ce.BehaviorRule = req.BehaviorRule
+	ce.DeliveryFormat = req.DeliveryFormat
 }
@@ -0,0 +1,144 @@
+package model
+
+import "strings"
+
+// 批量操作中单条记录的处理状态
+const (
+	BulkStatusSucceeded  = "succeeded"
+	BulkStatusUnchanged  = "unchanged"   // 修改后内容与原内容相同，未写入
+	BulkStatusFailed     = "failed"      // 校验或写入失败
+	BulkStatusRolledBack = "rolled_back" // 本条可以执行，但 atomic 模式下其他记录失败导致整体回滚
+)
+
+// 批量修改字段的方式
+const (
+	BulkFieldSet    = "set"    // 替换为指定内容
+	BulkFieldAppend = "append" // 追加到原内容末尾（另起一行）
+)
+
+// ContextElementBulkSelector 批量操作的目标：ids 与 filter 二选一
+type ContextElementBulkSelector struct {
+	IDs    []uint64                    `json:"ids" validate:"max=1000"`
+	Filter *ContextElementExportFilter `json:"filter"` // 与列表查询参数一致
+}
+
+// ContextElementBulkCreateRequest 批量创建请求
+type ContextElementBulkCreateRequest struct {
+	Items  []*ContextElementCreateRequest `json:"items" validate:"required,min=1,max=1000"`
+	Atomic bool                           `json:"atomic"` // 为 true 时任一记录失败则全部不写入
+}
+
+// ContextElementBulkDeleteRequest 批量删除请求（删除的记录进入回收站）
+type ContextElementBulkDeleteRequest struct {
+	ContextElementBulkSelector
+	Atomic bool `json:"atomic"`
+}
+
+// ContextElementBulkFieldPatch 批量修改单个字段
+type ContextElementBulkFieldPatch struct {
+	Field string `json:"field" validate:"required,oneof=task_goal ai_role my_role key_info behavior_rule delivery_format"`
+	Op    string `json:"op" validate:"omitempty,oneof=set append"` // 默认 set
+	Value string `json:"value" validate:"max=5000"`
+}
+
+// ContextElementBulkPatchRequest 批量修改请求
+type ContextElementBulkPatchRequest struct {
+	ContextElementBulkSelector
+	Fields     []*ContextElementBulkFieldPatch `json:"fields" validate:"max=6,dive"`
+	AddTags    []string                        `json:"add_tags" validate:"max=20,dive,max=50"`
+	RemoveTags []string                        `json:"remove_tags" validate:"max=20,dive,max=50"`
+	Atomic     bool                            `json:"atomic"`
+}
+
+// IsEmpty 是否不包含任何修改
+func (req *ContextElementBulkPatchRequest) IsEmpty() bool {
+	return len(req.Fields) == 0 && len(req.AddTags) == 0 && len(req.RemoveTags) == 0
+}
+
+// ContextElementBulkItemResult 单条记录的处理结果
+type ContextElementBulkItemResult struct {
+	Index  int    `json:"index,omitempty"` // 批量创建时为请求中的序号（从1开始）
+	ID     uint64 `json:"id,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ContextElementBulkResult 批量操作结果
+type ContextElementBulkResult struct {
+	Atomic    bool                            `json:"atomic"`
+	Committed bool                            `json:"committed"` // 事务是否提交，atomic 模式下有记录失败时为 false
+	Total     int                             `json:"total"`
+	Succeeded int                             `json:"succeeded"`
+	Unchanged int                             `json:"unchanged"`
+	Failed    int                             `json:"failed"`
+	Results   []*ContextElementBulkItemResult `json:"results"`
+}
+
+// ApplyBulkPatch 将批量修改应用到六要素，返回整体更新请求
+func (ce *ContextElement) ApplyBulkPatch(req *ContextElementBulkPatchRequest) *ContextElementUpdateRequest {
+	patched := &ContextElement{
+		Subject:        ce.Subject,
+		TaskGoal:       ce.TaskGoal,
+		AIRole:         ce.AIRole,
+		MyRole:         ce.MyRole,
+		KeyInfo:        ce.KeyInfo,
+		BehaviorRule:   ce.BehaviorRule,
+		DeliveryFormat: ce.DeliveryFormat,
+	}
+	for _, field := range req.Fields {
+		value := field.Value
+		if current := patched.FieldValue(field.Field); field.Op == BulkFieldAppend && current != "" {
+			value = strings.TrimRight(current, "\n")
+			if field.Value != "" {
+				value += "\n" + field.Value
+			}
+		}
+		patched.SetFieldValue(field.Field, value)
+	}
+
+	removed := make(map[string]bool, len(req.RemoveTags))
+	for _, name := range NormalizeTagNames(req.RemoveTags) {
+		removed[name] = true
+	}
+	tags := make([]string, 0, len(ce.Tags)+len(req.AddTags))
+	for _, tag := range ce.Tags {
+		if !removed[tag.Name] {
+			tags = append(tags, tag.Name)
+		}
+	}
+	for _, name := range NormalizeTagNames(req.AddTags) {
+		if !removed[name] {
+			tags = append(tags, name)
+		}
+	}
+
+	return &ContextElementUpdateRequest{
+		Subject:        patched.Subject,
+		TaskGoal:       patched.TaskGoal,
+		AIRole:         patched.AIRole,
+		MyRole:         patched.MyRole,
+		KeyInfo:        patched.KeyInfo,
+		BehaviorRule:   patched.BehaviorRule,
+		DeliveryFormat: patched.DeliveryFormat,
+		Tags:           NormalizeTagNames(tags),
+	}
+}
+
+// SameContent 判断更新请求与六要素当前内容（含标签，忽略标签顺序）是否一致
+func (ce *ContextElement) SameContent(req *ContextElementUpdateRequest) bool {
+	if ce.Subject != req.Subject || ce.TaskGoal != req.TaskGoal || ce.AIRole != req.AIRole ||
+		ce.MyRole != req.MyRole || ce.KeyInfo != req.KeyInfo || ce.BehaviorRule != req.BehaviorRule ||
+		ce.DeliveryFormat != req.DeliveryFormat || len(ce.Tags) != len(req.Tags) {
+		return false
+	}
+	names := make(map[string]bool, len(ce.Tags))
+	for _, tag := range ce.Tags {
+		names[tag.Name] = true
+	}
+	for _, name := range req.Tags {
+		if !names[name] {
+			return false
+		}
+	}
+	return true
+}
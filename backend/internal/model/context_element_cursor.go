@@ -0,0 +1,77 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// ContextElementCursorRequest 游标分页查询六要素请求
+//
+// 首次请求不带 cursor，按 sort_by/sort_desc 排序；后续请求传入上次响应中的 next_cursor 或 prev_cursor，
+// 排序方式以游标中记录的为准。
+type ContextElementCursorRequest struct {
+	Cursor    string  `form:"cursor" validate:"max=1024"`
+	Size      int     `form:"size" validate:"min=1,max=100"`
+	Keyword   string  `form:"keyword" validate:"max=255"`
+	Subject   string  `form:"subject" validate:"max=255"`
+	AIRole    string  `form:"ai_role" validate:"max=255"`
+	MyRole    string  `form:"my_role" validate:"max=255"`
+	FolderID  *uint64 `form:"folder_id"`
+	Recursive bool    `form:"recursive"`
+	Tags      string  `form:"tags" validate:"max=1000"`
+	TagMode   string  `form:"tag_mode" validate:"omitempty,oneof=any all"`
+	SortBy    string  `form:"sort_by" validate:"oneof=created_at updated_at subject"`
+	SortDesc  bool    `form:"sort_desc"`
+	WithTotal bool    `form:"with_total"` // 是否返回总数（需要额外的计数查询）
+}
+
+// ContextElementCursorPage 游标分页结果
+type ContextElementCursorPage struct {
+	Items      []*ContextElementResponse
+	NextCursor string
+	PrevCursor string
+	Total      *int64
+}
+
+// ToQueryRequest 转换为列表查询条件
+func (req *ContextElementCursorRequest) ToQueryRequest() *ContextElementQueryRequest {
+	return &ContextElementQueryRequest{
+		Size:      req.Size,
+		Keyword:   req.Keyword,
+		Subject:   req.Subject,
+		AIRole:    req.AIRole,
+		MyRole:    req.MyRole,
+		FolderID:  req.FolderID,
+		Recursive: req.Recursive,
+		Tags:      req.Tags,
+		TagMode:   req.TagMode,
+		SortBy:    req.SortBy,
+		SortDesc:  req.SortDesc,
+	}
+}
+
+// SortValue 获取排序字段的值（用于生成游标）
+func (ce *ContextElement) SortValue(sortBy string) string {
+	switch sortBy {
+	case "created_at":
+		return ce.CreatedAt.Format(time.RFC3339Nano)
+	case "updated_at":
+		return ce.UpdatedAt.Format(time.RFC3339Nano)
+	case "subject":
+		return ce.Subject
+	default:
+		return ""
+	}
+}
+
+// ParseSortValue 将游标中的排序字段值转换为查询参数
+func ParseSortValue(sortBy, value string) (interface{}, error) {
+	switch sortBy {
+	case "created_at", "updated_at":
+		return time.Parse(time.RFC3339Nano, value)
+	case "subject":
+		return value, nil
+	default:
+		return nil, errors.New("不支持的排序字段")
+	}
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// DuplicateSuffix 复制六要素时追加在主题后的后缀
+const DuplicateSuffix = "(副本)"
+
+// duplicateSuffixPattern 匹配主题末尾的副本后缀，如 "(副本)"、"(副本 2)"
+var duplicateSuffixPattern = regexp.MustCompile(`\s*\(副本(?: \d+)?\)$`)
+
+// subjectMaxLength 主题的最大字符数
+const subjectMaxLength = 255
+
+// ContextElementDuplicateRequest 复制六要素请求，字段为空表示沿用原记录的内容
+type ContextElementDuplicateRequest struct {
+	Subject        *string  `json:"subject" validate:"omitempty,max=255"` // 指定时不追加副本后缀
+	TaskGoal       *string  `json:"task_goal" validate:"omitempty,max=5000"`
+	AIRole         *string  `json:"ai_role" validate:"omitempty,max=5000"`
+	MyRole         *string  `json:"my_role" validate:"omitempty,max=5000"`
+	KeyInfo        *string  `json:"key_info" validate:"omitempty,max=5000"`
+	BehaviorRule   *string  `json:"behavior_rule" validate:"omitempty,max=5000"`
+	DeliveryFormat *string  `json:"delivery_format" validate:"omitempty,max=5000"`
+	Tags           []string `json:"tags" validate:"omitempty,max=20,dive,max=50"` // 省略时沿用原记录的标签，空数组表示不带标签
+	FolderID       *uint64  `json:"folder_id"`                                    // 为空时与原记录相同，0 表示根目录
+}
+
+// Duplicate 复制六要素内容并应用覆盖字段，主题和标签由调用方设置
+func (ce *ContextElement) Duplicate(req *ContextElementDuplicateRequest) *ContextElement {
+	sourceID := ce.ID
+	clone := &ContextElement{
+		UserID:         ce.UserID,
+		FolderID:       ce.FolderID,
+		ClonedFromID:   &sourceID,
+		Subject:        ce.Subject,
+		TaskGoal:       ce.TaskGoal,
+		AIRole:         ce.AIRole,
+		MyRole:         ce.MyRole,
+		KeyInfo:        ce.KeyInfo,
+		BehaviorRule:   ce.BehaviorRule,
+		DeliveryFormat: ce.DeliveryFormat,
+	}
+
+	overrides := map[string]*string{
+		"task_goal":       req.TaskGoal,
+		"ai_role":         req.AIRole,
+		"my_role":         req.MyRole,
+		"key_info":        req.KeyInfo,
+		"behavior_rule":   req.BehaviorRule,
+		"delivery_format": req.DeliveryFormat,
+	}
+	for key, value := range overrides {
+		if value != nil {
+			clone.SetFieldValue(key, *value)
+		}
+	}
+
+	if req.FolderID != nil {
+		clone.FolderID = req.FolderID
+		if *req.FolderID == 0 {
+			clone.FolderID = nil
+		}
+	}
+	return clone
+}
+
+// DuplicateSubjectBase 去掉主题末尾已有的副本后缀，作为生成副本主题的基础
+func DuplicateSubjectBase(subject string) string {
+	return duplicateSuffixPattern.ReplaceAllString(subject, "")
+}
+
+// DuplicateSubject 生成第 n 个副本的主题：n 为 1 时为 "主题(副本)"，之后为 "主题(副本 n)"
+//
+// 超过主题长度限制时截断基础部分，保证后缀完整。
+func DuplicateSubject(base string, n int) string {
+	suffix := DuplicateSuffix
+	if n > 1 {
+		suffix = fmt.Sprintf("(副本 %d)", n)
+	}
+
+	if limit := subjectMaxLength - utf8.RuneCountInString(suffix); utf8.RuneCountInString(base) > limit {
+		base = strings.TrimSpace(string([]rune(base)[:limit]))
+	}
+	return base + suffix
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// BeforeCreate 创建前钩子：初始化乐观锁版本号
+func (ce *ContextElement) BeforeCreate(tx *gorm.DB) error {
+	if ce.Version == 0 {
+		ce.Version = 1
+	}
+	return nil
+}
+
+// ETag 获取六要素的实体标签，由ID和版本号组成
+func (ce *ContextElement) ETag() string {
+	return fmt.Sprintf(`"%d-%d"`, ce.ID, ce.Version)
+}
+
+// MatchETag 判断 If-Match 请求头是否与实体标签匹配
+//
+// 支持 * 以及逗号分隔的多个标签，弱标签前缀 W/ 会被忽略。
+func MatchETag(ifMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package model
+
+import "cese-backend/pkg/lint"
+
+// ContextElementLintRequest 检查未保存的六要素草稿
+type ContextElementLintRequest struct {
+	Subject        string   `json:"subject" validate:"max=255"`
+	TaskGoal       string   `json:"task_goal" validate:"max=5000"`
+	AIRole         string   `json:"ai_role" validate:"max=5000"`
+	MyRole         string   `json:"my_role" validate:"max=5000"`
+	KeyInfo        string   `json:"key_info" validate:"max=5000"`
+	BehaviorRule   string   `json:"behavior_rule" validate:"max=5000"`
+	DeliveryFormat string   `json:"delivery_format" validate:"max=5000"`
+	Variables      []string `json:"variables" validate:"omitempty,max=50,dive,max=64"` // 已定义的变量名，省略时不检查变量是否已定义
+}
+
+// ContextElementLintResponse 检查结果
+type ContextElementLintResponse struct {
+	ElementID uint64 `json:"element_id,omitempty"` // 检查已保存的六要素时返回
+	*lint.Report
+}
+
+// LintRuleResponse 检查规则
+type LintRuleResponse struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+}
+
+// ToLintDocument 转换为待检查的提示词
+func (req *ContextElementLintRequest) ToLintDocument() *lint.Document {
+	return &lint.Document{
+		Fields: map[string]string{
+			lint.FieldSubject:        req.Subject,
+			lint.FieldTaskGoal:       req.TaskGoal,
+			lint.FieldAIRole:         req.AIRole,
+			lint.FieldMyRole:         req.MyRole,
+			lint.FieldKeyInfo:        req.KeyInfo,
+			lint.FieldBehaviorRule:   req.BehaviorRule,
+			lint.FieldDeliveryFormat: req.DeliveryFormat,
+		},
+		Variables: req.Variables,
+	}
+}
+
+// ToLintDocument 转换为待检查的提示词，variables 为已定义的变量名
+func (ce *ContextElement) ToLintDocument(variables []string) *lint.Document {
+	fields := map[string]string{lint.FieldSubject: ce.Subject}
+	for _, field := range ElementFields {
+		fields[field.Key] = ce.FieldValue(field.Key)
+	}
+	return &lint.Document{Fields: fields, Variables: variables}
+}
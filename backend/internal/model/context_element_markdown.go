@@ -0,0 +1,157 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"cese-backend/pkg/prompt"
+)
+
+// elementHeadingAliases Markdown 二级标题到字段名的映射（含英文别名）
+var elementHeadingAliases = map[string][]string{
+	"subject":         {"主题", "Subject", "Title"},
+	"task_goal":       {"任务目标", "目标", "Task Goal", "Goal", "Objective"},
+	"ai_role":         {"AI的角色", "AI角色", "AI Role", "Assistant Role"},
+	"my_role":         {"我的角色", "My Role", "User Role"},
+	"key_info":        {"关键信息", "Key Information", "Key Info", "Context", "Background"},
+	"behavior_rule":   {"行为规则", "Behavior Rule", "Behavior Rules", "Rules", "Constraints"},
+	"delivery_format": {"交付格式", "Delivery Format", "Output Format", "Format"},
+}
+
+// ContextElementMarkdownMeta Markdown 文档的 YAML 元数据
+type ContextElementMarkdownMeta struct {
+	Subject string   `yaml:"subject"`
+	Tags    []string `yaml:"tags,omitempty"`
+}
+
+// ContextElementMarkdownImportRequest 上传 Markdown 请求（文件通过multipart表单字段file上传，或直接作为请求体）
+type ContextElementMarkdownImportRequest struct {
+	DryRun   bool    `query:"dry_run" form:"dry_run"`     // 为true时只解析不保存
+	FolderID *uint64 `query:"folder_id" form:"folder_id"` // 所在文件夹，为空表示根目录
+}
+
+// ContextElementMarkdownImportResponse 上传 Markdown 响应
+type ContextElementMarkdownImportResponse struct {
+	Parsed   *ContextElementCreateRequest `json:"parsed"`
+	Element  *ContextElementResponse      `json:"element,omitempty"`
+	Warnings []string                     `json:"warnings"`
+}
+
+// MarkdownHeadings 获取可识别的 Markdown 标题及对应的字段名
+func MarkdownHeadings() map[string]string {
+	headings := make(map[string]string)
+	for key, aliases := range elementHeadingAliases {
+		headings[key] = key
+		for _, alias := range aliases {
+			headings[alias] = key
+		}
+	}
+	return headings
+}
+
+// ParseContextElementMarkdown 将六要素 Markdown 文档解析为创建请求
+//
+// 主题依次取元数据 subject、“## 主题”段落、一级标题和 fallbackSubject；
+// 标签取元数据 tags（列表或逗号分隔的字符串）。返回值中的提示信息说明了被合并或忽略的内容。
+func ParseContextElementMarkdown(text, fallbackSubject string) (*ContextElementCreateRequest, []string, error) {
+	doc, err := prompt.ParseMarkdown(text, MarkdownHeadings())
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(doc.Sections) == 0 {
+		return nil, nil, errors.New("未找到六要素段落")
+	}
+
+	warnings := make([]string, 0)
+	for _, heading := range doc.Unknown {
+		warnings = append(warnings, fmt.Sprintf("未识别的标题“%s”，内容已并入上一段落", heading))
+	}
+
+	element := &ContextElement{}
+	seen := make(map[string]bool, len(doc.Sections))
+	for _, section := range doc.Sections {
+		content := section.Content
+		if seen[section.Key] {
+			warnings = append(warnings, fmt.Sprintf("段落“%s”重复出现，内容已合并", section.Title))
+			content = strings.TrimSpace(element.FieldValue(section.Key) + "\n\n" + content)
+		}
+		seen[section.Key] = true
+		element.SetFieldValue(section.Key, content)
+	}
+
+	subject := frontMatterString(doc.FrontMatter, "subject")
+	if subject == "" {
+		subject = frontMatterString(doc.FrontMatter, "title")
+	}
+	if subject == "" {
+		subject = element.Subject
+	}
+	if subject == "" {
+		subject = doc.Title
+	}
+	if subject == "" {
+		subject = fallbackSubject
+	}
+
+	return &ContextElementCreateRequest{
+		Subject:        strings.TrimSpace(subject),
+		TaskGoal:       element.TaskGoal,
+		AIRole:         element.AIRole,
+		MyRole:         element.MyRole,
+		KeyInfo:        element.KeyInfo,
+		BehaviorRule:   element.BehaviorRule,
+		DeliveryFormat: element.DeliveryFormat,
+		Tags:           frontMatterTags(doc.FrontMatter),
+	}, warnings, nil
+}
+
+// ToMarkdown 将六要素序列化为带元数据的 Markdown 文档（与六要素提示词模板格式一致）
+func (ce *ContextElement) ToMarkdown() (string, error) {
+	meta := &ContextElementMarkdownMeta{Subject: ce.Subject}
+	for _, tag := range ce.Tags {
+		meta.Tags = append(meta.Tags, tag.Name)
+	}
+	return prompt.MarshalMarkdown(meta, ce.PromptSections())
+}
+
+// ToTransferItem 转换为导入导出格式
+func (req *ContextElementCreateRequest) ToTransferItem() *ContextElementTransferItem {
+	return &ContextElementTransferItem{
+		Subject:        req.Subject,
+		TaskGoal:       req.TaskGoal,
+		AIRole:         req.AIRole,
+		MyRole:         req.MyRole,
+		KeyInfo:        req.KeyInfo,
+		BehaviorRule:   req.BehaviorRule,
+		DeliveryFormat: req.DeliveryFormat,
+		Tags:           req.Tags,
+	}
+}
+
+// frontMatterString 读取元数据中的字符串值
+func frontMatterString(frontMatter map[string]interface{}, key string) string {
+	value, ok := frontMatter[key]
+	if !ok || value == nil {
+		return ""
+	}
+	return strings.TrimSpace(fmt.Sprint(value))
+}
+
+// frontMatterTags 读取元数据中的标签（列表或逗号分隔的字符串）
+func frontMatterTags(frontMatter map[string]interface{}) []string {
+	switch value := frontMatter["tags"].(type) {
+	case []interface{}:
+		names := make([]string, 0, len(value))
+		for _, v := range value {
+			if v != nil {
+				names = append(names, fmt.Sprint(v))
+			}
+		}
+		return NormalizeTagNames(names)
+	case string:
+		return SplitTagNames(value)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"encoding/json"
+	"mime"
+	"strings"
+)
+
+// 补丁格式
+const (
+	PatchFormatMerge = "merge" // JSON Merge Patch（RFC 7386）
+	PatchFormatJSON  = "json"  // JSON Patch（RFC 6902）
+)
+
+// 补丁的 Content-Type
+const (
+	ContentTypeMergePatch = "application/merge-patch+json"
+	ContentTypeJSONPatch  = "application/json-patch+json"
+)
+
+// PatchFormatFromContentType 根据 Content-Type 判断补丁格式
+//
+// application/json 时按请求体判断：数组为 JSON Patch，其他为 JSON Merge Patch；不支持的类型返回空字符串。
+func PatchFormatFromContentType(contentType string, body []byte) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil && contentType != "" {
+		return ""
+	}
+	switch mediaType {
+	case ContentTypeMergePatch:
+		return PatchFormatMerge
+	case ContentTypeJSONPatch:
+		return PatchFormatJSON
+	case "", "application/json":
+		if strings.HasPrefix(strings.TrimSpace(string(body)), "[") {
+			return PatchFormatJSON
+		}
+		return PatchFormatMerge
+	default:
+		return ""
+	}
+}
+
+// ToPatchDocument 生成补丁作用的JSON文档，包含全部可编辑字段（空字段也会输出，以便 JSON Patch 的 replace/test 操作）
+func (ce *ContextElement) ToPatchDocument() ([]byte, error) {
+	tags := make([]string, len(ce.Tags))
+	for i, tag := range ce.Tags {
+		tags[i] = tag.Name
+	}
+	return json.Marshal(&ContextElementUpdateRequest{
+		Subject:        ce.Subject,
+		TaskGoal:       ce.TaskGoal,
+		AIRole:         ce.AIRole,
+		MyRole:         ce.MyRole,
+		KeyInfo:        ce.KeyInfo,
+		BehaviorRule:   ce.BehaviorRule,
+		DeliveryFormat: ce.DeliveryFormat,
+		Tags:           tags,
+	})
+}
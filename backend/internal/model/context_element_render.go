@@ -0,0 +1,52 @@
+package model
+
+import (
+	"strings"
+
+	"cese-backend/pkg/prompt"
+)
+
+// ContextElementRenderRequest 渲染提示词请求
+type ContextElementRenderRequest struct {
+	Format    string `form:"format" validate:"omitempty,oneof=markdown text xml json"`
+	Order     string `form:"order" validate:"max=255"` // 逗号分隔的字段名，如 task_goal,ai_role
+	OmitEmpty bool   `form:"omit_empty"`
+	Raw       bool   `form:"raw"` // 为true时直接返回提示词文本而非JSON包装
+}
+
+// ContextElementRenderResponse 渲染提示词响应
+type ContextElementRenderResponse struct {
+	ElementID      uint64                  `json:"element_id"`
+	ElementVersion uint64                  `json:"element_version,omitempty"` // 渲染时六要素的版本号
+	Format         string                  `json:"format"`
+	Content        string                  `json:"content"`
+	Variables      map[string]string       `json:"variables,omitempty"` // 实际代入的变量值
+	Errors         []*prompt.VariableError `json:"errors,omitempty"`    // 变量校验错误
+}
+
+// OrderFields 解析段落顺序
+func (req *ContextElementRenderRequest) OrderFields() []string {
+	if strings.TrimSpace(req.Order) == "" {
+		return nil
+	}
+	var fields []string
+	for _, field := range strings.Split(req.Order, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// PromptSections 将六要素转换为提示词段落（按模板顺序）
+func (ce *ContextElement) PromptSections() []prompt.Section {
+	sections := make([]prompt.Section, len(ElementFields))
+	for i, field := range ElementFields {
+		sections[i] = prompt.Section{
+			Key:     field.Key,
+			Title:   field.Label,
+			Content: ce.FieldValue(field.Key),
+		}
+	}
+	return sections
+}
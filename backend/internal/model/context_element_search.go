@@ -0,0 +1,17 @@
+package model
+
+// 搜索结果中的排序方式
+const SortByRelevance = "relevance"
+
+// ContextElementSearchHit 搜索命中记录及其相关度得分
+type ContextElementSearchHit struct {
+	Element *ContextElement
+	Score   float64
+}
+
+// ContextElementSearchResult 搜索结果
+type ContextElementSearchResult struct {
+	*ContextElementResponse
+	Score      float64             `json:"score"`      // 相关度得分
+	Highlights map[string][]string `json:"highlights"` // 字段名 -> 高亮片段（命中部分以<em></em>包裹）
+}
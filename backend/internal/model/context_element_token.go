@@ -0,0 +1,79 @@
+package model
+
+import "math"
+
+// ContextElementTokenQuery 统计已保存六要素的令牌数
+type ContextElementTokenQuery struct {
+	Model     string `form:"model" validate:"max=100"` // 为空时使用默认模型
+	Format    string `form:"format" validate:"omitempty,oneof=markdown text xml json"`
+	OmitEmpty bool   `form:"omit_empty"`
+}
+
+// ContextElementTokenRequest 统计未保存的六要素草稿或任意文本的令牌数
+type ContextElementTokenRequest struct {
+	Model          string `json:"model" validate:"max=100"` // 为空时使用默认模型
+	Format         string `json:"format" validate:"omitempty,oneof=markdown text xml json"`
+	OmitEmpty      bool   `json:"omit_empty"`
+	Text           string `json:"text" validate:"max=40000"` // 指定时只统计该文本（如已渲染的提示词），忽略六要素字段；上限按六个字段渲染后的最大长度留出余量
+	TaskGoal       string `json:"task_goal" validate:"max=5000"`
+	AIRole         string `json:"ai_role" validate:"max=5000"`
+	MyRole         string `json:"my_role" validate:"max=5000"`
+	KeyInfo        string `json:"key_info" validate:"max=5000"`
+	BehaviorRule   string `json:"behavior_rule" validate:"max=5000"`
+	DeliveryFormat string `json:"delivery_format" validate:"max=5000"`
+}
+
+// FieldTokenCount 单个字段的令牌数
+type FieldTokenCount struct {
+	Field  string `json:"field"`
+	Label  string `json:"label"`
+	Tokens int    `json:"tokens"`
+}
+
+// TokenCost 估算的输入费用
+type TokenCost struct {
+	InputPrice float64 `json:"input_price"` // 每百万输入令牌的价格
+	Currency   string  `json:"currency"`
+	Estimated  float64 `json:"estimated"`
+}
+
+// ContextElementTokenResponse 令牌数统计结果
+type ContextElementTokenResponse struct {
+	ElementID      uint64             `json:"element_id,omitempty"` // 统计已保存的六要素时返回
+	Model          string             `json:"model"`
+	Encoding       string             `json:"encoding"`
+	Exact          bool               `json:"exact"`            // false 表示编码方式没有词表（cjk_approx），令牌数为近似值
+	Fields         []*FieldTokenCount `json:"fields,omitempty"` // 各字段内容本身的令牌数
+	Format         string             `json:"format,omitempty"` // 渲染提示词使用的格式
+	Total          int                `json:"total"`            // 渲染后完整提示词（或指定文本）的令牌数
+	ContextWindow  int                `json:"context_window,omitempty"`
+	ExceedsContext bool               `json:"exceeds_context"` // 是否超出模型的上下文窗口
+	Cost           TokenCost          `json:"cost"`
+}
+
+// TokenModelResponse 可用于统计令牌数的模型
+type TokenModelResponse struct {
+	Name          string  `json:"name"`
+	Encoding      string  `json:"encoding"`
+	InputPrice    float64 `json:"input_price"`
+	Currency      string  `json:"currency"`
+	ContextWindow int     `json:"context_window,omitempty"`
+	Default       bool    `json:"default"`
+}
+
+// ToContextElement 将草稿转换为六要素（仅用于统计，不保存）
+func (req *ContextElementTokenRequest) ToContextElement() *ContextElement {
+	return &ContextElement{
+		TaskGoal:       req.TaskGoal,
+		AIRole:         req.AIRole,
+		MyRole:         req.MyRole,
+		KeyInfo:        req.KeyInfo,
+		BehaviorRule:   req.BehaviorRule,
+		DeliveryFormat: req.DeliveryFormat,
+	}
+}
+
+// EstimateCost 按每百万令牌的价格估算费用，保留6位小数
+func EstimateCost(tokens int, pricePerMillion float64) float64 {
+	return math.Round(float64(tokens)*pricePerMillion) / 1e6
+}
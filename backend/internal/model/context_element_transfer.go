@@ -0,0 +1,155 @@
+package model
+
+import (
+	"time"
+
+	"cese-backend/pkg/prompt"
+)
+
+// 导出格式
+const (
+	ExportFormatJSON = "json"
+	ExportFormatCSV  = "csv"
+	ExportFormatZIP  = "zip"
+)
+
+// ImportFormatMarkdown 导入格式：单个六要素 Markdown 文档
+const ImportFormatMarkdown = "markdown"
+
+// 导入冲突处理方式（按主题判断冲突）
+const (
+	ImportConflictSkip      = "skip"      // 已存在同主题记录时跳过
+	ImportConflictOverwrite = "overwrite" // 已存在同主题记录时覆盖其内容
+)
+
+// 导入行状态
+const (
+	ImportStatusCreated = "created"
+	ImportStatusUpdated = "updated"
+	ImportStatusSkipped = "skipped"
+	ImportStatusFailed  = "failed"
+)
+
+// ExportFileVersion 导出文件格式版本
+const ExportFileVersion = 1
+
+// ContextElementExportFilter 导出过滤条件（与列表查询参数一致）
+type ContextElementExportFilter struct {
+	Keyword   string  `json:"keyword" validate:"max=255"`
+	Subject   string  `json:"subject" validate:"max=255"`
+	AIRole    string  `json:"ai_role" validate:"max=255"`
+	MyRole    string  `json:"my_role" validate:"max=255"`
+	FolderID  *uint64 `json:"folder_id"`
+	Recursive bool    `json:"recursive"`
+	Tags      string  `json:"tags" validate:"max=1000"`
+	TagMode   string  `json:"tag_mode" validate:"omitempty,oneof=any all"`
+}
+
+// ContextElementExportRequest 导出六要素请求
+// 指定ids时按ID导出，否则指定filter时按过滤条件导出，都未指定时导出全部
+type ContextElementExportRequest struct {
+	Format string                      `json:"format" validate:"omitempty,oneof=json csv zip"`
+	IDs    []uint64                    `json:"ids" validate:"max=1000"`
+	Filter *ContextElementExportFilter `json:"filter"`
+}
+
+// ToQueryRequest 将导出过滤条件转换为查询请求
+func (f *ContextElementExportFilter) ToQueryRequest() *ContextElementQueryRequest {
+	return &ContextElementQueryRequest{
+		Keyword:   f.Keyword,
+		Subject:   f.Subject,
+		AIRole:    f.AIRole,
+		MyRole:    f.MyRole,
+		FolderID:  f.FolderID,
+		Recursive: f.Recursive,
+		Tags:      f.Tags,
+		TagMode:   f.TagMode,
+	}
+}
+
+// ContextElementTransferItem 导入导出的单条六要素
+type ContextElementTransferItem struct {
+	Subject        string   `json:"subject" validate:"required,max=255"`
+	TaskGoal       string   `json:"task_goal" validate:"max=5000"`
+	AIRole         string   `json:"ai_role" validate:"max=5000"`
+	MyRole         string   `json:"my_role" validate:"max=5000"`
+	KeyInfo        string   `json:"key_info" validate:"max=5000"`
+	BehaviorRule   string   `json:"behavior_rule" validate:"max=5000"`
+	DeliveryFormat string   `json:"delivery_format" validate:"max=5000"`
+	Tags           []string `json:"tags" validate:"max=20,dive,max=50"`
+
+	Variables []prompt.Variable `json:"variables,omitempty" validate:"max=50"` // 变量定义，CSV不包含；为空表示导入时不修改已有记录的变量定义
+}
+
+// ContextElementExportDocument JSON导出文件结构
+type ContextElementExportDocument struct {
+	Version    int                           `json:"version"`
+	ExportedAt time.Time                     `json:"exported_at"`
+	Elements   []*ContextElementTransferItem `json:"elements"`
+}
+
+// ContextElementExportFile 导出文件
+type ContextElementExportFile struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// ContextElementImportRequest 导入六要素请求（文件通过multipart表单字段file上传）
+type ContextElementImportRequest struct {
+	Format   string  `form:"format" validate:"omitempty,oneof=json csv zip markdown"` // 为空时按文件扩展名判断
+	Conflict string  `form:"conflict" validate:"omitempty,oneof=skip overwrite"`
+	DryRun   bool    `form:"dry_run"`
+	FolderID *uint64 `form:"folder_id"` // 新建记录所在文件夹，为空表示根目录
+}
+
+// ContextElementImportRow 单行导入结果
+type ContextElementImportRow struct {
+	Row       int    `json:"row"`              // 从1开始的记录序号
+	Source    string `json:"source,omitempty"` // ZIP导入时记录所在的文件名
+	Subject   string `json:"subject"`
+	Status    string `json:"status"`
+	ElementID uint64 `json:"element_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ContextElementImportResult 导入结果
+type ContextElementImportResult struct {
+	DryRun  bool                       `json:"dry_run"`
+	Total   int                        `json:"total"`
+	Success int                        `json:"success"`
+	Skipped int                        `json:"skipped"`
+	Failed  int                        `json:"failed"`
+	Errors  []string                   `json:"errors"`
+	Rows    []*ContextElementImportRow `json:"rows"`
+}
+
+// ToTransferItem 转换为导入导出格式
+func (ce *ContextElement) ToTransferItem() *ContextElementTransferItem {
+	tags := make([]string, len(ce.Tags))
+	for i, tag := range ce.Tags {
+		tags[i] = tag.Name
+	}
+
+	return &ContextElementTransferItem{
+		Subject:        ce.Subject,
+		TaskGoal:       ce.TaskGoal,
+		AIRole:         ce.AIRole,
+		MyRole:         ce.MyRole,
+		KeyInfo:        ce.KeyInfo,
+		BehaviorRule:   ce.BehaviorRule,
+		DeliveryFormat: ce.DeliveryFormat,
+		Tags:           tags,
+	}
+}
+
+// ApplyTransferItem 用导入内容整体覆盖六要素字段
+func (ce *ContextElement) ApplyTransferItem(item *ContextElementTransferItem) {
+	ce.Subject = item.Subject
+	ce.TaskGoal = item.TaskGoal
+	ce.AIRole = item.AIRole
+	ce.MyRole = item.MyRole
+	ce.KeyInfo = item.KeyInfo
+	ce.BehaviorRule = item.BehaviorRule
+	ce.DeliveryFormat = item.DeliveryFormat
+}
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// ContextElementTrashQueryRequest 查询回收站请求
+type ContextElementTrashQueryRequest struct {
+	Page    int    `form:"page" validate:"min=1"`
+	Size    int    `form:"size" validate:"min=1,max=100"`
+	Keyword string `form:"keyword" validate:"max=255"` // 按主题模糊匹配
+}
+
+// ContextElementTrashRequest 批量恢复或彻底删除请求
+type ContextElementTrashRequest struct {
+	IDs []uint64 `json:"ids" validate:"required,min=1,max=1000"`
+}
+
+// ContextElementTrashResponse 回收站中的六要素
+type ContextElementTrashResponse struct {
+	*ContextElementResponse
+	DeletedAt time.Time  `json:"deleted_at"`
+	PurgeAt   *time.Time `json:"purge_at"` // 预计被自动彻底删除的时间，为空表示不会自动删除
+}
+
+// ContextElementTrashResult 批量操作结果
+type ContextElementTrashResult struct {
+	Affected int64 `json:"affected"`
+}
+
+// ToTrashResponse 转换为回收站响应格式
+func (ce *ContextElement) ToTrashResponse(retention time.Duration) *ContextElementTrashResponse {
+	resp := &ContextElementTrashResponse{
+		ContextElementResponse: ce.ToResponse(),
+		DeletedAt:              ce.DeletedAt.Time,
+	}
+	if retention > 0 {
+		purgeAt := ce.DeletedAt.Time.Add(retention)
+		resp.PurgeAt = &purgeAt
+	}
+	return resp
+}
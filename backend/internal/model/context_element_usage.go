@@ -0,0 +1,89 @@
+package model
+
+import "time"
+
+// 六要素的使用事件
+const (
+	UsageOpen       = "open"       // 打开详情
+	UsageRender     = "render"     // 渲染提示词
+	UsageGeneration = "generation" // 调用大模型生成
+	UsageCopy       = "copy"       // 复制提示词（由客户端上报）
+)
+
+// 使用情况相关的排序方式
+const (
+	SortByLastUsed = "last_used" // 最近使用时间（打开或使用）
+	SortByUseCount = "use_count" // 使用次数（渲染、生成和复制的总和）
+)
+
+// ContextElementUsage 六要素的收藏、置顶和使用统计（按用户记录，首次收藏或使用时创建）
+type ContextElementUsage struct {
+	ContextElementID uint64     `json:"context_element_id" gorm:"primaryKey;autoIncrement:false;comment:六要素ID"`
+	UserID           uint64     `json:"user_id" gorm:"not null;index:idx_usage_user_last_used;comment:用户ID"`
+	Favorite         bool       `json:"favorite" gorm:"not null;default:false;comment:是否收藏"`
+	Pinned           bool       `json:"pinned" gorm:"not null;default:false;comment:是否置顶"`
+	PinnedAt         *time.Time `json:"pinned_at" gorm:"comment:置顶时间"`
+	LastOpenedAt     *time.Time `json:"last_opened_at" gorm:"comment:最近打开时间"`
+	LastUsedAt       *time.Time `json:"last_used_at" gorm:"index:idx_usage_user_last_used;comment:最近打开或使用时间"`
+	RenderCount      int64      `json:"render_count" gorm:"not null;default:0;comment:渲染次数"`
+	GenerationCount  int64      `json:"generation_count" gorm:"not null;default:0;comment:生成次数"`
+	CopyCount        int64      `json:"copy_count" gorm:"not null;default:0;comment:复制次数"`
+	UseCount         int64      `json:"use_count" gorm:"not null;default:0;comment:使用次数（渲染、生成和复制的总和）"`
+	UpdatedAt        time.Time  `json:"updated_at" gorm:"comment:更新时间"`
+}
+
+// TableName 指定表名
+func (ContextElementUsage) TableName() string {
+	return "cese_context_element_usage"
+}
+
+// ContextElementUsageRequest 上报使用事件请求
+type ContextElementUsageRequest struct {
+	Event string `json:"event" binding:"required" validate:"required,oneof=copy"` // 打开、渲染和生成由服务端记录
+}
+
+// ContextElementRecentRequest 最近使用的六要素查询请求
+type ContextElementRecentRequest struct {
+	Limit int `form:"limit" validate:"min=0,max=50"` // 默认10
+}
+
+// ContextElementUsageResponse 六要素的收藏、置顶和使用统计响应
+type ContextElementUsageResponse struct {
+	Favorite        bool       `json:"favorite"`
+	Pinned          bool       `json:"pinned"`
+	PinnedAt        *time.Time `json:"pinned_at"`
+	LastOpenedAt    *time.Time `json:"last_opened_at"`
+	LastUsedAt      *time.Time `json:"last_used_at"`
+	RenderCount     int64      `json:"render_count"`
+	GenerationCount int64      `json:"generation_count"`
+	CopyCount       int64      `json:"copy_count"`
+	UseCount        int64      `json:"use_count"`
+}
+
+// IsUsageEvent 判断是否为支持的使用事件
+func IsUsageEvent(event string) bool {
+	switch event {
+	case UsageOpen, UsageRender, UsageGeneration, UsageCopy:
+		return true
+	default:
+		return false
+	}
+}
+
+// ToResponse 转换为响应格式，没有记录时返回全部为零值的统计
+func (u *ContextElementUsage) ToResponse() *ContextElementUsageResponse {
+	if u == nil {
+		return &ContextElementUsageResponse{}
+	}
+	return &ContextElementUsageResponse{
+		Favorite:        u.Favorite,
+		Pinned:          u.Pinned,
+		PinnedAt:        u.PinnedAt,
+		LastOpenedAt:    u.LastOpenedAt,
+		LastUsedAt:      u.LastUsedAt,
+		RenderCount:     u.RenderCount,
+		GenerationCount: u.GenerationCount,
+		CopyCount:       u.CopyCount,
+		UseCount:        u.UseCount,
+	}
+}
@@ -0,0 +1,117 @@
+package model
+
+import (
+	"time"
+
+	"cese-backend/pkg/prompt"
+)
+
+// ContextElementVariable 六要素模板变量定义模型
+type ContextElementVariable struct {
+	ID           uint64    `json:"id" gorm:"primaryKey;autoIncrement;comment:变量ID"`
+	ElementID    uint64    `json:"element_id" gorm:"not null;uniqueIndex:idx_element_variable;comment:六要素ID"`
+	Name         string    `json:"name" gorm:"type:varchar(64);not null;uniqueIndex:idx_element_variable;comment:变量名"`
+	Type         string    `json:"type" gorm:"type:varchar(16);not null;comment:变量类型"`
+	DefaultValue string    `json:"default" gorm:"type:varchar(1000);comment:默认值"`
+	Required     bool      `json:"required" gorm:"not null;default:false;comment:是否必填"`
+	Description  string    `json:"description" gorm:"type:varchar(500);comment:变量说明"`
+	Options      []string  `json:"options,omitempty" gorm:"type:text;serializer:json;comment:枚举可选值"`
+	SortOrder    int       `json:"sort_order" gorm:"not null;default:0;comment:排序"`
+	CreatedAt    time.Time `json:"created_at" gorm:"comment:创建时间"`
+	UpdatedAt    time.Time `json:"updated_at" gorm:"comment:更新时间"`
+}
+
+// TableName 指定表名
+func (ContextElementVariable) TableName() string {
+	return "cese_context_element_variable"
+}
+
+// ContextElementVariablesUpdateRequest 设置变量定义请求
+type ContextElementVariablesUpdateRequest struct {
+	Variables []prompt.Variable `json:"variables" validate:"max=50"`
+}
+
+// ContextElementVariablesResponse 变量定义响应
+type ContextElementVariablesResponse struct {
+	ElementID  uint64            `json:"element_id"`
+	Detected   []string          `json:"detected"`   // 六要素中出现的变量
+	Undeclared []string          `json:"undeclared"` // 出现但未定义的变量
+	Variables  []prompt.Variable `json:"variables"`  // 已定义的变量
+
+	Errors []*prompt.VariableError `json:"errors,omitempty"` // 变量定义校验错误
+}
+
+// ContextElementRenderVariablesRequest 代入变量渲染提示词请求
+type ContextElementRenderVariablesRequest struct {
+	Format    string                   `json:"format" validate:"omitempty,oneof=markdown text xml json"`
+	Order     []string                 `json:"order" validate:"max=6"`
+	OmitEmpty bool                     `json:"omit_empty"`
+	Variables map[string]interface{}   `json:"variables"`
+	Overrides *ContextElementOverrides `json:"overrides"` // 仅用于本次渲染的字段内容，不修改六要素
+}
+
+// ContextElementOverrides 临时覆盖的六要素字段，字段为空表示沿用原内容
+type ContextElementOverrides struct {
+	TaskGoal       *string `json:"task_goal,omitempty" validate:"omitempty,max=5000"`
+	AIRole         *string `json:"ai_role,omitempty" validate:"omitempty,max=5000"`
+	MyRole         *string `json:"my_role,omitempty" validate:"omitempty,max=5000"`
+	KeyInfo        *string `json:"key_info,omitempty" validate:"omitempty,max=5000"`
+	BehaviorRule   *string `json:"behavior_rule,omitempty" validate:"omitempty,max=5000"`
+	DeliveryFormat *string `json:"delivery_format,omitempty" validate:"omitempty,max=5000"`
+}
+
+// ApplyTo 将覆盖的字段写入六要素（只修改内存中的对象）
+func (o *ContextElementOverrides) ApplyTo(ce *ContextElement) {
+	overrides := map[string]*string{
+		"task_goal":       o.TaskGoal,
+		"ai_role":         o.AIRole,
+		"my_role":         o.MyRole,
+		"key_info":        o.KeyInfo,
+		"behavior_rule":   o.BehaviorRule,
+		"delivery_format": o.DeliveryFormat,
+	}
+	for key, value := range overrides {
+		if value != nil {
+			ce.SetFieldValue(key, *value)
+		}
+	}
+}
+
+// ToVariable 转换为变量定义
+func (v *ContextElementVariable) ToVariable() prompt.Variable {
+	return prompt.Variable{
+		Name:        v.Name,
+		Type:        v.Type,
+		Default:     v.DefaultValue,
+		Required:    v.Required,
+		Description: v.Description,
+		Options:     v.Options,
+	}
+}
+
+// NewContextElementVariables 根据变量定义生成模型列表
+func NewContextElementVariables(elementID uint64, variables []prompt.Variable) []*ContextElementVariable {
+	models := make([]*ContextElementVariable, len(variables))
+	for i, v := range variables {
+		models[i] = &ContextElementVariable{
+			ElementID:    elementID,
+			Name:         v.Name,
+			Type:         v.Type,
+			DefaultValue: v.Default,
+			Required:     v.Required,
+			Description:  v.Description,
+			Options:      v.Options,
+			SortOrder:    i,
+		}
+	}
+	return models
+}
+
+// FieldValues 获取六要素各字段内容（按模板顺序）
+func (ce *ContextElement) FieldValues() []string {
+	values := make([]string, len(ElementFields))
+	for i, field := range ElementFields {
+		values[i] = ce.FieldValue(field.Key)
+	}
+	return values
+}
@@ -0,0 +1,148 @@
+package model
+
+import (
+	"time"
+
+	"cese-backend/pkg/diff"
+)
+
+// ContextElementVersion 六要素历史版本模型
+type ContextElementVersion struct {
+	ID             uint64    `json:"id" gorm:"primaryKey;autoIncrement;comment:版本记录ID"`
+	ElementID      uint64    `json:"element_id" gorm:"not null;uniqueIndex:idx_element_version;comment:六要素ID"`
+	UserID         uint64    `json:"user_id" gorm:"not null;index;comment:用户ID"`
+	VersionNo      int       `json:"version_no" gorm:"not null;uniqueIndex:idx_element_version;comment:版本号"`
+	Subject        string    `json:"subject" gorm:"type:varchar(255);not null;comment:主题"`
+	TaskGoal       string    `json:"task_goal" gorm:"type:text;comment:任务目标"`
+	AIRole         string    `json:"ai_role" gorm:"type:text;comment:AI的角色"`
+	MyRole         string    `json:"my_role" gorm:"type:text;comment:我的角色"`
+	KeyInfo        string    `json:"key_info" gorm:"type:text;comment:关键信息"`
+	BehaviorRule   string    `json:"behavior_rule" gorm:"type:text;comment:行为规则"`
+	DeliveryFormat string    `json:"delivery_format" gorm:"type:text;comment:交付格式"`
+	CreatedAt      time.Time `json:"created_at" gorm:"comment:创建时间"`
+}
+
+// TableName 指定表名
+func (ContextElementVersion) TableName() string {
+	return "cese_context_element_version"
+}
+
+// ContextElementVersionResponse 历史版本响应
+type ContextElementVersionResponse struct {
+	ID             uint64    `json:"id"`
+	ElementID      uint64    `json:"element_id"`
+	VersionNo      int       `json:"version_no"`
+	Subject        string    `json:"subject"`
+	TaskGoal       string    `json:"task_goal"`
+	AIRole         string    `json:"ai_role"`
+	MyRole         string    `json:"my_role"`
+	KeyInfo        string    `json:"key_info"`
+	BehaviorRule   string    `json:"behavior_rule"`
+	DeliveryFormat string    `json:"delivery_format"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// ContextElementVersionDiffRequest 版本对比请求
+type ContextElementVersionDiffRequest struct {
+	From int `form:"from" validate:"min=1"`
+	To   int `form:"to" validate:"min=0"` // 0 表示当前版本
+}
+
+// FieldDiff 单个字段的差异
+type FieldDiff struct {
+	Field   string      `json:"field"`
+	Label   string      `json:"label"`
+	Changed bool        `json:"changed"`
+	From    string      `json:"from"`
+	To      string      `json:"to"`
+	Lines   []diff.Line `json:"lines,omitempty"`
+}
+
+// ContextElementVersionDiffResponse 版本对比响应
+type ContextElementVersionDiffResponse struct {
+	ElementID   uint64       `json:"element_id"`
+	FromVersion int          `json:"from_version"`
+	ToVersion   int          `json:"to_version"` // 0 表示当前版本
+	Fields      []*FieldDiff `json:"fields"`
+}
+
+// NewContextElementVersion 根据六要素当前状态生成版本快照
+func NewContextElementVersion(element *ContextElement) *ContextElementVersion {
+	return &ContextElementVersion{
+		ElementID:      element.ID,
+		UserID:         element.UserID,
+		Subject:        element.Subject,
+		TaskGoal:       element.TaskGoal,
+		AIRole:         element.AIRole,
+		MyRole:         element.MyRole,
+		KeyInfo:        element.KeyInfo,
+		BehaviorRule:   element.BehaviorRule,
+		DeliveryFormat: element.DeliveryFormat,
+	}
+}
+
+// ToResponse 转换为响应格式
+func (v *ContextElementVersion) ToResponse() *ContextElementVersionResponse {
+	return &ContextElementVersionResponse{
+		ID:             v.ID,
+		ElementID:      v.ElementID,
+		VersionNo:      v.VersionNo,
+		Subject:        v.Subject,
+		TaskGoal:       v.TaskGoal,
+		AIRole:         v.AIRole,
+		MyRole:         v.MyRole,
+		KeyInfo:        v.KeyInfo,
+		BehaviorRule:   v.BehaviorRule,
+		DeliveryFormat: v.DeliveryFormat,
+		CreatedAt:      v.CreatedAt,
+	}
+}
+
+// ToContextElement 将版本快照转换为六要素内容（不含ID等元信息）
+func (v *ContextElementVersion) ToContextElement() *ContextElement {
+	return &ContextElement{
+		ID:             v.ElementID,
+		UserID:         v.UserID,
+		Subject:        v.Subject,
+		TaskGoal:       v.TaskGoal,
+		AIRole:         v.AIRole,
+		MyRole:         v.MyRole,
+		KeyInfo:        v.KeyInfo,
+		BehaviorRule:   v.BehaviorRule,
+		DeliveryFormat: v.DeliveryFormat,
+	}
+}
+
+// RestoreFrom 使用版本快照覆盖六要素内容
+func (ce *ContextElement) RestoreFrom(v *ContextElementVersion) {
+	ce.Subject = v.Subject
+	ce.TaskGoal = v.TaskGoal
+	ce.AIRole = v.AIRole
+	ce.MyRole = v.MyRole
+	ce.KeyInfo = v.KeyInfo
+	ce.BehaviorRule = v.BehaviorRule
+	ce.DeliveryFormat = v.DeliveryFormat
+}
+
+// DiffContextElements 逐字段对比两份六要素内容
+func DiffContextElements(from, to *ContextElement) []*FieldDiff {
+	fields := append([]ElementField{{Key: "subject", Label: "主题"}}, ElementFields...)
+
+	diffs := make([]*FieldDiff, 0, len(fields))
+	for _, field := range fields {
+		fromValue := from.FieldValue(field.Key)
+		toValue := to.FieldValue(field.Key)
+		fd := &FieldDiff{
+			Field:   field.Key,
+			Label:   field.Label,
+			Changed: fromValue != toValue,
+			From:    fromValue,
+			To:      toValue,
+		}
+		if fd.Changed {
+			fd.Lines = diff.Lines(fromValue, toValue)
+		}
+		diffs = append(diffs, fd)
+	}
+	return diffs
+}
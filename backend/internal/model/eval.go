@@ -0,0 +1,338 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"cese-backend/pkg/eval"
+	"cese-backend/pkg/provider"
+)
+
+// EvalDataset 评估数据集，每个用例包括输入变量和对输出的预期
+type EvalDataset struct {
+	ID          uint64      `json:"id" gorm:"primaryKey;autoIncrement;comment:评估数据集ID"`
+	UserID      uint64      `json:"user_id" gorm:"not null;index;comment:用户ID"`
+	ElementID   uint64      `json:"element_id" gorm:"not null;index;comment:六要素ID"`
+	Name        string      `json:"name" gorm:"type:varchar(100);not null;comment:名称"`
+	Description string      `json:"description" gorm:"type:text;comment:描述"`
+	CreatedAt   time.Time   `json:"created_at" gorm:"comment:创建时间"`
+	UpdatedAt   time.Time   `json:"updated_at" gorm:"comment:更新时间"`
+	Cases       []*EvalCase `json:"cases,omitempty" gorm:"foreignKey:DatasetID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName 指定表名
+func (EvalDataset) TableName() string {
+	return "cese_eval_dataset"
+}
+
+// EvalCase 评估用例
+type EvalCase struct {
+	ID         uint64 `json:"id" gorm:"primaryKey;autoIncrement;comment:评估用例ID"`
+	DatasetID  uint64 `json:"dataset_id" gorm:"not null;index;comment:评估数据集ID"`
+	Position   int    `json:"position" gorm:"not null;comment:用例序号"`
+	Name       string `json:"name" gorm:"type:varchar(100);comment:名称"`
+	Variables  string `json:"-" gorm:"type:text;comment:模板变量的值(JSON)"`
+	Assertions string `json:"-" gorm:"type:text;comment:断言(JSON)"`
+}
+
+// TableName 指定表名
+func (EvalCase) TableName() string {
+	return "cese_eval_case"
+}
+
+// 评估运行状态
+const (
+	EvalRunStatusRunning   = "running"   // 正在后台执行用例
+	EvalRunStatusCompleted = "completed" // 全部用例已执行
+	EvalRunStatusCanceled  = "canceled"  // 服务关闭或重启，未执行全部用例
+)
+
+// EvalRun 评估运行，记录执行时六要素的版本和每个用例的结果
+type EvalRun struct {
+	ID             uint64        `json:"id" gorm:"primaryKey;autoIncrement;comment:评估运行ID"`
+	UserID         uint64        `json:"user_id" gorm:"not null;index;comment:用户ID"`
+	DatasetID      uint64        `json:"dataset_id" gorm:"not null;index;comment:评估数据集ID"`
+	ElementID      uint64        `json:"element_id" gorm:"not null;index:idx_element_created;comment:六要素ID"`
+	ElementVersion uint64        `json:"element_version" gorm:"not null;default:0;comment:运行时六要素的版本号"`
+	Provider       string        `json:"provider" gorm:"type:varchar(50);not null;comment:模型服务名称"`
+	Model          string        `json:"model" gorm:"type:varchar(100);not null;comment:模型名称"`
+	JudgeProvider  string        `json:"judge_provider" gorm:"type:varchar(50);comment:评审模型服务名称"`
+	JudgeModel     string        `json:"judge_model" gorm:"type:varchar(100);comment:评审模型名称"`
+	Format         string        `json:"format" gorm:"type:varchar(20);not null;comment:提示词格式"`
+	Config         string        `json:"-" gorm:"type:text;comment:采样参数(JSON)"`
+	Status         string        `json:"status" gorm:"type:varchar(20);not null;comment:状态"`
+	Total          int           `json:"total" gorm:"not null;default:0;comment:用例数"`
+	Passed         int           `json:"passed" gorm:"not null;default:0;comment:通过数"`
+	Failed         int           `json:"failed" gorm:"not null;default:0;comment:未通过数"`
+	Errored        int           `json:"errored" gorm:"not null;default:0;comment:无法评估数"`
+	TotalTokens    int           `json:"total_tokens" gorm:"not null;default:0;comment:总令牌数"`
+	DurationMs     int64         `json:"duration_ms" gorm:"not null;default:0;comment:耗时(毫秒)"`
+	CreatedAt      time.Time     `json:"created_at" gorm:"index:idx_element_created;comment:创建时间"`
+	Results        []*EvalResult `json:"results,omitempty" gorm:"foreignKey:RunID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName 指定表名
+func (EvalRun) TableName() string {
+	return "cese_eval_run"
+}
+
+// EvalResult 用例的执行和评估结果
+type EvalResult struct {
+	ID               uint64 `json:"id" gorm:"primaryKey;autoIncrement;comment:评估结果ID"`
+	RunID            uint64 `json:"run_id" gorm:"not null;index;comment:评估运行ID"`
+	CaseID           uint64 `json:"case_id" gorm:"not null;index;comment:评估用例ID"`
+	CaseName         string `json:"case_name" gorm:"type:varchar(100);comment:用例名称"`
+	Prompt           string `json:"prompt" gorm:"type:mediumtext;comment:发送给模型的提示词"`
+	Content          string `json:"content" gorm:"type:mediumtext;comment:生成的内容"`
+	Status           string `json:"status" gorm:"type:varchar(20);not null;comment:评估结果"`
+	Error            string `json:"error" gorm:"type:text;comment:错误信息"`
+	Checks           string `json:"-" gorm:"type:text;comment:断言结果(JSON)"`
+	PromptTokens     int    `json:"prompt_tokens" gorm:"not null;default:0;comment:输入令牌数"`
+	CompletionTokens int    `json:"completion_tokens" gorm:"not null;default:0;comment:输出令牌数"`
+	TotalTokens      int    `json:"total_tokens" gorm:"not null;default:0;comment:总令牌数"`
+	DurationMs       int64  `json:"duration_ms" gorm:"not null;default:0;comment:耗时(毫秒)"`
+}
+
+// TableName 指定表名
+func (EvalResult) TableName() string {
+	return "cese_eval_result"
+}
+
+// EvalDatasetCreateRequest 创建评估数据集请求
+type EvalDatasetCreateRequest struct {
+	ElementID   uint64             `json:"element_id" binding:"required" validate:"required"`
+	Name        string             `json:"name" binding:"required" validate:"required,max=100"`
+	Description string             `json:"description" validate:"max=2000"`
+	Cases       []*EvalCaseRequest `json:"cases" validate:"max=100,dive,required"`
+}
+
+// EvalDatasetUpdateRequest 更新评估数据集请求（整体替换用例）
+type EvalDatasetUpdateRequest struct {
+	Name        string             `json:"name" binding:"required" validate:"required,max=100"`
+	Description string             `json:"description" validate:"max=2000"`
+	Cases       []*EvalCaseRequest `json:"cases" validate:"max=100,dive,required"`
+}
+
+// EvalCaseRequest 评估用例
+type EvalCaseRequest struct {
+	Name       string                 `json:"name" validate:"max=100"` // 为空时使用“用例1”、“用例2”…
+	Variables  map[string]interface{} `json:"variables"`
+	Assertions []*eval.Assertion      `json:"assertions" validate:"required,min=1,max=20,dive,required"`
+}
+
+// EvalDatasetQueryRequest 查询评估数据集请求
+type EvalDatasetQueryRequest struct {
+	ElementID uint64 `form:"element_id"`
+}
+
+// EvalRunCreateRequest 运行评估请求；variables 为各用例共用的变量，用例中的同名变量优先
+type EvalRunCreateRequest struct {
+	GenerateRequest
+	JudgeProvider string `json:"judge_provider" validate:"max=50"` // llm_judge 断言使用的模型服务，为空时与生成相同
+	JudgeModel    string `json:"judge_model" validate:"max=100"`   // 为空时与生成相同（评审模型服务不同时使用其第一个模型）
+}
+
+// EvalRunQueryRequest 查询评估运行请求
+type EvalRunQueryRequest struct {
+	Page      int    `form:"page" validate:"min=1"`
+	Size      int    `form:"size" validate:"min=1,max=100"`
+	DatasetID uint64 `form:"dataset_id"`
+	ElementID uint64 `form:"element_id"`
+}
+
+// EvalDatasetResponse 评估数据集响应
+type EvalDatasetResponse struct {
+	ID          uint64              `json:"id"`
+	ElementID   uint64              `json:"element_id"`
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	CaseCount   int                 `json:"case_count"`
+	Cases       []*EvalCaseResponse `json:"cases,omitempty"` // 列表中不返回
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+}
+
+// EvalCaseResponse 评估用例响应
+type EvalCaseResponse struct {
+	ID         uint64                 `json:"id"`
+	Position   int                    `json:"position"`
+	Name       string                 `json:"name"`
+	Variables  map[string]interface{} `json:"variables"`
+	Assertions []*eval.Assertion      `json:"assertions"`
+}
+
+// EvalRunResponse 评估运行响应
+type EvalRunResponse struct {
+	ID             uint64                `json:"id"`
+	DatasetID      uint64                `json:"dataset_id"`
+	ElementID      uint64                `json:"element_id"`
+	ElementVersion uint64                `json:"element_version"`
+	Provider       string                `json:"provider"`
+	Model          string                `json:"model"`
+	JudgeProvider  string                `json:"judge_provider,omitempty"`
+	JudgeModel     string                `json:"judge_model,omitempty"`
+	Format         string                `json:"format"`
+	Config         *GenerationConfig     `json:"config"`
+	Status         string                `json:"status"`
+	Total          int                   `json:"total"`
+	Passed         int                   `json:"passed"`
+	Failed         int                   `json:"failed"`
+	Errored        int                   `json:"errored"`
+	PassRate       float64               `json:"pass_rate"` // 通过数/用例数
+	TotalTokens    int                   `json:"total_tokens"`
+	DurationMs     int64                 `json:"duration_ms"`
+	Results        []*EvalResultResponse `json:"results,omitempty"` // 列表中不返回
+	CreatedAt      time.Time             `json:"created_at"`
+}
+
+// EvalResultResponse 用例结果响应
+type EvalResultResponse struct {
+	ID         uint64         `json:"id"`
+	CaseID     uint64         `json:"case_id"`
+	CaseName   string         `json:"case_name"`
+	Prompt     string         `json:"prompt"`
+	Content    string         `json:"content"`
+	Status     string         `json:"status"`
+	Error      string         `json:"error,omitempty"`
+	Checks     []*eval.Result `json:"checks"`
+	Usage      provider.Usage `json:"usage"`
+	DurationMs int64          `json:"duration_ms"`
+}
+
+// SetCases 使用请求内容替换用例
+func (d *EvalDataset) SetCases(cases []*EvalCaseRequest) {
+	d.Cases = make([]*EvalCase, len(cases))
+	for i, c := range cases {
+		variables, _ := json.Marshal(c.Variables)
+		assertions, _ := json.Marshal(c.Assertions)
+		d.Cases[i] = &EvalCase{
+			DatasetID:  d.ID,
+			Position:   i + 1,
+			Name:       c.Name,
+			Variables:  string(variables),
+			Assertions: string(assertions),
+		}
+	}
+}
+
+// VariableValues 解析用例的变量值
+func (c *EvalCase) VariableValues() map[string]interface{} {
+	values := map[string]interface{}{}
+	if c.Variables != "" {
+		_ = json.Unmarshal([]byte(c.Variables), &values)
+	}
+	return values
+}
+
+// AssertionList 解析用例的断言
+func (c *EvalCase) AssertionList() []*eval.Assertion {
+	var assertions []*eval.Assertion
+	if c.Assertions != "" {
+		_ = json.Unmarshal([]byte(c.Assertions), &assertions)
+	}
+	return assertions
+}
+
+// ToResponse 转换为响应格式，withCases 为 false 时不返回用例
+func (d *EvalDataset) ToResponse(withCases bool) *EvalDatasetResponse {
+	resp := &EvalDatasetResponse{
+		ID:          d.ID,
+		ElementID:   d.ElementID,
+		Name:        d.Name,
+		Description: d.Description,
+		CaseCount:   len(d.Cases),
+		CreatedAt:   d.CreatedAt,
+		UpdatedAt:   d.UpdatedAt,
+	}
+	if withCases {
+		resp.Cases = make([]*EvalCaseResponse, len(d.Cases))
+		for i, c := range d.Cases {
+			resp.Cases[i] = &EvalCaseResponse{
+				ID:         c.ID,
+				Position:   c.Position,
+				Name:       c.Name,
+				Variables:  c.VariableValues(),
+				Assertions: c.AssertionList(),
+			}
+		}
+	}
+	return resp
+}
+
+// SetChecks 保存断言结果
+func (r *EvalResult) SetChecks(checks []*eval.Result) {
+	data, _ := json.Marshal(checks)
+	r.Checks = string(data)
+}
+
+// AddResult 追加用例结果并更新统计
+func (r *EvalRun) AddResult(result *EvalResult) {
+	r.Results = append(r.Results, result)
+	r.TotalTokens += result.TotalTokens
+	switch result.Status {
+	case eval.StatusPassed:
+		r.Passed++
+	case eval.StatusFailed:
+		r.Failed++
+	default:
+		r.Errored++
+	}
+}
+
+// ToResponse 转换为响应格式，withResults 为 false 时不返回用例结果
+func (r *EvalRun) ToResponse(withResults bool) *EvalRunResponse {
+	config := &GenerationConfig{}
+	if r.Config != "" {
+		_ = json.Unmarshal([]byte(r.Config), config)
+	}
+
+	resp := &EvalRunResponse{
+		ID:             r.ID,
+		DatasetID:      r.DatasetID,
+		ElementID:      r.ElementID,
+		ElementVersion: r.ElementVersion,
+		Provider:       r.Provider,
+		Model:          r.Model,
+		JudgeProvider:  r.JudgeProvider,
+		JudgeModel:     r.JudgeModel,
+		Format:         r.Format,
+		Config:         config,
+		Status:         r.Status,
+		Total:          r.Total,
+		Passed:         r.Passed,
+		Failed:         r.Failed,
+		Errored:        r.Errored,
+		TotalTokens:    r.TotalTokens,
+		DurationMs:     r.DurationMs,
+		CreatedAt:      r.CreatedAt,
+	}
+	if r.Total > 0 {
+		resp.PassRate = float64(r.Passed) / float64(r.Total)
+	}
+	if withResults {
+		resp.Results = make([]*EvalResultResponse, len(r.Results))
+		for i, result := range r.Results {
+			var checks []*eval.Result
+			if result.Checks != "" {
+				_ = json.Unmarshal([]byte(result.Checks), &checks)
+			}
+			resp.Results[i] = &EvalResultResponse{
+				ID:       result.ID,
+				CaseID:   result.CaseID,
+				CaseName: result.CaseName,
+				Prompt:   result.Prompt,
+				Content:  result.Content,
+				Status:   result.Status,
+				Error:    result.Error,
+				Checks:   checks,
+				Usage: provider.Usage{
+					PromptTokens:     result.PromptTokens,
+					CompletionTokens: result.CompletionTokens,
+					TotalTokens:      result.TotalTokens,
+				},
+				DurationMs: result.DurationMs,
+			}
+		}
+	}
+	return resp
+}
@@ -0,0 +1,142 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// Folder 文件夹模型（支持多级嵌套）
+type Folder struct {
+	ID        uint64    `json:"id" gorm:"primaryKey;autoIncrement;comment:文件夹ID"`
+	UserID    uint64    `json:"user_id" gorm:"not null;index;comment:用户ID"`
+	ParentID  *uint64   `json:"parent_id" gorm:"index;comment:父文件夹ID，为空表示根目录"`
+	Name      string    `json:"name" gorm:"type:varchar(100);not null;comment:文件夹名称"`
+	CreatedAt time.Time `json:"created_at" gorm:"comment:创建时间"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"comment:更新时间"`
+}
+
+// TableName 指定表名
+func (Folder) TableName() string {
+	return "cese_folder"
+}
+
+// 删除文件夹方式
+const (
+	FolderDeleteCascade  = "cascade"  // 同时删除子文件夹及其中的六要素
+	FolderDeleteReparent = "reparent" // 子文件夹及六要素移动到上级文件夹
+)
+
+// FolderCreateRequest 创建文件夹请求
+type FolderCreateRequest struct {
+	Name     string  `json:"name" binding:"required" validate:"required,max=100"`
+	ParentID *uint64 `json:"parent_id"`
+}
+
+// FolderRenameRequest 重命名文件夹请求
+type FolderRenameRequest struct {
+	Name string `json:"name" binding:"required" validate:"required,max=100"`
+}
+
+// FolderMoveRequest 移动文件夹请求
+type FolderMoveRequest struct {
+	ParentID *uint64 `json:"parent_id"` // 为空表示移动到根目录
+}
+
+// FolderDeleteRequest 删除文件夹请求
+type FolderDeleteRequest struct {
+	Mode string `form:"mode" validate:"omitempty,oneof=cascade reparent"`
+}
+
+// ContextElementMoveRequest 移动六要素到文件夹请求
+type ContextElementMoveRequest struct {
+	IDs      []uint64 `json:"ids" validate:"max=500"`
+	FolderID *uint64  `json:"folder_id"` // 为空表示移动到根目录
+}
+
+// FolderResponse 文件夹响应
+type FolderResponse struct {
+	ID           uint64            `json:"id"`
+	ParentID     *uint64           `json:"parent_id"`
+	Name         string            `json:"name"`
+	ElementCount int64             `json:"element_count"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+	Children     []*FolderResponse `json:"children,omitempty"`
+}
+
+// ToResponse 转换为响应格式
+func (f *Folder) ToResponse() *FolderResponse {
+	return &FolderResponse{
+		ID:        f.ID,
+		ParentID:  f.ParentID,
+		Name:      f.Name,
+		CreatedAt: f.CreatedAt,
+		UpdatedAt: f.UpdatedAt,
+	}
+}
+
+// BuildFolderTree 将文件夹列表组装为树形结构
+func BuildFolderTree(folders []*Folder, counts map[uint64]int64) []*FolderResponse {
+	nodes := make(map[uint64]*FolderResponse, len(folders))
+	for _, folder := range folders {
+		node := folder.ToResponse()
+		node.ElementCount = counts[folder.ID]
+		nodes[folder.ID] = node
+	}
+
+	roots := make([]*FolderResponse, 0)
+	for _, folder := range folders {
+		node := nodes[folder.ID]
+		if folder.ParentID != nil {
+			if parent, ok := nodes[*folder.ParentID]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+	return roots
+}
+
+// InParent 判断文件夹是否位于指定的上级文件夹下（parentID 为空表示根目录）
+func (f *Folder) InParent(parentID *uint64) bool {
+	if f.ParentID == nil || parentID == nil {
+		return f.ParentID == nil && parentID == nil
+	}
+	return *f.ParentID == *parentID
+}
+
+// DescendantFolderIDs 获取指定文件夹及其全部子孙文件夹ID
+func DescendantFolderIDs(folders []*Folder, rootID uint64) []uint64 {
+	children := make(map[uint64][]uint64, len(folders))
+	for _, folder := range folders {
+		if folder.ParentID != nil {
+			children[*folder.ParentID] = append(children[*folder.ParentID], folder.ID)
+		}
+	}
+
+	ids := []uint64{rootID}
+	for i := 0; i < len(ids); i++ {
+		ids = append(ids, children[ids[i]]...)
+	}
+	return ids
+}
+
+// ReparentNameConflicts 删除文件夹并将子文件夹移动到上级时，返回与上级文件夹中已有文件夹重名的子文件夹名称
+// 名称比较不区分大小写，与数据库的排序规则一致
+func ReparentNameConflicts(folders []*Folder, folder *Folder) []string {
+	siblings := make(map[string]bool)
+	for _, f := range folders {
+		if f.ID != folder.ID && f.InParent(folder.ParentID) {
+			siblings[strings.ToLower(f.Name)] = true
+		}
+	}
+
+	var conflicts []string
+	for _, f := range folders {
+		if f.InParent(&folder.ID) && siblings[strings.ToLower(f.Name)] {
+			conflicts = append(conflicts, f.Name)
+		}
+	}
+	return conflicts
+}
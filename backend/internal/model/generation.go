@@ -0,0 +1,86 @@
+package model
+
+import (
+	"cese-backend/pkg/prompt"
+	"cese-backend/pkg/provider"
+)
+
+// GenerateRequest 生成内容请求，采样参数为空时使用模型服务的默认值
+type GenerateRequest struct {
+	Provider         string                 `json:"provider" validate:"max=50"` // 为空时使用默认模型服务
+	Model            string                 `json:"model" validate:"max=100"`   // 为空时使用模型服务的第一个模型
+	Temperature      *float64               `json:"temperature" validate:"omitempty,min=0,max=2"`
+	MaxTokens        int                    `json:"max_tokens" validate:"min=0"`
+	TopP             *float64               `json:"top_p" validate:"omitempty,min=0,max=1"`
+	FrequencyPenalty *float64               `json:"frequency_penalty" validate:"omitempty,min=-2,max=2"`
+	PresencePenalty  *float64               `json:"presence_penalty" validate:"omitempty,min=-2,max=2"`
+	Format           string                 `json:"format" validate:"omitempty,oneof=markdown text xml json"` // 渲染提示词的格式
+	Variables        map[string]interface{} `json:"variables"`                                                // 模板变量的值
+}
+
+// GenerateResponse 生成结果
+type GenerateResponse struct {
+	RecordID       uint64                  `json:"record_id,omitempty"` // 生成记录ID
+	ElementID      uint64                  `json:"element_id"`
+	ElementVersion uint64                  `json:"element_version"` // 生成时六要素的版本号
+	Provider       string                  `json:"provider"`
+	Model          string                  `json:"model"`
+	Format         string                  `json:"format"`
+	Prompt         string                  `json:"prompt"` // 发送给模型的提示词
+	Content        string                  `json:"content"`
+	FinishReason   string                  `json:"finish_reason"`
+	Usage          provider.Usage          `json:"usage"`
+	DurationMs     int64                   `json:"duration_ms"`
+	Errors         []*prompt.VariableError `json:"errors,omitempty"` // 变量校验错误
+}
+
+// ProviderResponse 可用的模型服务
+type ProviderResponse struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	Models  []string `json:"models"`
+	Default bool     `json:"default"`
+}
+
+// ToProviderRequest 转换为模型服务的请求，提示词作为用户消息发送
+func (req *GenerateRequest) ToProviderRequest(model, content string, maxTokens int) *provider.Request {
+	return &provider.Request{
+		Model:            model,
+		Messages:         []provider.Message{{Role: provider.RoleUser, Content: content}},
+		Temperature:      req.Temperature,
+		MaxTokens:        maxTokens,
+		TopP:             req.TopP,
+		FrequencyPenalty: req.FrequencyPenalty,
+		PresencePenalty:  req.PresencePenalty,
+	}
+}
+
+// SamplingConfig 生成时使用的采样参数
+func (req *GenerateRequest) SamplingConfig(maxTokens int) *GenerationConfig {
+	return &GenerationConfig{
+		Temperature:      req.Temperature,
+		MaxTokens:        maxTokens,
+		TopP:             req.TopP,
+		FrequencyPenalty: req.FrequencyPenalty,
+		PresencePenalty:  req.PresencePenalty,
+	}
+}
+
+// 流式生成的 SSE 事件名称
+const (
+	GenerateEventDelta = "delta" // 新生成的一段内容
+	GenerateEventUsage = "usage" // 令牌用量
+	GenerateEventDone  = "done"  // 生成完成，数据为完整的生成结果
+	GenerateEventError = "error" // 生成失败或已取消
+)
+
+// GenerateDeltaEvent delta 事件的数据
+type GenerateDeltaEvent struct {
+	Content string `json:"content"`
+}
+
+// GenerateErrorEvent error 事件的数据
+type GenerateErrorEvent struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
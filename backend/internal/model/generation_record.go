@@ -0,0 +1,174 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"cese-backend/pkg/provider"
+)
+
+// 生成记录状态
+const (
+	GenerationStatusCompleted = "completed" // 生成完成
+	GenerationStatusFailed    = "failed"    // 模型服务出错或超时
+	GenerationStatusCanceled  = "canceled"  // 客户端取消
+)
+
+// GenerationRecord 生成记录模型，保存发送给模型的提示词快照和生成结果
+type GenerationRecord struct {
+	ID               uint64    `json:"id" gorm:"primaryKey;autoIncrement;comment:生成记录ID"`
+	UserID           uint64    `json:"user_id" gorm:"not null;index:idx_user_created;comment:用户ID"`
+	ElementID        uint64    `json:"element_id" gorm:"not null;index;comment:六要素ID"`
+	ElementVersion   uint64    `json:"element_version" gorm:"not null;default:0;comment:生成时六要素的版本号"`
+	Provider         string    `json:"provider" gorm:"type:varchar(50);not null;index;comment:模型服务名称"`
+	Model            string    `json:"model" gorm:"type:varchar(100);not null;index;comment:模型名称"`
+	Format           string    `json:"format" gorm:"type:varchar(20);not null;comment:提示词格式"`
+	Prompt           string    `json:"prompt" gorm:"type:mediumtext;comment:发送给模型的提示词"`
+	Variables        string    `json:"-" gorm:"type:text;comment:模板变量的值(JSON)"`
+	Config           string    `json:"-" gorm:"type:text;comment:采样参数(JSON)"`
+	Content          string    `json:"content" gorm:"type:mediumtext;comment:生成的内容"`
+	FinishReason     string    `json:"finish_reason" gorm:"type:varchar(50);comment:结束原因"`
+	Status           string    `json:"status" gorm:"type:varchar(20);not null;comment:状态"`
+	Error            string    `json:"error" gorm:"type:text;comment:错误信息"`
+	PromptTokens     int       `json:"prompt_tokens" gorm:"not null;default:0;comment:输入令牌数"`
+	CompletionTokens int       `json:"completion_tokens" gorm:"not null;default:0;comment:输出令牌数"`
+	TotalTokens      int       `json:"total_tokens" gorm:"not null;default:0;comment:总令牌数"`
+	DurationMs       int64     `json:"duration_ms" gorm:"not null;default:0;comment:耗时(毫秒)"`
+	Starred          bool      `json:"starred" gorm:"not null;default:false;comment:是否收藏"`
+	Note             string    `json:"note" gorm:"type:text;comment:备注"`
+	CreatedAt        time.Time `json:"created_at" gorm:"index:idx_user_created;comment:创建时间"`
+	UpdatedAt        time.Time `json:"updated_at" gorm:"comment:更新时间"`
+}
+
+// TableName 指定表名
+func (GenerationRecord) TableName() string {
+	return "cese_generation_record"
+}
+
+// GenerationConfig 生成时使用的采样参数
+type GenerationConfig struct {
+	Temperature      *float64 `json:"temperature,omitempty"`
+	MaxTokens        int      `json:"max_tokens"`
+	TopP             *float64 `json:"top_p,omitempty"`
+	FrequencyPenalty *float64 `json:"frequency_penalty,omitempty"`
+	PresencePenalty  *float64 `json:"presence_penalty,omitempty"`
+}
+
+// GenerationRecordQueryRequest 查询生成记录请求
+type GenerationRecordQueryRequest struct {
+	Page      int    `form:"page" validate:"min=1"`
+	Size      int    `form:"size" validate:"min=1,max=100"`
+	ElementID uint64 `form:"element_id"`
+	Provider  string `form:"provider" validate:"max=50"`
+	Model     string `form:"model" validate:"max=100"`
+	Status    string `form:"status" validate:"omitempty,oneof=completed failed canceled"`
+	Starred   *bool  `form:"starred"`
+	StartDate string `form:"start_date" validate:"omitempty,datetime=2006-01-02"` // 创建日期起（含）
+	EndDate   string `form:"end_date" validate:"omitempty,datetime=2006-01-02"`   // 创建日期止（含）
+	SortBy    string `form:"sort_by" validate:"oneof=created_at duration_ms total_tokens"`
+	SortDesc  bool   `form:"sort_desc"`
+}
+
+// DateRange 解析创建日期范围，返回 [since, before)，未指定的一端为 nil
+func (req *GenerationRecordQueryRequest) DateRange() (since, before *time.Time) {
+	if t, err := time.ParseInLocation("2006-01-02", req.StartDate, time.Local); err == nil {
+		since = &t
+	}
+	if t, err := time.ParseInLocation("2006-01-02", req.EndDate, time.Local); err == nil {
+		t = t.AddDate(0, 0, 1)
+		before = &t
+	}
+	return since, before
+}
+
+// GenerationRecordUpdateRequest 更新生成记录请求（收藏和备注），字段为空时不修改
+type GenerationRecordUpdateRequest struct {
+	Starred *bool   `json:"starred"`
+	Note    *string `json:"note" validate:"omitempty,max=5000"`
+}
+
+// GenerationRecordResponse 生成记录响应
+type GenerationRecordResponse struct {
+	ID             uint64                 `json:"id"`
+	ElementID      uint64                 `json:"element_id"`
+	ElementVersion uint64                 `json:"element_version"`
+	Provider       string                 `json:"provider"`
+	Model          string                 `json:"model"`
+	Format         string                 `json:"format"`
+	Prompt         string                 `json:"prompt"`
+	Variables      map[string]interface{} `json:"variables"`
+	Config         *GenerationConfig      `json:"config"`
+	Content        string                 `json:"content"`
+	FinishReason   string                 `json:"finish_reason"`
+	Status         string                 `json:"status"`
+	Error          string                 `json:"error,omitempty"`
+	Usage          provider.Usage         `json:"usage"`
+	DurationMs     int64                  `json:"duration_ms"`
+	Starred        bool                   `json:"starred"`
+	Note           string                 `json:"note"`
+	CreatedAt      time.Time              `json:"created_at"`
+	UpdatedAt      time.Time              `json:"updated_at"`
+}
+
+// NewGenerationRecord 根据生成请求和结果创建生成记录
+func NewGenerationRecord(userID uint64, req *GenerateRequest, result *GenerateResponse, maxTokens int) *GenerationRecord {
+	variables, _ := json.Marshal(req.Variables)
+	config, _ := json.Marshal(req.SamplingConfig(maxTokens))
+
+	return &GenerationRecord{
+		UserID:           userID,
+		ElementID:        result.ElementID,
+		ElementVersion:   result.ElementVersion,
+		Provider:         result.Provider,
+		Model:            result.Model,
+		Format:           result.Format,
+		Prompt:           result.Prompt,
+		Variables:        string(variables),
+		Config:           string(config),
+		Content:          result.Content,
+		FinishReason:     result.FinishReason,
+		Status:           GenerationStatusCompleted,
+		PromptTokens:     result.Usage.PromptTokens,
+		CompletionTokens: result.Usage.CompletionTokens,
+		TotalTokens:      result.Usage.TotalTokens,
+		DurationMs:       result.DurationMs,
+	}
+}
+
+// ToResponse 转换为响应格式
+func (r *GenerationRecord) ToResponse() *GenerationRecordResponse {
+	var variables map[string]interface{}
+	if r.Variables != "" {
+		_ = json.Unmarshal([]byte(r.Variables), &variables)
+	}
+	config := &GenerationConfig{}
+	if r.Config != "" {
+		_ = json.Unmarshal([]byte(r.Config), config)
+	}
+
+	return &GenerationRecordResponse{
+		ID:             r.ID,
+		ElementID:      r.ElementID,
+		ElementVersion: r.ElementVersion,
+		Provider:       r.Provider,
+		Model:          r.Model,
+		Format:         r.Format,
+		Prompt:         r.Prompt,
+		Variables:      variables,
+		Config:         config,
+		Content:        r.Content,
+		FinishReason:   r.FinishReason,
+		Status:         r.Status,
+		Error:          r.Error,
+		Usage: provider.Usage{
+			PromptTokens:     r.PromptTokens,
+			CompletionTokens: r.CompletionTokens,
+			TotalTokens:      r.TotalTokens,
+		},
+		DurationMs: r.DurationMs,
+		Starred:    r.Starred,
+		Note:       r.Note,
+		CreatedAt:  r.CreatedAt,
+		UpdatedAt:  r.UpdatedAt,
+	}
+}
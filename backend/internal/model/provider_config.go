@@ -0,0 +1,115 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ProviderConfig 用户保存的模型服务配置，API Key 加密存储
+type ProviderConfig struct {
+	ID           uint64    `json:"id" gorm:"primaryKey;autoIncrement;comment:模型服务配置ID"`
+	UserID       uint64    `json:"user_id" gorm:"not null;uniqueIndex:idx_user_provider_name;comment:用户ID"`
+	Name         string    `json:"name" gorm:"type:varchar(50);not null;uniqueIndex:idx_user_provider_name;comment:名称"`
+	Type         string    `json:"type" gorm:"type:varchar(20);not null;comment:提供商类型"`
+	BaseURL      string    `json:"base_url" gorm:"type:varchar(500);not null;comment:接口地址"`
+	Models       string    `json:"-" gorm:"type:text;comment:可用模型(JSON)"`
+	Enabled      bool      `json:"enabled" gorm:"not null;default:true;comment:是否启用"`
+	ExtraConfig  string    `json:"-" gorm:"type:text;comment:其他配置(JSON)"`
+	APIKeyCipher string    `json:"-" gorm:"type:text;comment:加密的API Key"`
+	APIKeyID     string    `json:"-" gorm:"type:varchar(16);index;comment:加密API Key使用的密钥ID"`
+	APIKeyMask   string    `json:"-" gorm:"type:varchar(20);comment:API Key掩码"`
+	CreatedAt    time.Time `json:"created_at" gorm:"comment:创建时间"`
+	UpdatedAt    time.Time `json:"updated_at" gorm:"comment:更新时间"`
+}
+
+// TableName 指定表名
+func (ProviderConfig) TableName() string {
+	return "cese_provider_config"
+}
+
+// ProviderConfigCreateRequest 创建模型服务配置请求
+type ProviderConfigCreateRequest struct {
+	Name        string                 `json:"name" binding:"required" validate:"required,max=50"`
+	Type        string                 `json:"type" binding:"required" validate:"required,oneof=openai baidu alibaba tencent bytedance ollama"`
+	BaseURL     string                 `json:"base_url" binding:"required" validate:"required,url,max=500"`
+	APIKey      string                 `json:"api_key" validate:"max=500"`
+	Models      []string               `json:"models" validate:"max=50,dive,required,max=100"` // 第一个为默认模型
+	Enabled     *bool                  `json:"enabled"`                                        // 默认启用
+	ExtraConfig map[string]interface{} `json:"extra_config"`                                   // 其他配置，如 API 版本、组织ID
+}
+
+// ProviderConfigUpdateRequest 更新模型服务配置请求（整体替换），api_key 省略时保持不变，为空字符串时清除
+type ProviderConfigUpdateRequest struct {
+	Name        string                 `json:"name" binding:"required" validate:"required,max=50"`
+	Type        string                 `json:"type" binding:"required" validate:"required,oneof=openai baidu alibaba tencent bytedance ollama"`
+	BaseURL     string                 `json:"base_url" binding:"required" validate:"required,url,max=500"`
+	APIKey      *string                `json:"api_key" validate:"omitempty,max=500"`
+	Models      []string               `json:"models" validate:"max=50,dive,required,max=100"`
+	Enabled     bool                   `json:"enabled"`
+	ExtraConfig map[string]interface{} `json:"extra_config"`
+}
+
+// ProviderConfigResponse 模型服务配置响应，不包含 API Key 明文
+type ProviderConfigResponse struct {
+	ID           uint64                 `json:"id"`
+	Name         string                 `json:"name"`
+	Type         string                 `json:"type"`
+	BaseURL      string                 `json:"base_url"`
+	Models       []string               `json:"models"`
+	Enabled      bool                   `json:"enabled"`
+	ExtraConfig  map[string]interface{} `json:"extra_config"`
+	HasAPIKey    bool                   `json:"has_api_key"`
+	APIKeyMasked string                 `json:"api_key_masked,omitempty"` // 如 ****c3d4
+	CreatedAt    time.Time              `json:"created_at"`
+	UpdatedAt    time.Time              `json:"updated_at"`
+}
+
+// Apply 使用请求内容覆盖配置（不含 API Key）
+func (p *ProviderConfig) Apply(name, providerType, baseURL string, models []string, enabled bool, extra map[string]interface{}) {
+	if models == nil {
+		models = []string{}
+	}
+	if extra == nil {
+		extra = map[string]interface{}{}
+	}
+	modelsJSON, _ := json.Marshal(models)
+	extraJSON, _ := json.Marshal(extra)
+
+	p.Name = name
+	p.Type = providerType
+	p.BaseURL = baseURL
+	p.Models = string(modelsJSON)
+	p.Enabled = enabled
+	p.ExtraConfig = string(extraJSON)
+}
+
+// ModelList 解析可用模型
+func (p *ProviderConfig) ModelList() []string {
+	models := []string{}
+	if p.Models != "" {
+		_ = json.Unmarshal([]byte(p.Models), &models)
+	}
+	return models
+}
+
+// ToResponse 转换为响应格式
+func (p *ProviderConfig) ToResponse() *ProviderConfigResponse {
+	extra := map[string]interface{}{}
+	if p.ExtraConfig != "" {
+		_ = json.Unmarshal([]byte(p.ExtraConfig), &extra)
+	}
+
+	return &ProviderConfigResponse{
+		ID:           p.ID,
+		Name:         p.Name,
+		Type:         p.Type,
+		BaseURL:      p.BaseURL,
+		Models:       p.ModelList(),
+		Enabled:      p.Enabled,
+		ExtraConfig:  extra,
+		HasAPIKey:    p.APIKeyCipher != "",
+		APIKeyMasked: p.APIKeyMask,
+		CreatedAt:    p.CreatedAt,
+		UpdatedAt:    p.UpdatedAt,
+	}
+}
@@ -0,0 +1,100 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// Tag 标签模型（按用户隔离）
+type Tag struct {
+	ID        uint64    `json:"id" gorm:"primaryKey;autoIncrement;comment:标签ID"`
+	UserID    uint64    `json:"user_id" gorm:"not null;uniqueIndex:idx_user_tag_name;comment:用户ID"`
+	Name      string    `json:"name" gorm:"type:varchar(50);not null;uniqueIndex:idx_user_tag_name;comment:标签名"`
+	CreatedAt time.Time `json:"created_at" gorm:"comment:创建时间"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"comment:更新时间"`
+}
+
+// TableName 指定表名
+func (Tag) TableName() string {
+	return "cese_tag"
+}
+
+// TagWithCount 标签及其关联的六要素数量
+type TagWithCount struct {
+	Tag
+	Count int64 `json:"count"`
+}
+
+// TagCreateRequest 创建标签请求
+type TagCreateRequest struct {
+	Name string `json:"name" binding:"required" validate:"required,max=50"`
+}
+
+// TagRenameRequest 重命名标签请求
+type TagRenameRequest struct {
+	Name string `json:"name" binding:"required" validate:"required,max=50"`
+}
+
+// TagMergeRequest 合并标签请求
+type TagMergeRequest struct {
+	SourceIDs []uint64 `json:"source_ids" binding:"required" validate:"required,min=1,max=100"`
+	TargetID  uint64   `json:"target_id" binding:"required" validate:"required"`
+}
+
+// TagResponse 标签响应
+type TagResponse struct {
+	ID        uint64    `json:"id"`
+	Name      string    `json:"name"`
+	Count     int64     `json:"count"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ToResponse 转换为响应格式
+func (t *Tag) ToResponse() *TagResponse {
+	return &TagResponse{
+		ID:        t.ID,
+		Name:      t.Name,
+		CreatedAt: t.CreatedAt,
+		UpdatedAt: t.UpdatedAt,
+	}
+}
+
+// ToResponse 转换为响应格式
+func (t *TagWithCount) ToResponse() *TagResponse {
+	resp := t.Tag.ToResponse()
+	resp.Count = t.Count
+	return resp
+}
+
+// NormalizeTagNames 去除标签名首尾空白、空值和重复项
+func NormalizeTagNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		normalized = append(normalized, name)
+	}
+	return normalized
+}
+
+// NewTags 按名称构造尚未保存的标签，由Repository在写入六要素的事务中查找或创建
+func NewTags(userID uint64, names []string) []Tag {
+	tags := make([]Tag, len(names))
+	for i, name := range names {
+		tags[i] = Tag{UserID: userID, Name: name}
+	}
+	return tags
+}
+
+// SplitTagNames 解析逗号分隔的标签名
+func SplitTagNames(value string) []string {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	return NormalizeTagNames(strings.Split(value, ","))
+}
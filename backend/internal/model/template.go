@@ -0,0 +1,169 @@
+package model
+
+import (
+	"time"
+
+	"cese-backend/pkg/prompt"
+
+	"gorm.io/gorm"
+)
+
+// Template 公共模板模型（发布时的六要素快照）
+type Template struct {
+	ID               uint64            `json:"id" gorm:"primaryKey;autoIncrement;comment:模板ID"`
+	UserID           uint64            `json:"user_id" gorm:"not null;index;comment:发布者用户ID"`
+	ElementID        uint64            `json:"element_id" gorm:"not null;uniqueIndex;comment:来源六要素ID"`
+	SourceTemplateID *uint64           `json:"source_template_id" gorm:"index;comment:来源六要素派生自的模板ID"`
+	Subject          string            `json:"subject" gorm:"type:varchar(255);not null;index;comment:主题"`
+	Description      string            `json:"description" gorm:"type:varchar(500);comment:模板描述"`
+	Category         string            `json:"category" gorm:"type:varchar(50);not null;index;comment:模板分类"`
+	TaskGoal         string            `json:"task_goal" gorm:"type:text;comment:任务目标"`
+	AIRole           string            `json:"ai_role" gorm:"type:text;comment:AI的角色"`
+	MyRole           string            `json:"my_role" gorm:"type:text;comment:我的角色"`
+	KeyInfo          string            `json:"key_info" gorm:"type:text;comment:关键信息"`
+	BehaviorRule     string            `json:"behavior_rule" gorm:"type:text;comment:行为规则"`
+	DeliveryFormat   string            `json:"delivery_format" gorm:"type:text;comment:交付格式"`
+	Tags             []string          `json:"tags" gorm:"type:text;serializer:json;comment:标签名"`
+	Variables        []prompt.Variable `json:"variables" gorm:"type:text;serializer:json;comment:变量定义"`
+	ForkCount        int64             `json:"fork_count" gorm:"not null;default:0;index;comment:复刻次数"`
+	CreatedAt        time.Time         `json:"created_at" gorm:"index;comment:发布时间"`
+	UpdatedAt        time.Time         `json:"updated_at" gorm:"comment:更新时间"`
+	DeletedAt        gorm.DeletedAt    `json:"-" gorm:"index;comment:取消发布时间"`
+}
+
+// TableName 指定表名
+func (Template) TableName() string {
+	return "cese_template"
+}
+
+// TemplatePublishRequest 发布模板请求
+type TemplatePublishRequest struct {
+	Description string `json:"description" validate:"max=500"`
+	Category    string `json:"category" binding:"required" validate:"required,max=50"`
+}
+
+// TemplateQueryRequest 查询公共模板请求
+type TemplateQueryRequest struct {
+	Page     int    `form:"page" validate:"min=1"`
+	Size     int    `form:"size" validate:"min=1,max=100"`
+	Keyword  string `form:"keyword" validate:"max=255"`
+	Category string `form:"category" validate:"max=50"`
+	Mine     bool   `form:"mine"` // 仅查询自己发布的模板（需要登录）
+	SortBy   string `form:"sort_by" validate:"oneof=created_at updated_at fork_count"`
+	SortDesc bool   `form:"sort_desc"`
+}
+
+// TemplateForkRequest 复刻模板请求
+type TemplateForkRequest struct {
+	Subject  string  `json:"subject" validate:"max=255"` // 为空时沿用模板主题
+	FolderID *uint64 `json:"folder_id"`                  // 为空表示根目录
+}
+
+// TemplateCategoryResponse 模板分类响应
+type TemplateCategoryResponse struct {
+	Category string `json:"category"`
+	Count    int64  `json:"count"`
+}
+
+// TemplateResponse 公共模板响应
+type TemplateResponse struct {
+	ID               uint64            `json:"id"`
+	UserID           uint64            `json:"user_id"`
+	ElementID        uint64            `json:"element_id"`
+	SourceTemplateID *uint64           `json:"source_template_id"`
+	Subject          string            `json:"subject"`
+	Description      string            `json:"description"`
+	Category         string            `json:"category"`
+	TaskGoal         string            `json:"task_goal"`
+	AIRole           string            `json:"ai_role"`
+	MyRole           string            `json:"my_role"`
+	KeyInfo          string            `json:"key_info"`
+	BehaviorRule     string            `json:"behavior_rule"`
+	DeliveryFormat   string            `json:"delivery_format"`
+	Tags             []string          `json:"tags"`
+	Variables        []prompt.Variable `json:"variables"`
+	ForkCount        int64             `json:"fork_count"`
+	CreatedAt        time.Time         `json:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+}
+
+// ToResponse 转换为响应格式
+func (t *Template) ToResponse() *TemplateResponse {
+	tags := t.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	variables := t.Variables
+	if variables == nil {
+		variables = []prompt.Variable{}
+	}
+
+	return &TemplateResponse{
+		ID:               t.ID,
+		UserID:           t.UserID,
+		ElementID:        t.ElementID,
+		SourceTemplateID: t.SourceTemplateID,
+		Subject:          t.Subject,
+		Description:      t.Description,
+		Category:         t.Category,
+		TaskGoal:         t.TaskGoal,
+		AIRole:           t.AIRole,
+		MyRole:           t.MyRole,
+		KeyInfo:          t.KeyInfo,
+		BehaviorRule:     t.BehaviorRule,
+		DeliveryFormat:   t.DeliveryFormat,
+		Tags:             tags,
+		Variables:        variables,
+		ForkCount:        t.ForkCount,
+		CreatedAt:        t.CreatedAt,
+		UpdatedAt:        t.UpdatedAt,
+	}
+}
+
+// PublishFrom 用六要素当前内容和变量定义刷新模板快照
+func (t *Template) PublishFrom(ce *ContextElement, variables []*ContextElementVariable, req *TemplatePublishRequest) {
+	t.UserID = ce.UserID
+	t.ElementID = ce.ID
+	t.SourceTemplateID = ce.SourceTemplateID
+	t.Subject = ce.Subject
+	t.Description = req.Description
+	t.Category = req.Category
+	t.TaskGoal = ce.TaskGoal
+	t.AIRole = ce.AIRole
+	t.MyRole = ce.MyRole
+	t.KeyInfo = ce.KeyInfo
+	t.BehaviorRule = ce.BehaviorRule
+	t.DeliveryFormat = ce.DeliveryFormat
+
+	t.Tags = make([]string, len(ce.Tags))
+	for i, tag := range ce.Tags {
+		t.Tags[i] = tag.Name
+	}
+
+	t.Variables = make([]prompt.Variable, len(variables))
+	for i, variable := range variables {
+		t.Variables[i] = variable.ToVariable()
+	}
+}
+
+// ToContextElement 将模板复刻为用户的六要素（记录来源模板）
+func (t *Template) ToContextElement(userID uint64, req *TemplateForkRequest) *ContextElement {
+	subject := t.Subject
+	if req.Subject != "" {
+		subject = req.Subject
+	}
+
+	sourceTemplateID := t.ID
+	return &ContextElement{
+		UserID:           userID,
+		FolderID:         req.FolderID,
+		SourceTemplateID: &sourceTemplateID,
+		Subject:          subject,
+		TaskGoal:         t.TaskGoal,
+		AIRole:           t.AIRole,
+		MyRole:           t.MyRole,
+		KeyInfo:          t.KeyInfo,
+		BehaviorRule:     t.BehaviorRule,
+		DeliveryFormat:   t.DeliveryFormat,
+	}
+}
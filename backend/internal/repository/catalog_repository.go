@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"cese-backend/internal/model"
+	"cese-backend/pkg/search"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrCatalogItemExists 同一字段下已有同名目录项
+var ErrCatalogItemExists = errors.New("目录项已存在")
+
+// CatalogRepository 推荐目录数据访问接口
+type CatalogRepository interface {
+	Create(item *model.CatalogItem) error
+	GetByID(id uint64) (*model.CatalogItem, error)
+	GetByName(field, name string) (*model.CatalogItem, error)
+	GetList(field, category, prefix string, limit int) ([]*model.CatalogItem, error)
+	Update(item *model.CatalogItem) error
+	Delete(id uint64) error
+	Seed(items []*model.CatalogItem) (int, error)
+	GetFieldUsage(userID uint64, field, prefix string, limit int) ([]*model.FieldUsage, error)
+}
+
+// catalogRepository 推荐目录数据访问实现
+type catalogRepository struct {
+	db *gorm.DB
+}
+
+// NewCatalogRepository 创建推荐目录Repository实例
+func NewCatalogRepository(db *gorm.DB) CatalogRepository {
+	return &catalogRepository{db: db}
+}
+
+// Create 创建目录项，同一字段下已有同名目录项时返回 ErrCatalogItemExists。
+// 同名目录项已被删除时恢复该记录并覆盖其内容，保留内置数据标识，避免被重新导入。
+func (r *catalogRepository) Create(item *model.CatalogItem) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var deleted model.CatalogItem
+		err := tx.Unscoped().
+			Where("field = ? AND name = ? AND deleted_at IS NOT NULL", item.Field, item.Name).
+			First(&deleted).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.Create(item).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		item.ID = deleted.ID
+		item.SeedKey = deleted.SeedKey
+		item.CreatedAt = now
+		item.UpdatedAt = now
+		return tx.Unscoped().Model(&model.CatalogItem{}).
+			Where("id = ?", deleted.ID).
+			Updates(map[string]interface{}{
+				"category":    item.Category,
+				"description": item.Description,
+				"example":     item.Example,
+				"sort_order":  item.SortOrder,
+				"created_at":  now,
+				"updated_at":  now,
+				"deleted_at":  nil,
+			}).Error
+	})
+	return translateCatalogError(err)
+}
+
+// GetByID 根据ID获取目录项
+func (r *catalogRepository) GetByID(id uint64) (*model.CatalogItem, error) {
+	var item model.CatalogItem
+	err := r.db.Where("id = ?", id).First(&item).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+// GetByName 根据字段和名称获取目录项
+func (r *catalogRepository) GetByName(field, name string) (*model.CatalogItem, error) {
+	var item model.CatalogItem
+	err := r.db.Where("field = ? AND name = ?", field, name).First(&item).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+// GetList 获取目录项，可按字段、分类和名称前缀过滤；limit 为0时不限制数量
+func (r *catalogRepository) GetList(field, category, prefix string, limit int) ([]*model.CatalogItem, error) {
+	query := r.db.Model(&model.CatalogItem{})
+	if field != "" {
+		query = query.Where("field = ?", field)
+	}
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+	if prefix != "" {
+		pattern := search.EscapeLike(prefix) + "%"
+		query = query.Where("name LIKE ? OR example LIKE ?", pattern, pattern)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var items []*model.CatalogItem
+	if err := query.Order("field ASC, sort_order ASC, id ASC").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Update 更新目录项，同一字段下已有同名目录项时返回 ErrCatalogItemExists；
+// 改为已删除目录项的名称时，彻底删除该已删除的目录项
+func (r *catalogRepository) Update(item *model.CatalogItem) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().
+			Where("field = ? AND name = ? AND id <> ? AND deleted_at IS NOT NULL", item.Field, item.Name, item.ID).
+			Delete(&model.CatalogItem{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(item).
+			Select("field", "category", "name", "description", "example", "sort_order", "updated_at").
+			Updates(item).Error
+	})
+	return translateCatalogError(err)
+}
+
+// Delete 删除目录项（软删除，内置数据删除后不会被重新导入）
+func (r *catalogRepository) Delete(id uint64) error {
+	return r.db.Delete(&model.CatalogItem{}, id).Error
+}
+
+// Seed 导入数据库中还没有的内置目录项，已导入过（包括已被删除）的不会覆盖，
+// 同一字段下已有同名目录项（包括已被删除）的也不导入，返回导入数量
+func (r *catalogRepository) Seed(items []*model.CatalogItem) (int, error) {
+	if len(items) == 0 {
+		return 0, nil
+	}
+
+	keys := make([]string, len(items))
+	names := make([]string, len(items))
+	for i, item := range items {
+		keys[i] = *item.SeedKey
+		names[i] = item.Name
+	}
+
+	var existing []string
+	err := r.db.Unscoped().Model(&model.CatalogItem{}).
+		Where("seed_key IN ?", keys).
+		Pluck("seed_key", &existing).Error
+	if err != nil {
+		return 0, err
+	}
+
+	seeded := make(map[string]bool, len(existing))
+	for _, key := range existing {
+		seeded[key] = true
+	}
+
+	var named []*model.CatalogItem
+	err = r.db.Unscoped().Model(&model.CatalogItem{}).
+		Select("field", "name").
+		Where("name IN ?", names).
+		Find(&named).Error
+	if err != nil {
+		return 0, err
+	}
+	// name列的排序规则不区分大小写，按小写比较才能与唯一索引一致
+	taken := make(map[[2]string]bool, len(named))
+	for _, item := range named {
+		taken[catalogNameKey(item)] = true
+	}
+
+	missing := make([]*model.CatalogItem, 0, len(items))
+	for _, item := range items {
+		key := catalogNameKey(item)
+		if !seeded[*item.SeedKey] && !taken[key] {
+			missing = append(missing, item)
+			taken[key] = true
+		}
+	}
+	if len(missing) == 0 {
+		return 0, nil
+	}
+
+	// 排序规则还会忽略尾部空格等差异，仍然冲突的跳过，避免整批失败导致启动中断
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&missing)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return int(result.RowsAffected), nil
+}
+
+// catalogNameKey 同一字段下目录项名称的比较键
+func catalogNameKey(item *model.CatalogItem) [2]string {
+	return [2]string{item.Field, strings.ToLower(item.Name)}
+}
+
+// GetFieldUsage 统计用户六要素中某个字段最常用的内容，可按内容前缀过滤
+func (r *catalogRepository) GetFieldUsage(userID uint64, field, prefix string, limit int) ([]*model.FieldUsage, error) {
+	// 字段名来自白名单，避免拼接任意列名
+	switch field {
+	case model.CatalogFieldAIRole, model.CatalogFieldMyRole, model.CatalogFieldDeliveryFormat:
+	default:
+		return nil, errors.New("不支持的字段: " + field)
+	}
+
+	query := r.db.Model(&model.ContextElement{}).
+		Select("? AS field, "+field+" AS value, COUNT(*) AS count, MAX(updated_at) AS last_used_at", field).
+		Where("user_id = ? AND "+field+" <> ''", userID)
+	if prefix != "" {
+		query = query.Where(field+" LIKE ?", search.EscapeLike(prefix)+"%")
+	}
+
+	var usages []*model.FieldUsage
+	err := query.Group(field).
+		Order("count DESC, last_used_at DESC").
+		Limit(limit).
+		Scan(&usages).Error
+	if err != nil {
+		return nil, err
+	}
+	return usages, nil
+}
+
+// translateCatalogError 将唯一索引冲突转换为 ErrCatalogItemExists
+func translateCatalogError(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrCatalogItemExists
+	}
+	return err
+}
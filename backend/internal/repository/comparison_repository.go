@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"errors"
+
+	"cese-backend/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// ComparisonRepository 对比运行数据访问接口
+type ComparisonRepository interface {
+	Create(run *model.ComparisonRun) error
+	GetByID(id uint64) (*model.ComparisonRun, error)
+	GetList(userID uint64, req *model.ComparisonQueryRequest) ([]*model.ComparisonRun, int64, error)
+	Update(run *model.ComparisonRun) error
+	Delete(id uint64) error
+}
+
+// comparisonRepository 对比运行数据访问实现
+type comparisonRepository struct {
+	db *gorm.DB
+}
+
+// NewComparisonRepository 创建对比运行Repository实例
+func NewComparisonRepository(db *gorm.DB) ComparisonRepository {
+	return &comparisonRepository{db: db}
+}
+
+// Create 创建对比运行及其变体和执行结果
+func (r *comparisonRepository) Create(run *model.ComparisonRun) error {
+	return r.db.Create(run).Error
+}
+
+// GetByID 根据ID获取对比运行，包含变体和执行结果
+func (r *comparisonRepository) GetByID(id uint64) (*model.ComparisonRun, error) {
+	var run model.ComparisonRun
+	err := r.db.
+		Preload("Variants", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Preload("Variants.Outputs", func(db *gorm.DB) *gorm.DB { return db.Order("attempt ASC") }).
+		Where("id = ?", id).First(&run).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &run, nil
+}
+
+// GetList 分页查询用户的对比运行，按创建时间倒序
+func (r *comparisonRepository) GetList(userID uint64, req *model.ComparisonQueryRequest) ([]*model.ComparisonRun, int64, error) {
+	var runs []*model.ComparisonRun
+	var total int64
+
+	query := r.db.Model(&model.ComparisonRun{}).Where("user_id = ?", userID)
+	if req.ElementID != 0 {
+		query = query.Where("id IN (?)", r.db.Model(&model.ComparisonVariant{}).Select("run_id").Where("element_id = ?", req.ElementID))
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.Size
+	err := query.
+		Preload("Variants", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Preload("Variants.Outputs").
+		Order("created_at DESC").Order("id DESC").
+		Offset(offset).Limit(req.Size).Find(&runs).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return runs, total, nil
+}
+
+// Update 更新胜出变体和备注
+func (r *comparisonRepository) Update(run *model.ComparisonRun) error {
+	return r.db.Model(&model.ComparisonRun{ID: run.ID}).Updates(map[string]interface{}{
+		"winner_variant_id": run.WinnerVariantID,
+		"note":              run.Note,
+	}).Error
+}
+
+// Delete 删除对比运行及其变体和执行结果
+func (r *comparisonRepository) Delete(id uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		variantIDs := tx.Model(&model.ComparisonVariant{}).Select("id").Where("run_id = ?", id)
+		if err := tx.Where("variant_id IN (?)", variantIDs).Delete(&model.ComparisonOutput{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("run_id = ?", id).Delete(&model.ComparisonVariant{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.ComparisonRun{}, id).Error
+	})
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"errors"
+
+	"cese-backend/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// errBulkAborted atomic 模式下某条记录写入失败，用于回滚整个事务
+var errBulkAborted = errors.New("批量操作已回滚")
+
+// ElementUpdate 批量更新中的单条记录
+type ElementUpdate struct {
+	Element  *model.ContextElement        // 已应用修改的六要素（Tags 为更新后的全部标签），Version 为读取时的版本号
+	Previous *model.ContextElementVersion // 更新前的版本快照
+}
+
+// BulkCreate 在同一事务中创建多条六要素（含标签关联）
+func (r *contextElementRepository) BulkCreate(elements []*model.ContextElement, atomic bool) ([]error, error) {
+	return r.bulkWrite(len(elements), atomic, func(tx *gorm.DB, i int) error {
+		return createElement(tx, elements[i])
+	})
+}
+
+// BulkUpdate 在同一事务中更新多条六要素，每条都会保存版本快照并按版本号条件更新
+func (r *contextElementRepository) BulkUpdate(updates []*ElementUpdate, atomic bool) ([]error, error) {
+	errs, err := r.bulkWrite(len(updates), atomic, func(tx *gorm.DB, i int) error {
+		update := updates[i]
+		expected := update.Element.Version
+		if err := updateWithVersion(tx, update.Element, update.Previous); err != nil {
+			update.Element.Version = expected
+			return err
+		}
+		if err := replaceTags(tx, update.Element); err != nil {
+			update.Element.Version = expected
+			return err
+		}
+		return nil
+	})
+	return errs, err
+}
+
+// BulkDelete 在同一事务中删除多条六要素（进入回收站），版本号变化的记录返回 ErrVersionConflict
+func (r *contextElementRepository) BulkDelete(elements []*model.ContextElement, atomic bool) ([]error, error) {
+	return r.bulkWrite(len(elements), atomic, func(tx *gorm.DB, i int) error {
+		return deleteWithVersion(tx, elements[i])
+	})
+}
+
+// bulkWrite 在同一事务中依次执行 n 条写操作，返回每条的错误
+//
+// atomic 为 true 时任一条失败即回滚整个事务，之后的记录不再执行；
+// 否则每条在各自的保存点中执行，失败的记录只回滚自身，其余记录照常提交。
+// 第二个返回值为事务本身的错误（如提交失败），此时所有写入都未生效。
+func (r *contextElementRepository) bulkWrite(n int, atomic bool, write func(tx *gorm.DB, i int) error) ([]error, error) {
+	errs := make([]error, n)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for i := 0; i < n; i++ {
+			if atomic {
+				if err := write(tx, i); err != nil {
+					errs[i] = err
+					return errBulkAborted
+				}
+				continue
+			}
+			errs[i] = tx.Transaction(func(tx *gorm.DB) error {
+				return write(tx, i)
+			})
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBulkAborted) {
+		return errs, err
+	}
+	return errs, nil
+}
@@ -2,42 +2,122 @@ package repository
 
 import (
 	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
 
+	"cese-backend/internal/config"
 	"cese-backend/internal/model"
+	"cese-backend/pkg/cursor"
+	"cese-backend/pkg/search"
 
 	"gorm.io/gorm"
 )
 
+// 全文索引覆盖的列（与 createFullTextIndexes 中的索引定义一致）
+const (
+	fullTextOtherColumns = "ai_role, my_role, key_info, behavior_rule, delivery_format"
+	fullTextAllColumns   = "subject, task_goal, " + fullTextOtherColumns
+)
+
+// ngramTokenSize MySQL ngram 解析器的分词长度（ngram_token_size 默认值），更短的关键词无法命中全文索引
+const ngramTokenSize = 2
+
+// ErrVersionConflict 记录已被其他请求修改（乐观锁版本号不一致）
+var ErrVersionConflict = errors.New("版本冲突")
+
 // ContextElementRepository 六要素数据访问接口
 type ContextElementRepository interface {
 	Create(element *model.ContextElement) error
+	CreateWithVariables(element *model.ContextElement, variables []*model.ContextElementVariable) error
 	GetByID(id uint64) (*model.ContextElement, error)
 	GetByUserID(userID uint64, req *model.ContextElementQueryRequest) ([]*model.ContextElement, int64, error)
+	GetByCursor(userID uint64, req *model.ContextElementQueryRequest, after *cursor.Cursor, limit int) ([]*model.ContextElement, error)
+	CountByUserID(userID uint64, req *model.ContextElementQueryRequest) (int64, error)
+	GetAllByUserID(userID uint64, req *model.ContextElementQueryRequest) ([]*model.ContextElement, error)
+	GetByIDs(userID uint64, ids []uint64) ([]*model.ContextElement, error)
+	GetBySubjects(userID uint64, subjects []string) ([]*model.ContextElement, error)
 	Update(element *model.ContextElement) error
+	UpdateWithVersion(element *model.ContextElement, previous *model.ContextElementVersion) error
+	UpdateWithTags(element *model.ContextElement, previous *model.ContextElementVersion) error
+	MoveToFolder(userID uint64, ids []uint64, folderID *uint64) (int64, error)
 	Delete(id uint64) error
+	DeleteWithVersion(element *model.ContextElement) error
+	GetDeletedByUserID(userID uint64, req *model.ContextElementTrashQueryRequest) ([]*model.ContextElement, int64, error)
+	RestoreDeleted(userID uint64, ids []uint64) (int64, error)
+	PurgeDeleted(userID uint64, ids []uint64) (int64, error)
+	PurgeDeletedBefore(before time.Time, limit int) (int64, error)
+	BulkCreate(elements []*model.ContextElement, atomic bool) ([]error, error)
+	BulkUpdate(updates []*ElementUpdate, atomic bool) ([]error, error)
+	BulkDelete(elements []*model.ContextElement, atomic bool) ([]error, error)
 	ExistsByID(id uint64) (bool, error)
-	Search(userID uint64, req *model.ContextElementQueryRequest) ([]*model.ContextElement, int64, error)
+	Search(userID uint64, req *model.ContextElementQueryRequest) ([]*model.ContextElementSearchHit, int64, error)
 }
 
 // contextElementRepository 六要素数据访问实现
 type contextElementRepository struct {
-	db *gorm.DB
+	db        *gorm.DB
+	searchCfg config.SearchConfig
 }
 
 // NewContextElementRepository 创建六要素Repository实例
-func NewContextElementRepository(db *gorm.DB) ContextElementRepository {
-	return &contextElementRepository{db: db}
+func NewContextElementRepository(db *gorm.DB, searchCfg config.SearchConfig) ContextElementRepository {
+	return &contextElementRepository{db: db, searchCfg: searchCfg}
 }
 
-// Create 创建六要素记录
+// Create 创建六要素记录，element.Tags 按名称查找或创建（同一事务）
 func (r *contextElementRepository) Create(element *model.ContextElement) error {
-	return r.db.Create(element).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return createElement(tx, element)
+	})
+}
+
+// CreateWithVariables 创建六要素记录及其标签、变量定义（同一事务）
+func (r *contextElementRepository) CreateWithVariables(element *model.ContextElement, variables []*model.ContextElementVariable) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := createElement(tx, element); err != nil {
+			return err
+		}
+		if len(variables) == 0 {
+			return nil
+		}
+		for _, variable := range variables {
+			variable.ElementID = element.ID
+		}
+		return tx.Create(&variables).Error
+	})
+}
+
+// createElement 在事务中查找或创建标签并写入六要素
+func createElement(tx *gorm.DB, element *model.ContextElement) error {
+	if err := resolveTags(tx, element); err != nil {
+		return err
+	}
+	return tx.Create(element).Error
+}
+
+// resolveTags 按名称查找或创建 element.Tags 中的标签，替换为已保存的标签
+func resolveTags(tx *gorm.DB, element *model.ContextElement) error {
+	if len(element.Tags) == 0 {
+		return nil
+	}
+	names := make([]string, len(element.Tags))
+	for i, tag := range element.Tags {
+		names[i] = tag.Name
+	}
+	tags, err := findOrCreateTags(tx, element.UserID, names)
+	if err != nil {
+		return err
+	}
+	element.Tags = tags
+	return nil
 }
 
 // GetByID 根据ID获取六要素记录
 func (r *contextElementRepository) GetByID(id uint64) (*model.ContextElement, error) {
 	var element model.ContextElement
-	err := r.db.Where("id = ?", id).First(&element).Error
+	err := r.db.Preload("Tags").Where("id = ?", id).First(&element).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -55,7 +135,7 @@ func (r *contextElementRepository) GetByUserID(userID uint64, req *model.Context
 	query := r.db.Model(&model.ContextElement{}).Where("user_id = ?", userID)
 
 	// 应用过滤条件
-	query = r.applyFilters(query, req)
+	query = r.applyFilters(query, userID, req)
 
 	// 获取总数
 	if err := query.Count(&total).Error; err != nil {
@@ -67,16 +147,170 @@ func (r *contextElementRepository) GetByUserID(userID uint64, req *model.Context
 
 	// 应用分页
 	offset := (req.Page - 1) * req.Size
-	if err := query.Offset(offset).Limit(req.Size).Find(&elements).Error; err != nil {
+	if err := query.Preload("Tags").Offset(offset).Limit(req.Size).Find(&elements).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return elements, total, nil
 }
 
+// GetByCursor 按键集（排序字段 + ID）分页获取六要素列表
+//
+// after 为空时从头开始；after.Backward 为 true 时获取边界记录之前的数据，结果仍按查询方向返回（由调用方翻转）。
+func (r *contextElementRepository) GetByCursor(userID uint64, req *model.ContextElementQueryRequest, after *cursor.Cursor, limit int) ([]*model.ContextElement, error) {
+	var elements []*model.ContextElement
+
+	column := keysetColumn(req.SortBy)
+	query := r.db.Model(&model.ContextElement{}).Where("user_id = ?", userID)
+
+	// 应用过滤条件
+	query = r.applyFilters(query, userID, req)
+
+	// 实际扫描方向：向前翻页时与排序方向相反
+	desc := req.SortDesc
+	if after != nil && after.Backward {
+		desc = !desc
+	}
+
+	// 从边界记录之后开始
+	if after != nil {
+		value, err := model.ParseSortValue(column, after.Value)
+		if err != nil {
+			return nil, err
+		}
+		op := ">"
+		if desc {
+			op = "<"
+		}
+		query = query.Where(fmt.Sprintf("(%s %s ? OR (%s = ? AND id %s ?))", column, op, column, op), value, value, after.ID)
+	}
+
+	direction := " ASC"
+	if desc {
+		direction = " DESC"
+	}
+	if err := query.Preload("Tags").Order(column + direction).Order("id" + direction).Limit(limit).Find(&elements).Error; err != nil {
+		return nil, err
+	}
+
+	return elements, nil
+}
+
+// CountByUserID 根据过滤条件统计用户的六要素数量
+func (r *contextElementRepository) CountByUserID(userID uint64, req *model.ContextElementQueryRequest) (int64, error) {
+	var total int64
+	query := r.db.Model(&model.ContextElement{}).Where("user_id = ?", userID)
+	query = r.applyFilters(query, userID, req)
+	if err := query.Count(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// GetAllByUserID 根据过滤条件获取用户的全部六要素（不分页，按创建时间正序）
+func (r *contextElementRepository) GetAllByUserID(userID uint64, req *model.ContextElementQueryRequest) ([]*model.ContextElement, error) {
+	var elements []*model.ContextElement
+	query := r.db.Model(&model.ContextElement{}).Where("user_id = ?", userID)
+	query = r.applyFilters(query, userID, req)
+	if err := query.Preload("Tags").Order("created_at ASC, id ASC").Find(&elements).Error; err != nil {
+		return nil, err
+	}
+	return elements, nil
+}
+
+// GetByIDs 根据ID列表获取用户的六要素
+func (r *contextElementRepository) GetByIDs(userID uint64, ids []uint64) ([]*model.ContextElement, error) {
+	var elements []*model.ContextElement
+	err := r.db.Preload("Tags").Where("user_id = ? AND id IN ?", userID, ids).Order("id ASC").Find(&elements).Error
+	if err != nil {
+		return nil, err
+	}
+	return elements, nil
+}
+
+// GetBySubjects 根据主题列表获取用户的六要素
+func (r *contextElementRepository) GetBySubjects(userID uint64, subjects []string) ([]*model.ContextElement, error) {
+	var elements []*model.ContextElement
+	err := r.db.Preload("Tags").Where("user_id = ? AND subject IN ?", userID, subjects).Order("id ASC").Find(&elements).Error
+	if err != nil {
+		return nil, err
+	}
+	return elements, nil
+}
+
 // Update 更新六要素记录
 func (r *contextElementRepository) Update(element *model.ContextElement) error {
-	return r.db.Save(element).Error
+	return r.db.Omit("Tags").Save(element).Error
+}
+
+// UpdateWithVersion 保存更新前的版本快照并更新六要素记录（同一事务）
+//
+// 仅当数据库中的版本号仍为 element.Version 时更新并将版本号加一，否则返回 ErrVersionConflict。
+func (r *contextElementRepository) UpdateWithVersion(element *model.ContextElement, previous *model.ContextElementVersion) error {
+	expected := element.Version
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		return updateWithVersion(tx, element, previous)
+	})
+	if err != nil {
+		element.Version = expected
+	}
+	return err
+}
+
+// updateWithVersion 在事务中写入版本快照并按版本号条件更新六要素
+func updateWithVersion(tx *gorm.DB, element *model.ContextElement, previous *model.ContextElementVersion) error {
+	if err := createVersion(tx, previous); err != nil {
+		return err
+	}
+
+	expected := element.Version
+	element.Version = expected + 1
+	result := tx.Model(element).Select("*").Omit("Tags", "UserID", "FolderID", "CreatedAt").
+		Where("version = ?", expected).Updates(element)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// UpdateWithTags 保存版本快照、更新六要素并将标签替换为 element.Tags（同一事务）
+//
+// element.Tags 按名称查找或创建；版本号不一致时返回 ErrVersionConflict，标签不会改动。
+func (r *contextElementRepository) UpdateWithTags(element *model.ContextElement, previous *model.ContextElementVersion) error {
+	expected := element.Version
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := updateWithVersion(tx, element, previous); err != nil {
+			return err
+		}
+		return replaceTags(tx, element)
+	})
+	if err != nil {
+		element.Version = expected
+	}
+	return err
+}
+
+// replaceTags 在事务中将六要素关联的标签替换为 element.Tags
+func replaceTags(tx *gorm.DB, element *model.ContextElement) error {
+	if err := resolveTags(tx, element); err != nil {
+		return err
+	}
+	tags := element.Tags
+	if tags == nil {
+		tags = []model.Tag{}
+	}
+	return tx.Model(element).Association("Tags").Replace(tags)
+}
+
+// MoveToFolder 将用户的六要素移动到指定文件夹（folderID为空表示根目录）
+func (r *contextElementRepository) MoveToFolder(userID uint64, ids []uint64, folderID *uint64) (int64, error) {
+	result := r.db.Model(&model.ContextElement{}).
+		Where("user_id = ? AND id IN ?", userID, ids).
+		Update("folder_id", folderID)
+	return result.RowsAffected, result.Error
 }
 
 // Delete 删除六要素记录
@@ -84,6 +318,23 @@ func (r *contextElementRepository) Delete(id uint64) error {
 	return r.db.Delete(&model.ContextElement{}, id).Error
 }
 
+// DeleteWithVersion 删除六要素记录，数据库中的版本号与 element.Version 不一致时返回 ErrVersionConflict
+func (r *contextElementRepository) DeleteWithVersion(element *model.ContextElement) error {
+	return deleteWithVersion(r.db, element)
+}
+
+// deleteWithVersion 按版本号条件删除六要素
+func deleteWithVersion(tx *gorm.DB, element *model.ContextElement) error {
+	result := tx.Where("version = ?", element.Version).Delete(&model.ContextElement{}, element.ID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
 // ExistsByID 检查六要素记录是否存在
 func (r *contextElementRepository) ExistsByID(id uint64) (bool, error) {
 	var count int64
@@ -94,13 +345,175 @@ func (r *contextElementRepository) ExistsByID(id uint64) (bool, error) {
 	return count > 0, nil
 }
 
-// Search 搜索六要素记录
-func (r *contextElementRepository) Search(userID uint64, req *model.ContextElementQueryRequest) ([]*model.ContextElement, int64, error) {
-	return r.GetByUserID(userID, req)
+// Search 按关键词搜索六要素记录，默认按相关度得分倒序
+func (r *contextElementRepository) Search(userID uint64, req *model.ContextElementQueryRequest) ([]*model.ContextElementSearchHit, int64, error) {
+	var total int64
+
+	query := r.db.Model(&model.ContextElement{}).Where("user_id = ?", userID)
+
+	// 应用过滤条件（包含关键词匹配）
+	query = r.applyFilters(query, userID, req)
+
+	// 获取总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []*model.ContextElementSearchHit{}, 0, nil
+	}
+
+	// 计算相关度得分并排序
+	scoreSQL, scoreArgs := r.scoreExpression(search.ParseQuery(req.Keyword).Positives())
+	query = query.Select("id, "+scoreSQL+" AS score", scoreArgs...)
+	if req.SortBy == "" || req.SortBy == model.SortByRelevance {
+		if req.PinnedFirst {
+			query = applyPinnedFirst(query)
+		}
+		query = query.Order("score DESC").Order("id DESC")
+	} else {
+		query = r.applySorting(query, req)
+	}
+
+	// 应用分页
+	var rows []struct {
+		ID    uint64
+		Score float64
+	}
+	offset := (req.Page - 1) * req.Size
+	if err := query.Offset(offset).Limit(req.Size).Scan(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+	if len(rows) == 0 {
+		return []*model.ContextElementSearchHit{}, total, nil
+	}
+
+	// 加载命中记录并保持得分顺序
+	ids := make([]uint64, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	elements, err := r.GetByIDs(userID, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	elementMap := make(map[uint64]*model.ContextElement, len(elements))
+	for _, element := range elements {
+		elementMap[element.ID] = element
+	}
+
+	hits := make([]*model.ContextElementSearchHit, 0, len(rows))
+	for _, row := range rows {
+		if element, ok := elementMap[row.ID]; ok {
+			hits = append(hits, &model.ContextElementSearchHit{Element: element, Score: row.Score})
+		}
+	}
+
+	return hits, total, nil
+}
+
+// applyKeyword 应用关键词条件：全部关键词和短语必须命中，排除词不能命中
+func (r *contextElementRepository) applyKeyword(query *gorm.DB, keyword string) *gorm.DB {
+	q := search.ParseQuery(keyword)
+
+	indexed, short := r.splitFullText(q.Positives())
+	if len(indexed) > 0 {
+		query = query.Where("MATCH("+fullTextAllColumns+") AGAINST(? IN BOOLEAN MODE)", search.BooleanRequired(indexed))
+	}
+	for _, word := range short {
+		condition, args := likeAnyColumn(word, strings.Split(fullTextAllColumns, ", "))
+		query = query.Where(condition, args...)
+	}
+
+	indexed, short = r.splitFullText(q.Excludes)
+	if len(indexed) > 0 {
+		query = query.Where("NOT MATCH("+fullTextAllColumns+") AGAINST(? IN BOOLEAN MODE)", search.BooleanAny(indexed))
+	}
+	for _, word := range short {
+		condition, args := likeAnyColumn(word, strings.Split(fullTextAllColumns, ", "))
+		query = query.Where("NOT "+condition, args...)
+	}
+
+	return query
+}
+
+// scoreExpression 生成相关度得分表达式：主题、任务目标和其他字段分别匹配后按权重求和
+func (r *contextElementRepository) scoreExpression(words []string) (string, []interface{}) {
+	if len(words) == 0 {
+		return "0", nil
+	}
+
+	weights := r.searchCfg.GetWeights()
+	groups := []struct {
+		columns string
+		weight  float64
+	}{
+		{columns: "subject", weight: weights.Subject},
+		{columns: "task_goal", weight: weights.TaskGoal},
+		{columns: fullTextOtherColumns, weight: weights.Others},
+	}
+
+	parts := make([]string, 0, len(groups))
+	var args []interface{}
+	indexed, short := r.splitFullText(words)
+	if len(indexed) > 0 {
+		against := search.BooleanAny(indexed)
+		for _, group := range groups {
+			parts = append(parts, "MATCH("+group.columns+") AGAINST(? IN BOOLEAN MODE) * ?")
+			args = append(args, against, group.weight)
+		}
+	}
+
+	// LIKE 匹配的关键词按命中个数计分
+	for _, word := range short {
+		for _, group := range groups {
+			condition, likeArgs := likeAnyColumn(word, strings.Split(group.columns, ", "))
+			parts = append(parts, "(CASE WHEN "+condition+" THEN ? ELSE 0 END)")
+			args = append(append(args, likeArgs...), group.weight)
+		}
+	}
+	return "(" + strings.Join(parts, " + ") + ")", args
+}
+
+// splitFullText 将关键词分为使用全文索引匹配的和使用 LIKE 匹配的：
+// ngram 索引无法命中短于 ngramTokenSize 个字符的关键词（如单个汉字），这些关键词改用 LIKE；LIKE 模式下全部使用 LIKE
+func (r *contextElementRepository) splitFullText(words []string) (indexed, short []string) {
+	if !r.searchCfg.UseFullText() {
+		return nil, words
+	}
+	for _, word := range words {
+		if utf8.RuneCountInString(word) < ngramTokenSize {
+			short = append(short, word)
+		} else {
+			indexed = append(indexed, word)
+		}
+	}
+	return indexed, short
+}
+
+// keysetColumn 获取键集分页的排序列，不支持的字段按创建时间处理
+func keysetColumn(sortBy string) string {
+	switch sortBy {
+	case "updated_at", "subject":
+		return sortBy
+	default:
+		return "created_at"
+	}
+}
+
+// likeAnyColumn 生成任一列包含关键词的 LIKE 条件
+func likeAnyColumn(word string, columns []string) (string, []interface{}) {
+	pattern := "%" + search.EscapeLike(word) + "%"
+	conditions := make([]string, len(columns))
+	args := make([]interface{}, len(columns))
+	for i, column := range columns {
+		conditions[i] = column + " LIKE ?"
+		args[i] = pattern
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")", args
 }
 
 // applyFilters 应用过滤条件
-func (r *contextElementRepository) applyFilters(query *gorm.DB, req *model.ContextElementQueryRequest) *gorm.DB {
+func (r *contextElementRepository) applyFilters(query *gorm.DB, userID uint64, req *model.ContextElementQueryRequest) *gorm.DB {
 	// 主题过滤
 	if req.Subject != "" {
 		query = query.Where("subject LIKE ?", "%"+req.Subject+"%")
@@ -116,18 +529,71 @@ func (r *contextElementRepository) applyFilters(query *gorm.DB, req *model.Conte
 		query = query.Where("my_role LIKE ?", "%"+req.MyRole+"%")
 	}
 
-	// 关键词搜索（全文搜索）
+	// 文件夹过滤：0 表示根目录，recursive 时包含子孙文件夹
+	if req.FolderID != nil {
+		switch {
+		case *req.FolderID == 0:
+			query = query.Where("folder_id IS NULL")
+		case req.Recursive:
+			var folders []*model.Folder
+			if err := r.db.Where("user_id = ?", userID).Find(&folders).Error; err != nil {
+				query.AddError(err)
+				return query
+			}
+			query = query.Where("folder_id IN ?", model.DescendantFolderIDs(folders, *req.FolderID))
+		default:
+			query = query.Where("folder_id = ?", *req.FolderID)
+		}
+	}
+
+	// 标签过滤：any 匹配任一标签，all 需包含全部标签
+	if tagNames := model.SplitTagNames(req.Tags); len(tagNames) > 0 {
+		subQuery := r.db.Table("cese_context_element_tag AS et").
+			Select("et.context_element_id").
+			Joins("JOIN cese_tag AS t ON t.id = et.tag_id").
+			Where("t.user_id = ? AND t.name IN ?", userID, tagNames)
+		if req.TagMode == "all" {
+			subQuery = subQuery.Group("et.context_element_id").Having("COUNT(DISTINCT t.id) = ?", len(tagNames))
+		}
+		query = query.Where("id IN (?)", subQuery)
+	}
+
+	// 收藏和置顶过滤：没有使用统计记录的六要素视为未收藏、未置顶
+	if req.Favorite != nil {
+		query = applyUsageFlag(query, "favorite", *req.Favorite)
+	}
+	if req.Pinned != nil {
+		query = applyUsageFlag(query, "pinned", *req.Pinned)
+	}
+
+	// 关键词搜索（全文搜索，支持短语和排除词）
 	if req.Keyword != "" {
-		keyword := "%" + req.Keyword + "%"
-		query = query.Where(
-			"subject LIKE ? OR task_goal LIKE ? OR ai_role LIKE ? OR my_role LIKE ? OR key_info LIKE ? OR behavior_rule LIKE ? OR delivery_format LIKE ?",
-			keyword, keyword, keyword, keyword, keyword, keyword, keyword,
-		)
+		query = r.applyKeyword(query, req.Keyword)
 	}
 
 	return query
 }
 
+// applyUsageFlag 按使用统计中的收藏或置顶标记过滤
+func applyUsageFlag(query *gorm.DB, column string, value bool) *gorm.DB {
+	subQuery := "SELECT context_element_id FROM cese_context_element_usage WHERE " + column + " = TRUE"
+	if value {
+		return query.Where("id IN (" + subQuery + ")")
+	}
+	return query.Where("id NOT IN (" + subQuery + ")")
+}
+
+// usageColumn 生成读取六要素使用统计中某一列的相关子查询（没有记录时为 NULL）
+func usageColumn(column string) string {
+	return "(SELECT u." + column + " FROM cese_context_element_usage AS u WHERE u.context_element_id = cese_context_element.id)"
+}
+
+// applyPinnedFirst 置顶的六要素排在最前，按置顶时间倒序
+func applyPinnedFirst(query *gorm.DB) *gorm.DB {
+	return query.Order("COALESCE(" + usageColumn("pinned") + ", FALSE) DESC").
+		Order(usageColumn("pinned_at") + " DESC")
+}
+
 // applySorting 应用排序
 func (r *contextElementRepository) applySorting(query *gorm.DB, req *model.ContextElementQueryRequest) *gorm.DB {
 	sortBy := req.SortBy
@@ -135,23 +601,30 @@ func (r *contextElementRepository) applySorting(query *gorm.DB, req *model.Conte
 		sortBy = "created_at"
 	}
 
-	// 验证排序字段
-	validSortFields := map[string]bool{
-		"created_at": true,
-		"updated_at": true,
-		"subject":    true,
+	// 验证排序字段，使用情况相关的排序读取使用统计
+	validSortFields := map[string]string{
+		"created_at":         "created_at",
+		"updated_at":         "updated_at",
+		"subject":            "subject",
+		model.SortByLastUsed: usageColumn("last_used_at"),
+		model.SortByUseCount: "COALESCE(" + usageColumn("use_count") + ", 0)",
 	}
 
-	if !validSortFields[sortBy] {
-		sortBy = "created_at"
+	column, ok := validSortFields[sortBy]
+	if !ok {
+		column = "created_at"
 	}
 
-	// 应用排序
+	if req.PinnedFirst {
+		query = applyPinnedFirst(query)
+	}
+
+	// 应用排序，以 id 同向排序打破并列，保证分页结果稳定
+	direction := " ASC"
 	if req.SortDesc {
-		query = query.Order(sortBy + " DESC")
-	} else {
-		query = query.Order(sortBy + " ASC")
+		direction = " DESC"
 	}
+	query = query.Order(column + direction).Order("id" + direction)
 
 	return query
 }
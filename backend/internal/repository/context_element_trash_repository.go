@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"time"
+
+	"cese-backend/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// GetDeletedByUserID 获取用户回收站中的六要素（按删除时间倒序）
+func (r *contextElementRepository) GetDeletedByUserID(userID uint64, req *model.ContextElementTrashQueryRequest) ([]*model.ContextElement, int64, error) {
+	var elements []*model.ContextElement
+	var total int64
+
+	query := r.db.Unscoped().Model(&model.ContextElement{}).
+		Where("user_id = ? AND deleted_at IS NOT NULL", userID)
+	if req.Keyword != "" {
+		query = query.Where("subject LIKE ?", "%"+req.Keyword+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.Size
+	err := query.Preload("Tags").Order("deleted_at DESC, id DESC").Offset(offset).Limit(req.Size).Find(&elements).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return elements, total, nil
+}
+
+// RestoreDeleted 恢复用户回收站中的六要素，原文件夹已不存在时恢复到根目录
+func (r *contextElementRepository) RestoreDeleted(userID uint64, ids []uint64) (int64, error) {
+	var restored int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Model(&model.ContextElement{}).
+			Where("user_id = ? AND id IN ? AND deleted_at IS NOT NULL", userID, ids).
+			Update("deleted_at", nil)
+		if result.Error != nil {
+			return result.Error
+		}
+		restored = result.RowsAffected
+
+		return tx.Model(&model.ContextElement{}).
+			Where("user_id = ? AND id IN ? AND folder_id IS NOT NULL", userID, ids).
+			Where("folder_id NOT IN (?)", tx.Model(&model.Folder{}).Select("id").Where("user_id = ?", userID)).
+			Update("folder_id", nil).Error
+	})
+	return restored, err
+}
+
+// PurgeDeleted 彻底删除用户回收站中的六要素，ids 为空时清空整个回收站
+func (r *contextElementRepository) PurgeDeleted(userID uint64, ids []uint64) (int64, error) {
+	query := r.db.Unscoped().Model(&model.ContextElement{}).
+		Where("user_id = ? AND deleted_at IS NOT NULL", userID)
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+
+	var targets []uint64
+	if err := query.Pluck("id", &targets).Error; err != nil {
+		return 0, err
+	}
+	return r.purge(targets)
+}
+
+// PurgeDeletedBefore 彻底删除在指定时间之前进入回收站的六要素（每次最多 limit 条）
+func (r *contextElementRepository) PurgeDeletedBefore(before time.Time, limit int) (int64, error) {
+	var targets []uint64
+	err := r.db.Unscoped().Model(&model.ContextElement{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Order("deleted_at ASC").Limit(limit).
+		Pluck("id", &targets).Error
+	if err != nil {
+		return 0, err
+	}
+	return r.purge(targets)
+}
+
+// purge 在同一事务中删除六要素及其标签关联、历史版本、变量定义、使用统计、生成记录、
+// 评估数据集和评估运行、对比运行中的变体以及发布的公共模板
+func (r *contextElementRepository) purge(ids []uint64) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	var purged int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM cese_context_element_tag WHERE context_element_id IN ?", ids).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("element_id IN ?", ids).Delete(&model.ContextElementVersion{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("element_id IN ?", ids).Delete(&model.ContextElementVariable{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("context_element_id IN ?", ids).Delete(&model.ContextElementUsage{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("element_id IN ?", ids).Delete(&model.GenerationRecord{}).Error; err != nil {
+			return err
+		}
+		if err := purgeEvals(tx, ids); err != nil {
+			return err
+		}
+		if err := purgeComparisonVariants(tx, ids); err != nil {
+			return err
+		}
+		// 取消发布的模板也占用 element_id 唯一索引，一并物理删除
+		if err := tx.Unscoped().Where("element_id IN ?", ids).Delete(&model.Template{}).Error; err != nil {
+			return err
+		}
+		result := tx.Unscoped().Where("id IN ? AND deleted_at IS NOT NULL", ids).Delete(&model.ContextElement{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+	return purged, err
+}
+
+// purgeEvals 删除六要素的评估数据集及其用例、评估运行及其结果
+func purgeEvals(tx *gorm.DB, ids []uint64) error {
+	datasetIDs := tx.Model(&model.EvalDataset{}).Select("id").Where("element_id IN ?", ids)
+	runIDs := tx.Model(&model.EvalRun{}).Select("id").Where("element_id IN ? OR dataset_id IN (?)", ids, datasetIDs)
+	if err := tx.Where("run_id IN (?)", runIDs).Delete(&model.EvalResult{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("element_id IN ? OR dataset_id IN (?)", ids, datasetIDs).Delete(&model.EvalRun{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("dataset_id IN (?)", datasetIDs).Delete(&model.EvalCase{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("element_id IN ?", ids).Delete(&model.EvalDataset{}).Error
+}
+
+// purgeComparisonVariants 删除对比运行中引用这些六要素的变体及其执行结果，
+// 胜出变体被删除时清空胜出标记，不再包含任何变体的对比运行一并删除
+func purgeComparisonVariants(tx *gorm.DB, ids []uint64) error {
+	var variants []*model.ComparisonVariant
+	if err := tx.Select("id", "run_id").Where("element_id IN ?", ids).Find(&variants).Error; err != nil {
+		return err
+	}
+	if len(variants) == 0 {
+		return nil
+	}
+	variantIDs := make([]uint64, len(variants))
+	runIDs := make([]uint64, len(variants))
+	for i, variant := range variants {
+		variantIDs[i] = variant.ID
+		runIDs[i] = variant.RunID
+	}
+
+	if err := tx.Where("variant_id IN ?", variantIDs).Delete(&model.ComparisonOutput{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&model.ComparisonRun{}).Where("winner_variant_id IN ?", variantIDs).
+		Update("winner_variant_id", nil).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("id IN ?", variantIDs).Delete(&model.ComparisonVariant{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("id IN ? AND id NOT IN (?)", runIDs, tx.Model(&model.ComparisonVariant{}).Select("run_id").Where("run_id IN ?", runIDs)).
+		Delete(&model.ComparisonRun{}).Error
+}
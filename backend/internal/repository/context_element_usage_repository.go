@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"cese-backend/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// usageCountColumns 各使用事件对应的计数列，打开不计入使用次数
+var usageCountColumns = map[string]string{
+	model.UsageRender:     "render_count",
+	model.UsageGeneration: "generation_count",
+	model.UsageCopy:       "copy_count",
+}
+
+// ContextElementUsageRepository 六要素收藏、置顶和使用统计数据访问接口
+type ContextElementUsageRepository interface {
+	GetByElementID(elementID uint64) (*model.ContextElementUsage, error)
+	GetByElementIDs(elementIDs []uint64) ([]*model.ContextElementUsage, error)
+	GetRecent(userID uint64, limit int) ([]*model.ContextElementUsage, error)
+	Record(userID, elementID uint64, event string, at time.Time) error
+	SetFavorite(userID, elementID uint64, favorite bool) error
+	SetPinned(userID, elementID uint64, pinned bool, at time.Time) error
+}
+
+// contextElementUsageRepository 六要素收藏、置顶和使用统计数据访问实现
+type contextElementUsageRepository struct {
+	db *gorm.DB
+}
+
+// NewContextElementUsageRepository 创建六要素使用统计Repository实例
+func NewContextElementUsageRepository(db *gorm.DB) ContextElementUsageRepository {
+	return &contextElementUsageRepository{db: db}
+}
+
+// GetByElementID 获取六要素的使用统计，没有记录时返回 nil
+func (r *contextElementUsageRepository) GetByElementID(elementID uint64) (*model.ContextElementUsage, error) {
+	var usage model.ContextElementUsage
+	err := r.db.Where("context_element_id = ?", elementID).First(&usage).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &usage, nil
+}
+
+// GetByElementIDs 批量获取六要素的使用统计
+func (r *contextElementUsageRepository) GetByElementIDs(elementIDs []uint64) ([]*model.ContextElementUsage, error) {
+	var usages []*model.ContextElementUsage
+	if len(elementIDs) == 0 {
+		return usages, nil
+	}
+	if err := r.db.Where("context_element_id IN ?", elementIDs).Find(&usages).Error; err != nil {
+		return nil, err
+	}
+	return usages, nil
+}
+
+// GetRecent 获取用户最近打开或使用的六要素的统计（不含回收站中的六要素），按最近使用时间倒序
+func (r *contextElementUsageRepository) GetRecent(userID uint64, limit int) ([]*model.ContextElementUsage, error) {
+	var usages []*model.ContextElementUsage
+	err := r.db.Table("cese_context_element_usage AS u").
+		Select("u.*").
+		Joins("JOIN cese_context_element AS e ON e.id = u.context_element_id AND e.deleted_at IS NULL").
+		Where("u.user_id = ? AND u.last_used_at IS NOT NULL", userID).
+		Order("u.last_used_at DESC").
+		Limit(limit).
+		Scan(&usages).Error
+	if err != nil {
+		return nil, err
+	}
+	return usages, nil
+}
+
+// Record 记录一次使用事件：更新最近使用时间，打开时更新最近打开时间，其他事件累加对应计数和使用次数
+func (r *contextElementUsageRepository) Record(userID, elementID uint64, event string, at time.Time) error {
+	usage := &model.ContextElementUsage{
+		ContextElementID: elementID,
+		UserID:           userID,
+		LastUsedAt:       &at,
+	}
+	updates := map[string]interface{}{
+		"last_used_at": at,
+		"updated_at":   at,
+	}
+
+	if event == model.UsageOpen {
+		usage.LastOpenedAt = &at
+		updates["last_opened_at"] = at
+	} else {
+		column, ok := usageCountColumns[event]
+		if !ok {
+			return errors.New("不支持的使用事件: " + event)
+		}
+		switch event {
+		case model.UsageRender:
+			usage.RenderCount = 1
+		case model.UsageGeneration:
+			usage.GenerationCount = 1
+		case model.UsageCopy:
+			usage.CopyCount = 1
+		}
+		usage.UseCount = 1
+		updates[column] = gorm.Expr(column + " + 1")
+		updates["use_count"] = gorm.Expr("use_count + 1")
+	}
+
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "context_element_id"}},
+		DoUpdates: clause.Assignments(updates),
+	}).Create(usage).Error
+}
+
+// SetFavorite 收藏或取消收藏
+func (r *contextElementUsageRepository) SetFavorite(userID, elementID uint64, favorite bool) error {
+	usage := &model.ContextElementUsage{ContextElementID: elementID, UserID: userID, Favorite: favorite}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "context_element_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"favorite": favorite, "updated_at": time.Now()}),
+	}).Create(usage).Error
+}
+
+// SetPinned 置顶或取消置顶，取消时清空置顶时间
+func (r *contextElementUsageRepository) SetPinned(userID, elementID uint64, pinned bool, at time.Time) error {
+	usage := &model.ContextElementUsage{ContextElementID: elementID, UserID: userID, Pinned: pinned}
+	if pinned {
+		usage.PinnedAt = &at
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "context_element_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"pinned": pinned, "pinned_at": usage.PinnedAt, "updated_at": at}),
+	}).Create(usage).Error
+}
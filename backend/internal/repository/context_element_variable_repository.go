@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"cese-backend/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// ContextElementVariableRepository 六要素模板变量数据访问接口
+type ContextElementVariableRepository interface {
+	GetByElementID(elementID uint64) ([]*model.ContextElementVariable, error)
+	GetByElementIDs(elementIDs []uint64) ([]*model.ContextElementVariable, error)
+	ReplaceByElementID(elementID uint64, variables []*model.ContextElementVariable) error
+}
+
+// contextElementVariableRepository 六要素模板变量数据访问实现
+type contextElementVariableRepository struct {
+	db *gorm.DB
+}
+
+// NewContextElementVariableRepository 创建六要素模板变量Repository实例
+func NewContextElementVariableRepository(db *gorm.DB) ContextElementVariableRepository {
+	return &contextElementVariableRepository{db: db}
+}
+
+// GetByElementID 获取六要素的变量定义
+func (r *contextElementVariableRepository) GetByElementID(elementID uint64) ([]*model.ContextElementVariable, error) {
+	var variables []*model.ContextElementVariable
+	err := r.db.Where("element_id = ?", elementID).Order("sort_order ASC").Find(&variables).Error
+	if err != nil {
+		return nil, err
+	}
+	return variables, nil
+}
+
+// GetByElementIDs 批量获取多个六要素的变量定义，按六要素和排序返回
+func (r *contextElementVariableRepository) GetByElementIDs(elementIDs []uint64) ([]*model.ContextElementVariable, error) {
+	var variables []*model.ContextElementVariable
+	if len(elementIDs) == 0 {
+		return variables, nil
+	}
+	err := r.db.Where("element_id IN ?", elementIDs).Order("element_id ASC, sort_order ASC").Find(&variables).Error
+	if err != nil {
+		return nil, err
+	}
+	return variables, nil
+}
+
+// ReplaceByElementID 整体替换六要素的变量定义
+func (r *contextElementVariableRepository) ReplaceByElementID(elementID uint64, variables []*model.ContextElementVariable) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("element_id = ?", elementID).Delete(&model.ContextElementVariable{}).Error; err != nil {
+			return err
+		}
+		if len(variables) == 0 {
+			return nil
+		}
+		return tx.Create(&variables).Error
+	})
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"errors"
+
+	"cese-backend/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// ContextElementVersionRepository 六要素历史版本数据访问接口
+type ContextElementVersionRepository interface {
+	GetByElementID(elementID uint64) ([]*model.ContextElementVersion, error)
+	GetByVersionNo(elementID uint64, versionNo int) (*model.ContextElementVersion, error)
+}
+
+// contextElementVersionRepository 六要素历史版本数据访问实现
+type contextElementVersionRepository struct {
+	db *gorm.DB
+}
+
+// NewContextElementVersionRepository 创建六要素历史版本Repository实例
+func NewContextElementVersionRepository(db *gorm.DB) ContextElementVersionRepository {
+	return &contextElementVersionRepository{db: db}
+}
+
+// GetByElementID 获取六要素的全部历史版本（按版本号倒序）
+func (r *contextElementVersionRepository) GetByElementID(elementID uint64) ([]*model.ContextElementVersion, error) {
+	var versions []*model.ContextElementVersion
+	err := r.db.Where("element_id = ?", elementID).Order("version_no DESC").Find(&versions).Error
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// GetByVersionNo 根据版本号获取历史版本
+func (r *contextElementVersionRepository) GetByVersionNo(elementID uint64, versionNo int) (*model.ContextElementVersion, error) {
+	var version model.ContextElementVersion
+	err := r.db.Where("element_id = ? AND version_no = ?", elementID, versionNo).First(&version).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &version, nil
+}
+
+// createVersion 在事务中写入版本快照，版本号在已有最大值上递增
+func createVersion(tx *gorm.DB, version *model.ContextElementVersion) error {
+	var maxVersionNo int
+	err := tx.Model(&model.ContextElementVersion{}).
+		Where("element_id = ?", version.ElementID).
+		Select("COALESCE(MAX(version_no), 0)").
+		Scan(&maxVersionNo).Error
+	if err != nil {
+		return err
+	}
+
+	version.VersionNo = maxVersionNo + 1
+	return tx.Create(version).Error
+}
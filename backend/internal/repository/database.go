@@ -21,6 +21,8 @@ func InitDatabase(cfg *config.Config) error {
 	// 配置GORM
 	gormConfig := &gorm.Config{
 		Logger: logger.Default.LogMode(getLogLevel(cfg.Log.Level)),
+		// 将唯一索引冲突等数据库错误转换为 gorm 的通用错误
+		TranslateError: true,
 	}
 
 	// 连接数据库
@@ -50,6 +52,13 @@ func InitDatabase(cfg *config.Config) error {
 		return fmt.Errorf("数据库迁移失败: %w", err)
 	}
 
+	// 创建全文索引
+	if cfg.Search.UseFullText() {
+		if err := createFullTextIndexes(db); err != nil {
+			return fmt.Errorf("创建全文索引失败: %w", err)
+		}
+	}
+
 	DB = db
 	return nil
 }
@@ -59,9 +68,51 @@ func autoMigrate(db *gorm.DB) error {
 	return db.AutoMigrate(
 		&model.User{},
 		&model.ContextElement{},
+		&model.ContextElementVersion{},
+		&model.ContextElementVariable{},
+		&model.ContextElementUsage{},
+		&model.Tag{},
+		&model.Folder{},
+		&model.Template{},
+		&model.GenerationRecord{},
+		&model.ProviderConfig{},
+		&model.ComparisonRun{},
+		&model.ComparisonVariant{},
+		&model.ComparisonOutput{},
+		&model.EvalDataset{},
+		&model.EvalCase{},
+		&model.EvalRun{},
+		&model.EvalResult{},
+		&model.CatalogItem{},
 	)
 }
 
+// fullTextIndexes 六要素全文索引（名称 -> 列），按字段分组以便分别计算相关度权重
+var fullTextIndexes = []struct {
+	Name    string
+	Columns string
+}{
+	{Name: "ft_subject", Columns: "subject"},
+	{Name: "ft_task_goal", Columns: "task_goal"},
+	{Name: "ft_others", Columns: fullTextOtherColumns},
+	{Name: "ft_all", Columns: fullTextAllColumns},
+}
+
+// createFullTextIndexes 创建六要素全文索引（使用ngram解析器以支持中文分词）
+func createFullTextIndexes(db *gorm.DB) error {
+	for _, index := range fullTextIndexes {
+		if db.Migrator().HasIndex(&model.ContextElement{}, index.Name) {
+			continue
+		}
+		sql := fmt.Sprintf("CREATE FULLTEXT INDEX %s ON %s (%s) WITH PARSER ngram",
+			index.Name, model.ContextElement{}.TableName(), index.Columns)
+		if err := db.Exec(sql).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // getLogLevel 根据配置获取GORM日志级别
 func getLogLevel(level string) logger.LogLevel {
 	switch level {
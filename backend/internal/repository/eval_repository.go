@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"errors"
+
+	"cese-backend/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EvalRepository 评估数据集和评估运行数据访问接口
+type EvalRepository interface {
+	CreateDataset(dataset *model.EvalDataset) error
+	GetDatasetByID(id uint64) (*model.EvalDataset, error)
+	GetDatasets(userID uint64, req *model.EvalDatasetQueryRequest) ([]*model.EvalDataset, error)
+	UpdateDataset(dataset *model.EvalDataset) error
+	DeleteDataset(id uint64) error
+	CreateRun(run *model.EvalRun) error
+	AddRunResult(run *model.EvalRun, result *model.EvalResult) error
+	FinishRun(run *model.EvalRun) error
+	CancelRunningRuns() (int64, error)
+	GetRunByID(id uint64) (*model.EvalRun, error)
+	GetRuns(userID uint64, req *model.EvalRunQueryRequest) ([]*model.EvalRun, int64, error)
+	DeleteRun(id uint64) error
+}
+
+// ErrEvalRunDeleted 评估运行在执行过程中被删除
+var ErrEvalRunDeleted = errors.New("评估运行已删除")
+
+// evalRepository 评估数据集和评估运行数据访问实现
+type evalRepository struct {
+	db *gorm.DB
+}
+
+// NewEvalRepository 创建评估Repository实例
+func NewEvalRepository(db *gorm.DB) EvalRepository {
+	return &evalRepository{db: db}
+}
+
+// orderCases 按序号加载用例
+func orderCases(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC")
+}
+
+// CreateDataset 创建评估数据集及其用例
+func (r *evalRepository) CreateDataset(dataset *model.EvalDataset) error {
+	return r.db.Create(dataset).Error
+}
+
+// GetDatasetByID 根据ID获取评估数据集，包含用例
+func (r *evalRepository) GetDatasetByID(id uint64) (*model.EvalDataset, error) {
+	var dataset model.EvalDataset
+	err := r.db.Preload("Cases", orderCases).Where("id = ?", id).First(&dataset).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &dataset, nil
+}
+
+// GetDatasets 获取用户的评估数据集，按更新时间倒序
+func (r *evalRepository) GetDatasets(userID uint64, req *model.EvalDatasetQueryRequest) ([]*model.EvalDataset, error) {
+	var datasets []*model.EvalDataset
+	query := r.db.Preload("Cases", orderCases).Where("user_id = ?", userID)
+	if req.ElementID != 0 {
+		query = query.Where("element_id = ?", req.ElementID)
+	}
+	err := query.Order("updated_at DESC").Order("id DESC").Find(&datasets).Error
+	return datasets, err
+}
+
+// UpdateDataset 更新评估数据集并整体替换用例
+func (r *evalRepository) UpdateDataset(dataset *model.EvalDataset) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("dataset_id = ?", dataset.ID).Delete(&model.EvalCase{}).Error; err != nil {
+			return err
+		}
+		if len(dataset.Cases) > 0 {
+			if err := tx.Create(dataset.Cases).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(dataset).Select("name", "description", "updated_at").Updates(dataset).Error
+	})
+}
+
+// DeleteDataset 删除评估数据集、用例及其评估运行
+func (r *evalRepository) DeleteDataset(id uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		runIDs := tx.Model(&model.EvalRun{}).Select("id").Where("dataset_id = ?", id)
+		if err := tx.Where("run_id IN (?)", runIDs).Delete(&model.EvalResult{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("dataset_id = ?", id).Delete(&model.EvalRun{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("dataset_id = ?", id).Delete(&model.EvalCase{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.EvalDataset{}, id).Error
+	})
+}
+
+// CreateRun 创建评估运行
+func (r *evalRepository) CreateRun(run *model.EvalRun) error {
+	return r.db.Omit("Results").Create(run).Error
+}
+
+// AddRunResult 保存一个用例的结果并更新评估运行的统计，评估运行已被删除时返回 ErrEvalRunDeleted
+func (r *evalRepository) AddRunResult(run *model.EvalRun, result *model.EvalResult) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// 锁定评估运行，避免与删除评估运行、数据集或六要素并发时留下孤立的结果
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+			Where("id = ? AND status = ?", run.ID, model.EvalRunStatusRunning).
+			Take(&model.EvalRun{}).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrEvalRunDeleted
+		}
+		if err != nil {
+			return err
+		}
+
+		result.RunID = run.ID
+		if err := tx.Create(result).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.EvalRun{ID: run.ID}).Updates(map[string]interface{}{
+			"passed":       run.Passed,
+			"failed":       run.Failed,
+			"errored":      run.Errored,
+			"total_tokens": run.TotalTokens,
+		}).Error
+	})
+}
+
+// FinishRun 保存评估运行的最终状态和耗时
+func (r *evalRepository) FinishRun(run *model.EvalRun) error {
+	return r.db.Model(&model.EvalRun{}).
+		Where("id = ? AND status = ?", run.ID, model.EvalRunStatusRunning).
+		Updates(map[string]interface{}{
+			"status":      run.Status,
+			"duration_ms": run.DurationMs,
+		}).Error
+}
+
+// CancelRunningRuns 将仍处于执行中的评估运行标记为已取消（用于服务重启后清理中断的运行）
+func (r *evalRepository) CancelRunningRuns() (int64, error) {
+	result := r.db.Model(&model.EvalRun{}).
+		Where("status = ?", model.EvalRunStatusRunning).
+		Update("status", model.EvalRunStatusCanceled)
+	return result.RowsAffected, result.Error
+}
+
+// GetRunByID 根据ID获取评估运行，包含用例结果
+func (r *evalRepository) GetRunByID(id uint64) (*model.EvalRun, error) {
+	var run model.EvalRun
+	err := r.db.Preload("Results", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Where("id = ?", id).First(&run).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &run, nil
+}
+
+// GetRuns 分页查询用户的评估运行，按创建时间倒序（不含用例结果）
+func (r *evalRepository) GetRuns(userID uint64, req *model.EvalRunQueryRequest) ([]*model.EvalRun, int64, error) {
+	var runs []*model.EvalRun
+	var total int64
+
+	query := r.db.Model(&model.EvalRun{}).Where("user_id = ?", userID)
+	if req.DatasetID != 0 {
+		query = query.Where("dataset_id = ?", req.DatasetID)
+	}
+	if req.ElementID != 0 {
+		query = query.Where("element_id = ?", req.ElementID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.Size
+	if err := query.Order("created_at DESC").Order("id DESC").Offset(offset).Limit(req.Size).Find(&runs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return runs, total, nil
+}
+
+// DeleteRun 删除评估运行及其用例结果
+func (r *evalRepository) DeleteRun(id uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("run_id = ?", id).Delete(&model.EvalResult{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.EvalRun{}, id).Error
+	})
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"errors"
+
+	"cese-backend/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// FolderRepository 文件夹数据访问接口
+type FolderRepository interface {
+	Create(folder *model.Folder) error
+	GetByID(id uint64) (*model.Folder, error)
+	GetByUserID(userID uint64) ([]*model.Folder, error)
+	CountElements(userID uint64) (map[uint64]int64, error)
+	ExistsByName(userID uint64, parentID *uint64, name string) (bool, error)
+	Rename(id uint64, name string) error
+	Move(id uint64, parentID *uint64) error
+	DeleteCascade(userID uint64, ids []uint64) error
+	DeleteReparent(folder *model.Folder) error
+}
+
+// folderRepository 文件夹数据访问实现
+type folderRepository struct {
+	db *gorm.DB
+}
+
+// NewFolderRepository 创建文件夹Repository实例
+func NewFolderRepository(db *gorm.DB) FolderRepository {
+	return &folderRepository{db: db}
+}
+
+// Create 创建文件夹
+func (r *folderRepository) Create(folder *model.Folder) error {
+	return r.db.Create(folder).Error
+}
+
+// GetByID 根据ID获取文件夹
+func (r *folderRepository) GetByID(id uint64) (*model.Folder, error) {
+	var folder model.Folder
+	err := r.db.Where("id = ?", id).First(&folder).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &folder, nil
+}
+
+// GetByUserID 获取用户的全部文件夹
+func (r *folderRepository) GetByUserID(userID uint64) ([]*model.Folder, error) {
+	var folders []*model.Folder
+	err := r.db.Where("user_id = ?", userID).Order("name ASC").Find(&folders).Error
+	if err != nil {
+		return nil, err
+	}
+	return folders, nil
+}
+
+// CountElements 统计用户每个文件夹中直接包含的六要素数量
+func (r *folderRepository) CountElements(userID uint64) (map[uint64]int64, error) {
+	var rows []struct {
+		FolderID uint64
+		Count    int64
+	}
+	err := r.db.Model(&model.ContextElement{}).
+		Select("folder_id, COUNT(*) AS count").
+		Where("user_id = ? AND folder_id IS NOT NULL", userID).
+		Group("folder_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint64]int64, len(rows))
+	for _, row := range rows {
+		counts[row.FolderID] = row.Count
+	}
+	return counts, nil
+}
+
+// ExistsByName 检查同一父文件夹下是否存在同名文件夹
+func (r *folderRepository) ExistsByName(userID uint64, parentID *uint64, name string) (bool, error) {
+	var count int64
+	query := r.db.Model(&model.Folder{}).Where("user_id = ? AND name = ?", userID, name)
+	if parentID == nil {
+		query = query.Where("parent_id IS NULL")
+	} else {
+		query = query.Where("parent_id = ?", *parentID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Rename 重命名文件夹
+func (r *folderRepository) Rename(id uint64, name string) error {
+	return r.db.Model(&model.Folder{}).Where("id = ?", id).Update("name", name).Error
+}
+
+// Move 移动文件夹到新的父文件夹
+func (r *folderRepository) Move(id uint64, parentID *uint64) error {
+	return r.db.Model(&model.Folder{}).Where("id = ?", id).Update("parent_id", parentID).Error
+}
+
+// DeleteCascade 删除文件夹（含子孙文件夹）及其中的六要素
+func (r *folderRepository) DeleteCascade(userID uint64, ids []uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("user_id = ? AND folder_id IN ?", userID, ids).Delete(&model.ContextElement{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("user_id = ? AND id IN ?", userID, ids).Delete(&model.Folder{}).Error
+	})
+}
+
+// DeleteReparent 删除文件夹，子文件夹及六要素移动到其上级文件夹
+func (r *folderRepository) DeleteReparent(folder *model.Folder) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.Folder{}).
+			Where("user_id = ? AND parent_id = ?", folder.UserID, folder.ID).
+			Update("parent_id", folder.ParentID).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&model.ContextElement{}).
+			Where("user_id = ? AND folder_id = ?", folder.UserID, folder.ID).
+			Update("folder_id", folder.ParentID).Error
+		if err != nil {
+			return err
+		}
+		return tx.Delete(&model.Folder{}, folder.ID).Error
+	})
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"errors"
+
+	"cese-backend/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// GenerationRecordRepository 生成记录数据访问接口
+type GenerationRecordRepository interface {
+	Create(record *model.GenerationRecord) error
+	GetByID(id uint64) (*model.GenerationRecord, error)
+	GetList(userID uint64, req *model.GenerationRecordQueryRequest) ([]*model.GenerationRecord, int64, error)
+	Update(record *model.GenerationRecord) error
+	Delete(id uint64) error
+}
+
+// generationRecordRepository 生成记录数据访问实现
+type generationRecordRepository struct {
+	db *gorm.DB
+}
+
+// NewGenerationRecordRepository 创建生成记录Repository实例
+func NewGenerationRecordRepository(db *gorm.DB) GenerationRecordRepository {
+	return &generationRecordRepository{db: db}
+}
+
+// Create 创建生成记录
+func (r *generationRecordRepository) Create(record *model.GenerationRecord) error {
+	return r.db.Create(record).Error
+}
+
+// GetByID 根据ID获取生成记录
+func (r *generationRecordRepository) GetByID(id uint64) (*model.GenerationRecord, error) {
+	var record model.GenerationRecord
+	err := r.db.Where("id = ?", id).First(&record).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+// GetList 分页查询用户的生成记录
+func (r *generationRecordRepository) GetList(userID uint64, req *model.GenerationRecordQueryRequest) ([]*model.GenerationRecord, int64, error) {
+	var records []*model.GenerationRecord
+	var total int64
+
+	query := r.db.Model(&model.GenerationRecord{}).Where("user_id = ?", userID)
+	if req.ElementID != 0 {
+		query = query.Where("element_id = ?", req.ElementID)
+	}
+	if req.Provider != "" {
+		query = query.Where("provider = ?", req.Provider)
+	}
+	if req.Model != "" {
+		query = query.Where("model = ?", req.Model)
+	}
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.Starred != nil {
+		query = query.Where("starred = ?", *req.Starred)
+	}
+	since, before := req.DateRange()
+	if since != nil {
+		query = query.Where("created_at >= ?", *since)
+	}
+	if before != nil {
+		query = query.Where("created_at < ?", *before)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	sortBy := req.SortBy
+	if sortBy == "" {
+		sortBy = "created_at"
+	}
+	if req.SortDesc {
+		query = query.Order(sortBy + " DESC").Order("id DESC")
+	} else {
+		query = query.Order(sortBy + " ASC").Order("id ASC")
+	}
+
+	offset := (req.Page - 1) * req.Size
+	if err := query.Offset(offset).Limit(req.Size).Find(&records).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return records, total, nil
+}
+
+// Update 更新生成记录的收藏状态和备注
+func (r *generationRecordRepository) Update(record *model.GenerationRecord) error {
+	return r.db.Model(record).Select("starred", "note").Updates(record).Error
+}
+
+// Delete 删除生成记录
+func (r *generationRecordRepository) Delete(id uint64) error {
+	return r.db.Delete(&model.GenerationRecord{}, id).Error
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"errors"
+
+	"cese-backend/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// ProviderConfigRepository 模型服务配置数据访问接口
+type ProviderConfigRepository interface {
+	Create(config *model.ProviderConfig) error
+	GetByID(id uint64) (*model.ProviderConfig, error)
+	GetByName(userID uint64, name string) (*model.ProviderConfig, error)
+	GetByUserID(userID uint64) ([]*model.ProviderConfig, error)
+	GetByStaleKey(currentKeyID string) ([]*model.ProviderConfig, error)
+	Update(config *model.ProviderConfig) error
+	UpdateAPIKey(config *model.ProviderConfig) error
+	Delete(id uint64) error
+}
+
+// providerConfigRepository 模型服务配置数据访问实现
+type providerConfigRepository struct {
+	db *gorm.DB
+}
+
+// NewProviderConfigRepository 创建模型服务配置Repository实例
+func NewProviderConfigRepository(db *gorm.DB) ProviderConfigRepository {
+	return &providerConfigRepository{db: db}
+}
+
+// Create 创建模型服务配置
+func (r *providerConfigRepository) Create(config *model.ProviderConfig) error {
+	return r.db.Create(config).Error
+}
+
+// GetByID 根据ID获取模型服务配置
+func (r *providerConfigRepository) GetByID(id uint64) (*model.ProviderConfig, error) {
+	var config model.ProviderConfig
+	err := r.db.Where("id = ?", id).First(&config).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &config, nil
+}
+
+// GetByName 根据名称获取用户的模型服务配置
+func (r *providerConfigRepository) GetByName(userID uint64, name string) (*model.ProviderConfig, error) {
+	var config model.ProviderConfig
+	err := r.db.Where("user_id = ? AND name = ?", userID, name).First(&config).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &config, nil
+}
+
+// GetByUserID 获取用户的全部模型服务配置（按名称排序）
+func (r *providerConfigRepository) GetByUserID(userID uint64) ([]*model.ProviderConfig, error) {
+	var configs []*model.ProviderConfig
+	err := r.db.Where("user_id = ?", userID).Order("name ASC").Find(&configs).Error
+	if err != nil {
+		return nil, err
+	}
+	return configs, nil
+}
+
+// GetByStaleKey 获取 API Key 不是用当前密钥加密的配置
+func (r *providerConfigRepository) GetByStaleKey(currentKeyID string) ([]*model.ProviderConfig, error) {
+	var configs []*model.ProviderConfig
+	err := r.db.Where("api_key_cipher <> '' AND api_key_id <> ?", currentKeyID).Find(&configs).Error
+	if err != nil {
+		return nil, err
+	}
+	return configs, nil
+}
+
+// Update 更新模型服务配置（包括 API Key）
+func (r *providerConfigRepository) Update(config *model.ProviderConfig) error {
+	return r.db.Save(config).Error
+}
+
+// UpdateAPIKey 只更新加密的 API Key，不修改更新时间
+func (r *providerConfigRepository) UpdateAPIKey(config *model.ProviderConfig) error {
+	return r.db.Model(config).UpdateColumns(map[string]interface{}{
+		"api_key_cipher": config.APIKeyCipher,
+		"api_key_id":     config.APIKeyID,
+	}).Error
+}
+
+// Delete 删除模型服务配置
+func (r *providerConfigRepository) Delete(id uint64) error {
+	return r.db.Delete(&model.ProviderConfig{}, id).Error
+}
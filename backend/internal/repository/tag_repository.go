@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"errors"
+
+	"cese-backend/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// elementTag 六要素与标签的关联关系
+type elementTag struct {
+	ContextElementID uint64 `gorm:"primaryKey"`
+	TagID            uint64 `gorm:"primaryKey"`
+}
+
+// TableName 指定表名
+func (elementTag) TableName() string {
+	return "cese_context_element_tag"
+}
+
+// TagRepository 标签数据访问接口
+type TagRepository interface {
+	Create(tag *model.Tag) error
+	GetByID(id uint64) (*model.Tag, error)
+	GetByIDs(userID uint64, ids []uint64) ([]*model.Tag, error)
+	GetByName(userID uint64, name string) (*model.Tag, error)
+	GetByUserIDWithCount(userID uint64) ([]*model.TagWithCount, error)
+	Rename(id uint64, name string) error
+	Merge(targetID uint64, sourceIDs []uint64) error
+	Delete(id uint64) error
+}
+
+// tagRepository 标签数据访问实现
+type tagRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRepository 创建标签Repository实例
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+// Create 创建标签
+func (r *tagRepository) Create(tag *model.Tag) error {
+	return r.db.Create(tag).Error
+}
+
+// GetByID 根据ID获取标签
+func (r *tagRepository) GetByID(id uint64) (*model.Tag, error) {
+	var tag model.Tag
+	err := r.db.Where("id = ?", id).First(&tag).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// GetByIDs 根据ID列表获取用户的标签
+func (r *tagRepository) GetByIDs(userID uint64, ids []uint64) ([]*model.Tag, error) {
+	var tags []*model.Tag
+	err := r.db.Where("user_id = ? AND id IN ?", userID, ids).Find(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// GetByName 根据名称获取用户的标签
+func (r *tagRepository) GetByName(userID uint64, name string) (*model.Tag, error) {
+	var tag model.Tag
+	err := r.db.Where("user_id = ? AND name = ?", userID, name).First(&tag).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// GetByUserIDWithCount 获取用户的全部标签及关联的六要素数量
+func (r *tagRepository) GetByUserIDWithCount(userID uint64) ([]*model.TagWithCount, error) {
+	var tags []*model.TagWithCount
+	err := r.db.Table("cese_tag AS t").
+		Select("t.*, COUNT(e.id) AS count").
+		Joins("LEFT JOIN cese_context_element_tag AS et ON et.tag_id = t.id").
+		Joins("LEFT JOIN cese_context_element AS e ON e.id = et.context_element_id AND e.deleted_at IS NULL").
+		Where("t.user_id = ?", userID).
+		Group("t.id").
+		Order("t.name ASC").
+		Scan(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// findOrCreateTags 在事务中按名称查找用户的标签，不存在的自动创建（结果与names顺序一致）
+func findOrCreateTags(tx *gorm.DB, userID uint64, names []string) ([]model.Tag, error) {
+	if len(names) == 0 {
+		return []model.Tag{}, nil
+	}
+
+	var existing []model.Tag
+	if err := tx.Where("user_id = ? AND name IN ?", userID, names).Find(&existing).Error; err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]model.Tag, len(existing))
+	for _, tag := range existing {
+		byName[tag.Name] = tag
+	}
+
+	tags := make([]model.Tag, 0, len(names))
+	for _, name := range names {
+		tag, ok := byName[name]
+		if !ok {
+			tag = model.Tag{UserID: userID, Name: name}
+			if err := tx.Create(&tag).Error; err != nil {
+				return nil, err
+			}
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+// Rename 重命名标签
+func (r *tagRepository) Rename(id uint64, name string) error {
+	return r.db.Model(&model.Tag{}).Where("id = ?", id).Update("name", name).Error
+}
+
+// Merge 将源标签合并到目标标签：关联关系迁移到目标标签后删除源标签
+func (r *tagRepository) Merge(targetID uint64, sourceIDs []uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var elementIDs []uint64
+		err := tx.Model(&elementTag{}).Distinct("context_element_id").
+			Where("tag_id IN ?", sourceIDs).Pluck("context_element_id", &elementIDs).Error
+		if err != nil {
+			return err
+		}
+		if len(elementIDs) > 0 {
+			links := make([]elementTag, len(elementIDs))
+			for i, elementID := range elementIDs {
+				links[i] = elementTag{ContextElementID: elementID, TagID: targetID}
+			}
+			// 已带有目标标签的六要素跳过
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&links).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Exec("DELETE FROM cese_context_element_tag WHERE tag_id IN ?", sourceIDs).Error; err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", sourceIDs).Delete(&model.Tag{}).Error
+	})
+}
+
+// Delete 删除标签及其关联关系
+func (r *tagRepository) Delete(id uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM cese_context_element_tag WHERE tag_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.Tag{}, id).Error
+	})
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"errors"
+
+	"cese-backend/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// TemplateRepository 公共模板数据访问接口
+type TemplateRepository interface {
+	GetByID(id uint64) (*model.Template, error)
+	GetByElementID(elementID uint64) (*model.Template, error)
+	GetList(userID uint64, req *model.TemplateQueryRequest) ([]*model.Template, int64, error)
+	GetCategories() ([]*model.TemplateCategoryResponse, error)
+	Save(template *model.Template) error
+	Delete(id uint64) error
+	Fork(element *model.ContextElement, variables []*model.ContextElementVariable, templateID uint64) error
+}
+
+// sourceElementActive 来源六要素未进入回收站：六要素删除后模板不再公开，恢复后重新公开
+const sourceElementActive = "EXISTS (SELECT 1 FROM cese_context_element AS e " +
+	"WHERE e.id = cese_template.element_id AND e.deleted_at IS NULL)"
+
+// templateRepository 公共模板数据访问实现
+type templateRepository struct {
+	db *gorm.DB
+}
+
+// NewTemplateRepository 创建公共模板Repository实例
+func NewTemplateRepository(db *gorm.DB) TemplateRepository {
+	return &templateRepository{db: db}
+}
+
+// GetByID 根据ID获取已发布的模板（来源六要素在回收站中时视为不存在）
+func (r *templateRepository) GetByID(id uint64) (*model.Template, error) {
+	var template model.Template
+	err := r.db.Where("id = ?", id).Where(sourceElementActive).First(&template).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+// GetByElementID 根据来源六要素获取模板（包含已取消发布的模板，便于重新发布时复用）
+func (r *templateRepository) GetByElementID(elementID uint64) (*model.Template, error) {
+	var template model.Template
+	err := r.db.Unscoped().Where("element_id = ?", elementID).First(&template).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+// GetList 查询已发布的模板列表（userID不为0时仅查询该用户发布的模板）
+func (r *templateRepository) GetList(userID uint64, req *model.TemplateQueryRequest) ([]*model.Template, int64, error) {
+	var templates []*model.Template
+	var total int64
+
+	query := r.db.Model(&model.Template{}).Where(sourceElementActive)
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if req.Category != "" {
+		query = query.Where("category = ?", req.Category)
+	}
+	if req.Keyword != "" {
+		keyword := "%" + req.Keyword + "%"
+		query = query.Where(
+			"subject LIKE ? OR description LIKE ? OR task_goal LIKE ? OR ai_role LIKE ?",
+			keyword, keyword, keyword, keyword,
+		)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	sortBy := req.SortBy
+	if sortBy == "" {
+		sortBy = "created_at"
+	}
+	if req.SortDesc {
+		query = query.Order(sortBy + " DESC")
+	} else {
+		query = query.Order(sortBy + " ASC")
+	}
+
+	offset := (req.Page - 1) * req.Size
+	if err := query.Offset(offset).Limit(req.Size).Find(&templates).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return templates, total, nil
+}
+
+// GetCategories 获取已发布模板的分类及数量
+func (r *templateRepository) GetCategories() ([]*model.TemplateCategoryResponse, error) {
+	var categories []*model.TemplateCategoryResponse
+	err := r.db.Model(&model.Template{}).Where(sourceElementActive).
+		Select("category, COUNT(*) AS count").
+		Group("category").
+		Order("count DESC, category ASC").
+		Scan(&categories).Error
+	if err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+// Save 保存模板（重新发布时恢复已取消发布的记录）
+func (r *templateRepository) Save(template *model.Template) error {
+	template.DeletedAt = gorm.DeletedAt{}
+	return r.db.Unscoped().Save(template).Error
+}
+
+// Delete 取消发布模板
+func (r *templateRepository) Delete(id uint64) error {
+	return r.db.Delete(&model.Template{}, id).Error
+}
+
+// Fork 创建复刻的六要素（含标签和变量定义）并累加模板复刻次数（同一事务）
+func (r *templateRepository) Fork(element *model.ContextElement, variables []*model.ContextElementVariable, templateID uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := createElement(tx, element); err != nil {
+			return err
+		}
+		if len(variables) > 0 {
+			for _, variable := range variables {
+				variable.ElementID = element.ID
+			}
+			if err := tx.Create(&variables).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&model.Template{}).
+			Where("id = ?", templateID).
+			UpdateColumn("fork_count", gorm.Expr("fork_count + 1")).Error
+	})
+}
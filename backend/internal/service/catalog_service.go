@@ -0,0 +1,257 @@
+package service
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"cese-backend/internal/model"
+	"cese-backend/internal/repository"
+	"cese-backend/pkg/validator"
+)
+
+// defaultSuggestionLimit 未指定数量时每类推荐返回的数量
+const defaultSuggestionLimit = 10
+
+// catalogSeedData 内置的角色与交付格式目录，启动时导入数据库中还没有的项
+//
+//go:embed data/catalog.json
+var catalogSeedData []byte
+
+// CatalogService 角色与交付格式推荐服务接口
+type CatalogService interface {
+	Seed() (int, error)
+	GetSuggestions(userID uint64, req *model.SuggestionQueryRequest) (*model.SuggestionsResponse, error)
+	GetList(req *model.CatalogQueryRequest) ([]*model.CatalogItemResponse, error)
+	Create(req *model.CatalogItemRequest) (*model.CatalogItemResponse, error)
+	Update(itemID uint64, req *model.CatalogItemRequest) (*model.CatalogItemResponse, error)
+	Delete(itemID uint64) error
+}
+
+// catalogService 角色与交付格式推荐服务实现
+type catalogService struct {
+	catalogRepo repository.CatalogRepository
+}
+
+// NewCatalogService 创建推荐服务实例
+func NewCatalogService(catalogRepo repository.CatalogRepository) CatalogService {
+	return &catalogService{
+		catalogRepo: catalogRepo,
+	}
+}
+
+// Seed 导入内置目录，已导入过的项（包括管理员修改或删除的）保持不变，返回导入数量
+func (s *catalogService) Seed() (int, error) {
+	items, err := parseCatalogSeed(catalogSeedData)
+	if err != nil {
+		return 0, err
+	}
+	return s.catalogRepo.Seed(items)
+}
+
+// GetSuggestions 获取推荐：用户自己最常用的内容和目录中的预设项，均按前缀过滤
+func (s *catalogService) GetSuggestions(userID uint64, req *model.SuggestionQueryRequest) (*model.SuggestionsResponse, error) {
+	req.Q = strings.TrimSpace(req.Q)
+
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, errors.New("参数验证失败")
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultSuggestionLimit
+	}
+
+	fields := model.CatalogFields
+	if req.Field != "" {
+		fields = []string{req.Field}
+	}
+
+	personal := make([]*model.PersonalSuggestion, 0, req.Limit)
+	for _, field := range fields {
+		usages, err := s.catalogRepo.GetFieldUsage(userID, field, req.Q, req.Limit)
+		if err != nil {
+			return nil, errors.New("查询常用内容失败")
+		}
+		for _, usage := range usages {
+			personal = append(personal, usage.ToSuggestion())
+		}
+	}
+	// 查询多个字段时合并后按使用次数重新排序
+	if len(fields) > 1 {
+		sortPersonalSuggestions(personal)
+	}
+	if len(personal) > req.Limit {
+		personal = personal[:req.Limit]
+	}
+
+	items, err := s.catalogRepo.GetList(req.Field, "", req.Q, req.Limit)
+	if err != nil {
+		return nil, errors.New("查询推荐目录失败")
+	}
+
+	return &model.SuggestionsResponse{
+		Personal: personal,
+		Catalog:  catalogResponses(items),
+	}, nil
+}
+
+// GetList 获取推荐目录
+func (s *catalogService) GetList(req *model.CatalogQueryRequest) ([]*model.CatalogItemResponse, error) {
+	req.Category = strings.TrimSpace(req.Category)
+
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, errors.New("参数验证失败")
+	}
+
+	items, err := s.catalogRepo.GetList(req.Field, req.Category, "", 0)
+	if err != nil {
+		return nil, errors.New("查询推荐目录失败")
+	}
+	return catalogResponses(items), nil
+}
+
+// Create 新增目录项
+func (s *catalogService) Create(req *model.CatalogItemRequest) (*model.CatalogItemResponse, error) {
+	if err := s.validateItem(0, req); err != nil {
+		return nil, err
+	}
+
+	item := &model.CatalogItem{}
+	item.Apply(req)
+	if err := s.catalogRepo.Create(item); err != nil {
+		// 并发创建同名目录项时由唯一索引拦截
+		if errors.Is(err, repository.ErrCatalogItemExists) {
+			return nil, errors.New("目录项已存在")
+		}
+		return nil, errors.New("创建目录项失败")
+	}
+
+	return item.ToResponse(), nil
+}
+
+// Update 修改目录项，内置项修改后不会被重新导入覆盖
+func (s *catalogService) Update(itemID uint64, req *model.CatalogItemRequest) (*model.CatalogItemResponse, error) {
+	item, err := s.getItem(itemID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateItem(itemID, req); err != nil {
+		return nil, err
+	}
+
+	item.Apply(req)
+	if err := s.catalogRepo.Update(item); err != nil {
+		if errors.Is(err, repository.ErrCatalogItemExists) {
+			return nil, errors.New("目录项已存在")
+		}
+		return nil, errors.New("更新目录项失败")
+	}
+
+	return item.ToResponse(), nil
+}
+
+// Delete 删除目录项，内置项删除后不会被重新导入
+func (s *catalogService) Delete(itemID uint64) error {
+	if _, err := s.getItem(itemID); err != nil {
+		return err
+	}
+
+	if err := s.catalogRepo.Delete(itemID); err != nil {
+		return errors.New("删除目录项失败")
+	}
+
+	return nil
+}
+
+// validateItem 校验目录项请求，同一字段下名称不能重复
+func (s *catalogService) validateItem(itemID uint64, req *model.CatalogItemRequest) error {
+	req.Category = strings.TrimSpace(req.Category)
+	req.Name = strings.TrimSpace(req.Name)
+	req.Description = strings.TrimSpace(req.Description)
+	req.Example = strings.TrimSpace(req.Example)
+
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return errors.New("参数验证失败")
+	}
+
+	existing, err := s.catalogRepo.GetByName(req.Field, req.Name)
+	if err != nil {
+		return errors.New("查询目录项失败")
+	}
+	if existing != nil && existing.ID != itemID {
+		return errors.New("目录项已存在")
+	}
+	return nil
+}
+
+// getItem 获取目录项
+func (s *catalogService) getItem(itemID uint64) (*model.CatalogItem, error) {
+	item, err := s.catalogRepo.GetByID(itemID)
+	if err != nil {
+		return nil, errors.New("查询目录项失败")
+	}
+	if item == nil {
+		return nil, errors.New("目录项不存在")
+	}
+	return item, nil
+}
+
+// parseCatalogSeed 解析内置目录数据，排序值按每个字段内的顺序以10递增，便于管理员在中间插入
+func parseCatalogSeed(data []byte) ([]*model.CatalogItem, error) {
+	var seeds []*model.CatalogSeed
+	if err := json.Unmarshal(data, &seeds); err != nil {
+		return nil, fmt.Errorf("解析内置目录失败: %w", err)
+	}
+
+	keys := make(map[string]bool, len(seeds))
+	positions := make(map[string]int, len(model.CatalogFields))
+	items := make([]*model.CatalogItem, len(seeds))
+	for i, seed := range seeds {
+		if seed.Key == "" || keys[seed.Key] {
+			return nil, fmt.Errorf("内置目录第%d项的 key 为空或重复", i+1)
+		}
+		keys[seed.Key] = true
+
+		if !isCatalogField(seed.Field) || seed.Name == "" || seed.Example == "" {
+			return nil, fmt.Errorf("内置目录 %s 的字段、名称或示例无效", seed.Key)
+		}
+
+		positions[seed.Field]++
+		items[i] = seed.NewCatalogItem(positions[seed.Field] * 10)
+	}
+	return items, nil
+}
+
+// isCatalogField 判断是否为提供推荐的六要素字段
+func isCatalogField(field string) bool {
+	for _, f := range model.CatalogFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// sortPersonalSuggestions 按使用次数降序排列，次数相同时最近使用的在前
+func sortPersonalSuggestions(suggestions []*model.PersonalSuggestion) {
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Count != suggestions[j].Count {
+			return suggestions[i].Count > suggestions[j].Count
+		}
+		return suggestions[i].LastUsedAt.After(suggestions[j].LastUsedAt)
+	})
+}
+
+// catalogResponses 将目录项转换为响应格式
+func catalogResponses(items []*model.CatalogItem) []*model.CatalogItemResponse {
+	responses := make([]*model.CatalogItemResponse, len(items))
+	for i, item := range items {
+		responses[i] = item.ToResponse()
+	}
+	return responses
+}
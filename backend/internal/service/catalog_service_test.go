@@ -0,0 +1,253 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"cese-backend/internal/model"
+	"cese-backend/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockCatalogRepository 推荐目录Repository模拟
+type MockCatalogRepository struct {
+	mock.Mock
+}
+
+func (m *MockCatalogRepository) Create(item *model.CatalogItem) error {
+	args := m.Called(item)
+	return args.Error(0)
+}
+
+func (m *MockCatalogRepository) GetByID(id uint64) (*model.CatalogItem, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CatalogItem), args.Error(1)
+}
+
+func (m *MockCatalogRepository) GetByName(field, name string) (*model.CatalogItem, error) {
+	args := m.Called(field, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CatalogItem), args.Error(1)
+}
+
+func (m *MockCatalogRepository) GetList(field, category, prefix string, limit int) ([]*model.CatalogItem, error) {
+	args := m.Called(field, category, prefix, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.CatalogItem), args.Error(1)
+}
+
+func (m *MockCatalogRepository) Update(item *model.CatalogItem) error {
+	args := m.Called(item)
+	return args.Error(0)
+}
+
+func (m *MockCatalogRepository) Delete(id uint64) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockCatalogRepository) Seed(items []*model.CatalogItem) (int, error) {
+	args := m.Called(items)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockCatalogRepository) GetFieldUsage(userID uint64, field, prefix string, limit int) ([]*model.FieldUsage, error) {
+	args := m.Called(userID, field, prefix, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.FieldUsage), args.Error(1)
+}
+
+func TestCatalogService_Seed(t *testing.T) {
+	mockRepo := new(MockCatalogRepository)
+	service := NewCatalogService(mockRepo)
+
+	var seeded []*model.CatalogItem
+	mockRepo.On("Seed", mock.Anything).Run(func(args mock.Arguments) {
+		seeded = args.Get(0).([]*model.CatalogItem)
+	}).Return(3, nil)
+
+	count, err := service.Seed()
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	// 内置数据覆盖三个字段，排序值在每个字段内从10开始递增
+	byField := make(map[string][]*model.CatalogItem)
+	for _, item := range seeded {
+		require.NotNil(t, item.SeedKey)
+		assert.NotEmpty(t, item.Example)
+		byField[item.Field] = append(byField[item.Field], item)
+	}
+	for _, field := range model.CatalogFields {
+		require.NotEmpty(t, byField[field], field)
+		assert.Equal(t, 10, byField[field][0].SortOrder)
+	}
+	assert.Equal(t, "你是一位专业顾问，具有丰富经验的行业专家", byField[model.CatalogFieldAIRole][0].Example)
+
+	_, err = parseCatalogSeed([]byte(`[{"key": "a", "field": "task_goal", "name": "目标", "example": "完成任务"}]`))
+	assert.Error(t, err)
+	_, err = parseCatalogSeed([]byte(`[{"key": "a", "field": "ai_role", "name": "顾问", "example": "你是一位顾问"},
+		{"key": "a", "field": "my_role", "name": "学生", "example": "我是一名学生"}]`))
+	assert.Error(t, err)
+}
+
+func TestCatalogService_GetSuggestions(t *testing.T) {
+	mockRepo := new(MockCatalogRepository)
+	service := NewCatalogService(mockRepo)
+
+	now := time.Now()
+	mockRepo.On("GetFieldUsage", uint64(1), "ai_role", "技术", 2).Return([]*model.FieldUsage{
+		{Field: "ai_role", Value: "技术专家", Count: 2, LastUsedAt: now.Add(-time.Hour)},
+	}, nil)
+	mockRepo.On("GetFieldUsage", uint64(1), "my_role", "技术", 2).Return([]*model.FieldUsage{
+		{Field: "my_role", Value: "技术负责人\n负责团队的技术选型", Count: 5, LastUsedAt: now.Add(-time.Hour)},
+		{Field: "my_role", Value: "技术新人", Count: 2, LastUsedAt: now},
+	}, nil)
+	mockRepo.On("GetFieldUsage", uint64(1), "delivery_format", "技术", 2).Return([]*model.FieldUsage{}, nil)
+	mockRepo.On("GetList", "", "", "技术", 2).Return([]*model.CatalogItem{
+		{ID: 2, Field: "ai_role", Name: "技术专家", Example: "你是一位技术专家"},
+	}, nil)
+
+	// 多个字段的常用内容合并后按次数排序，次数相同时最近使用的在前
+	suggestions, err := service.GetSuggestions(1, &model.SuggestionQueryRequest{Q: " 技术 ", Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, suggestions.Personal, 2)
+	assert.Equal(t, "技术负责人", suggestions.Personal[0].Name)
+	assert.Equal(t, "技术负责人\n负责团队的技术选型", suggestions.Personal[0].Value)
+	assert.Equal(t, "技术新人", suggestions.Personal[1].Value)
+	require.Len(t, suggestions.Catalog, 1)
+	assert.Equal(t, "技术专家", suggestions.Catalog[0].Name)
+
+	// 默认每类返回10个
+	mockRepo.On("GetFieldUsage", uint64(1), "delivery_format", "", 10).Return([]*model.FieldUsage{}, nil)
+	mockRepo.On("GetList", "delivery_format", "", "", 10).Return([]*model.CatalogItem{}, nil)
+	suggestions, err = service.GetSuggestions(1, &model.SuggestionQueryRequest{Field: "delivery_format"})
+	require.NoError(t, err)
+	assert.Empty(t, suggestions.Personal)
+	assert.Empty(t, suggestions.Catalog)
+
+	_, err = service.GetSuggestions(1, &model.SuggestionQueryRequest{Field: "task_goal"})
+	assert.EqualError(t, err, "参数验证失败")
+	_, err = service.GetSuggestions(1, &model.SuggestionQueryRequest{Limit: 100})
+	assert.EqualError(t, err, "参数验证失败")
+}
+
+func TestCatalogService_Create(t *testing.T) {
+	mockRepo := new(MockCatalogRepository)
+	service := NewCatalogService(mockRepo)
+
+	mockRepo.On("GetByName", "ai_role", "技术专家").Return(nil, nil)
+	mockRepo.On("Create", mock.MatchedBy(func(item *model.CatalogItem) bool {
+		return item.Name == "技术专家" && item.SeedKey == nil
+	})).Return(nil).Once()
+	item, err := service.Create(&model.CatalogItemRequest{Field: "ai_role", Name: " 技术专家 ", Example: "你是一位技术专家"})
+	require.NoError(t, err)
+	assert.Equal(t, "技术专家", item.Name)
+
+	// 检查通过后被并发请求抢先创建时由唯一索引拦截
+	mockRepo.On("Create", mock.Anything).Return(repository.ErrCatalogItemExists).Once()
+	_, err = service.Create(&model.CatalogItemRequest{Field: "ai_role", Name: "技术专家", Example: "你是一位技术专家"})
+	assert.EqualError(t, err, "目录项已存在")
+}
+
+func TestCatalogService_Update(t *testing.T) {
+	seedKey := "ai_role.consultant"
+
+	tests := []struct {
+		name    string
+		itemID  uint64
+		req     *model.CatalogItemRequest
+		setup   func(*MockCatalogRepository)
+		wantErr string
+	}{
+		{
+			name:   "修改内置项",
+			itemID: 1,
+			req:    &model.CatalogItemRequest{Field: "ai_role", Name: " 资深顾问 ", Example: "你是一位资深顾问"},
+			setup: func(m *MockCatalogRepository) {
+				m.On("GetByID", uint64(1)).Return(&model.CatalogItem{ID: 1, Field: "ai_role", Name: "专业顾问", SeedKey: &seedKey}, nil)
+				m.On("GetByName", "ai_role", "资深顾问").Return(nil, nil)
+				m.On("Update", mock.MatchedBy(func(item *model.CatalogItem) bool {
+					return item.Name == "资深顾问" && item.SeedKey == &seedKey
+				})).Return(nil)
+			},
+		},
+		{
+			name:   "只修改说明",
+			itemID: 1,
+			req:    &model.CatalogItemRequest{Field: "ai_role", Name: "专业顾问", Description: "行业专家", Example: "你是一位专业顾问"},
+			setup: func(m *MockCatalogRepository) {
+				m.On("GetByID", uint64(1)).Return(&model.CatalogItem{ID: 1, Field: "ai_role", Name: "专业顾问"}, nil)
+				m.On("GetByName", "ai_role", "专业顾问").Return(&model.CatalogItem{ID: 1, Field: "ai_role", Name: "专业顾问"}, nil)
+				m.On("Update", mock.Anything).Return(nil)
+			},
+		},
+		{
+			name:   "名称已存在",
+			itemID: 1,
+			req:    &model.CatalogItemRequest{Field: "ai_role", Name: "技术专家", Example: "你是一位技术专家"},
+			setup: func(m *MockCatalogRepository) {
+				m.On("GetByID", uint64(1)).Return(&model.CatalogItem{ID: 1, Field: "ai_role", Name: "专业顾问"}, nil)
+				m.On("GetByName", "ai_role", "技术专家").Return(&model.CatalogItem{ID: 2, Field: "ai_role", Name: "技术专家"}, nil)
+			},
+			wantErr: "目录项已存在",
+		},
+		{
+			name:   "并发修改为同名",
+			itemID: 1,
+			req:    &model.CatalogItemRequest{Field: "ai_role", Name: "技术专家", Example: "你是一位技术专家"},
+			setup: func(m *MockCatalogRepository) {
+				m.On("GetByID", uint64(1)).Return(&model.CatalogItem{ID: 1, Field: "ai_role", Name: "专业顾问"}, nil)
+				m.On("GetByName", "ai_role", "技术专家").Return(nil, nil)
+				m.On("Update", mock.Anything).Return(repository.ErrCatalogItemExists)
+			},
+			wantErr: "目录项已存在",
+		},
+		{
+			name:   "目录项不存在",
+			itemID: 9,
+			req:    &model.CatalogItemRequest{Field: "ai_role", Name: "技术专家", Example: "你是一位技术专家"},
+			setup: func(m *MockCatalogRepository) {
+				m.On("GetByID", uint64(9)).Return(nil, nil)
+			},
+			wantErr: "目录项不存在",
+		},
+		{
+			name:   "不支持的字段",
+			itemID: 1,
+			req:    &model.CatalogItemRequest{Field: "task_goal", Name: "写周报", Example: "写一份周报"},
+			setup: func(m *MockCatalogRepository) {
+				m.On("GetByID", uint64(1)).Return(&model.CatalogItem{ID: 1, Field: "ai_role", Name: "专业顾问"}, nil)
+			},
+			wantErr: "参数验证失败",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockCatalogRepository)
+			tt.setup(mockRepo)
+			service := NewCatalogService(mockRepo)
+
+			item, err := service.Update(tt.itemID, tt.req)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.req.Name, item.Name)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
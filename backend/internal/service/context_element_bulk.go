@@ -0,0 +1,304 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"cese-backend/internal/model"
+	"cese-backend/internal/repository"
+	"cese-backend/pkg/validator"
+)
+
+// maxBulkRecords 单次批量操作的最大记录数
+const maxBulkRecords = 1000
+
+// bulkBatch 批量操作的处理结果，rows 与待写入的记录一一对应
+type bulkBatch struct {
+	result *model.ContextElementBulkResult
+	rows   []*model.ContextElementBulkItemResult
+}
+
+// newBulkBatch 创建批量操作结果
+func newBulkBatch(atomic bool, total int) *bulkBatch {
+	return &bulkBatch{
+		result: &model.ContextElementBulkResult{
+			Atomic:  atomic,
+			Total:   total,
+			Results: make([]*model.ContextElementBulkItemResult, 0, total),
+		},
+	}
+}
+
+// fail 记录失败的记录
+func (b *bulkBatch) fail(row *model.ContextElementBulkItemResult, err error) {
+	row.Status = model.BulkStatusFailed
+	row.Error = err.Error()
+	b.result.Failed++
+}
+
+// unchanged 记录内容未变化的记录
+func (b *bulkBatch) unchanged(row *model.ContextElementBulkItemResult) {
+	row.Status = model.BulkStatusUnchanged
+	b.result.Unchanged++
+}
+
+// pending 记录待写入的记录
+func (b *bulkBatch) pending(row *model.ContextElementBulkItemResult) {
+	b.rows = append(b.rows, row)
+}
+
+// aborted atomic 模式下是否已有记录失败（无需再写入）
+func (b *bulkBatch) aborted() bool {
+	return b.result.Atomic && b.result.Failed > 0
+}
+
+// finish 根据写入结果填写每条记录的状态，errs 为空表示未执行写入
+func (b *bulkBatch) finish(errs []error, describe func(error) error) *model.ContextElementBulkResult {
+	for i, row := range b.rows {
+		if errs != nil && errs[i] != nil {
+			b.fail(row, describe(errs[i]))
+		}
+	}
+
+	b.result.Committed = !b.aborted()
+	for _, row := range b.rows {
+		if row.Status == model.BulkStatusFailed {
+			continue
+		}
+		if b.result.Committed {
+			row.Status = model.BulkStatusSucceeded
+			b.result.Succeeded++
+		} else {
+			row.Status = model.BulkStatusRolledBack
+		}
+	}
+	return b.result
+}
+
+// BulkCreate 批量创建六要素，在同一事务中写入
+func (s *contextElementService) BulkCreate(userID uint64, req *model.ContextElementBulkCreateRequest) (*model.ContextElementBulkResult, error) {
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, errors.New("参数验证失败")
+	}
+
+	batch := newBulkBatch(req.Atomic, len(req.Items))
+	folders := make(map[uint64]error)
+	items := make([]*model.ContextElementCreateRequest, 0, len(req.Items))
+	for i, item := range req.Items {
+		row := &model.ContextElementBulkItemResult{Index: i + 1}
+		batch.result.Results = append(batch.result.Results, row)
+		if item == nil {
+			batch.fail(row, errors.New("记录不能为空"))
+			continue
+		}
+
+		item.Subject = strings.TrimSpace(item.Subject)
+		item.Tags = model.NormalizeTagNames(item.Tags)
+		if err := validator.ValidateStruct(item); err != nil {
+			batch.fail(row, describeValidationError(err))
+			continue
+		}
+
+		// 检查文件夹归属（同一文件夹只查询一次）
+		if item.FolderID != nil {
+			err, checked := folders[*item.FolderID]
+			if !checked {
+				err = s.checkFolderOwned(userID, *item.FolderID)
+				folders[*item.FolderID] = err
+			}
+			if err != nil {
+				batch.fail(row, err)
+				continue
+			}
+		}
+
+		batch.pending(row)
+		items = append(items, item)
+	}
+	if batch.aborted() || len(items) == 0 {
+		return batch.finish(nil, nil), nil
+	}
+
+	elements := make([]*model.ContextElement, len(items))
+	for i, item := range items {
+		elements[i] = item.ToContextElement(userID)
+		elements[i].Tags = model.NewTags(userID, item.Tags)
+	}
+
+	errs, err := s.elementRepo.BulkCreate(elements, req.Atomic)
+	if err != nil {
+		return nil, errors.New("批量创建六要素失败")
+	}
+	result := batch.finish(errs, func(error) error {
+		return errors.New("创建六要素记录失败")
+	})
+	for i, row := range batch.rows {
+		if row.Status == model.BulkStatusSucceeded {
+			row.ID = elements[i].ID
+		}
+	}
+
+	return result, nil
+}
+
+// BulkDelete 批量删除六要素（进入回收站），在同一事务中写入
+func (s *contextElementService) BulkDelete(userID uint64, req *model.ContextElementBulkDeleteRequest) (*model.ContextElementBulkResult, error) {
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, errors.New("参数验证失败")
+	}
+
+	ids, elements, err := s.selectBulkElements(userID, &req.ContextElementBulkSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := newBulkBatch(req.Atomic, len(ids))
+	targets := make([]*model.ContextElement, 0, len(ids))
+	for _, id := range ids {
+		row := &model.ContextElementBulkItemResult{ID: id}
+		batch.result.Results = append(batch.result.Results, row)
+		element, ok := elements[id]
+		if !ok {
+			batch.fail(row, errors.New("六要素记录不存在"))
+			continue
+		}
+		batch.pending(row)
+		targets = append(targets, element)
+	}
+	if batch.aborted() || len(targets) == 0 {
+		return batch.finish(nil, nil), nil
+	}
+
+	errs, err := s.elementRepo.BulkDelete(targets, req.Atomic)
+	if err != nil {
+		return nil, errors.New("批量删除六要素失败")
+	}
+
+	return batch.finish(errs, func(err error) error {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return errors.New("版本冲突")
+		}
+		return errors.New("删除六要素记录失败")
+	}), nil
+}
+
+// BulkPatch 批量修改六要素的字段和标签，在同一事务中写入，每条修改都会保存历史版本
+func (s *contextElementService) BulkPatch(userID uint64, req *model.ContextElementBulkPatchRequest) (*model.ContextElementBulkResult, error) {
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("参数验证失败: %v", describeValidationError(err))
+	}
+	if req.IsEmpty() {
+		return nil, errors.New("参数验证失败: 未指定要修改的内容")
+	}
+
+	ids, elements, err := s.selectBulkElements(userID, &req.ContextElementBulkSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := newBulkBatch(req.Atomic, len(ids))
+	var updates []*repository.ElementUpdate
+	for _, id := range ids {
+		row := &model.ContextElementBulkItemResult{ID: id}
+		batch.result.Results = append(batch.result.Results, row)
+		element, ok := elements[id]
+		if !ok {
+			batch.fail(row, errors.New("六要素记录不存在"))
+			continue
+		}
+
+		update := element.ApplyBulkPatch(req)
+		if err := validator.ValidateStruct(update); err != nil {
+			batch.fail(row, describeValidationError(err))
+			continue
+		}
+		if element.SameContent(update) {
+			batch.unchanged(row)
+			continue
+		}
+
+		previous := model.NewContextElementVersion(element)
+		element.UpdateFromRequest(update)
+		element.Tags = model.NewTags(userID, update.Tags)
+		batch.pending(row)
+		updates = append(updates, &repository.ElementUpdate{Element: element, Previous: previous})
+	}
+	if batch.aborted() || len(updates) == 0 {
+		return batch.finish(nil, nil), nil
+	}
+
+	errs, err := s.elementRepo.BulkUpdate(updates, req.Atomic)
+	if err != nil {
+		return nil, errors.New("批量修改六要素失败")
+	}
+
+	return batch.finish(errs, func(err error) error {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return errors.New("版本冲突")
+		}
+		return errors.New("更新六要素记录失败")
+	}), nil
+}
+
+// selectBulkElements 按ID列表或过滤条件选出批量操作的目标
+//
+// 返回目标ID（ID列表去重后保持请求顺序，过滤条件按创建时间排序）以及其中属于当前用户的六要素。
+func (s *contextElementService) selectBulkElements(userID uint64, selector *model.ContextElementBulkSelector) ([]uint64, map[uint64]*model.ContextElement, error) {
+	switch {
+	case len(selector.IDs) > 0 && selector.Filter != nil:
+		return nil, nil, errors.New("参数验证失败: ids和filter不能同时指定")
+	case len(selector.IDs) > 0:
+		seen := make(map[uint64]bool, len(selector.IDs))
+		ids := make([]uint64, 0, len(selector.IDs))
+		for _, id := range selector.IDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+
+		elements, err := s.elementRepo.GetByIDs(userID, ids)
+		if err != nil {
+			return nil, nil, errors.New("查询六要素列表失败")
+		}
+		return ids, indexElements(elements), nil
+	case selector.Filter != nil:
+		if err := validator.ValidateStruct(selector.Filter); err != nil {
+			return nil, nil, errors.New("参数验证失败")
+		}
+
+		query := selector.Filter.ToQueryRequest()
+		total, err := s.elementRepo.CountByUserID(userID, query)
+		if err != nil {
+			return nil, nil, errors.New("查询六要素列表失败")
+		}
+		if total > maxBulkRecords {
+			return nil, nil, fmt.Errorf("参数验证失败: 匹配的记录数超过%d条，请缩小过滤范围", maxBulkRecords)
+		}
+
+		elements, err := s.elementRepo.GetAllByUserID(userID, query)
+		if err != nil {
+			return nil, nil, errors.New("查询六要素列表失败")
+		}
+		ids := make([]uint64, len(elements))
+		for i, element := range elements {
+			ids[i] = element.ID
+		}
+		return ids, indexElements(elements), nil
+	default:
+		return nil, nil, errors.New("参数验证失败: 请指定ids或filter")
+	}
+}
+
+// indexElements 按ID索引六要素
+func indexElements(elements []*model.ContextElement) map[uint64]*model.ContextElement {
+	indexed := make(map[uint64]*model.ContextElement, len(elements))
+	for _, element := range elements {
+		indexed[element.ID] = element
+	}
+	return indexed
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"cese-backend/internal/model"
+	"cese-backend/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyBulkPatch(t *testing.T) {
+	element := testPatchElement()
+	req := &model.ContextElementBulkPatchRequest{
+		Fields: []*model.ContextElementBulkFieldPatch{
+			{Field: "behavior_rule", Op: model.BulkFieldAppend, Value: "不要使用表格"},
+			{Field: "delivery_format", Value: "Markdown"},
+			{Field: "ai_role", Op: model.BulkFieldAppend, Value: "项目助理"},
+		},
+		AddTags:    []string{"周报", " 办公 "},
+		RemoveTags: []string{"办公"},
+	}
+
+	update := element.ApplyBulkPatch(req)
+	assert.Equal(t, "周报生成", update.Subject)
+	assert.Equal(t, "简洁\n不要使用表格", update.BehaviorRule)
+	assert.Equal(t, "Markdown", update.DeliveryFormat)
+	assert.Equal(t, "项目助理", update.AIRole)
+	assert.Equal(t, []string{"周报"}, update.Tags)
+	assert.False(t, element.SameContent(update))
+
+	// 原记录不受影响
+	assert.Equal(t, "简洁", element.BehaviorRule)
+
+	unchanged := element.ApplyBulkPatch(&model.ContextElementBulkPatchRequest{AddTags: []string{"办公"}})
+	assert.True(t, element.SameContent(unchanged))
+}
+
+func TestContextElementService_BulkCreate(t *testing.T) {
+	items := func() []*model.ContextElementCreateRequest {
+		return []*model.ContextElementCreateRequest{
+			{Subject: "周报生成", Tags: []string{"办公"}},
+			{Subject: "  "},
+			{Subject: "会议纪要", Tags: []string{"办公", "会议"}},
+		}
+	}
+
+	t.Run("atomic模式下有记录校验失败时不写入", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		s := newTestElementService(elementRepo)
+
+		result, err := s.BulkCreate(1, &model.ContextElementBulkCreateRequest{Items: items(), Atomic: true})
+		require.NoError(t, err)
+		assert.False(t, result.Committed)
+		assert.Equal(t, 3, result.Total)
+		assert.Equal(t, 1, result.Failed)
+		assert.Equal(t, 0, result.Succeeded)
+		assert.Equal(t, model.BulkStatusRolledBack, result.Results[0].Status)
+		assert.Equal(t, model.BulkStatusFailed, result.Results[1].Status)
+		assert.Equal(t, "主题不能为空", result.Results[1].Error)
+		assert.Equal(t, 2, result.Results[1].Index)
+		assert.Equal(t, model.BulkStatusRolledBack, result.Results[2].Status)
+		elementRepo.AssertNotCalled(t, "BulkCreate", mock.Anything, mock.Anything)
+	})
+
+	t.Run("非atomic模式下写入其余记录", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		s := newTestElementService(elementRepo)
+
+		elementRepo.On("BulkCreate", mock.Anything, false).Run(func(args mock.Arguments) {
+			elements := args.Get(0).([]*model.ContextElement)
+			require.Len(t, elements, 2)
+			assert.Equal(t, []model.Tag{{UserID: 1, Name: "办公"}, {UserID: 1, Name: "会议"}}, elements[1].Tags)
+			elements[0].ID = 10
+			elements[1].ID = 11
+		}).Return([]error{nil, errors.New("duplicate")}, nil)
+
+		result, err := s.BulkCreate(1, &model.ContextElementBulkCreateRequest{Items: items()})
+		require.NoError(t, err)
+		assert.True(t, result.Committed)
+		assert.Equal(t, 1, result.Succeeded)
+		assert.Equal(t, 2, result.Failed)
+		assert.Equal(t, uint64(10), result.Results[0].ID)
+		assert.Equal(t, model.BulkStatusSucceeded, result.Results[0].Status)
+		assert.Equal(t, model.BulkStatusFailed, result.Results[2].Status)
+		assert.Equal(t, "创建六要素记录失败", result.Results[2].Error)
+		assert.Zero(t, result.Results[2].ID)
+	})
+}
+
+func TestContextElementService_BulkDelete(t *testing.T) {
+	t.Run("按ID删除，保持请求顺序并去重", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		s := newTestElementService(elementRepo)
+
+		first := &model.ContextElement{ID: 3, UserID: 1, Version: 1}
+		second := &model.ContextElement{ID: 5, UserID: 1, Version: 2}
+		elementRepo.On("GetByIDs", uint64(1), []uint64{5, 4, 3}).Return([]*model.ContextElement{first, second}, nil)
+		elementRepo.On("BulkDelete", []*model.ContextElement{second, first}, false).
+			Return([]error{repository.ErrVersionConflict, nil}, nil)
+
+		result, err := s.BulkDelete(1, &model.ContextElementBulkDeleteRequest{
+			ContextElementBulkSelector: model.ContextElementBulkSelector{IDs: []uint64{5, 4, 3, 5}},
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Results, 3)
+		assert.Equal(t, &model.ContextElementBulkItemResult{ID: 5, Status: model.BulkStatusFailed, Error: "版本冲突"}, result.Results[0])
+		assert.Equal(t, &model.ContextElementBulkItemResult{ID: 4, Status: model.BulkStatusFailed, Error: "六要素记录不存在"}, result.Results[1])
+		assert.Equal(t, &model.ContextElementBulkItemResult{ID: 3, Status: model.BulkStatusSucceeded}, result.Results[2])
+	})
+
+	t.Run("atomic模式下写入失败整体回滚", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		s := newTestElementService(elementRepo)
+
+		elements := []*model.ContextElement{{ID: 3, UserID: 1}, {ID: 5, UserID: 1}}
+		elementRepo.On("GetByIDs", uint64(1), []uint64{3, 5}).Return(elements, nil)
+		elementRepo.On("BulkDelete", elements, true).Return([]error{nil, repository.ErrVersionConflict}, nil)
+
+		result, err := s.BulkDelete(1, &model.ContextElementBulkDeleteRequest{
+			ContextElementBulkSelector: model.ContextElementBulkSelector{IDs: []uint64{3, 5}},
+			Atomic:                     true,
+		})
+		require.NoError(t, err)
+		assert.False(t, result.Committed)
+		assert.Equal(t, model.BulkStatusRolledBack, result.Results[0].Status)
+		assert.Equal(t, model.BulkStatusFailed, result.Results[1].Status)
+		assert.Equal(t, 0, result.Succeeded)
+	})
+
+	t.Run("按过滤条件删除时超过上限", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		s := newTestElementService(elementRepo)
+		elementRepo.On("CountByUserID", uint64(1), mock.Anything).Return(int64(maxBulkRecords+1), nil)
+
+		_, err := s.BulkDelete(1, &model.ContextElementBulkDeleteRequest{
+			ContextElementBulkSelector: model.ContextElementBulkSelector{Filter: &model.ContextElementExportFilter{Tags: "草稿"}},
+		})
+		assert.EqualError(t, err, "参数验证失败: 匹配的记录数超过1000条，请缩小过滤范围")
+		elementRepo.AssertNotCalled(t, "GetAllByUserID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("ids与filter同时指定", func(t *testing.T) {
+		s := newTestElementService(new(MockContextElementRepository))
+		_, err := s.BulkDelete(1, &model.ContextElementBulkDeleteRequest{
+			ContextElementBulkSelector: model.ContextElementBulkSelector{IDs: []uint64{1}, Filter: &model.ContextElementExportFilter{}},
+		})
+		assert.EqualError(t, err, "参数验证失败: ids和filter不能同时指定")
+	})
+}
+
+func TestContextElementService_BulkPatch(t *testing.T) {
+	t.Run("内容未变化的记录不写入", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		s := newTestElementService(elementRepo)
+
+		same := testPatchElement()
+		same.DeliveryFormat = "Markdown"
+		changed := testPatchElement()
+		changed.ID = 2
+		changed.Version = 4
+
+		filter := &model.ContextElementExportFilter{Tags: "办公"}
+		elementRepo.On("CountByUserID", uint64(1), mock.Anything).Return(int64(2), nil)
+		elementRepo.On("GetAllByUserID", uint64(1), mock.Anything).Return([]*model.ContextElement{same, changed}, nil)
+		elementRepo.On("BulkUpdate", mock.Anything, false).Run(func(args mock.Arguments) {
+			updates := args.Get(0).([]*repository.ElementUpdate)
+			require.Len(t, updates, 1)
+			assert.Equal(t, uint64(2), updates[0].Element.ID)
+			assert.Equal(t, "Markdown", updates[0].Element.DeliveryFormat)
+			assert.Equal(t, "", updates[0].Previous.DeliveryFormat)
+			assert.Equal(t, []model.Tag{{UserID: 1, Name: "办公"}}, updates[0].Element.Tags)
+		}).Return([]error{nil}, nil)
+
+		result, err := s.BulkPatch(1, &model.ContextElementBulkPatchRequest{
+			ContextElementBulkSelector: model.ContextElementBulkSelector{Filter: filter},
+			Fields:                     []*model.ContextElementBulkFieldPatch{{Field: "delivery_format", Value: "Markdown"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Unchanged)
+		assert.Equal(t, 1, result.Succeeded)
+		assert.Equal(t, model.BulkStatusUnchanged, result.Results[0].Status)
+		assert.Equal(t, model.BulkStatusSucceeded, result.Results[1].Status)
+	})
+
+	t.Run("修改后超过长度限制", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		s := newTestElementService(elementRepo)
+
+		element := testPatchElement()
+		element.BehaviorRule = string(make([]byte, 4000))
+		elementRepo.On("GetByIDs", uint64(1), []uint64{1}).Return([]*model.ContextElement{element}, nil)
+
+		value := string(make([]byte, 2000))
+		result, err := s.BulkPatch(1, &model.ContextElementBulkPatchRequest{
+			ContextElementBulkSelector: model.ContextElementBulkSelector{IDs: []uint64{1}},
+			Fields:                     []*model.ContextElementBulkFieldPatch{{Field: "behavior_rule", Op: model.BulkFieldAppend, Value: value}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, model.BulkStatusFailed, result.Results[0].Status)
+		assert.Equal(t, "行为规则长度不能超过5000", result.Results[0].Error)
+		elementRepo.AssertNotCalled(t, "BulkUpdate", mock.Anything, mock.Anything)
+	})
+
+	t.Run("未指定修改内容", func(t *testing.T) {
+		s := newTestElementService(new(MockContextElementRepository))
+		_, err := s.BulkPatch(1, &model.ContextElementBulkPatchRequest{
+			ContextElementBulkSelector: model.ContextElementBulkSelector{IDs: []uint64{1}},
+		})
+		assert.EqualError(t, err, "参数验证失败: 未指定要修改的内容")
+	})
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"errors"
+
+	"cese-backend/internal/model"
+	"cese-backend/pkg/cursor"
+	"cese-backend/pkg/validator"
+)
+
+// GetListByCursor 游标分页获取六要素列表
+func (s *contextElementService) GetListByCursor(userID uint64, req *model.ContextElementCursorRequest) (*model.ContextElementCursorPage, error) {
+	// 设置默认值
+	if req.Size <= 0 {
+		req.Size = s.config.Pagination.DefaultSize
+	}
+	if req.Size > s.config.Pagination.MaxSize {
+		req.Size = s.config.Pagination.MaxSize
+	}
+
+	// 排序方式以游标中记录的为准
+	var after *cursor.Cursor
+	if req.Cursor != "" {
+		c, err := cursor.Decode(req.Cursor)
+		if err != nil {
+			return nil, errors.New("无效的游标")
+		}
+		if _, err := model.ParseSortValue(c.SortBy, c.Value); err != nil {
+			return nil, errors.New("无效的游标")
+		}
+		after = c
+		req.SortBy = c.SortBy
+		req.SortDesc = c.Desc
+	} else if req.SortBy == "" {
+		req.SortBy = "created_at"
+		req.SortDesc = true // 默认按创建时间倒序
+	}
+
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, errors.New("参数验证失败")
+	}
+
+	// 多取一条用于判断是否还有更多数据
+	query := req.ToQueryRequest()
+	elements, err := s.elementRepo.GetByCursor(userID, query, after, req.Size+1)
+	if err != nil {
+		return nil, errors.New("查询六要素列表失败")
+	}
+	hasMore := len(elements) > req.Size
+	if hasMore {
+		elements = elements[:req.Size]
+	}
+
+	backward := after != nil && after.Backward
+	if backward {
+		for i, j := 0, len(elements)-1; i < j; i, j = i+1, j-1 {
+			elements[i], elements[j] = elements[j], elements[i]
+		}
+	}
+
+	page := &model.ContextElementCursorPage{
+		Items: make([]*model.ContextElementResponse, len(elements)),
+	}
+	for i, element := range elements {
+		page.Items[i] = element.ToResponse()
+	}
+
+	// 生成前后翻页游标：向后翻页时是否有下一页取决于多取的一条，向前翻页时反之
+	if len(elements) > 0 {
+		first, last := elements[0], elements[len(elements)-1]
+		if backward || hasMore {
+			page.NextCursor = cursor.Encode(cursor.Cursor{
+				SortBy: req.SortBy, Desc: req.SortDesc, Value: last.SortValue(req.SortBy), ID: last.ID,
+			})
+		}
+		if (backward && hasMore) || (!backward && after != nil) {
+			page.PrevCursor = cursor.Encode(cursor.Cursor{
+				SortBy: req.SortBy, Desc: req.SortDesc, Value: first.SortValue(req.SortBy), ID: first.ID, Backward: true,
+			})
+		}
+	}
+
+	if req.WithTotal {
+		total, err := s.elementRepo.CountByUserID(userID, query)
+		if err != nil {
+			return nil, errors.New("查询六要素列表失败")
+		}
+		page.Total = &total
+	}
+
+	return page, nil
+}
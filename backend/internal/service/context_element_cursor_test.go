@@ -0,0 +1,88 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"cese-backend/internal/model"
+	"cese-backend/pkg/cursor"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testCursorElements(ids ...uint64) []*model.ContextElement {
+	base := time.Date(2024, 10, 31, 8, 0, 0, 0, time.UTC)
+	elements := make([]*model.ContextElement, len(ids))
+	for i, id := range ids {
+		elements[i] = &model.ContextElement{ID: id, UserID: 1, CreatedAt: base.Add(time.Duration(id) * time.Minute)}
+	}
+	return elements
+}
+
+func responseIDs(items []*model.ContextElementResponse) []uint64 {
+	ids := make([]uint64, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	return ids
+}
+
+func TestContextElementService_GetListByCursor(t *testing.T) {
+	t.Run("首页按创建时间倒序并返回下一页游标", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		s := newTestElementService(elementRepo)
+
+		elementRepo.On("GetByCursor", uint64(1), mock.MatchedBy(func(q *model.ContextElementQueryRequest) bool {
+			return q.SortBy == "created_at" && q.SortDesc
+		}), (*cursor.Cursor)(nil), 3).Return(testCursorElements(9, 8, 7), nil)
+		elementRepo.On("CountByUserID", uint64(1), mock.Anything).Return(int64(9), nil)
+
+		page, err := s.GetListByCursor(1, &model.ContextElementCursorRequest{Size: 2, WithTotal: true})
+		require.NoError(t, err)
+
+		assert.Equal(t, []uint64{9, 8}, responseIDs(page.Items))
+		assert.Empty(t, page.PrevCursor)
+		require.NotNil(t, page.Total)
+		assert.Equal(t, int64(9), *page.Total)
+
+		next, err := cursor.Decode(page.NextCursor)
+		require.NoError(t, err)
+		assert.Equal(t, cursor.Cursor{SortBy: "created_at", Desc: true, Value: "2024-10-31T08:08:00Z", ID: 8}, *next)
+		elementRepo.AssertExpectations(t)
+	})
+
+	t.Run("向前翻页时翻转结果并沿用游标中的排序", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		s := newTestElementService(elementRepo)
+
+		prev := cursor.Cursor{SortBy: "created_at", Desc: true, Value: "2024-10-31T08:05:00Z", ID: 5, Backward: true}
+		// 向前扫描返回升序结果，且没有更多数据
+		elementRepo.On("GetByCursor", uint64(1), mock.MatchedBy(func(q *model.ContextElementQueryRequest) bool {
+			return q.SortBy == "created_at" && q.SortDesc
+		}), &prev, 3).Return(testCursorElements(6, 7), nil)
+
+		page, err := s.GetListByCursor(1, &model.ContextElementCursorRequest{Cursor: cursor.Encode(prev), Size: 2, SortBy: "subject"})
+		require.NoError(t, err)
+
+		assert.Equal(t, []uint64{7, 6}, responseIDs(page.Items))
+		assert.Empty(t, page.PrevCursor)
+		assert.Nil(t, page.Total)
+
+		next, err := cursor.Decode(page.NextCursor)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(6), next.ID)
+		assert.False(t, next.Backward)
+	})
+
+	t.Run("无效的游标", func(t *testing.T) {
+		s := newTestElementService(new(MockContextElementRepository))
+
+		_, err := s.GetListByCursor(1, &model.ContextElementCursorRequest{Cursor: "invalid"})
+		assert.EqualError(t, err, "无效的游标")
+
+		_, err = s.GetListByCursor(1, &model.ContextElementCursorRequest{Cursor: cursor.Encode(cursor.Cursor{SortBy: "id", ID: 1})})
+		assert.EqualError(t, err, "无效的游标")
+	})
+}
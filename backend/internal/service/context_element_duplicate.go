@@ -0,0 +1,107 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"cese-backend/internal/model"
+	"cese-backend/pkg/validator"
+)
+
+// maxDuplicateProbe 生成副本主题时最多尝试的序号
+const maxDuplicateProbe = 100
+
+// Duplicate 复制六要素（含标签和变量定义），可覆盖任意字段，并记录复制来源
+//
+// 未指定主题时在原主题后追加 "(副本)"，已存在同名副本时依次使用 "(副本 2)"、"(副本 3)"……
+func (s *contextElementService) Duplicate(userID, elementID uint64, req *model.ContextElementDuplicateRequest) (*model.ContextElementResponse, error) {
+	if req.Subject != nil {
+		subject := strings.TrimSpace(*req.Subject)
+		req.Subject = &subject
+		if subject == "" {
+			req.Subject = nil
+		}
+	}
+
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, errors.New("参数验证失败")
+	}
+
+	source, err := s.getOwnedElement(userID, elementID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 检查文件夹归属（沿用原文件夹时无需检查）
+	clone := source.Duplicate(req)
+	if req.FolderID != nil && clone.FolderID != nil {
+		if err := s.checkFolderOwned(userID, *clone.FolderID); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Subject != nil {
+		clone.Subject = *req.Subject
+	} else if clone.Subject, err = s.nextDuplicateSubject(userID, source.Subject); err != nil {
+		return nil, err
+	}
+
+	tagNames := make([]string, len(source.Tags))
+	for i, tag := range source.Tags {
+		tagNames[i] = tag.Name
+	}
+	if req.Tags != nil {
+		tagNames = model.NormalizeTagNames(req.Tags)
+	}
+	clone.Tags = model.NewTags(userID, tagNames)
+
+	// 复制变量定义
+	sourceVariables, err := s.variableRepo.GetByElementID(source.ID)
+	if err != nil {
+		return nil, errors.New("查询变量定义失败")
+	}
+	variables := make([]*model.ContextElementVariable, len(sourceVariables))
+	for i, variable := range sourceVariables {
+		variables[i] = &model.ContextElementVariable{
+			Name:         variable.Name,
+			Type:         variable.Type,
+			DefaultValue: variable.DefaultValue,
+			Required:     variable.Required,
+			Description:  variable.Description,
+			Options:      variable.Options,
+			SortOrder:    variable.SortOrder,
+		}
+	}
+
+	if err := s.elementRepo.CreateWithVariables(clone, variables); err != nil {
+		return nil, errors.New("复制六要素记录失败")
+	}
+
+	return clone.ToResponse(), nil
+}
+
+// nextDuplicateSubject 生成用户尚未使用的副本主题
+func (s *contextElementService) nextDuplicateSubject(userID uint64, subject string) (string, error) {
+	base := model.DuplicateSubjectBase(subject)
+	candidates := make([]string, maxDuplicateProbe)
+	for i := range candidates {
+		candidates[i] = model.DuplicateSubject(base, i+1)
+	}
+
+	existing, err := s.elementRepo.GetBySubjects(userID, candidates)
+	if err != nil {
+		return "", errors.New("查询六要素记录失败")
+	}
+	taken := make(map[string]bool, len(existing))
+	for _, element := range existing {
+		taken[element.Subject] = true
+	}
+
+	for _, candidate := range candidates {
+		if !taken[candidate] {
+			return candidate, nil
+		}
+	}
+	return model.DuplicateSubject(base, maxDuplicateProbe+1), nil
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"cese-backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockContextElementVariableRepository 模板变量Repository模拟
+type MockContextElementVariableRepository struct {
+	mock.Mock
+}
+
+func (m *MockContextElementVariableRepository) GetByElementID(elementID uint64) ([]*model.ContextElementVariable, error) {
+	args := m.Called(elementID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.ContextElementVariable), args.Error(1)
+}
+
+func (m *MockContextElementVariableRepository) GetByElementIDs(elementIDs []uint64) ([]*model.ContextElementVariable, error) {
+	args := m.Called(elementIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.ContextElementVariable), args.Error(1)
+}
+
+func (m *MockContextElementVariableRepository) ReplaceByElementID(elementID uint64, variables []*model.ContextElementVariable) error {
+	args := m.Called(elementID, variables)
+	return args.Error(0)
+}
+
+func TestDuplicateSubject(t *testing.T) {
+	assert.Equal(t, "周报生成(副本)", model.DuplicateSubject("周报生成", 1))
+	assert.Equal(t, "周报生成(副本 3)", model.DuplicateSubject("周报生成", 3))
+
+	assert.Equal(t, "周报生成", model.DuplicateSubjectBase("周报生成(副本)"))
+	assert.Equal(t, "周报生成", model.DuplicateSubjectBase("周报生成 (副本 12)"))
+	assert.Equal(t, "(副本)说明", model.DuplicateSubjectBase("(副本)说明"))
+
+	long := model.DuplicateSubject(strings.Repeat("长", 255), 2)
+	assert.Equal(t, 255, utf8.RuneCountInString(long))
+	assert.True(t, strings.HasSuffix(long, "(副本 2)"))
+}
+
+func TestContextElementService_Duplicate(t *testing.T) {
+	newService := func() (*contextElementService, *MockContextElementRepository, *MockContextElementVariableRepository) {
+		elementRepo := new(MockContextElementRepository)
+		variableRepo := new(MockContextElementVariableRepository)
+		s := newTestElementService(elementRepo)
+		s.variableRepo = variableRepo
+		return s, elementRepo, variableRepo
+	}
+
+	folderID := uint64(9)
+	source := func() *model.ContextElement {
+		element := testPatchElement()
+		element.FolderID = &folderID
+		element.Version = 5
+		return element
+	}
+
+	t.Run("自动追加副本后缀并复制标签和变量", func(t *testing.T) {
+		s, elementRepo, variableRepo := newService()
+
+		elementRepo.On("GetByID", uint64(1)).Return(source(), nil)
+		elementRepo.On("GetBySubjects", uint64(1), mock.Anything).
+			Return([]*model.ContextElement{{Subject: "周报生成(副本)"}}, nil)
+		variableRepo.On("GetByElementID", uint64(1)).Return([]*model.ContextElementVariable{
+			{ID: 3, ElementID: 1, Name: "week", Type: "string", SortOrder: 1},
+		}, nil)
+		elementRepo.On("CreateWithVariables", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			variables := args.Get(1).([]*model.ContextElementVariable)
+			require.Len(t, variables, 1)
+			assert.Zero(t, variables[0].ID)
+			assert.Equal(t, "week", variables[0].Name)
+			args.Get(0).(*model.ContextElement).ID = 20
+		}).Return(nil)
+
+		aiRole := "HR助理"
+		resp, err := s.Duplicate(1, 1, &model.ContextElementDuplicateRequest{AIRole: &aiRole})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(20), resp.ID)
+		assert.Equal(t, "周报生成(副本 2)", resp.Subject)
+		assert.Equal(t, "HR助理", resp.AIRole)
+		assert.Equal(t, "整理本周工作", resp.TaskGoal)
+		assert.Equal(t, []string{"办公"}, resp.Tags)
+		assert.Equal(t, &folderID, resp.FolderID)
+		require.NotNil(t, resp.ClonedFromID)
+		assert.Equal(t, uint64(1), *resp.ClonedFromID)
+		assert.Zero(t, resp.Version)
+	})
+
+	t.Run("指定主题、清空标签并移到根目录", func(t *testing.T) {
+		s, elementRepo, variableRepo := newService()
+
+		elementRepo.On("GetByID", uint64(1)).Return(source(), nil)
+		variableRepo.On("GetByElementID", uint64(1)).Return([]*model.ContextElementVariable{}, nil)
+		elementRepo.On("CreateWithVariables", mock.Anything, mock.Anything).Return(nil)
+
+		subject := " 周报生成（面向管理层） "
+		root := uint64(0)
+		empty := ""
+		resp, err := s.Duplicate(1, 1, &model.ContextElementDuplicateRequest{
+			Subject:      &subject,
+			BehaviorRule: &empty,
+			Tags:         []string{},
+			FolderID:     &root,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "周报生成（面向管理层）", resp.Subject)
+		assert.Equal(t, "", resp.BehaviorRule)
+		assert.Empty(t, resp.Tags)
+		assert.Nil(t, resp.FolderID)
+		elementRepo.AssertNotCalled(t, "GetBySubjects", mock.Anything, mock.Anything)
+	})
+
+	t.Run("无权复制他人的记录", func(t *testing.T) {
+		s, elementRepo, _ := newService()
+		elementRepo.On("GetByID", uint64(1)).Return(source(), nil)
+
+		_, err := s.Duplicate(2, 1, &model.ContextElementDuplicateRequest{})
+		assert.EqualError(t, err, "无权访问该记录")
+	})
+}
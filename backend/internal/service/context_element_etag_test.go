@@ -0,0 +1,145 @@
+package service
+
+import (
+	"testing"
+
+	"cese-backend/internal/config"
+	"cese-backend/internal/model"
+	"cese-backend/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testVersionedElement() *model.ContextElement {
+	element := testPatchElement()
+	element.ID = 7
+	element.Version = 3
+	return element
+}
+
+func TestMatchETag(t *testing.T) {
+	element := testVersionedElement()
+	assert.Equal(t, `"7-3"`, element.ETag())
+
+	assert.True(t, model.MatchETag(`"7-3"`, element.ETag()))
+	assert.True(t, model.MatchETag(`W/"7-3"`, element.ETag()))
+	assert.True(t, model.MatchETag(`"7-2", "7-3"`, element.ETag()))
+	assert.True(t, model.MatchETag(`*`, element.ETag()))
+	assert.False(t, model.MatchETag(`"7-2"`, element.ETag()))
+	assert.False(t, model.MatchETag(`7-3`, element.ETag()))
+}
+
+func TestContextElementService_UpdateIfMatch(t *testing.T) {
+	req := &model.ContextElementUpdateRequest{Subject: "周报生成"}
+
+	t.Run("strict模式下缺少If-Match", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		s := newTestElementService(elementRepo)
+		s.config.Concurrency.IfMatch = config.IfMatchStrict
+
+		elementRepo.On("GetByID", uint64(7)).Return(testVersionedElement(), nil)
+
+		_, err := s.Update(1, 7, req, "")
+		assert.EqualError(t, err, "缺少If-Match请求头")
+	})
+
+	t.Run("lenient模式下不携带If-Match时不校验", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		s := newTestElementService(elementRepo)
+
+		elementRepo.On("GetByID", uint64(7)).Return(testVersionedElement(), nil)
+		elementRepo.On("UpdateWithTags", mock.Anything, mock.Anything).Return(nil)
+
+		result, err := s.Update(1, 7, req, "")
+		require.NoError(t, err)
+		assert.Empty(t, result.KeyInfo)
+		assert.Empty(t, result.Tags)
+	})
+
+	t.Run("If-Match与当前版本不一致", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		s := newTestElementService(elementRepo)
+
+		elementRepo.On("GetByID", uint64(7)).Return(testVersionedElement(), nil)
+
+		_, err := s.Update(1, 7, req, `"7-2"`)
+		assert.EqualError(t, err, "版本冲突")
+		elementRepo.AssertNotCalled(t, "UpdateWithTags", mock.Anything, mock.Anything)
+	})
+
+	t.Run("写入时发现并发修改", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		s := newTestElementService(elementRepo)
+
+		elementRepo.On("GetByID", uint64(7)).Return(testVersionedElement(), nil)
+		elementRepo.On("UpdateWithTags", mock.Anything, mock.Anything).Return(repository.ErrVersionConflict)
+
+		_, err := s.Update(1, 7, req, `"7-3"`)
+		assert.EqualError(t, err, "版本冲突")
+	})
+}
+
+func TestContextElementService_DeleteIfMatch(t *testing.T) {
+	t.Run("版本一致时删除", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		s := newTestElementService(elementRepo)
+
+		elementRepo.On("GetByID", uint64(7)).Return(testVersionedElement(), nil)
+		elementRepo.On("DeleteWithVersion", mock.MatchedBy(func(e *model.ContextElement) bool {
+			return e.ID == 7 && e.Version == 3
+		})).Return(nil)
+
+		require.NoError(t, s.Delete(1, 7, `"7-3"`))
+		elementRepo.AssertExpectations(t)
+	})
+
+	t.Run("版本不一致", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		s := newTestElementService(elementRepo)
+
+		elementRepo.On("GetByID", uint64(7)).Return(testVersionedElement(), nil)
+
+		assert.EqualError(t, s.Delete(1, 7, `"7-1"`), "版本冲突")
+		elementRepo.AssertNotCalled(t, "DeleteWithVersion", mock.Anything)
+	})
+}
+
+func TestContextElementService_RestoreVersionIfMatch(t *testing.T) {
+	t.Run("strict模式下缺少If-Match", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		s := newTestVersionService(elementRepo, new(MockContextElementVersionRepository))
+		s.config.Concurrency.IfMatch = config.IfMatchStrict
+
+		elementRepo.On("GetByID", uint64(7)).Return(testVersionedElement(), nil)
+
+		_, err := s.RestoreVersion(1, 7, 1, "")
+		assert.EqualError(t, err, "缺少If-Match请求头")
+	})
+
+	t.Run("If-Match与当前版本不一致", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		versionRepo := new(MockContextElementVersionRepository)
+		s := newTestVersionService(elementRepo, versionRepo)
+
+		elementRepo.On("GetByID", uint64(7)).Return(testVersionedElement(), nil)
+
+		_, err := s.RestoreVersion(1, 7, 1, `"7-2"`)
+		assert.EqualError(t, err, "版本冲突")
+		versionRepo.AssertNotCalled(t, "GetByVersionNo", mock.Anything, mock.Anything)
+	})
+
+	t.Run("写入时发现并发修改", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		versionRepo := new(MockContextElementVersionRepository)
+		s := newTestVersionService(elementRepo, versionRepo)
+
+		elementRepo.On("GetByID", uint64(7)).Return(testVersionedElement(), nil)
+		versionRepo.On("GetByVersionNo", uint64(7), 1).Return(testElementVersion(1, "周报", "整理工作"), nil)
+		elementRepo.On("UpdateWithVersion", mock.Anything, mock.Anything).Return(repository.ErrVersionConflict)
+
+		_, err := s.RestoreVersion(1, 7, 1, `"7-3"`)
+		assert.EqualError(t, err, "版本冲突")
+	})
+}
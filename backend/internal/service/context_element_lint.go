@@ -0,0 +1,60 @@
+package service
+
+import (
+	"errors"
+
+	"cese-backend/internal/model"
+	"cese-backend/pkg/lint"
+	"cese-backend/pkg/validator"
+)
+
+// newLinter 按配置创建提示词检查器（规则ID在加载配置时已校验）
+func newLinter(rules map[string]bool) *lint.Linter {
+	linter, err := lint.New(rules)
+	if err != nil {
+		linter, _ = lint.New(nil)
+	}
+	return linter
+}
+
+// Lint 检查未保存的六要素草稿
+func (s *contextElementService) Lint(req *model.ContextElementLintRequest) (*model.ContextElementLintResponse, error) {
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, errors.New("参数验证失败")
+	}
+
+	return &model.ContextElementLintResponse{Report: s.linter.Lint(req.ToLintDocument())}, nil
+}
+
+// LintElement 检查已保存的六要素，结合已定义的变量检查未定义的占位符
+func (s *contextElementService) LintElement(userID, elementID uint64) (*model.ContextElementLintResponse, error) {
+	element, err := s.getOwnedElement(userID, elementID)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := s.getVariableSchema(elementID)
+	if err != nil {
+		return nil, err
+	}
+	variables := make([]string, len(schema))
+	for i, v := range schema {
+		variables[i] = v.Name
+	}
+
+	return &model.ContextElementLintResponse{
+		ElementID: elementID,
+		Report:    s.linter.Lint(element.ToLintDocument(variables)),
+	}, nil
+}
+
+// ListLintRules 获取已启用的检查规则
+func (s *contextElementService) ListLintRules() []*model.LintRuleResponse {
+	rules := s.linter.Rules()
+	responses := make([]*model.LintRuleResponse, len(rules))
+	for i, rule := range rules {
+		responses[i] = &model.LintRuleResponse{ID: rule.ID, Description: rule.Description}
+	}
+	return responses
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"testing"
+
+	"cese-backend/internal/model"
+	"cese-backend/pkg/lint"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextElementService_LintElement(t *testing.T) {
+	elementRepo := new(MockContextElementRepository)
+	variableRepo := new(MockContextElementVariableRepository)
+	s := newTestElementService(elementRepo)
+	s.variableRepo = variableRepo
+
+	element := testPatchElement()
+	element.TaskGoal = "为{{product}}整理本周的销售周报"
+	elementRepo.On("GetByID", uint64(1)).Return(element, nil)
+	variableRepo.On("GetByElementID", uint64(1)).Return([]*model.ContextElementVariable{
+		{ElementID: 1, Name: "product", Type: "string"},
+		{ElementID: 1, Name: "region", Type: "string"},
+	}, nil)
+
+	result, err := s.LintElement(1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), result.ElementID)
+	assert.Less(t, result.Score, 100)
+
+	var variableMessages []string
+	for _, finding := range result.Findings {
+		if finding.Rule == lint.RuleUnresolvedVariables {
+			variableMessages = append(variableMessages, finding.Message)
+		}
+	}
+	assert.Equal(t, []string{"变量 region 已定义但没有使用"}, variableMessages)
+
+	_, err = s.LintElement(2, 1)
+	assert.EqualError(t, err, "无权访问该记录")
+}
+
+func TestContextElementService_LintDisabledRules(t *testing.T) {
+	s := newTestElementService(new(MockContextElementRepository))
+	s.linter = newLinter(map[string]bool{lint.RuleMissingSection: false, lint.RuleRoleAudience: false})
+
+	result, err := s.Lint(&model.ContextElementLintRequest{Subject: "周报生成"})
+	require.NoError(t, err)
+	assert.Empty(t, result.Findings)
+	assert.Equal(t, 100, result.Score)
+	assert.Len(t, s.ListLintRules(), len(lint.RuleIDs())-2)
+}
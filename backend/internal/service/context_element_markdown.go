@@ -0,0 +1,66 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"cese-backend/internal/model"
+	"cese-backend/pkg/validator"
+)
+
+// ImportMarkdown 解析六要素 Markdown 文档并创建记录，dry_run 时只返回解析结果
+func (s *contextElementService) ImportMarkdown(userID uint64, filename string, data []byte, req *model.ContextElementMarkdownImportRequest) (*model.ContextElementMarkdownImportResponse, error) {
+	var fallbackSubject string
+	if filename != "" {
+		fallbackSubject = strings.TrimSuffix(path.Base(filename), path.Ext(filename))
+	}
+
+	parsed, warnings, err := model.ParseContextElementMarkdown(string(data), fallbackSubject)
+	if err != nil {
+		return nil, fmt.Errorf("Markdown解析失败: %v", err)
+	}
+	parsed.Tags = model.NormalizeTagNames(parsed.Tags)
+	parsed.FolderID = req.FolderID
+
+	// 参数验证
+	if err := validator.ValidateStruct(parsed); err != nil {
+		return nil, fmt.Errorf("参数验证失败: %v", describeValidationError(err))
+	}
+
+	result := &model.ContextElementMarkdownImportResponse{
+		Parsed:   parsed,
+		Warnings: warnings,
+	}
+	if req.DryRun {
+		return result, nil
+	}
+
+	element, err := s.Create(userID, parsed)
+	if err != nil {
+		return nil, err
+	}
+	result.Element = element
+
+	return result, nil
+}
+
+// ExportMarkdown 将六要素导出为 Markdown 文档
+func (s *contextElementService) ExportMarkdown(userID, elementID uint64) (*model.ContextElementExportFile, error) {
+	element, err := s.getOwnedElement(userID, elementID)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := element.ToMarkdown()
+	if err != nil {
+		return nil, errors.New("导出六要素失败")
+	}
+
+	return &model.ContextElementExportFile{
+		Filename:    safeFilename(element.Subject) + ".md",
+		ContentType: "text/markdown; charset=utf-8",
+		Data:        []byte(content),
+	}, nil
+}
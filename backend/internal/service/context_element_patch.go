@@ -0,0 +1,65 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"cese-backend/internal/model"
+	"cese-backend/pkg/jsonpatch"
+	"cese-backend/pkg/validator"
+)
+
+// Patch 以 JSON Merge Patch 或 JSON Patch 局部更新六要素
+//
+// 补丁作用于包含全部可编辑字段和标签的文档：字段缺省表示不变，Merge Patch 中的 null 或 JSON Patch 的 remove 表示清空。
+func (s *contextElementService) Patch(userID, elementID uint64, format string, patch []byte, ifMatch string) (*model.ContextElementResponse, error) {
+	element, err := s.getUpdatableElement(userID, elementID, ifMatch)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := applyElementPatch(element, format, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("参数验证失败: %v", describeValidationError(err))
+	}
+
+	return s.replaceElement(userID, element, req)
+}
+
+// applyElementPatch 将补丁应用到六要素文档，返回等价的整体更新请求
+func applyElementPatch(element *model.ContextElement, format string, patch []byte) (*model.ContextElementUpdateRequest, error) {
+	doc, err := element.ToPatchDocument()
+	if err != nil {
+		return nil, errors.New("更新六要素记录失败")
+	}
+
+	var patched []byte
+	switch format {
+	case model.PatchFormatMerge:
+		patched, err = jsonpatch.MergePatch(doc, patch)
+	case model.PatchFormatJSON:
+		patched, err = jsonpatch.ApplyPatch(doc, patch)
+	default:
+		return nil, errors.New("不支持的补丁格式")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("补丁应用失败: %v", err)
+	}
+
+	// 补丁结果只能包含可编辑字段
+	var req model.ContextElementUpdateRequest
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		return nil, fmt.Errorf("补丁应用失败: %v", err)
+	}
+
+	return &req, nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"testing"
+
+	"cese-backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testPatchElement() *model.ContextElement {
+	return &model.ContextElement{
+		ID:           1,
+		UserID:       1,
+		Subject:      "周报生成",
+		TaskGoal:     "整理本周工作",
+		KeyInfo:      "项目列表",
+		BehaviorRule: "简洁",
+		Tags:         []model.Tag{{ID: 1, Name: "办公"}},
+	}
+}
+
+func TestApplyElementPatch(t *testing.T) {
+	t.Run("Merge Patch中null清空字段，缺省字段保持不变", func(t *testing.T) {
+		req, err := applyElementPatch(testPatchElement(), model.PatchFormatMerge,
+			[]byte(`{"key_info":null,"behavior_rule":"","ai_role":"项目助理"}`))
+		require.NoError(t, err)
+
+		assert.Equal(t, "周报生成", req.Subject)
+		assert.Equal(t, "整理本周工作", req.TaskGoal)
+		assert.Equal(t, "项目助理", req.AIRole)
+		assert.Empty(t, req.KeyInfo)
+		assert.Empty(t, req.BehaviorRule)
+		assert.Equal(t, []string{"办公"}, req.Tags)
+	})
+
+	t.Run("JSON Patch删除字段和追加标签", func(t *testing.T) {
+		req, err := applyElementPatch(testPatchElement(), model.PatchFormatJSON, []byte(`[
+			{"op":"test","path":"/subject","value":"周报生成"},
+			{"op":"remove","path":"/key_info"},
+			{"op":"add","path":"/tags/-","value":"周报"}
+		]`))
+		require.NoError(t, err)
+
+		assert.Empty(t, req.KeyInfo)
+		assert.Equal(t, "简洁", req.BehaviorRule)
+		assert.Equal(t, []string{"办公", "周报"}, req.Tags)
+	})
+
+	t.Run("补丁错误", func(t *testing.T) {
+		_, err := applyElementPatch(testPatchElement(), model.PatchFormatMerge, []byte(`{"unknown":"x"}`))
+		assert.ErrorContains(t, err, "补丁应用失败")
+
+		_, err = applyElementPatch(testPatchElement(), model.PatchFormatMerge, []byte(`{"subject":1}`))
+		assert.ErrorContains(t, err, "补丁应用失败")
+
+		_, err = applyElementPatch(testPatchElement(), model.PatchFormatJSON, []byte(`[{"op":"test","path":"/subject","value":"其他"}]`))
+		assert.ErrorContains(t, err, "test 操作未通过")
+
+		_, err = applyElementPatch(testPatchElement(), "xml", []byte(`{}`))
+		assert.EqualError(t, err, "不支持的补丁格式")
+	})
+}
+
+func TestContextElementService_Patch(t *testing.T) {
+	t.Run("清空字段并保存版本", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		s := newTestElementService(elementRepo)
+
+		elementRepo.On("GetByID", uint64(1)).Return(testPatchElement(), nil)
+		elementRepo.On("UpdateWithTags", mock.MatchedBy(func(e *model.ContextElement) bool {
+			return e.KeyInfo == "" && e.BehaviorRule == "简洁" && assert.ObjectsAreEqual(model.NewTags(1, []string{"办公"}), e.Tags)
+		}), mock.MatchedBy(func(v *model.ContextElementVersion) bool {
+			return v.KeyInfo == "项目列表"
+		})).Return(nil)
+
+		result, err := s.Patch(1, 1, model.PatchFormatMerge, []byte(`{"key_info":null}`), "")
+		require.NoError(t, err)
+		assert.Empty(t, result.KeyInfo)
+		assert.Equal(t, []string{"办公"}, result.Tags)
+		elementRepo.AssertExpectations(t)
+	})
+
+	t.Run("不能清空主题", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		s := newTestElementService(elementRepo)
+
+		elementRepo.On("GetByID", uint64(1)).Return(testPatchElement(), nil)
+
+		_, err := s.Patch(1, 1, model.PatchFormatMerge, []byte(`{"subject":null}`), "")
+		assert.EqualError(t, err, "参数验证失败: 主题不能为空")
+		elementRepo.AssertNotCalled(t, "UpdateWithTags", mock.Anything, mock.Anything)
+	})
+
+	t.Run("无权更新", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		s := newTestElementService(elementRepo)
+
+		elementRepo.On("GetByID", uint64(1)).Return(testPatchElement(), nil)
+
+		_, err := s.Patch(2, 1, model.PatchFormatMerge, []byte(`{}`), "")
+		assert.EqualError(t, err, "无权更新该记录")
+	})
+}
+
+func TestPatchFormatFromContentType(t *testing.T) {
+	assert.Equal(t, model.PatchFormatMerge, model.PatchFormatFromContentType("application/merge-patch+json", []byte(`{}`)))
+	assert.Equal(t, model.PatchFormatJSON, model.PatchFormatFromContentType("application/json-patch+json; charset=utf-8", nil))
+	assert.Equal(t, model.PatchFormatJSON, model.PatchFormatFromContentType("application/json", []byte(" [{}]")))
+	assert.Equal(t, model.PatchFormatMerge, model.PatchFormatFromContentType("", []byte(`{}`)))
+	assert.Equal(t, "", model.PatchFormatFromContentType("text/plain", []byte(`{}`)))
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"cese-backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextElementService_SearchRanking(t *testing.T) {
+	elementRepo := new(MockContextElementRepository)
+	s := newTestElementService(elementRepo)
+
+	// 未指定排序时按相关度排序，结果保持Repository返回的得分顺序
+	elementRepo.On("Search", uint64(1), mock.MatchedBy(func(req *model.ContextElementQueryRequest) bool {
+		return req.SortBy == model.SortByRelevance && req.Page == 1 && req.Size == 15
+	})).Return([]*model.ContextElementSearchHit{
+		{Element: &model.ContextElement{ID: 3, UserID: 1, Subject: "周报", TaskGoal: "写周报"}, Score: 8},
+		{Element: &model.ContextElement{ID: 1, UserID: 1, Subject: "日报", TaskGoal: "汇总周报"}, Score: 3},
+		{Element: &model.ContextElement{ID: 2, UserID: 1, Subject: "月报", KeyInfo: "参考周报"}, Score: 1},
+	}, int64(3), nil)
+
+	results, total, err := s.Search(1, &model.ContextElementQueryRequest{Keyword: "周报"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, results, 3)
+	assert.Equal(t, []uint64{3, 1, 2}, []uint64{results[0].ID, results[1].ID, results[2].ID})
+	assert.Equal(t, []float64{8, 3, 1}, []float64{results[0].Score, results[1].Score, results[2].Score})
+	assert.Equal(t, []string{"<em>周报</em>"}, results[0].Highlights["subject"])
+	assert.Equal(t, []string{"汇总<em>周报</em>"}, results[1].Highlights["task_goal"])
+	assert.NotContains(t, results[1].Highlights, "subject")
+	assert.Equal(t, []string{"参考<em>周报</em>"}, results[2].Highlights["key_info"])
+}
+
+func TestContextElementService_SearchPhraseAndExclude(t *testing.T) {
+	elementRepo := new(MockContextElementRepository)
+	s := newTestElementService(elementRepo)
+
+	// 短语整体高亮，排除词不参与高亮
+	req := &model.ContextElementQueryRequest{Keyword: `"周报 模板" 总结 -草稿`}
+	elementRepo.On("Search", uint64(1), req).Return([]*model.ContextElementSearchHit{
+		{Element: &model.ContextElement{ID: 1, UserID: 1, Subject: "周报 模板", TaskGoal: "总结本周工作，不是草稿"}, Score: 2},
+	}, int64(1), nil)
+
+	results, _, err := s.Search(1, req)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, []string{"<em>周报 模板</em>"}, results[0].Highlights["subject"])
+	assert.Equal(t, []string{"<em>总结</em>本周工作，不是草稿"}, results[0].Highlights["task_goal"])
+
+	// 只有排除词时不查询
+	_, _, err = s.Search(1, &model.ContextElementQueryRequest{Keyword: "-草稿 -\"旧 模板\""})
+	assert.EqualError(t, err, "搜索关键词不能为空")
+	elementRepo.AssertNumberOfCalls(t, "Search", 1)
+}
+
+func TestContextElementService_SearchSnippets(t *testing.T) {
+	elementRepo := new(MockContextElementRepository)
+	s := newTestElementService(elementRepo)
+	s.config.Search.SnippetContext = 2
+	s.config.Search.MaxSnippets = 2
+
+	// 命中位置前后保留2个字符，最多2个片段，原文做HTML转义
+	req := &model.ContextElementQueryRequest{Keyword: "周报"}
+	elementRepo.On("Search", uint64(1), req).Return([]*model.ContextElementSearchHit{
+		{Element: &model.ContextElement{ID: 1, UserID: 1, Subject: "测试",
+			TaskGoal: "每周一写<b>周报</b>，周三检查周报进度，周五再汇总周报"}, Score: 3},
+	}, int64(1), nil)
+
+	results, _, err := s.Search(1, req)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, []string{"…b&gt;<em>周报</em>&lt;/…", "…检查<em>周报</em>进度…"}, results[0].Highlights["task_goal"])
+	assert.NotContains(t, results[0].Highlights, "subject")
+}
+
+func TestContextElementService_SearchError(t *testing.T) {
+	elementRepo := new(MockContextElementRepository)
+	s := newTestElementService(elementRepo)
+
+	elementRepo.On("Search", uint64(1), mock.Anything).Return(nil, int64(0), errors.New("db error"))
+	_, _, err := s.Search(1, &model.ContextElementQueryRequest{Keyword: "周报"})
+	assert.EqualError(t, err, "搜索六要素记录失败")
+
+	_, _, err = s.Search(1, &model.ContextElementQueryRequest{Keyword: "周报", SortBy: "score"})
+	assert.EqualError(t, err, "参数验证失败")
+}
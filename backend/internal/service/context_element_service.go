@@ -6,6 +6,10 @@ import (
 	"cese-backend/internal/config"
 	"cese-backend/internal/model"
 	"cese-backend/internal/repository"
+	"cese-backend/pkg/lint"
+	"cese-backend/pkg/prompt"
+	"cese-backend/pkg/search"
+	"cese-backend/pkg/tokenizer"
 	"cese-backend/pkg/validator"
 )
 
@@ -14,22 +18,73 @@ type ContextElementService interface {
 	Create(userID uint64, req *model.ContextElementCreateRequest) (*model.ContextElementResponse, error)
 	GetByID(userID, elementID uint64) (*model.ContextElementResponse, error)
 	GetList(userID uint64, req *model.ContextElementQueryRequest) ([]*model.ContextElementResponse, int64, error)
-	Update(userID, elementID uint64, req *model.ContextElementUpdateRequest) (*model.ContextElementResponse, error)
-	Delete(userID, elementID uint64) error
-	Search(userID uint64, req *model.ContextElementQueryRequest) ([]*model.ContextElementResponse, int64, error)
+	GetListByCursor(userID uint64, req *model.ContextElementCursorRequest) (*model.ContextElementCursorPage, error)
+	Update(userID, elementID uint64, req *model.ContextElementUpdateRequest, ifMatch string) (*model.ContextElementResponse, error)
+	Patch(userID, elementID uint64, format string, patch []byte, ifMatch string) (*model.ContextElementResponse, error)
+	Delete(userID, elementID uint64, ifMatch string) error
+	Duplicate(userID, elementID uint64, req *model.ContextElementDuplicateRequest) (*model.ContextElementResponse, error)
+	ListTrash(userID uint64, req *model.ContextElementTrashQueryRequest) ([]*model.ContextElementTrashResponse, int64, error)
+	RestoreTrash(userID uint64, req *model.ContextElementTrashRequest) (*model.ContextElementTrashResult, error)
+	PurgeTrash(userID uint64, req *model.ContextElementTrashRequest) (*model.ContextElementTrashResult, error)
+	BulkCreate(userID uint64, req *model.ContextElementBulkCreateRequest) (*model.ContextElementBulkResult, error)
+	BulkDelete(userID uint64, req *model.ContextElementBulkDeleteRequest) (*model.ContextElementBulkResult, error)
+	BulkPatch(userID uint64, req *model.ContextElementBulkPatchRequest) (*model.ContextElementBulkResult, error)
+	Search(userID uint64, req *model.ContextElementQueryRequest) ([]*model.ContextElementSearchResult, int64, error)
+	ListVersions(userID, elementID uint64) ([]*model.ContextElementVersionResponse, error)
+	GetVersion(userID, elementID uint64, versionNo int) (*model.ContextElementVersionResponse, error)
+	DiffVersions(userID, elementID uint64, req *model.ContextElementVersionDiffRequest) (*model.ContextElementVersionDiffResponse, error)
+	RestoreVersion(userID, elementID uint64, versionNo int, ifMatch string) (*model.ContextElementResponse, error)
+	Render(userID, elementID uint64, req *model.ContextElementRenderRequest) (*model.ContextElementRenderResponse, error)
+	GetVariables(userID, elementID uint64) (*model.ContextElementVariablesResponse, error)
+	UpdateVariables(userID, elementID uint64, req *model.ContextElementVariablesUpdateRequest) (*model.ContextElementVariablesResponse, error)
+	RenderWithVariables(userID, elementID uint64, req *model.ContextElementRenderVariablesRequest) (*model.ContextElementRenderResponse, error)
+	MoveToFolder(userID uint64, req *model.ContextElementMoveRequest) (int64, error)
+	Export(userID uint64, req *model.ContextElementExportRequest) (*model.ContextElementExportFile, error)
+	Import(userID uint64, filename string, data []byte, req *model.ContextElementImportRequest) (*model.ContextElementImportResult, error)
+	ImportMarkdown(userID uint64, filename string, data []byte, req *model.ContextElementMarkdownImportRequest) (*model.ContextElementMarkdownImportResponse, error)
+	ExportMarkdown(userID, elementID uint64) (*model.ContextElementExportFile, error)
+	Lint(req *model.ContextElementLintRequest) (*model.ContextElementLintResponse, error)
+	LintElement(userID, elementID uint64) (*model.ContextElementLintResponse, error)
+	ListLintRules() []*model.LintRuleResponse
+	CountTokens(userID, elementID uint64, req *model.ContextElementTokenQuery) (*model.ContextElementTokenResponse, error)
+	CountDraftTokens(req *model.ContextElementTokenRequest) (*model.ContextElementTokenResponse, error)
+	ListTokenModels() []*model.TokenModelResponse
+	RecordUsage(userID, elementID uint64, event string) (*model.ContextElementUsageResponse, error)
+	SetFavorite(userID, elementID uint64, favorite bool) (*model.ContextElementUsageResponse, error)
+	SetPinned(userID, elementID uint64, pinned bool) (*model.ContextElementUsageResponse, error)
+	GetRecent(userID uint64, req *model.ContextElementRecentRequest) ([]*model.ContextElementResponse, error)
 }
 
 // contextElementService 六要素服务实现
 type contextElementService struct {
-	elementRepo repository.ContextElementRepository
-	config      *config.Config
+	elementRepo  repository.ContextElementRepository
+	versionRepo  repository.ContextElementVersionRepository
+	variableRepo repository.ContextElementVariableRepository
+	folderRepo   repository.FolderRepository
+	usageRepo    repository.ContextElementUsageRepository
+	linter       *lint.Linter
+	tokenizers   *tokenizer.Registry
+	config       *config.Config
 }
 
 // NewContextElementService 创建六要素服务实例
-func NewContextElementService(elementRepo repository.ContextElementRepository, cfg *config.Config) ContextElementService {
+func NewContextElementService(
+	elementRepo repository.ContextElementRepository,
+	versionRepo repository.ContextElementVersionRepository,
+	variableRepo repository.ContextElementVariableRepository,
+	folderRepo repository.FolderRepository,
+	usageRepo repository.ContextElementUsageRepository,
+	cfg *config.Config,
+) ContextElementService {
 	return &contextElementService{
-		elementRepo: elementRepo,
-		config:      cfg,
+		elementRepo:  elementRepo,
+		versionRepo:  versionRepo,
+		variableRepo: variableRepo,
+		folderRepo:   folderRepo,
+		usageRepo:    usageRepo,
+		linter:       newLinter(cfg.Lint.Rules),
+		tokenizers:   tokenizer.NewRegistry(cfg.Tokenizer.VocabDir),
+		config:       cfg,
 	}
 }
 
@@ -40,8 +95,16 @@ func (s *contextElementService) Create(userID uint64, req *model.ContextElementC
 		return nil, errors.New("参数验证失败")
 	}
 
+	// 检查文件夹归属
+	if req.FolderID != nil {
+		if err := s.checkFolderOwned(userID, *req.FolderID); err != nil {
+			return nil, err
+		}
+	}
+
 	// 创建六要素记录
 	element := req.ToContextElement(userID)
+	element.Tags = model.NewTags(userID, model.NormalizeTagNames(req.Tags))
 	if err := s.elementRepo.Create(element); err != nil {
 		return nil, errors.New("创建六要素记录失败")
 	}
@@ -64,7 +127,11 @@ func (s *contextElementService) GetByID(userID, elementID uint64) (*model.Contex
 		return nil, errors.New("无权访问该记录")
 	}
 
-	return element.ToResponse(), nil
+	resp := element.ToResponse()
+	if err := s.attachUsage([]*model.ContextElementResponse{resp}); err != nil {
+		return nil, err
+	}
+	return resp, nil
 }
 
 // GetList 获取六要素列表
@@ -88,18 +155,30 @@ func (s *contextElementService) GetList(userID uint64, req *model.ContextElement
 	for i, element := range elements {
 		responses[i] = element.ToResponse()
 	}
+	if err := s.attachUsage(responses); err != nil {
+		return nil, 0, err
+	}
 
 	return responses, total, nil
 }
 
 // Update 更新六要素记录
-func (s *contextElementService) Update(userID, elementID uint64, req *model.ContextElementUpdateRequest) (*model.ContextElementResponse, error) {
+func (s *contextElementService) Update(userID, elementID uint64, req *model.ContextElementUpdateRequest, ifMatch string) (*model.ContextElementResponse, error) {
 	// 参数验证
 	if err := validator.ValidateStruct(req); err != nil {
 		return nil, errors.New("参数验证失败")
 	}
 
-	// 查找记录
+	element, err := s.getUpdatableElement(userID, elementID, ifMatch)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.replaceElement(userID, element, req)
+}
+
+// getUpdatableElement 获取当前用户可更新的六要素记录并校验 If-Match
+func (s *contextElementService) getUpdatableElement(userID, elementID uint64, ifMatch string) (*model.ContextElement, error) {
 	element, err := s.elementRepo.GetByID(elementID)
 	if err != nil {
 		return nil, errors.New("查询六要素记录失败")
@@ -113,9 +192,36 @@ func (s *contextElementService) Update(userID, elementID uint64, req *model.Cont
 		return nil, errors.New("无权更新该记录")
 	}
 
-	// 更新记录
+	if err := s.checkIfMatch(element, ifMatch); err != nil {
+		return nil, err
+	}
+
+	return element, nil
+}
+
+// checkIfMatch 校验 If-Match 请求头与记录当前的 ETag 是否一致
+func (s *contextElementService) checkIfMatch(element *model.ContextElement, ifMatch string) error {
+	if ifMatch == "" {
+		if s.config.Concurrency.RequireIfMatch() {
+			return errors.New("缺少If-Match请求头")
+		}
+		return nil
+	}
+	if !model.MatchETag(ifMatch, element.ETag()) {
+		return errors.New("版本冲突")
+	}
+	return nil
+}
+
+// replaceElement 用请求内容整体替换六要素（含标签），同时保存更新前的版本快照
+func (s *contextElementService) replaceElement(userID uint64, element *model.ContextElement, req *model.ContextElementUpdateRequest) (*model.ContextElementResponse, error) {
+	previous := model.NewContextElementVersion(element)
 	element.UpdateFromRequest(req)
-	if err := s.elementRepo.Update(element); err != nil {
+	element.Tags = model.NewTags(userID, model.NormalizeTagNames(req.Tags))
+	if err := s.elementRepo.UpdateWithTags(element, previous); err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return nil, errors.New("版本冲突")
+		}
 		return nil, errors.New("更新六要素记录失败")
 	}
 
@@ -123,7 +229,7 @@ func (s *contextElementService) Update(userID, elementID uint64, req *model.Cont
 }
 
 // Delete 删除六要素记录
-func (s *contextElementService) Delete(userID, elementID uint64) error {
+func (s *contextElementService) Delete(userID, elementID uint64, ifMatch string) error {
 	// 查找记录
 	element, err := s.elementRepo.GetByID(elementID)
 	if err != nil {
@@ -138,17 +244,27 @@ func (s *contextElementService) Delete(userID, elementID uint64) error {
 		return errors.New("无权删除该记录")
 	}
 
-	// 删除记录
-	if err := s.elementRepo.Delete(elementID); err != nil {
+	if err := s.checkIfMatch(element, ifMatch); err != nil {
+		return err
+	}
+
+	// 删除记录（版本号变化时视为冲突）
+	if err := s.elementRepo.DeleteWithVersion(element); err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return errors.New("版本冲突")
+		}
 		return errors.New("删除六要素记录失败")
 	}
 
 	return nil
 }
 
-// Search 搜索六要素记录
-func (s *contextElementService) Search(userID uint64, req *model.ContextElementQueryRequest) ([]*model.ContextElementResponse, int64, error) {
-	// 设置默认值
+// Search 全文搜索六要素记录，返回相关度得分和各字段的高亮片段
+func (s *contextElementService) Search(userID uint64, req *model.ContextElementQueryRequest) ([]*model.ContextElementSearchResult, int64, error) {
+	// 设置默认值：搜索默认按相关度排序
+	if req.SortBy == "" {
+		req.SortBy = model.SortByRelevance
+	}
 	s.setDefaultQueryParams(req)
 
 	// 参数验证
@@ -156,19 +272,386 @@ func (s *contextElementService) Search(userID uint64, req *model.ContextElementQ
 		return nil, 0, errors.New("参数验证失败")
 	}
 
+	words := search.ParseQuery(req.Keyword).Positives()
+	if len(words) == 0 {
+		return nil, 0, errors.New("搜索关键词不能为空")
+	}
+
 	// 搜索数据
-	elements, total, err := s.elementRepo.Search(userID, req)
+	hits, total, err := s.elementRepo.Search(userID, req)
 	if err != nil {
 		return nil, 0, errors.New("搜索六要素记录失败")
 	}
 
-	// 转换为响应格式
-	responses := make([]*model.ContextElementResponse, len(elements))
-	for i, element := range elements {
-		responses[i] = element.ToResponse()
+	// 转换为响应格式并生成高亮片段
+	opts := search.HighlightOptions{
+		Context:     s.config.Search.SnippetContext,
+		MaxSnippets: s.config.Search.MaxSnippets,
+	}
+	results := make([]*model.ContextElementSearchResult, len(hits))
+	for i, hit := range hits {
+		highlights := make(map[string][]string)
+		if snippets := search.Highlight(hit.Element.Subject, words, opts); len(snippets) > 0 {
+			highlights["subject"] = snippets
+		}
+		for _, field := range model.ElementFields {
+			if snippets := search.Highlight(hit.Element.FieldValue(field.Key), words, opts); len(snippets) > 0 {
+				highlights[field.Key] = snippets
+			}
+		}
+		results[i] = &model.ContextElementSearchResult{
+			ContextElementResponse: hit.Element.ToResponse(),
+			Score:                  hit.Score,
+			Highlights:             highlights,
+		}
 	}
 
-	return responses, total, nil
+	responses := make([]*model.ContextElementResponse, len(results))
+	for i, result := range results {
+		responses[i] = result.ContextElementResponse
+	}
+	if err := s.attachUsage(responses); err != nil {
+		return nil, 0, err
+	}
+
+	return results, total, nil
+}
+
+// ListVersions 获取六要素历史版本列表
+func (s *contextElementService) ListVersions(userID, elementID uint64) ([]*model.ContextElementVersionResponse, error) {
+	if _, err := s.getOwnedElement(userID, elementID); err != nil {
+		return nil, err
+	}
+
+	versions, err := s.versionRepo.GetByElementID(elementID)
+	if err != nil {
+		return nil, errors.New("查询历史版本失败")
+	}
+
+	responses := make([]*model.ContextElementVersionResponse, len(versions))
+	for i, version := range versions {
+		responses[i] = version.ToResponse()
+	}
+
+	return responses, nil
+}
+
+// GetVersion 获取六要素指定历史版本
+func (s *contextElementService) GetVersion(userID, elementID uint64, versionNo int) (*model.ContextElementVersionResponse, error) {
+	if _, err := s.getOwnedElement(userID, elementID); err != nil {
+		return nil, err
+	}
+
+	version, err := s.getVersion(elementID, versionNo)
+	if err != nil {
+		return nil, err
+	}
+
+	return version.ToResponse(), nil
+}
+
+// DiffVersions 对比六要素两个版本（To为0时与当前版本对比）
+func (s *contextElementService) DiffVersions(userID, elementID uint64, req *model.ContextElementVersionDiffRequest) (*model.ContextElementVersionDiffResponse, error) {
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, errors.New("参数验证失败")
+	}
+
+	element, err := s.getOwnedElement(userID, elementID)
+	if err != nil {
+		return nil, err
+	}
+
+	fromVersion, err := s.getVersion(elementID, req.From)
+	if err != nil {
+		return nil, err
+	}
+
+	to := element
+	if req.To > 0 {
+		toVersion, err := s.getVersion(elementID, req.To)
+		if err != nil {
+			return nil, err
+		}
+		to = toVersion.ToContextElement()
+	}
+
+	return &model.ContextElementVersionDiffResponse{
+		ElementID:   elementID,
+		FromVersion: req.From,
+		ToVersion:   req.To,
+		Fields:      model.DiffContextElements(fromVersion.ToContextElement(), to),
+	}, nil
+}
+
+// RestoreVersion 将历史版本恢复为当前版本（恢复前的内容会作为新的历史版本保存）
+func (s *contextElementService) RestoreVersion(userID, elementID uint64, versionNo int, ifMatch string) (*model.ContextElementResponse, error) {
+	element, err := s.getUpdatableElement(userID, elementID, ifMatch)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := s.getVersion(elementID, versionNo)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := model.NewContextElementVersion(element)
+	element.RestoreFrom(version)
+	if err := s.elementRepo.UpdateWithVersion(element, previous); err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return nil, errors.New("版本冲突")
+		}
+		return nil, errors.New("恢复历史版本失败")
+	}
+
+	return element.ToResponse(), nil
+}
+
+// Render 将六要素渲染为提示词
+func (s *contextElementService) Render(userID, elementID uint64, req *model.ContextElementRenderRequest) (*model.ContextElementRenderResponse, error) {
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, errors.New("参数验证失败")
+	}
+	if req.Format == "" {
+		req.Format = prompt.FormatMarkdown
+	}
+
+	element, err := s.getOwnedElement(userID, elementID)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := prompt.Render(element.PromptSections(), prompt.Options{
+		Format:    req.Format,
+		Order:     req.OrderFields(),
+		OmitEmpty: req.OmitEmpty,
+	})
+	if err != nil {
+		return nil, errors.New("参数验证失败")
+	}
+
+	return &model.ContextElementRenderResponse{
+		ElementID: elementID,
+		Format:    req.Format,
+		Content:   content,
+	}, nil
+}
+
+// GetVariables 获取六要素的变量定义及检测到的变量
+func (s *contextElementService) GetVariables(userID, elementID uint64) (*model.ContextElementVariablesResponse, error) {
+	element, err := s.getOwnedElement(userID, elementID)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := s.getVariableSchema(elementID)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildVariablesResponse(element, schema), nil
+}
+
+// UpdateVariables 整体替换六要素的变量定义，定义不合法时返回逐个变量的错误
+func (s *contextElementService) UpdateVariables(userID, elementID uint64, req *model.ContextElementVariablesUpdateRequest) (*model.ContextElementVariablesResponse, error) {
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, errors.New("参数验证失败")
+	}
+
+	element, err := s.elementRepo.GetByID(elementID)
+	if err != nil {
+		return nil, errors.New("查询六要素记录失败")
+	}
+	if element == nil {
+		return nil, errors.New("六要素记录不存在")
+	}
+
+	// 检查权限：只能更新自己的记录
+	if element.UserID != userID {
+		return nil, errors.New("无权更新该记录")
+	}
+
+	if errs := prompt.ValidateSchema(req.Variables); len(errs) > 0 {
+		return &model.ContextElementVariablesResponse{
+			ElementID: elementID,
+			Errors:    errs,
+		}, nil
+	}
+
+	if err := s.variableRepo.ReplaceByElementID(elementID, model.NewContextElementVariables(elementID, req.Variables)); err != nil {
+		return nil, errors.New("保存变量定义失败")
+	}
+
+	return buildVariablesResponse(element, req.Variables), nil
+}
+
+// RenderWithVariables 代入变量值渲染提示词，变量校验失败时返回逐个变量的错误
+func (s *contextElementService) RenderWithVariables(userID, elementID uint64, req *model.ContextElementRenderVariablesRequest) (*model.ContextElementRenderResponse, error) {
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, errors.New("参数验证失败")
+	}
+	if req.Format == "" {
+		req.Format = prompt.FormatMarkdown
+	}
+
+	element, err := s.getOwnedElement(userID, elementID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Overrides != nil {
+		// 复制后再覆盖，不影响原记录
+		overridden := *element
+		req.Overrides.ApplyTo(&overridden)
+		element = &overridden
+	}
+
+	schema, err := s.getVariableSchema(elementID)
+	if err != nil {
+		return nil, err
+	}
+
+	values, errs := prompt.ResolveValues(prompt.DetectVariables(element.FieldValues()...), schema, req.Variables)
+	if len(errs) > 0 {
+		return &model.ContextElementRenderResponse{
+			ElementID:      elementID,
+			ElementVersion: element.Version,
+			Format:         req.Format,
+			Errors:         errs,
+		}, nil
+	}
+
+	sections := element.PromptSections()
+	for i := range sections {
+		sections[i].Content = prompt.Substitute(sections[i].Content, values)
+	}
+
+	content, err := prompt.Render(sections, prompt.Options{
+		Format:    req.Format,
+		Order:     req.Order,
+		OmitEmpty: req.OmitEmpty,
+	})
+	if err != nil {
+		return nil, errors.New("参数验证失败")
+	}
+
+	return &model.ContextElementRenderResponse{
+		ElementID:      elementID,
+		ElementVersion: element.Version,
+		Format:         req.Format,
+		Content:        content,
+		Variables:      values,
+	}, nil
+}
+
+// getVariableSchema 获取六要素的变量定义
+func (s *contextElementService) getVariableSchema(elementID uint64) ([]prompt.Variable, error) {
+	variables, err := s.variableRepo.GetByElementID(elementID)
+	if err != nil {
+		return nil, errors.New("查询变量定义失败")
+	}
+
+	schema := make([]prompt.Variable, len(variables))
+	for i, v := range variables {
+		schema[i] = v.ToVariable()
+	}
+	return schema, nil
+}
+
+// buildVariablesResponse 组装变量定义响应
+func buildVariablesResponse(element *model.ContextElement, schema []prompt.Variable) *model.ContextElementVariablesResponse {
+	detected := prompt.DetectVariables(element.FieldValues()...)
+
+	declared := make(map[string]bool, len(schema))
+	for _, v := range schema {
+		declared[v.Name] = true
+	}
+	undeclared := make([]string, 0)
+	for _, name := range detected {
+		if !declared[name] {
+			undeclared = append(undeclared, name)
+		}
+	}
+
+	if detected == nil {
+		detected = []string{}
+	}
+	if schema == nil {
+		schema = []prompt.Variable{}
+	}
+
+	return &model.ContextElementVariablesResponse{
+		ElementID:  element.ID,
+		Detected:   detected,
+		Undeclared: undeclared,
+		Variables:  schema,
+	}
+}
+
+// MoveToFolder 将六要素移动到指定文件夹（folder_id为空表示根目录），返回实际移动的数量
+func (s *contextElementService) MoveToFolder(userID uint64, req *model.ContextElementMoveRequest) (int64, error) {
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil || len(req.IDs) == 0 {
+		return 0, errors.New("参数验证失败")
+	}
+
+	if req.FolderID != nil {
+		if err := s.checkFolderOwned(userID, *req.FolderID); err != nil {
+			return 0, err
+		}
+	}
+
+	moved, err := s.elementRepo.MoveToFolder(userID, req.IDs, req.FolderID)
+	if err != nil {
+		return 0, errors.New("移动六要素失败")
+	}
+
+	return moved, nil
+}
+
+// checkFolderOwned 检查文件夹是否属于当前用户
+func (s *contextElementService) checkFolderOwned(userID, folderID uint64) error {
+	folder, err := s.folderRepo.GetByID(folderID)
+	if err != nil {
+		return errors.New("查询文件夹失败")
+	}
+	if folder == nil || folder.UserID != userID {
+		return errors.New("文件夹不存在")
+	}
+	return nil
+}
+
+// getOwnedElement 获取当前用户拥有的六要素记录
+func (s *contextElementService) getOwnedElement(userID, elementID uint64) (*model.ContextElement, error) {
+	element, err := s.elementRepo.GetByID(elementID)
+	if err != nil {
+		return nil, errors.New("查询六要素记录失败")
+	}
+	if element == nil {
+		return nil, errors.New("六要素记录不存在")
+	}
+
+	// 检查权限：只能访问自己的记录
+	if element.UserID != userID {
+		return nil, errors.New("无权访问该记录")
+	}
+
+	return element, nil
+}
+
+// getVersion 获取指定版本号的历史版本
+func (s *contextElementService) getVersion(elementID uint64, versionNo int) (*model.ContextElementVersion, error) {
+	version, err := s.versionRepo.GetByVersionNo(elementID, versionNo)
+	if err != nil {
+		return nil, errors.New("查询历史版本失败")
+	}
+	if version == nil {
+		return nil, errors.New("历史版本不存在")
+	}
+	return version, nil
 }
 
 // setDefaultQueryParams 设置查询参数默认值
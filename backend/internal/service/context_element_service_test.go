@@ -0,0 +1,177 @@
+package service
+
+import (
+	"time"
+
+	"cese-backend/internal/config"
+	"cese-backend/internal/model"
+	"cese-backend/internal/repository"
+	"cese-backend/pkg/cursor"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockContextElementRepository 六要素Repository模拟
+type MockContextElementRepository struct {
+	mock.Mock
+}
+
+func (m *MockContextElementRepository) Create(element *model.ContextElement) error {
+	args := m.Called(element)
+	return args.Error(0)
+}
+
+func (m *MockContextElementRepository) CreateWithVariables(element *model.ContextElement, variables []*model.ContextElementVariable) error {
+	args := m.Called(element, variables)
+	return args.Error(0)
+}
+
+func (m *MockContextElementRepository) GetByID(id uint64) (*model.ContextElement, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ContextElement), args.Error(1)
+}
+
+func (m *MockContextElementRepository) GetByUserID(userID uint64, req *model.ContextElementQueryRequest) ([]*model.ContextElement, int64, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*model.ContextElement), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockContextElementRepository) GetByCursor(userID uint64, req *model.ContextElementQueryRequest, after *cursor.Cursor, limit int) ([]*model.ContextElement, error) {
+	args := m.Called(userID, req, after, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.ContextElement), args.Error(1)
+}
+
+func (m *MockContextElementRepository) CountByUserID(userID uint64, req *model.ContextElementQueryRequest) (int64, error) {
+	args := m.Called(userID, req)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockContextElementRepository) GetAllByUserID(userID uint64, req *model.ContextElementQueryRequest) ([]*model.ContextElement, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.ContextElement), args.Error(1)
+}
+
+func (m *MockContextElementRepository) GetByIDs(userID uint64, ids []uint64) ([]*model.ContextElement, error) {
+	args := m.Called(userID, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.ContextElement), args.Error(1)
+}
+
+func (m *MockContextElementRepository) GetBySubjects(userID uint64, subjects []string) ([]*model.ContextElement, error) {
+	args := m.Called(userID, subjects)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.ContextElement), args.Error(1)
+}
+
+func (m *MockContextElementRepository) Update(element *model.ContextElement) error {
+	args := m.Called(element)
+	return args.Error(0)
+}
+
+func (m *MockContextElementRepository) UpdateWithVersion(element *model.ContextElement, previous *model.ContextElementVersion) error {
+	args := m.Called(element, previous)
+	return args.Error(0)
+}
+
+func (m *MockContextElementRepository) UpdateWithTags(element *model.ContextElement, previous *model.ContextElementVersion) error {
+	args := m.Called(element, previous)
+	return args.Error(0)
+}
+
+func (m *MockContextElementRepository) MoveToFolder(userID uint64, ids []uint64, folderID *uint64) (int64, error) {
+	args := m.Called(userID, ids, folderID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockContextElementRepository) Delete(id uint64) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockContextElementRepository) DeleteWithVersion(element *model.ContextElement) error {
+	args := m.Called(element)
+	return args.Error(0)
+}
+
+func (m *MockContextElementRepository) ExistsByID(id uint64) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockContextElementRepository) Search(userID uint64, req *model.ContextElementQueryRequest) ([]*model.ContextElementSearchHit, int64, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*model.ContextElementSearchHit), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockContextElementRepository) GetDeletedByUserID(userID uint64, req *model.ContextElementTrashQueryRequest) ([]*model.ContextElement, int64, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*model.ContextElement), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockContextElementRepository) RestoreDeleted(userID uint64, ids []uint64) (int64, error) {
+	args := m.Called(userID, ids)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockContextElementRepository) PurgeDeleted(userID uint64, ids []uint64) (int64, error) {
+	args := m.Called(userID, ids)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockContextElementRepository) PurgeDeletedBefore(before time.Time, limit int) (int64, error) {
+	args := m.Called(before, limit)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockContextElementRepository) BulkCreate(elements []*model.ContextElement, atomic bool) ([]error, error) {
+	args := m.Called(elements, atomic)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]error), args.Error(1)
+}
+
+func (m *MockContextElementRepository) BulkUpdate(updates []*repository.ElementUpdate, atomic bool) ([]error, error) {
+	args := m.Called(updates, atomic)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]error), args.Error(1)
+}
+
+func (m *MockContextElementRepository) BulkDelete(elements []*model.ContextElement, atomic bool) ([]error, error) {
+	args := m.Called(elements, atomic)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]error), args.Error(1)
+}
+
+func newTestElementService(elementRepo *MockContextElementRepository) *contextElementService {
+	cfg := &config.Config{
+		Pagination: config.PaginationConfig{DefaultPage: 1, DefaultSize: 15, MaxSize: 100},
+	}
+	return NewContextElementService(elementRepo, nil, nil, nil, newTestUsageRepository(), cfg).(*contextElementService)
+}
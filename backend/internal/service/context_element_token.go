@@ -0,0 +1,130 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"cese-backend/internal/config"
+	"cese-backend/internal/model"
+	"cese-backend/pkg/prompt"
+	"cese-backend/pkg/tokenizer"
+	"cese-backend/pkg/validator"
+)
+
+// CountTokens 统计已保存六要素各字段和渲染后提示词的令牌数，并估算输入费用
+func (s *contextElementService) CountTokens(userID, elementID uint64, req *model.ContextElementTokenQuery) (*model.ContextElementTokenResponse, error) {
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, errors.New("参数验证失败")
+	}
+
+	element, err := s.getOwnedElement(userID, elementID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.countElementTokens(element, req.Model, req.Format, req.OmitEmpty)
+	if err != nil {
+		return nil, err
+	}
+	result.ElementID = elementID
+	return result, nil
+}
+
+// CountDraftTokens 统计未保存的六要素草稿或任意文本的令牌数，并估算输入费用
+func (s *contextElementService) CountDraftTokens(req *model.ContextElementTokenRequest) (*model.ContextElementTokenResponse, error) {
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, errors.New("参数验证失败")
+	}
+
+	if req.Text == "" {
+		return s.countElementTokens(req.ToContextElement(), req.Model, req.Format, req.OmitEmpty)
+	}
+
+	pricing, counter, err := s.getTokenizer(req.Model)
+	if err != nil {
+		return nil, err
+	}
+	return newTokenResponse(pricing, counter, counter.Count(req.Text)), nil
+}
+
+// ListTokenModels 获取可用于统计令牌数的模型及价格
+func (s *contextElementService) ListTokenModels() []*model.TokenModelResponse {
+	models := s.config.Tokenizer.Models
+	responses := make([]*model.TokenModelResponse, len(models))
+	for i, m := range models {
+		responses[i] = &model.TokenModelResponse{
+			Name:          m.Name,
+			Encoding:      m.Encoding,
+			InputPrice:    m.InputPrice,
+			Currency:      m.Currency,
+			ContextWindow: m.ContextWindow,
+			Default:       m.Name == s.config.Tokenizer.DefaultModel,
+		}
+	}
+	return responses
+}
+
+// countElementTokens 统计六要素各字段的令牌数，并以渲染后的完整提示词作为总数
+func (s *contextElementService) countElementTokens(element *model.ContextElement, modelName, format string, omitEmpty bool) (*model.ContextElementTokenResponse, error) {
+	if format == "" {
+		format = prompt.FormatMarkdown
+	}
+
+	pricing, counter, err := s.getTokenizer(modelName)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := prompt.Render(element.PromptSections(), prompt.Options{Format: format, OmitEmpty: omitEmpty})
+	if err != nil {
+		return nil, errors.New("参数验证失败")
+	}
+
+	result := newTokenResponse(pricing, counter, counter.Count(content))
+	result.Format = format
+	result.Fields = make([]*model.FieldTokenCount, len(model.ElementFields))
+	for i, field := range model.ElementFields {
+		result.Fields[i] = &model.FieldTokenCount{
+			Field:  field.Key,
+			Label:  field.Label,
+			Tokens: counter.Count(element.FieldValue(field.Key)),
+		}
+	}
+	return result, nil
+}
+
+// getTokenizer 获取模型的价格配置和令牌计数器，模型为空时使用默认模型
+func (s *contextElementService) getTokenizer(modelName string) (*config.ModelPricing, tokenizer.Tokenizer, error) {
+	pricing := s.config.Tokenizer.FindModel(modelName)
+	if pricing == nil {
+		if modelName == "" {
+			return nil, nil, errors.New("未配置默认模型")
+		}
+		return nil, nil, fmt.Errorf("不支持的模型: %s", modelName)
+	}
+
+	counter, err := s.tokenizers.Get(pricing.Encoding)
+	if err != nil {
+		return nil, nil, errors.New("加载词表失败")
+	}
+	return pricing, counter, nil
+}
+
+// newTokenResponse 根据令牌总数生成统计结果
+func newTokenResponse(pricing *config.ModelPricing, counter tokenizer.Tokenizer, total int) *model.ContextElementTokenResponse {
+	return &model.ContextElementTokenResponse{
+		Model:          pricing.Name,
+		Encoding:       pricing.Encoding,
+		Exact:          counter.Exact(),
+		Total:          total,
+		ContextWindow:  pricing.ContextWindow,
+		ExceedsContext: pricing.ContextWindow > 0 && total > pricing.ContextWindow,
+		Cost: model.TokenCost{
+			InputPrice: pricing.InputPrice,
+			Currency:   pricing.Currency,
+			Estimated:  model.EstimateCost(total, pricing.InputPrice),
+		},
+	}
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"cese-backend/internal/config"
+	"cese-backend/internal/model"
+	"cese-backend/pkg/tokenizer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestTokenService 创建配置了模型价格的测试服务
+func newTestTokenService(elementRepo *MockContextElementRepository) *contextElementService {
+	s := newTestElementService(elementRepo)
+	s.config.Tokenizer = config.TokenizerConfig{
+		DefaultModel: "gpt-4o",
+		Models: []config.ModelPricing{
+			{Name: "gpt-4o", Encoding: tokenizer.EncodingO200K, InputPrice: 2.5, Currency: "USD", ContextWindow: 128000},
+			{Name: "tiny", Encoding: tokenizer.EncodingCJK, InputPrice: 1, Currency: "CNY", ContextWindow: 10},
+		},
+	}
+	return s
+}
+
+func TestContextElementService_CountTokens(t *testing.T) {
+	elementRepo := new(MockContextElementRepository)
+	s := newTestTokenService(elementRepo)
+	elementRepo.On("GetByID", uint64(1)).Return(testPatchElement(), nil)
+
+	result, err := s.CountTokens(1, 1, &model.ContextElementTokenQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), result.ElementID)
+	assert.Equal(t, "gpt-4o", result.Model)
+	assert.Equal(t, tokenizer.EncodingO200K, result.Encoding)
+	assert.True(t, result.Exact) // 使用内置词表精确计数
+	assert.Equal(t, "markdown", result.Format)
+	require.Len(t, result.Fields, len(model.ElementFields))
+	assert.Equal(t, "task_goal", result.Fields[0].Field)
+	assert.Greater(t, result.Fields[0].Tokens, 0)
+	assert.Equal(t, 0, result.Fields[1].Tokens)
+
+	// 渲染后的提示词包含段落标题，总数大于各字段之和
+	sum := 0
+	for _, field := range result.Fields {
+		sum += field.Tokens
+	}
+	assert.Greater(t, result.Total, sum)
+	assert.False(t, result.ExceedsContext)
+	assert.Equal(t, model.EstimateCost(result.Total, 2.5), result.Cost.Estimated)
+
+	tiny, err := s.CountTokens(1, 1, &model.ContextElementTokenQuery{Model: "tiny", OmitEmpty: true})
+	require.NoError(t, err)
+	assert.False(t, tiny.Exact)
+	assert.True(t, tiny.ExceedsContext)
+	assert.Equal(t, "CNY", tiny.Cost.Currency)
+
+	_, err = s.CountTokens(1, 1, &model.ContextElementTokenQuery{Model: "unknown"})
+	assert.EqualError(t, err, "不支持的模型: unknown")
+
+	_, err = s.CountTokens(2, 1, &model.ContextElementTokenQuery{})
+	assert.EqualError(t, err, "无权访问该记录")
+}
+
+func TestContextElementService_CountDraftTokens(t *testing.T) {
+	s := newTestTokenService(new(MockContextElementRepository))
+
+	result, err := s.CountDraftTokens(&model.ContextElementTokenRequest{Text: "hello world"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Total)
+	assert.Empty(t, result.Fields)
+
+	result, err = s.CountDraftTokens(&model.ContextElementTokenRequest{Model: "tiny", KeyInfo: "项目列表"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Fields[3].Tokens)
+
+	_, err = s.CountDraftTokens(&model.ContextElementTokenRequest{Text: strings.Repeat("字", 40001)})
+	assert.EqualError(t, err, "参数验证失败")
+
+	s.config.Tokenizer.DefaultModel = ""
+	_, err = s.CountDraftTokens(&model.ContextElementTokenRequest{Text: "hello"})
+	assert.EqualError(t, err, "未配置默认模型")
+}
+
+func TestEstimateCost(t *testing.T) {
+	assert.Equal(t, 0.0025, model.EstimateCost(1000, 2.5))
+	assert.Equal(t, 0.000001, model.EstimateCost(1, 0.6))
+	assert.Equal(t, 0.0, model.EstimateCost(0, 10))
+}
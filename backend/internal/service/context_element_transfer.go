@@ -0,0 +1,664 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"cese-backend/internal/model"
+	"cese-backend/pkg/prompt"
+	"cese-backend/pkg/validator"
+
+	playground "github.com/go-playground/validator/v10"
+)
+
+const (
+	// maxImportRecords 单次导入的最大记录数
+	maxImportRecords = 1000
+	// maxZipEntrySize ZIP内单个文件解压后的最大字节数
+	maxZipEntrySize = 1 << 20
+	// maxZipEntries ZIP内的最大文件数（含目录和被忽略的文件）
+	maxZipEntries = maxImportRecords
+	// maxZipTotalSize ZIP内待导入文件解压后的最大总字节数
+	maxZipTotalSize = 32 << 20
+)
+
+// utf8BOM CSV文件头部的BOM，便于Excel正确识别中文
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// unsafeFilenameChars 文件名中需要替换的字符
+var unsafeFilenameChars = regexp.MustCompile(`[\\/:*?"<>|\s]+`)
+
+// transferFieldLabels 导入校验错误中使用的字段名称
+var transferFieldLabels = map[string]string{
+	"Subject":        "主题",
+	"TaskGoal":       "任务目标",
+	"AIRole":         "AI的角色",
+	"MyRole":         "我的角色",
+	"KeyInfo":        "关键信息",
+	"BehaviorRule":   "行为规则",
+	"DeliveryFormat": "交付格式",
+	"Tags":           "标签",
+	"Variables":      "变量定义",
+}
+
+// importRecord 待导入的记录
+type importRecord struct {
+	Source string
+	Item   *model.ContextElementTransferItem
+	Err    error // 解析阶段的错误（如CSV列数不符）
+}
+
+// Export 导出六要素（按ID、按过滤条件或全部）
+func (s *contextElementService) Export(userID uint64, req *model.ContextElementExportRequest) (*model.ContextElementExportFile, error) {
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, errors.New("参数验证失败")
+	}
+	if req.Filter != nil {
+		if err := validator.ValidateStruct(req.Filter); err != nil {
+			return nil, errors.New("参数验证失败")
+		}
+	}
+	if req.Format == "" {
+		req.Format = model.ExportFormatJSON
+	}
+
+	var elements []*model.ContextElement
+	var err error
+	switch {
+	case len(req.IDs) > 0:
+		elements, err = s.elementRepo.GetByIDs(userID, req.IDs)
+	case req.Filter != nil:
+		elements, err = s.elementRepo.GetAllByUserID(userID, req.Filter.ToQueryRequest())
+	default:
+		elements, err = s.elementRepo.GetAllByUserID(userID, &model.ContextElementQueryRequest{})
+	}
+	if err != nil {
+		return nil, errors.New("查询六要素列表失败")
+	}
+
+	items, err := s.transferItems(elements)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := encodeExport(req.Format, items, time.Now())
+	if err != nil {
+		return nil, errors.New("导出六要素失败")
+	}
+
+	return file, nil
+}
+
+// transferItems 将六要素连同变量定义转换为导出格式
+func (s *contextElementService) transferItems(elements []*model.ContextElement) ([]*model.ContextElementTransferItem, error) {
+	ids := make([]uint64, len(elements))
+	for i, element := range elements {
+		ids[i] = element.ID
+	}
+	variables, err := s.variableRepo.GetByElementIDs(ids)
+	if err != nil {
+		return nil, errors.New("查询变量定义失败")
+	}
+	schemas := make(map[uint64][]prompt.Variable, len(elements))
+	for _, variable := range variables {
+		schemas[variable.ElementID] = append(schemas[variable.ElementID], variable.ToVariable())
+	}
+
+	items := make([]*model.ContextElementTransferItem, len(elements))
+	for i, element := range elements {
+		items[i] = element.ToTransferItem()
+		items[i].Variables = schemas[element.ID]
+	}
+	return items, nil
+}
+
+// subjectKey 冲突判断使用的主题键，与主题列不区分大小写的排序规则一致
+func subjectKey(subject string) string {
+	return strings.ToLower(subject)
+}
+
+// Import 导入六要素，逐条校验并返回每条记录的处理结果
+// 按主题（不区分大小写）判断冲突：skip 跳过已存在的主题，overwrite 覆盖已存在记录的内容、标签和变量定义；dry_run 时只校验不写入
+func (s *contextElementService) Import(userID uint64, filename string, data []byte, req *model.ContextElementImportRequest) (*model.ContextElementImportResult, error) {
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, errors.New("参数验证失败")
+	}
+	if req.Conflict == "" {
+		req.Conflict = model.ImportConflictSkip
+	}
+
+	format := req.Format
+	if format == "" {
+		format = importFormatFromFilename(filename)
+	}
+
+	records, err := decodeImport(format, filename, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) > maxImportRecords {
+		return nil, fmt.Errorf("导入记录数不能超过%d条", maxImportRecords)
+	}
+
+	if req.FolderID != nil {
+		if err := s.checkFolderOwned(userID, *req.FolderID); err != nil {
+			return nil, err
+		}
+	}
+
+	// 规范化主题后预先加载同主题的已有记录，用于冲突判断
+	subjects := make([]string, 0, len(records))
+	for _, record := range records {
+		if record.Item == nil {
+			continue
+		}
+		record.Item.Subject = strings.TrimSpace(record.Item.Subject)
+		record.Item.Tags = model.NormalizeTagNames(record.Item.Tags)
+		if record.Item.Subject != "" {
+			subjects = append(subjects, record.Item.Subject)
+		}
+	}
+	existing := make(map[string]*model.ContextElement, len(subjects))
+	if len(subjects) > 0 {
+		elements, err := s.elementRepo.GetBySubjects(userID, subjects)
+		if err != nil {
+			return nil, errors.New("查询六要素记录失败")
+		}
+		for _, element := range elements {
+			if _, ok := existing[subjectKey(element.Subject)]; !ok {
+				existing[subjectKey(element.Subject)] = element
+			}
+		}
+	}
+
+	result := &model.ContextElementImportResult{
+		DryRun: req.DryRun,
+		Total:  len(records),
+		Errors: []string{},
+		Rows:   make([]*model.ContextElementImportRow, 0, len(records)),
+	}
+	for i, record := range records {
+		row := &model.ContextElementImportRow{Row: i + 1, Source: record.Source}
+		if record.Item != nil {
+			row.Subject = record.Item.Subject
+		}
+
+		if err := s.importRecord(userID, record, existing, req, row); err != nil {
+			row.Status = model.ImportStatusFailed
+			row.Error = err.Error()
+		}
+
+		switch row.Status {
+		case model.ImportStatusFailed:
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Sprintf("第%d条: %s", row.Row, row.Error))
+		case model.ImportStatusSkipped:
+			result.Skipped++
+		default:
+			result.Success++
+		}
+		result.Rows = append(result.Rows, row)
+	}
+
+	return result, nil
+}
+
+// importRecord 导入单条记录并填写处理结果
+func (s *contextElementService) importRecord(userID uint64, record *importRecord, existing map[string]*model.ContextElement, req *model.ContextElementImportRequest, row *model.ContextElementImportRow) error {
+	if record.Err != nil {
+		return record.Err
+	}
+
+	item := record.Item
+	if err := validator.ValidateStruct(item); err != nil {
+		return describeValidationError(err)
+	}
+
+	if errs := prompt.ValidateSchema(item.Variables); len(errs) > 0 {
+		return fmt.Errorf("变量%s定义错误: %s", errs[0].Name, errs[0].Message)
+	}
+
+	element, conflict := existing[subjectKey(item.Subject)]
+	if conflict && req.Conflict == model.ImportConflictSkip {
+		row.Status = model.ImportStatusSkipped
+		row.ElementID = element.ID
+		row.Error = "主题已存在"
+		return nil
+	}
+
+	if req.DryRun {
+		if conflict {
+			row.Status = model.ImportStatusUpdated
+			row.ElementID = element.ID
+		} else {
+			row.Status = model.ImportStatusCreated
+			// 记录本次将要创建的主题，文件内重复主题按冲突处理
+			existing[subjectKey(item.Subject)] = &model.ContextElement{Subject: item.Subject}
+		}
+		return nil
+	}
+
+	tags := model.NewTags(userID, item.Tags)
+	if conflict {
+		previous := model.NewContextElementVersion(element)
+		element.ApplyTransferItem(item)
+		element.Tags = tags
+		if err := s.elementRepo.UpdateWithTags(element, previous); err != nil {
+			return errors.New("更新六要素记录失败")
+		}
+		if item.Variables != nil {
+			if err := s.variableRepo.ReplaceByElementID(element.ID, model.NewContextElementVariables(element.ID, item.Variables)); err != nil {
+				return errors.New("保存变量定义失败")
+			}
+		}
+		row.Status = model.ImportStatusUpdated
+		row.ElementID = element.ID
+		return nil
+	}
+
+	element = &model.ContextElement{UserID: userID, FolderID: req.FolderID, Tags: tags}
+	element.ApplyTransferItem(item)
+	if err := s.elementRepo.CreateWithVariables(element, model.NewContextElementVariables(0, item.Variables)); err != nil {
+		return errors.New("创建六要素记录失败")
+	}
+	existing[subjectKey(item.Subject)] = element
+	row.Status = model.ImportStatusCreated
+	row.ElementID = element.ID
+	return nil
+}
+
+// describeValidationError 将校验错误转换为可读的中文描述
+func describeValidationError(err error) error {
+	var validationErrors playground.ValidationErrors
+	if !errors.As(err, &validationErrors) || len(validationErrors) == 0 {
+		return errors.New("参数验证失败")
+	}
+
+	messages := make([]string, 0, len(validationErrors))
+	for _, fe := range validationErrors {
+		field := fe.StructField()
+		if i := strings.Index(field, "["); i >= 0 {
+			field = field[:i]
+		}
+		label, ok := transferFieldLabels[field]
+		if !ok {
+			label = fe.Field()
+		}
+
+		switch fe.Tag() {
+		case "required":
+			messages = append(messages, label+"不能为空")
+		case "max":
+			if fe.Kind() == reflect.Slice {
+				messages = append(messages, fmt.Sprintf("%s不能超过%s个", label, fe.Param()))
+			} else {
+				messages = append(messages, fmt.Sprintf("%s长度不能超过%s", label, fe.Param()))
+			}
+		default:
+			messages = append(messages, label+"格式错误")
+		}
+	}
+	return errors.New(strings.Join(messages, "；"))
+}
+
+// encodeExport 按格式生成导出文件
+func encodeExport(format string, items []*model.ContextElementTransferItem, now time.Time) (*model.ContextElementExportFile, error) {
+	basename := "context-elements-" + now.Format("20060102150405")
+
+	switch format {
+	case model.ExportFormatCSV:
+		data, err := encodeCSV(items)
+		if err != nil {
+			return nil, err
+		}
+		return &model.ContextElementExportFile{Filename: basename + ".csv", ContentType: "text/csv; charset=utf-8", Data: data}, nil
+	case model.ExportFormatZIP:
+		data, err := encodeZIP(items)
+		if err != nil {
+			return nil, err
+		}
+		return &model.ContextElementExportFile{Filename: basename + ".zip", ContentType: "application/zip", Data: data}, nil
+	default:
+		data, err := marshalJSON(&model.ContextElementExportDocument{
+			Version:    model.ExportFileVersion,
+			ExportedAt: now,
+			Elements:   items,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &model.ContextElementExportFile{Filename: basename + ".json", ContentType: "application/json; charset=utf-8", Data: data}, nil
+	}
+}
+
+// csvHeader CSV表头：主题、六要素字段、标签
+func csvHeader() []string {
+	header := []string{"subject"}
+	for _, field := range model.ElementFields {
+		header = append(header, field.Key)
+	}
+	return append(header, "tags")
+}
+
+// encodeCSV 生成CSV，多个标签以逗号分隔写在同一列
+func encodeCSV(items []*model.ContextElementTransferItem) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(utf8BOM)
+
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(csvHeader()); err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		element := &model.ContextElement{}
+		element.ApplyTransferItem(item)
+
+		record := []string{item.Subject}
+		for _, field := range model.ElementFields {
+			record = append(record, element.FieldValue(field.Key))
+		}
+		record = append(record, strings.Join(item.Tags, ","))
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeZIP 生成ZIP，每条六要素一个JSON文件
+func encodeZIP(items []*model.ContextElementTransferItem) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for i, item := range items {
+		data, err := marshalJSON(item)
+		if err != nil {
+			return nil, err
+		}
+		file, err := writer.Create(fmt.Sprintf("%03d-%s.json", i+1, safeFilename(item.Subject)))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := file.Write(data); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// safeFilename 将主题转换为可用作文件名的字符串
+func safeFilename(subject string) string {
+	name := strings.Trim(unsafeFilenameChars.ReplaceAllString(subject, "_"), "_.")
+	if runes := []rune(name); len(runes) > 50 {
+		name = string(runes[:50])
+	}
+	if name == "" {
+		name = "untitled"
+	}
+	return name
+}
+
+// marshalJSON 编码为带缩进的JSON（不转义HTML字符）
+func marshalJSON(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// importFormatFromFilename 根据文件扩展名判断导入格式
+func importFormatFromFilename(filename string) string {
+	switch ext := strings.TrimPrefix(strings.ToLower(path.Ext(filename)), "."); ext {
+	case "md", "markdown":
+		return model.ImportFormatMarkdown
+	default:
+		return ext
+	}
+}
+
+// decodeImport 按格式解析导入文件
+func decodeImport(format, filename string, data []byte) ([]*importRecord, error) {
+	var records []*importRecord
+	var err error
+	switch format {
+	case model.ImportFormatMarkdown:
+		records = []*importRecord{decodeMarkdown("", filename, data)}
+	case model.ExportFormatJSON:
+		var items []*model.ContextElementTransferItem
+		items, err = decodeJSON(data)
+		records = wrapItems("", items)
+	case model.ExportFormatCSV:
+		records, err = decodeCSV(data)
+	case model.ExportFormatZIP:
+		records, err = decodeZIP(data)
+	default:
+		return nil, errors.New("不支持的导入文件格式")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("导入文件解析失败: %v", err)
+	}
+	return records, nil
+}
+
+// decodeJSON 解析JSON：支持导出文件结构、六要素数组或单条六要素
+func decodeJSON(data []byte) ([]*model.ContextElementTransferItem, error) {
+	data = bytes.TrimSpace(bytes.TrimPrefix(data, utf8BOM))
+	if len(data) == 0 {
+		return nil, errors.New("文件内容为空")
+	}
+
+	if data[0] == '[' {
+		var items []*model.ContextElementTransferItem
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, err
+		}
+		return items, nil
+	}
+
+	var document struct {
+		Elements []*model.ContextElementTransferItem `json:"elements"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	if document.Elements != nil {
+		return document.Elements, nil
+	}
+
+	var item model.ContextElementTransferItem
+	if err := json.Unmarshal(data, &item); err != nil {
+		return nil, err
+	}
+	return []*model.ContextElementTransferItem{&item}, nil
+}
+
+// decodeCSV 解析CSV，表头可使用字段名（如 task_goal）或中文名称（如 任务目标）
+func decodeCSV(data []byte) ([]*importRecord, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, utf8BOM)))
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, errors.New("文件内容为空")
+		}
+		return nil, err
+	}
+
+	columns := make(map[int]string, len(header))
+	for i, name := range header {
+		if key := csvColumnKey(strings.TrimSpace(name)); key != "" {
+			columns[i] = key
+		}
+	}
+	hasSubject := false
+	for _, key := range columns {
+		hasSubject = hasSubject || key == "subject"
+	}
+	if !hasSubject {
+		return nil, errors.New("缺少subject列")
+	}
+
+	var records []*importRecord
+	for {
+		values, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(values) != len(header) {
+			records = append(records, &importRecord{Err: fmt.Errorf("列数与表头不一致（应为%d列，实际%d列）", len(header), len(values))})
+			continue
+		}
+
+		element := &model.ContextElement{}
+		var tags []string
+		for i, value := range values {
+			switch key := columns[i]; key {
+			case "":
+			case "tags":
+				tags = model.SplitTagNames(value)
+			default:
+				element.SetFieldValue(key, value)
+			}
+		}
+		item := element.ToTransferItem()
+		item.Tags = append(item.Tags, tags...)
+		records = append(records, &importRecord{Item: item})
+	}
+	return records, nil
+}
+
+// csvColumnKey 将CSV表头转换为字段名，无法识别时返回空字符串
+func csvColumnKey(name string) string {
+	switch name {
+	case "subject", "主题":
+		return "subject"
+	case "tags", "标签":
+		return "tags"
+	}
+	for _, field := range model.ElementFields {
+		if name == field.Key || name == field.Label {
+			return field.Key
+		}
+	}
+	return ""
+}
+
+// decodeMarkdown 解析单个六要素 Markdown 文档，缺少主题时使用文件名
+func decodeMarkdown(source, filename string, data []byte) *importRecord {
+	var subject string
+	if filename != "" {
+		subject = strings.TrimSuffix(path.Base(filename), path.Ext(filename))
+	}
+	req, _, err := model.ParseContextElementMarkdown(string(data), subject)
+	if err != nil {
+		return &importRecord{Source: source, Err: fmt.Errorf("Markdown解析失败: %v", err)}
+	}
+	return &importRecord{Source: source, Item: req.ToTransferItem()}
+}
+
+// decodeZIP 解析ZIP中的全部JSON和Markdown文件（JSON文件可包含一条或多条六要素）
+func decodeZIP(data []byte) ([]*importRecord, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	if len(reader.File) > maxZipEntries {
+		return nil, fmt.Errorf("ZIP内文件数不能超过%d个", maxZipEntries)
+	}
+
+	// 解压前按文件头中的大小检查总量（读取时 archive/zip 会校验实际大小与文件头一致）
+	files := make([]*zip.File, 0, len(reader.File))
+	var totalSize uint64
+	for _, file := range reader.File {
+		format := importFormatFromFilename(file.Name)
+		if file.FileInfo().IsDir() || strings.HasPrefix(file.Name, "__MACOSX/") ||
+			(format != model.ExportFormatJSON && format != model.ImportFormatMarkdown) {
+			continue
+		}
+		totalSize += file.UncompressedSize64
+		if totalSize > maxZipTotalSize {
+			return nil, fmt.Errorf("ZIP内文件解压后总大小不能超过%dMB", maxZipTotalSize>>20)
+		}
+		files = append(files, file)
+	}
+
+	var records []*importRecord
+	for _, file := range files {
+		name := file.Name
+		format := importFormatFromFilename(name)
+		content, err := readZipFile(file)
+		if err != nil {
+			records = append(records, &importRecord{Source: name, Err: err})
+			continue
+		}
+		if format == model.ImportFormatMarkdown {
+			records = append(records, decodeMarkdown(name, name, content))
+			continue
+		}
+		items, err := decodeJSON(content)
+		if err != nil {
+			records = append(records, &importRecord{Source: name, Err: fmt.Errorf("JSON解析失败: %v", err)})
+			continue
+		}
+		records = append(records, wrapItems(name, items)...)
+	}
+	return records, nil
+}
+
+// readZipFile 读取ZIP内的文件（限制解压后大小）
+func readZipFile(file *zip.File) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(io.LimitReader(rc, maxZipEntrySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > maxZipEntrySize {
+		return nil, errors.New("文件过大")
+	}
+	return content, nil
+}
+
+// wrapItems 将解析出的六要素包装为待导入记录
+func wrapItems(source string, items []*model.ContextElementTransferItem) []*importRecord {
+	records := make([]*importRecord, 0, len(items))
+	for _, item := range items {
+		if item == nil {
+			records = append(records, &importRecord{Source: source, Err: errors.New("记录为空")})
+			continue
+		}
+		records = append(records, &importRecord{Source: source, Item: item})
+	}
+	return records
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"cese-backend/internal/model"
+	"cese-backend/pkg/prompt"
+	"cese-backend/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testTransferItems() []*model.ContextElementTransferItem {
+	return []*model.ContextElementTransferItem{
+		{Subject: "周报生成", TaskGoal: "整理本周工作{{week}}", AIRole: "项目助理", DeliveryFormat: "Markdown 列表，\"三条\"以内", Tags: []string{"办公", "周报"},
+			Variables: []prompt.Variable{{Name: "week", Type: prompt.VarTypeEnum, Default: "本周", Required: true, Options: []string{"本周", "上周"}}}},
+		{Subject: "代码评审/后端", KeyInfo: "多行\n内容", Tags: []string{}},
+	}
+}
+
+func TestTransferRoundTrip(t *testing.T) {
+	now := time.Date(2024, 10, 31, 8, 0, 0, 0, time.UTC)
+
+	for _, format := range []string{model.ExportFormatJSON, model.ExportFormatCSV, model.ExportFormatZIP} {
+		t.Run(format, func(t *testing.T) {
+			file, err := encodeExport(format, testTransferItems(), now)
+			require.NoError(t, err)
+			assert.Equal(t, "context-elements-20241031080000."+format, file.Filename)
+
+			records, err := decodeImport(format, file.Filename, file.Data)
+			require.NoError(t, err)
+			require.Len(t, records, 2)
+			for i, want := range testTransferItems() {
+				// CSV不包含变量定义
+				if format == model.ExportFormatCSV {
+					want.Variables = nil
+				}
+				require.NoError(t, records[i].Err)
+				assert.Equal(t, want, records[i].Item)
+			}
+			if format == model.ExportFormatZIP {
+				assert.Equal(t, "002-代码评审_后端.json", records[1].Source)
+			}
+		})
+	}
+}
+
+func TestDecodeJSONShapes(t *testing.T) {
+	items, err := decodeJSON([]byte(`[{"subject":"A"},{"subject":"B"}]`))
+	require.NoError(t, err)
+	assert.Len(t, items, 2)
+
+	items, err = decodeJSON([]byte(`{"subject":"单条","task_goal":"目标"}`))
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "目标", items[0].TaskGoal)
+
+	_, err = decodeJSON([]byte(`  `))
+	assert.Error(t, err)
+}
+
+func TestDecodeCSVWithChineseHeader(t *testing.T) {
+	data := []byte("主题,任务目标,备注,标签\n周报,整理工作,忽略,\"办公, 周报\"\n缺列\n")
+
+	records, err := decodeCSV(data)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "周报", records[0].Item.Subject)
+	assert.Equal(t, "整理工作", records[0].Item.TaskGoal)
+	assert.Equal(t, []string{"办公", "周报"}, records[0].Item.Tags)
+	assert.Error(t, records[1].Err)
+
+	_, err = decodeCSV([]byte("task_goal\n目标\n"))
+	assert.EqualError(t, err, "缺少subject列")
+}
+
+func TestContextElementService_ImportSubjectConflict(t *testing.T) {
+	data := []byte(`[{"subject":" 周报生成 ","task_goal":"整理下周计划","tags":[" 办公 "]}]`)
+	existing := func() []*model.ContextElement {
+		return []*model.ContextElement{{ID: 3, UserID: 1, Subject: "周报生成", Version: 2}}
+	}
+
+	t.Run("首尾空白不影响跳过判断", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		s := newTestElementService(elementRepo)
+		elementRepo.On("GetBySubjects", uint64(1), []string{"周报生成"}).Return(existing(), nil)
+
+		result, err := s.Import(1, "elements.json", data, &model.ContextElementImportRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Skipped)
+		assert.Equal(t, "周报生成", result.Rows[0].Subject)
+		assert.Equal(t, uint64(3), result.Rows[0].ElementID)
+		elementRepo.AssertNotCalled(t, "CreateWithVariables", mock.Anything, mock.Anything)
+	})
+
+	t.Run("主题不区分大小写", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		s := newTestElementService(elementRepo)
+		elementRepo.On("GetBySubjects", uint64(1), []string{"weekly report"}).
+			Return([]*model.ContextElement{{ID: 4, UserID: 1, Subject: "Weekly Report"}}, nil)
+
+		result, err := s.Import(1, "elements.json", []byte(`[{"subject":"weekly report"}]`), &model.ContextElementImportRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Skipped)
+		assert.Equal(t, uint64(4), result.Rows[0].ElementID)
+		elementRepo.AssertNotCalled(t, "CreateWithVariables", mock.Anything, mock.Anything)
+	})
+
+	t.Run("覆盖已有记录而不是新建", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		s := newTestElementService(elementRepo)
+		elementRepo.On("GetBySubjects", uint64(1), []string{"周报生成"}).Return(existing(), nil)
+		elementRepo.On("UpdateWithTags", mock.MatchedBy(func(e *model.ContextElement) bool {
+			return e.ID == 3 && e.TaskGoal == "整理下周计划" && assert.ObjectsAreEqual(model.NewTags(1, []string{"办公"}), e.Tags)
+		}), mock.Anything).Return(nil)
+
+		result, err := s.Import(1, "elements.json", data, &model.ContextElementImportRequest{Conflict: model.ImportConflictOverwrite})
+		require.NoError(t, err)
+		assert.Equal(t, model.ImportStatusUpdated, result.Rows[0].Status)
+		elementRepo.AssertExpectations(t)
+		elementRepo.AssertNotCalled(t, "CreateWithVariables", mock.Anything, mock.Anything)
+	})
+}
+
+func TestContextElementService_ImportVariables(t *testing.T) {
+	data := []byte(`[
+		{"subject":"周报生成","task_goal":"整理{{week}}工作","variables":[{"name":"week","type":"enum","default":"本周","options":["本周","上周"]}]},
+		{"subject":"日报","variables":[{"name":"1day","type":"string"}]}
+	]`)
+
+	t.Run("新建时一并创建变量定义", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		s := newTestElementService(elementRepo)
+		elementRepo.On("GetBySubjects", uint64(1), []string{"周报生成", "日报"}).Return([]*model.ContextElement{}, nil)
+		elementRepo.On("CreateWithVariables", mock.Anything, mock.MatchedBy(func(variables []*model.ContextElementVariable) bool {
+			return len(variables) == 1 && variables[0].Name == "week" && variables[0].Type == prompt.VarTypeEnum &&
+				variables[0].DefaultValue == "本周" && assert.ObjectsAreEqual([]string{"本周", "上周"}, variables[0].Options)
+		})).Return(nil)
+
+		result, err := s.Import(1, "elements.json", data, &model.ContextElementImportRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Success)
+		assert.Equal(t, model.ImportStatusFailed, result.Rows[1].Status)
+		assert.Contains(t, result.Rows[1].Error, "变量1day定义错误")
+		elementRepo.AssertNumberOfCalls(t, "CreateWithVariables", 1)
+	})
+
+	t.Run("覆盖时替换变量定义", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		variableRepo := new(MockContextElementVariableRepository)
+		s := newTestElementService(elementRepo)
+		s.variableRepo = variableRepo
+		elementRepo.On("GetBySubjects", uint64(1), []string{"周报生成"}).
+			Return([]*model.ContextElement{{ID: 3, UserID: 1, Subject: "周报生成"}}, nil)
+		elementRepo.On("UpdateWithTags", mock.Anything, mock.Anything).Return(nil)
+		variableRepo.On("ReplaceByElementID", uint64(3), mock.MatchedBy(func(variables []*model.ContextElementVariable) bool {
+			return len(variables) == 1 && variables[0].ElementID == 3 && variables[0].Name == "week"
+		})).Return(nil)
+
+		result, err := s.Import(1, "elements.json", []byte(`[{"subject":"周报生成","variables":[{"name":"week","type":"string"}]}]`),
+			&model.ContextElementImportRequest{Conflict: model.ImportConflictOverwrite})
+		require.NoError(t, err)
+		assert.Equal(t, model.ImportStatusUpdated, result.Rows[0].Status)
+		variableRepo.AssertExpectations(t)
+
+		// 文件中没有变量定义时保留原有定义
+		_, err = s.Import(1, "elements.json", []byte(`[{"subject":"周报生成"}]`),
+			&model.ContextElementImportRequest{Conflict: model.ImportConflictOverwrite})
+		require.NoError(t, err)
+		variableRepo.AssertNumberOfCalls(t, "ReplaceByElementID", 1)
+	})
+}
+
+func TestContextElementService_ExportVariables(t *testing.T) {
+	elementRepo := new(MockContextElementRepository)
+	variableRepo := new(MockContextElementVariableRepository)
+	s := newTestElementService(elementRepo)
+	s.variableRepo = variableRepo
+
+	elementRepo.On("GetByIDs", uint64(1), []uint64{3, 4}).Return([]*model.ContextElement{
+		{ID: 3, UserID: 1, Subject: "周报生成", TaskGoal: "整理{{week}}工作"},
+		{ID: 4, UserID: 1, Subject: "日报"},
+	}, nil)
+	variableRepo.On("GetByElementIDs", []uint64{3, 4}).Return([]*model.ContextElementVariable{
+		{ElementID: 3, Name: "week", Type: prompt.VarTypeString, DefaultValue: "本周", SortOrder: 0},
+	}, nil)
+
+	file, err := s.Export(1, &model.ContextElementExportRequest{IDs: []uint64{3, 4}})
+	require.NoError(t, err)
+	records, err := decodeImport(model.ExportFormatJSON, file.Filename, file.Data)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, []prompt.Variable{{Name: "week", Type: prompt.VarTypeString, Default: "本周"}}, records[0].Item.Variables)
+	assert.Nil(t, records[1].Item.Variables)
+}
+
+// buildZip 生成包含指定文件的ZIP
+func buildZip(t *testing.T, files map[string][]byte) []byte {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for name, content := range files {
+		file, err := writer.Create(name)
+		require.NoError(t, err)
+		_, err = file.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func TestDecodeZIPLimits(t *testing.T) {
+	t.Run("文件数超过上限", func(t *testing.T) {
+		files := make(map[string][]byte, maxZipEntries+1)
+		for i := 0; i <= maxZipEntries; i++ {
+			files[fmt.Sprintf("%04d.txt", i)] = nil
+		}
+
+		_, err := decodeImport(model.ExportFormatZIP, "elements.zip", buildZip(t, files))
+		assert.EqualError(t, err, fmt.Sprintf("导入文件解析失败: ZIP内文件数不能超过%d个", maxZipEntries))
+	})
+
+	t.Run("解压后总大小超过上限", func(t *testing.T) {
+		content := []byte(`{"subject":"周报","key_info":"` + strings.Repeat("a", maxZipEntrySize-64) + `"}`)
+		files := make(map[string][]byte)
+		for i := 0; i*len(content) <= maxZipTotalSize; i++ {
+			files[fmt.Sprintf("%03d.json", i)] = content
+		}
+
+		_, err := decodeImport(model.ExportFormatZIP, "elements.zip", buildZip(t, files))
+		assert.EqualError(t, err, "导入文件解析失败: ZIP内文件解压后总大小不能超过32MB")
+	})
+
+	t.Run("被忽略的文件不计入总大小", func(t *testing.T) {
+		files := map[string][]byte{
+			"001.json":  []byte(`{"subject":"周报"}`),
+			"image.png": bytes.Repeat([]byte{0}, maxZipTotalSize+1),
+		}
+
+		records, err := decodeImport(model.ExportFormatZIP, "elements.zip", buildZip(t, files))
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		assert.Equal(t, "周报", records[0].Item.Subject)
+	})
+}
+
+func TestDescribeValidationError(t *testing.T) {
+	item := &model.ContextElementTransferItem{Tags: make([]string, 21)}
+	err := describeValidationError(validator.ValidateStruct(item))
+	assert.EqualError(t, err, "主题不能为空；标签不能超过20个")
+}
+
+func TestDecodeMarkdown(t *testing.T) {
+	text := "---\ntags: 办公, 周报\n---\n## Task Goal\n整理本周工作\n\n## 交付格式\nMarkdown 列表\n"
+
+	records, err := decodeImport(model.ImportFormatMarkdown, "docs/周报生成.md", []byte(text))
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.NoError(t, records[0].Err)
+	assert.Equal(t, "周报生成", records[0].Item.Subject)
+	assert.Equal(t, "整理本周工作", records[0].Item.TaskGoal)
+	assert.Equal(t, "Markdown 列表", records[0].Item.DeliveryFormat)
+	assert.Equal(t, []string{"办公", "周报"}, records[0].Item.Tags)
+
+	records, err = decodeImport(importFormatFromFilename("说明.MD"), "说明.MD", []byte("只有正文"))
+	require.NoError(t, err)
+	assert.EqualError(t, records[0].Err, "Markdown解析失败: 未找到六要素段落")
+}
+
+func TestMarkdownRoundTrip(t *testing.T) {
+	element := &model.ContextElement{
+		Subject:      "代码评审",
+		TaskGoal:     "检查提交的代码\n## 行为规则\n以上是引用的原文",
+		BehaviorRule: "```go\n## 不是标题\n```",
+		Tags:         []model.Tag{{Name: "研发"}},
+	}
+
+	text, err := element.ToMarkdown()
+	require.NoError(t, err)
+
+	parsed, warnings, err := model.ParseContextElementMarkdown(text, "")
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+	assert.Equal(t, "代码评审", parsed.Subject)
+	assert.Equal(t, element.TaskGoal, parsed.TaskGoal)
+	assert.Equal(t, element.BehaviorRule, parsed.BehaviorRule)
+	assert.Equal(t, []string{"研发"}, parsed.Tags)
+}
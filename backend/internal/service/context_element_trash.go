@@ -0,0 +1,158 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"cese-backend/internal/config"
+	"cese-backend/internal/model"
+	"cese-backend/internal/repository"
+	"cese-backend/pkg/logger"
+	"cese-backend/pkg/validator"
+)
+
+// trashSweepBatchSize 自动清理时每批彻底删除的记录数
+const trashSweepBatchSize = 500
+
+// ListTrash 获取回收站中的六要素
+func (s *contextElementService) ListTrash(userID uint64, req *model.ContextElementTrashQueryRequest) ([]*model.ContextElementTrashResponse, int64, error) {
+	// 设置默认值
+	if req.Page <= 0 {
+		req.Page = s.config.Pagination.DefaultPage
+	}
+	if req.Size <= 0 {
+		req.Size = s.config.Pagination.DefaultSize
+	}
+	if req.Size > s.config.Pagination.MaxSize {
+		req.Size = s.config.Pagination.MaxSize
+	}
+
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, 0, errors.New("参数验证失败")
+	}
+
+	elements, total, err := s.elementRepo.GetDeletedByUserID(userID, req)
+	if err != nil {
+		return nil, 0, errors.New("查询回收站失败")
+	}
+
+	retention := s.config.Trash.GetRetention()
+	responses := make([]*model.ContextElementTrashResponse, len(elements))
+	for i, element := range elements {
+		responses[i] = element.ToTrashResponse(retention)
+	}
+
+	return responses, total, nil
+}
+
+// RestoreTrash 从回收站恢复六要素
+func (s *contextElementService) RestoreTrash(userID uint64, req *model.ContextElementTrashRequest) (*model.ContextElementTrashResult, error) {
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, errors.New("参数验证失败")
+	}
+
+	restored, err := s.elementRepo.RestoreDeleted(userID, req.IDs)
+	if err != nil {
+		return nil, errors.New("恢复六要素记录失败")
+	}
+
+	return &model.ContextElementTrashResult{Affected: restored}, nil
+}
+
+// PurgeTrash 彻底删除回收站中的六要素，req 为空时清空回收站
+func (s *contextElementService) PurgeTrash(userID uint64, req *model.ContextElementTrashRequest) (*model.ContextElementTrashResult, error) {
+	var ids []uint64
+	if req != nil {
+		// 参数验证
+		if err := validator.ValidateStruct(req); err != nil {
+			return nil, errors.New("参数验证失败")
+		}
+		ids = req.IDs
+	}
+
+	purged, err := s.elementRepo.PurgeDeleted(userID, ids)
+	if err != nil {
+		return nil, errors.New("彻底删除六要素记录失败")
+	}
+
+	return &model.ContextElementTrashResult{Affected: purged}, nil
+}
+
+// TrashSweeper 回收站自动清理任务，定期彻底删除超过保留期限的六要素
+type TrashSweeper struct {
+	elementRepo repository.ContextElementRepository
+	retention   time.Duration
+	interval    time.Duration
+	stop        chan struct{}
+	done        chan struct{}
+	once        sync.Once
+}
+
+// NewTrashSweeper 创建回收站自动清理任务
+func NewTrashSweeper(elementRepo repository.ContextElementRepository, cfg *config.Config) *TrashSweeper {
+	return &TrashSweeper{
+		elementRepo: elementRepo,
+		retention:   cfg.Trash.GetRetention(),
+		interval:    cfg.Trash.GetSweepInterval(),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// Start 在后台启动自动清理，保留天数为0时不启动
+func (t *TrashSweeper) Start() {
+	if t.retention <= 0 {
+		close(t.done)
+		return
+	}
+
+	go func() {
+		defer close(t.done)
+
+		ticker := time.NewTicker(t.interval)
+		defer ticker.Stop()
+
+		for {
+			if purged, err := t.Sweep(time.Now()); err != nil {
+				logger.GetLogger().Errorf("回收站自动清理失败: %v", err)
+			} else if purged > 0 {
+				logger.GetLogger().Infof("回收站自动清理完成，彻底删除 %d 条六要素", purged)
+			}
+
+			select {
+			case <-ticker.C:
+			case <-t.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop 停止自动清理并等待当前批次完成
+func (t *TrashSweeper) Stop() {
+	t.once.Do(func() { close(t.stop) })
+	<-t.done
+}
+
+// Sweep 彻底删除在 now 之前已超过保留期限的六要素，返回删除的记录数
+func (t *TrashSweeper) Sweep(now time.Time) (int64, error) {
+	if t.retention <= 0 {
+		return 0, nil
+	}
+
+	before := now.Add(-t.retention)
+	var total int64
+	for {
+		purged, err := t.elementRepo.PurgeDeletedBefore(before, trashSweepBatchSize)
+		total += purged
+		if err != nil {
+			return total, err
+		}
+		if purged < trashSweepBatchSize {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"cese-backend/internal/config"
+	"cese-backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestContextElementService_ListTrash(t *testing.T) {
+	deletedAt := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	element := testPatchElement()
+	element.DeletedAt = gorm.DeletedAt{Time: deletedAt, Valid: true}
+
+	t.Run("计算预计彻底删除时间", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		s := newTestElementService(elementRepo)
+		s.config.Trash.RetentionDays = 30
+
+		elementRepo.On("GetDeletedByUserID", uint64(1), mock.Anything).Return([]*model.ContextElement{element}, int64(1), nil)
+
+		items, total, err := s.ListTrash(1, &model.ContextElementTrashQueryRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		require.Len(t, items, 1)
+		assert.Equal(t, deletedAt, items[0].DeletedAt)
+		require.NotNil(t, items[0].PurgeAt)
+		assert.Equal(t, deletedAt.AddDate(0, 0, 30), *items[0].PurgeAt)
+	})
+
+	t.Run("保留天数为0时不自动删除", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		s := newTestElementService(elementRepo)
+
+		elementRepo.On("GetDeletedByUserID", uint64(1), mock.Anything).Return([]*model.ContextElement{element}, int64(1), nil)
+
+		items, _, err := s.ListTrash(1, &model.ContextElementTrashQueryRequest{})
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Nil(t, items[0].PurgeAt)
+	})
+}
+
+func TestContextElementService_RestoreTrash(t *testing.T) {
+	t.Run("ID列表为空", func(t *testing.T) {
+		s := newTestElementService(new(MockContextElementRepository))
+		_, err := s.RestoreTrash(1, &model.ContextElementTrashRequest{})
+		assert.EqualError(t, err, "参数验证失败")
+	})
+
+	t.Run("恢复成功", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		s := newTestElementService(elementRepo)
+		elementRepo.On("RestoreDeleted", uint64(1), []uint64{3, 4}).Return(int64(2), nil)
+
+		result, err := s.RestoreTrash(1, &model.ContextElementTrashRequest{IDs: []uint64{3, 4}})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), result.Affected)
+	})
+}
+
+func TestContextElementService_PurgeTrash(t *testing.T) {
+	t.Run("清空回收站", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		s := newTestElementService(elementRepo)
+		elementRepo.On("PurgeDeleted", uint64(1), []uint64(nil)).Return(int64(5), nil)
+
+		result, err := s.PurgeTrash(1, nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), result.Affected)
+	})
+
+	t.Run("ID列表为空", func(t *testing.T) {
+		s := newTestElementService(new(MockContextElementRepository))
+		_, err := s.PurgeTrash(1, &model.ContextElementTrashRequest{})
+		assert.EqualError(t, err, "参数验证失败")
+	})
+}
+
+func TestTrashSweeper_Sweep(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	cfg := &config.Config{Trash: config.TrashConfig{RetentionDays: 7}}
+
+	t.Run("分批删除直到不足一批", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		before := now.AddDate(0, 0, -7)
+		elementRepo.On("PurgeDeletedBefore", before, trashSweepBatchSize).Return(int64(trashSweepBatchSize), nil).Twice()
+		elementRepo.On("PurgeDeletedBefore", before, trashSweepBatchSize).Return(int64(12), nil).Once()
+
+		purged, err := NewTrashSweeper(elementRepo, cfg).Sweep(now)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2*trashSweepBatchSize+12), purged)
+		elementRepo.AssertNumberOfCalls(t, "PurgeDeletedBefore", 3)
+	})
+
+	t.Run("删除失败时返回已删除数量", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		elementRepo.On("PurgeDeletedBefore", mock.Anything, trashSweepBatchSize).Return(int64(trashSweepBatchSize), nil).Once()
+		elementRepo.On("PurgeDeletedBefore", mock.Anything, trashSweepBatchSize).Return(int64(0), errors.New("db error")).Once()
+
+		purged, err := NewTrashSweeper(elementRepo, cfg).Sweep(now)
+		assert.Error(t, err)
+		assert.Equal(t, int64(trashSweepBatchSize), purged)
+	})
+
+	t.Run("保留天数为0时不清理", func(t *testing.T) {
+		elementRepo := new(MockContextElementRepository)
+		purged, err := NewTrashSweeper(elementRepo, &config.Config{}).Sweep(now)
+		require.NoError(t, err)
+		assert.Zero(t, purged)
+		elementRepo.AssertNotCalled(t, "PurgeDeletedBefore", mock.Anything, mock.Anything)
+	})
+}
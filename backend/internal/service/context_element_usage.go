@@ -0,0 +1,139 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"cese-backend/internal/model"
+	"cese-backend/pkg/validator"
+)
+
+// defaultRecentLimit 未指定数量时最近使用返回的六要素数量
+const defaultRecentLimit = 10
+
+// RecordUsage 记录一次打开、渲染、生成或复制，返回更新后的使用统计
+func (s *contextElementService) RecordUsage(userID, elementID uint64, event string) (*model.ContextElementUsageResponse, error) {
+	if !model.IsUsageEvent(event) {
+		return nil, errors.New("参数验证失败")
+	}
+	if _, err := s.getOwnedElement(userID, elementID); err != nil {
+		return nil, err
+	}
+
+	if err := s.usageRepo.Record(userID, elementID, event, time.Now()); err != nil {
+		return nil, errors.New("记录使用情况失败")
+	}
+	return s.getUsage(elementID)
+}
+
+// SetFavorite 收藏或取消收藏六要素
+func (s *contextElementService) SetFavorite(userID, elementID uint64, favorite bool) (*model.ContextElementUsageResponse, error) {
+	if _, err := s.getOwnedElement(userID, elementID); err != nil {
+		return nil, err
+	}
+
+	if err := s.usageRepo.SetFavorite(userID, elementID, favorite); err != nil {
+		return nil, errors.New("更新收藏失败")
+	}
+	return s.getUsage(elementID)
+}
+
+// SetPinned 置顶或取消置顶六要素，已置顶时保持原置顶时间
+func (s *contextElementService) SetPinned(userID, elementID uint64, pinned bool) (*model.ContextElementUsageResponse, error) {
+	if _, err := s.getOwnedElement(userID, elementID); err != nil {
+		return nil, err
+	}
+
+	usage, err := s.usageRepo.GetByElementID(elementID)
+	if err != nil {
+		return nil, errors.New("查询使用情况失败")
+	}
+	if usage != nil && usage.Pinned == pinned {
+		return usage.ToResponse(), nil
+	}
+
+	if err := s.usageRepo.SetPinned(userID, elementID, pinned, time.Now()); err != nil {
+		return nil, errors.New("更新置顶失败")
+	}
+	return s.getUsage(elementID)
+}
+
+// GetRecent 获取最近打开或使用的六要素，按最近使用时间倒序
+func (s *contextElementService) GetRecent(userID uint64, req *model.ContextElementRecentRequest) ([]*model.ContextElementResponse, error) {
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, errors.New("参数验证失败")
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultRecentLimit
+	}
+
+	usages, err := s.usageRepo.GetRecent(userID, req.Limit)
+	if err != nil {
+		return nil, errors.New("查询最近使用失败")
+	}
+	if len(usages) == 0 {
+		return []*model.ContextElementResponse{}, nil
+	}
+
+	ids := make([]uint64, len(usages))
+	for i, usage := range usages {
+		ids[i] = usage.ContextElementID
+	}
+	elements, err := s.elementRepo.GetByIDs(userID, ids)
+	if err != nil {
+		return nil, errors.New("查询六要素列表失败")
+	}
+
+	byID := make(map[uint64]*model.ContextElement, len(elements))
+	for _, element := range elements {
+		byID[element.ID] = element
+	}
+
+	// 按最近使用的顺序返回
+	responses := make([]*model.ContextElementResponse, 0, len(usages))
+	for _, usage := range usages {
+		element, ok := byID[usage.ContextElementID]
+		if !ok {
+			continue
+		}
+		resp := element.ToResponse()
+		resp.Usage = usage.ToResponse()
+		responses = append(responses, resp)
+	}
+	return responses, nil
+}
+
+// getUsage 获取六要素的使用统计
+func (s *contextElementService) getUsage(elementID uint64) (*model.ContextElementUsageResponse, error) {
+	usage, err := s.usageRepo.GetByElementID(elementID)
+	if err != nil {
+		return nil, errors.New("查询使用情况失败")
+	}
+	return usage.ToResponse(), nil
+}
+
+// attachUsage 为六要素响应填充收藏、置顶和使用统计
+func (s *contextElementService) attachUsage(responses []*model.ContextElementResponse) error {
+	if len(responses) == 0 {
+		return nil
+	}
+
+	ids := make([]uint64, len(responses))
+	for i, resp := range responses {
+		ids[i] = resp.ID
+	}
+	usages, err := s.usageRepo.GetByElementIDs(ids)
+	if err != nil {
+		return errors.New("查询使用情况失败")
+	}
+
+	byID := make(map[uint64]*model.ContextElementUsage, len(usages))
+	for _, usage := range usages {
+		byID[usage.ContextElementID] = usage
+	}
+	for _, resp := range responses {
+		resp.Usage = byID[resp.ID].ToResponse()
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"cese-backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockContextElementUsageRepository 六要素使用统计Repository模拟
+type MockContextElementUsageRepository struct {
+	mock.Mock
+}
+
+func (m *MockContextElementUsageRepository) GetByElementID(elementID uint64) (*model.ContextElementUsage, error) {
+	args := m.Called(elementID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ContextElementUsage), args.Error(1)
+}
+
+func (m *MockContextElementUsageRepository) GetByElementIDs(elementIDs []uint64) ([]*model.ContextElementUsage, error) {
+	args := m.Called(elementIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.ContextElementUsage), args.Error(1)
+}
+
+func (m *MockContextElementUsageRepository) GetRecent(userID uint64, limit int) ([]*model.ContextElementUsage, error) {
+	args := m.Called(userID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.ContextElementUsage), args.Error(1)
+}
+
+func (m *MockContextElementUsageRepository) Record(userID, elementID uint64, event string, at time.Time) error {
+	args := m.Called(userID, elementID, event, at)
+	return args.Error(0)
+}
+
+func (m *MockContextElementUsageRepository) SetFavorite(userID, elementID uint64, favorite bool) error {
+	args := m.Called(userID, elementID, favorite)
+	return args.Error(0)
+}
+
+func (m *MockContextElementUsageRepository) SetPinned(userID, elementID uint64, pinned bool, at time.Time) error {
+	args := m.Called(userID, elementID, pinned, at)
+	return args.Error(0)
+}
+
+// newTestUsageRepository 返回没有任何使用统计的Repository，供不关心使用统计的测试使用
+func newTestUsageRepository() *MockContextElementUsageRepository {
+	usageRepo := new(MockContextElementUsageRepository)
+	usageRepo.On("GetByElementIDs", mock.Anything).Return([]*model.ContextElementUsage{}, nil).Maybe()
+	return usageRepo
+}
+
+func TestContextElementService_GetListWithUsage(t *testing.T) {
+	elementRepo := new(MockContextElementRepository)
+	s := newTestElementService(elementRepo)
+	usageRepo := new(MockContextElementUsageRepository)
+	s.usageRepo = usageRepo
+
+	favorite := true
+	req := &model.ContextElementQueryRequest{Favorite: &favorite, SortBy: model.SortByUseCount, SortDesc: true, PinnedFirst: true}
+	elementRepo.On("GetByUserID", uint64(1), req).Return([]*model.ContextElement{
+		{ID: 2, UserID: 1, Subject: "周报"},
+		{ID: 3, UserID: 1, Subject: "日报"},
+	}, int64(2), nil)
+	usageRepo.On("GetByElementIDs", []uint64{2, 3}).Return([]*model.ContextElementUsage{
+		{ContextElementID: 2, Favorite: true, Pinned: true, RenderCount: 3, CopyCount: 2, UseCount: 5},
+	}, nil)
+
+	elements, total, err := s.GetList(1, req)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, elements, 2)
+	assert.True(t, elements[0].Usage.Pinned)
+	assert.Equal(t, int64(5), elements[0].Usage.UseCount)
+	// 没有使用统计记录时返回零值
+	require.NotNil(t, elements[1].Usage)
+	assert.False(t, elements[1].Usage.Favorite)
+	assert.Zero(t, elements[1].Usage.UseCount)
+
+	_, _, err = s.GetList(1, &model.ContextElementQueryRequest{SortBy: "opened_at"})
+	assert.EqualError(t, err, "参数验证失败")
+}
+
+func TestContextElementService_SearchWithUsage(t *testing.T) {
+	elementRepo := new(MockContextElementRepository)
+	s := newTestElementService(elementRepo)
+	usageRepo := new(MockContextElementUsageRepository)
+	s.usageRepo = usageRepo
+
+	req := &model.ContextElementQueryRequest{Keyword: "周报"}
+	elementRepo.On("Search", uint64(1), req).Return([]*model.ContextElementSearchHit{
+		{Element: &model.ContextElement{ID: 2, UserID: 1, Subject: "周报"}, Score: 2},
+		{Element: &model.ContextElement{ID: 3, UserID: 1, Subject: "月度周报"}, Score: 1},
+	}, int64(2), nil)
+	usageRepo.On("GetByElementIDs", []uint64{2, 3}).Return([]*model.ContextElementUsage{
+		{ContextElementID: 3, Favorite: true, UseCount: 4},
+	}, nil)
+
+	results, total, err := s.Search(1, req)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, results, 2)
+	require.NotNil(t, results[0].Usage)
+	assert.Zero(t, results[0].Usage.UseCount)
+	assert.True(t, results[1].Usage.Favorite)
+	assert.Equal(t, int64(4), results[1].Usage.UseCount)
+}
+
+func TestContextElementService_RecordUsage(t *testing.T) {
+	elementRepo := new(MockContextElementRepository)
+	s := newTestElementService(elementRepo)
+	usageRepo := new(MockContextElementUsageRepository)
+	s.usageRepo = usageRepo
+
+	elementRepo.On("GetByID", uint64(2)).Return(&model.ContextElement{ID: 2, UserID: 1}, nil)
+	elementRepo.On("GetByID", uint64(3)).Return(&model.ContextElement{ID: 3, UserID: 2}, nil)
+	usageRepo.On("Record", uint64(1), uint64(2), model.UsageCopy, mock.AnythingOfType("time.Time")).Return(nil)
+	usageRepo.On("GetByElementID", uint64(2)).Return(&model.ContextElementUsage{ContextElementID: 2, CopyCount: 1, UseCount: 1}, nil)
+
+	usage, err := s.RecordUsage(1, 2, model.UsageCopy)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), usage.CopyCount)
+
+	_, err = s.RecordUsage(1, 3, model.UsageCopy)
+	assert.EqualError(t, err, "无权访问该记录")
+	_, err = s.RecordUsage(1, 2, "share")
+	assert.EqualError(t, err, "参数验证失败")
+	usageRepo.AssertNumberOfCalls(t, "Record", 1)
+}
+
+func TestContextElementService_SetPinned(t *testing.T) {
+	elementRepo := new(MockContextElementRepository)
+	s := newTestElementService(elementRepo)
+	usageRepo := new(MockContextElementUsageRepository)
+	s.usageRepo = usageRepo
+
+	pinnedAt := time.Now().Add(-time.Hour)
+	elementRepo.On("GetByID", uint64(2)).Return(&model.ContextElement{ID: 2, UserID: 1}, nil)
+	elementRepo.On("GetByID", uint64(3)).Return(&model.ContextElement{ID: 3, UserID: 1}, nil)
+	usageRepo.On("GetByElementID", uint64(2)).Return(&model.ContextElementUsage{ContextElementID: 2, Pinned: true, PinnedAt: &pinnedAt}, nil)
+	usageRepo.On("GetByElementID", uint64(3)).Return(nil, nil).Once()
+	usageRepo.On("SetPinned", uint64(1), uint64(3), true, mock.AnythingOfType("time.Time")).Return(nil)
+	usageRepo.On("GetByElementID", uint64(3)).Return(&model.ContextElementUsage{ContextElementID: 3, Pinned: true, PinnedAt: &pinnedAt}, nil)
+
+	// 已置顶时保持原置顶时间
+	usage, err := s.SetPinned(1, 2, true)
+	require.NoError(t, err)
+	assert.Equal(t, &pinnedAt, usage.PinnedAt)
+	usageRepo.AssertNotCalled(t, "SetPinned", uint64(1), uint64(2), true, mock.Anything)
+
+	// 没有使用统计记录时创建
+	usage, err = s.SetPinned(1, 3, true)
+	require.NoError(t, err)
+	assert.True(t, usage.Pinned)
+}
+
+func TestContextElementService_GetRecent(t *testing.T) {
+	elementRepo := new(MockContextElementRepository)
+	s := newTestElementService(elementRepo)
+	usageRepo := new(MockContextElementUsageRepository)
+	s.usageRepo = usageRepo
+
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+	usageRepo.On("GetRecent", uint64(1), 10).Return([]*model.ContextElementUsage{
+		{ContextElementID: 5, LastUsedAt: &now, GenerationCount: 1, UseCount: 1},
+		{ContextElementID: 2, LastUsedAt: &earlier},
+	}, nil)
+	elementRepo.On("GetByIDs", uint64(1), []uint64{5, 2}).Return([]*model.ContextElement{
+		{ID: 2, UserID: 1, Subject: "周报"},
+		{ID: 5, UserID: 1, Subject: "日报"},
+	}, nil)
+
+	// 按最近使用的顺序返回，而不是ID顺序
+	elements, err := s.GetRecent(1, &model.ContextElementRecentRequest{})
+	require.NoError(t, err)
+	require.Len(t, elements, 2)
+	assert.Equal(t, uint64(5), elements[0].ID)
+	assert.Equal(t, int64(1), elements[0].Usage.GenerationCount)
+	assert.Equal(t, uint64(2), elements[1].ID)
+
+	_, err = s.GetRecent(1, &model.ContextElementRecentRequest{Limit: 100})
+	assert.EqualError(t, err, "参数验证失败")
+}
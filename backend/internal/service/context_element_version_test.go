@@ -0,0 +1,192 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"cese-backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockContextElementVersionRepository 六要素历史版本Repository模拟
+type MockContextElementVersionRepository struct {
+	mock.Mock
+}
+
+func (m *MockContextElementVersionRepository) GetByElementID(elementID uint64) ([]*model.ContextElementVersion, error) {
+	args := m.Called(elementID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.ContextElementVersion), args.Error(1)
+}
+
+func (m *MockContextElementVersionRepository) GetByVersionNo(elementID uint64, versionNo int) (*model.ContextElementVersion, error) {
+	args := m.Called(elementID, versionNo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ContextElementVersion), args.Error(1)
+}
+
+func newTestVersionService(elementRepo *MockContextElementRepository, versionRepo *MockContextElementVersionRepository) *contextElementService {
+	s := newTestElementService(elementRepo)
+	s.versionRepo = versionRepo
+	return s
+}
+
+func testElementVersion(versionNo int, subject, taskGoal string) *model.ContextElementVersion {
+	return &model.ContextElementVersion{
+		ID:        uint64(versionNo),
+		ElementID: 7,
+		UserID:    1,
+		VersionNo: versionNo,
+		Subject:   subject,
+		TaskGoal:  taskGoal,
+		KeyInfo:   "项目列表",
+	}
+}
+
+func TestContextElementService_UpdateSavesPreviousVersion(t *testing.T) {
+	elementRepo := new(MockContextElementRepository)
+	s := newTestElementService(elementRepo)
+
+	elementRepo.On("GetByID", uint64(7)).Return(testVersionedElement(), nil)
+	elementRepo.On("UpdateWithTags",
+		mock.MatchedBy(func(e *model.ContextElement) bool { return e.TaskGoal == "整理下周计划" && len(e.Tags) == 0 }),
+		mock.MatchedBy(func(v *model.ContextElementVersion) bool {
+			// 保存的是修改前的内容
+			return v.ElementID == 7 && v.TaskGoal == "整理本周工作" && v.KeyInfo == "项目列表"
+		}),
+	).Return(nil)
+
+	result, err := s.Update(1, 7, &model.ContextElementUpdateRequest{Subject: "周报生成", TaskGoal: "整理下周计划"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "整理下周计划", result.TaskGoal)
+	elementRepo.AssertExpectations(t)
+}
+
+func TestContextElementService_ListVersions(t *testing.T) {
+	elementRepo := new(MockContextElementRepository)
+	versionRepo := new(MockContextElementVersionRepository)
+	s := newTestVersionService(elementRepo, versionRepo)
+
+	elementRepo.On("GetByID", uint64(7)).Return(testVersionedElement(), nil)
+	versionRepo.On("GetByElementID", uint64(7)).Return([]*model.ContextElementVersion{
+		testElementVersion(2, "周报生成", "整理本周工作"),
+		testElementVersion(1, "周报", "整理工作"),
+	}, nil)
+
+	versions, err := s.ListVersions(1, 7)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, 2, versions[0].VersionNo)
+	assert.Equal(t, "周报", versions[1].Subject)
+
+	// 不能查看他人的历史版本
+	_, err = s.ListVersions(2, 7)
+	assert.EqualError(t, err, "无权访问该记录")
+	versionRepo.AssertNumberOfCalls(t, "GetByElementID", 1)
+}
+
+func TestContextElementService_GetVersion(t *testing.T) {
+	elementRepo := new(MockContextElementRepository)
+	versionRepo := new(MockContextElementVersionRepository)
+	s := newTestVersionService(elementRepo, versionRepo)
+
+	elementRepo.On("GetByID", uint64(7)).Return(testVersionedElement(), nil)
+	versionRepo.On("GetByVersionNo", uint64(7), 1).Return(testElementVersion(1, "周报", "整理工作"), nil)
+	versionRepo.On("GetByVersionNo", uint64(7), 9).Return(nil, nil)
+	versionRepo.On("GetByVersionNo", uint64(7), 5).Return(nil, errors.New("db error"))
+
+	version, err := s.GetVersion(1, 7, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "整理工作", version.TaskGoal)
+
+	_, err = s.GetVersion(1, 7, 9)
+	assert.EqualError(t, err, "历史版本不存在")
+	_, err = s.GetVersion(1, 7, 5)
+	assert.EqualError(t, err, "查询历史版本失败")
+}
+
+func TestContextElementService_DiffVersions(t *testing.T) {
+	elementRepo := new(MockContextElementRepository)
+	versionRepo := new(MockContextElementVersionRepository)
+	s := newTestVersionService(elementRepo, versionRepo)
+
+	elementRepo.On("GetByID", uint64(7)).Return(testVersionedElement(), nil)
+	versionRepo.On("GetByVersionNo", uint64(7), 1).Return(testElementVersion(1, "周报", "整理工作"), nil)
+	versionRepo.On("GetByVersionNo", uint64(7), 2).Return(testElementVersion(2, "周报", "整理本周工作"), nil)
+
+	fieldByKey := func(fields []*model.FieldDiff) map[string]*model.FieldDiff {
+		byKey := make(map[string]*model.FieldDiff, len(fields))
+		for _, field := range fields {
+			byKey[field.Field] = field
+		}
+		return byKey
+	}
+
+	t.Run("两个历史版本对比", func(t *testing.T) {
+		result, err := s.DiffVersions(1, 7, &model.ContextElementVersionDiffRequest{From: 1, To: 2})
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.ToVersion)
+
+		fields := fieldByKey(result.Fields)
+		assert.False(t, fields["subject"].Changed)
+		assert.True(t, fields["task_goal"].Changed)
+		assert.Equal(t, "整理工作", fields["task_goal"].From)
+		assert.Equal(t, "整理本周工作", fields["task_goal"].To)
+		assert.NotEmpty(t, fields["task_goal"].Lines)
+		assert.Empty(t, fields["key_info"].Lines)
+	})
+
+	t.Run("与当前版本对比", func(t *testing.T) {
+		result, err := s.DiffVersions(1, 7, &model.ContextElementVersionDiffRequest{From: 1})
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.ToVersion)
+
+		fields := fieldByKey(result.Fields)
+		assert.True(t, fields["subject"].Changed)
+		assert.Equal(t, "周报生成", fields["subject"].To)
+		assert.Equal(t, "简洁", fields["behavior_rule"].To)
+	})
+
+	t.Run("缺少起始版本", func(t *testing.T) {
+		_, err := s.DiffVersions(1, 7, &model.ContextElementVersionDiffRequest{})
+		assert.EqualError(t, err, "参数验证失败")
+	})
+}
+
+func TestContextElementService_RestoreVersion(t *testing.T) {
+	elementRepo := new(MockContextElementRepository)
+	versionRepo := new(MockContextElementVersionRepository)
+	s := newTestVersionService(elementRepo, versionRepo)
+
+	elementRepo.On("GetByID", uint64(7)).Return(testVersionedElement(), nil)
+	versionRepo.On("GetByVersionNo", uint64(7), 1).Return(testElementVersion(1, "周报", "整理工作"), nil)
+	versionRepo.On("GetByVersionNo", uint64(7), 9).Return(nil, nil)
+	elementRepo.On("UpdateWithVersion",
+		mock.MatchedBy(func(e *model.ContextElement) bool {
+			return e.ID == 7 && e.Subject == "周报" && e.TaskGoal == "整理工作" && e.BehaviorRule == ""
+		}),
+		mock.MatchedBy(func(v *model.ContextElementVersion) bool {
+			// 恢复前的内容作为新的历史版本保存
+			return v.Subject == "周报生成" && v.BehaviorRule == "简洁"
+		}),
+	).Return(nil)
+
+	result, err := s.RestoreVersion(1, 7, 1, "")
+	require.NoError(t, err)
+	assert.Equal(t, "周报", result.Subject)
+	// 标签不属于版本内容，恢复时保持不变
+	assert.Len(t, result.Tags, 1)
+
+	_, err = s.RestoreVersion(1, 7, 9, "")
+	assert.EqualError(t, err, "历史版本不存在")
+	_, err = s.RestoreVersion(2, 7, 1, "")
+	assert.EqualError(t, err, "无权更新该记录")
+	elementRepo.AssertNumberOfCalls(t, "UpdateWithVersion", 1)
+}
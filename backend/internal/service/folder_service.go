@@ -0,0 +1,207 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"cese-backend/internal/model"
+	"cese-backend/internal/repository"
+	"cese-backend/pkg/validator"
+)
+
+// FolderService 文件夹服务接口
+type FolderService interface {
+	GetTree(userID uint64) ([]*model.FolderResponse, error)
+	Create(userID uint64, req *model.FolderCreateRequest) (*model.FolderResponse, error)
+	Rename(userID, folderID uint64, req *model.FolderRenameRequest) (*model.FolderResponse, error)
+	Move(userID, folderID uint64, req *model.FolderMoveRequest) (*model.FolderResponse, error)
+	Delete(userID, folderID uint64, req *model.FolderDeleteRequest) error
+}
+
+// folderService 文件夹服务实现
+type folderService struct {
+	folderRepo repository.FolderRepository
+}
+
+// NewFolderService 创建文件夹服务实例
+func NewFolderService(folderRepo repository.FolderRepository) FolderService {
+	return &folderService{
+		folderRepo: folderRepo,
+	}
+}
+
+// GetTree 获取用户的文件夹树
+func (s *folderService) GetTree(userID uint64) ([]*model.FolderResponse, error) {
+	folders, err := s.folderRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, errors.New("查询文件夹失败")
+	}
+
+	counts, err := s.folderRepo.CountElements(userID)
+	if err != nil {
+		return nil, errors.New("查询文件夹失败")
+	}
+
+	return model.BuildFolderTree(folders, counts), nil
+}
+
+// Create 创建文件夹
+func (s *folderService) Create(userID uint64, req *model.FolderCreateRequest) (*model.FolderResponse, error) {
+	req.Name = strings.TrimSpace(req.Name)
+
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, errors.New("参数验证失败")
+	}
+
+	if req.ParentID != nil {
+		if _, err := s.getOwnedFolder(userID, *req.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.checkNameAvailable(userID, req.ParentID, req.Name); err != nil {
+		return nil, err
+	}
+
+	folder := &model.Folder{
+		UserID:   userID,
+		ParentID: req.ParentID,
+		Name:     req.Name,
+	}
+	if err := s.folderRepo.Create(folder); err != nil {
+		return nil, errors.New("创建文件夹失败")
+	}
+
+	return folder.ToResponse(), nil
+}
+
+// Rename 重命名文件夹
+func (s *folderService) Rename(userID, folderID uint64, req *model.FolderRenameRequest) (*model.FolderResponse, error) {
+	req.Name = strings.TrimSpace(req.Name)
+
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, errors.New("参数验证失败")
+	}
+
+	folder, err := s.getOwnedFolder(userID, folderID)
+	if err != nil {
+		return nil, err
+	}
+	if folder.Name == req.Name {
+		return folder.ToResponse(), nil
+	}
+
+	if err := s.checkNameAvailable(userID, folder.ParentID, req.Name); err != nil {
+		return nil, err
+	}
+
+	if err := s.folderRepo.Rename(folderID, req.Name); err != nil {
+		return nil, errors.New("重命名文件夹失败")
+	}
+
+	folder.Name = req.Name
+	return folder.ToResponse(), nil
+}
+
+// Move 移动文件夹（不能移动到自身或其子孙文件夹下，已在目标位置时不做修改）
+func (s *folderService) Move(userID, folderID uint64, req *model.FolderMoveRequest) (*model.FolderResponse, error) {
+	folder, err := s.getOwnedFolder(userID, folderID)
+	if err != nil {
+		return nil, err
+	}
+	if folder.InParent(req.ParentID) {
+		return folder.ToResponse(), nil
+	}
+
+	if req.ParentID != nil {
+		if _, err := s.getOwnedFolder(userID, *req.ParentID); err != nil {
+			return nil, err
+		}
+
+		folders, err := s.folderRepo.GetByUserID(userID)
+		if err != nil {
+			return nil, errors.New("查询文件夹失败")
+		}
+		for _, id := range model.DescendantFolderIDs(folders, folderID) {
+			if id == *req.ParentID {
+				return nil, errors.New("不能移动到自身或子文件夹下")
+			}
+		}
+	}
+
+	if err := s.checkNameAvailable(userID, req.ParentID, folder.Name); err != nil {
+		return nil, err
+	}
+
+	if err := s.folderRepo.Move(folderID, req.ParentID); err != nil {
+		return nil, errors.New("移动文件夹失败")
+	}
+
+	folder.ParentID = req.ParentID
+	return folder.ToResponse(), nil
+}
+
+// Delete 删除文件夹：cascade 同时删除子文件夹及六要素，reparent（默认）将其移动到上级文件夹
+func (s *folderService) Delete(userID, folderID uint64, req *model.FolderDeleteRequest) error {
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return errors.New("参数验证失败")
+	}
+
+	folder, err := s.getOwnedFolder(userID, folderID)
+	if err != nil {
+		return err
+	}
+
+	if req.Mode == model.FolderDeleteCascade {
+		folders, err := s.folderRepo.GetByUserID(userID)
+		if err != nil {
+			return errors.New("查询文件夹失败")
+		}
+		if err := s.folderRepo.DeleteCascade(userID, model.DescendantFolderIDs(folders, folderID)); err != nil {
+			return errors.New("删除文件夹失败")
+		}
+		return nil
+	}
+
+	// 子文件夹移动到上级后不能与上级中已有的文件夹重名
+	folders, err := s.folderRepo.GetByUserID(userID)
+	if err != nil {
+		return errors.New("查询文件夹失败")
+	}
+	if conflicts := model.ReparentNameConflicts(folders, folder); len(conflicts) > 0 {
+		return fmt.Errorf("上级文件夹中已有同名文件夹: %s", strings.Join(conflicts, ", "))
+	}
+
+	if err := s.folderRepo.DeleteReparent(folder); err != nil {
+		return errors.New("删除文件夹失败")
+	}
+	return nil
+}
+
+// getOwnedFolder 获取当前用户拥有的文件夹
+func (s *folderService) getOwnedFolder(userID, folderID uint64) (*model.Folder, error) {
+	folder, err := s.folderRepo.GetByID(folderID)
+	if err != nil {
+		return nil, errors.New("查询文件夹失败")
+	}
+	if folder == nil || folder.UserID != userID {
+		return nil, errors.New("文件夹不存在")
+	}
+	return folder, nil
+}
+
+// checkNameAvailable 检查同级文件夹名称是否可用
+func (s *folderService) checkNameAvailable(userID uint64, parentID *uint64, name string) error {
+	exists, err := s.folderRepo.ExistsByName(userID, parentID, name)
+	if err != nil {
+		return errors.New("查询文件夹失败")
+	}
+	if exists {
+		return errors.New("文件夹已存在")
+	}
+	return nil
+}
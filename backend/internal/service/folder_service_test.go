@@ -0,0 +1,280 @@
+package service
+
+import (
+	"testing"
+
+	"cese-backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockFolderRepository 文件夹Repository模拟
+type MockFolderRepository struct {
+	mock.Mock
+}
+
+func (m *MockFolderRepository) Create(folder *model.Folder) error {
+	args := m.Called(folder)
+	return args.Error(0)
+}
+
+func (m *MockFolderRepository) GetByID(id uint64) (*model.Folder, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Folder), args.Error(1)
+}
+
+func (m *MockFolderRepository) GetByUserID(userID uint64) ([]*model.Folder, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Folder), args.Error(1)
+}
+
+func (m *MockFolderRepository) CountElements(userID uint64) (map[uint64]int64, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uint64]int64), args.Error(1)
+}
+
+func (m *MockFolderRepository) ExistsByName(userID uint64, parentID *uint64, name string) (bool, error) {
+	args := m.Called(userID, parentID, name)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockFolderRepository) Rename(id uint64, name string) error {
+	args := m.Called(id, name)
+	return args.Error(0)
+}
+
+func (m *MockFolderRepository) Move(id uint64, parentID *uint64) error {
+	args := m.Called(id, parentID)
+	return args.Error(0)
+}
+
+func (m *MockFolderRepository) DeleteCascade(userID uint64, ids []uint64) error {
+	args := m.Called(userID, ids)
+	return args.Error(0)
+}
+
+func (m *MockFolderRepository) DeleteReparent(folder *model.Folder) error {
+	args := m.Called(folder)
+	return args.Error(0)
+}
+
+func uint64Ptr(v uint64) *uint64 {
+	return &v
+}
+
+// testFolders 工作(1) > 周报(2) > 草稿(3)，以及根目录下的 个人(4)
+func testFolders() []*model.Folder {
+	return []*model.Folder{
+		{ID: 1, UserID: 1, Name: "工作"},
+		{ID: 2, UserID: 1, ParentID: uint64Ptr(1), Name: "周报"},
+		{ID: 3, UserID: 1, ParentID: uint64Ptr(2), Name: "草稿"},
+		{ID: 4, UserID: 1, Name: "个人"},
+	}
+}
+
+func newTestFolderService() (FolderService, *MockFolderRepository) {
+	folderRepo := new(MockFolderRepository)
+	for _, folder := range testFolders() {
+		folderRepo.On("GetByID", folder.ID).Return(folder, nil).Maybe()
+	}
+	folderRepo.On("GetByID", uint64(9)).Return(&model.Folder{ID: 9, UserID: 2, Name: "他人的"}, nil).Maybe()
+	folderRepo.On("GetByUserID", uint64(1)).Return(testFolders(), nil).Maybe()
+	return NewFolderService(folderRepo), folderRepo
+}
+
+func TestFolderService_Create(t *testing.T) {
+	t.Run("在上级文件夹下创建", func(t *testing.T) {
+		s, folderRepo := newTestFolderService()
+		folderRepo.On("ExistsByName", uint64(1), uint64Ptr(1), "月报").Return(false, nil)
+		folderRepo.On("Create", mock.MatchedBy(func(f *model.Folder) bool {
+			return f.UserID == 1 && f.Name == "月报" && *f.ParentID == 1
+		})).Run(func(args mock.Arguments) {
+			args.Get(0).(*model.Folder).ID = 5
+		}).Return(nil)
+
+		folder, err := s.Create(1, &model.FolderCreateRequest{Name: " 月报 ", ParentID: uint64Ptr(1)})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(5), folder.ID)
+		assert.Equal(t, "月报", folder.Name)
+	})
+
+	t.Run("同级重名", func(t *testing.T) {
+		s, folderRepo := newTestFolderService()
+		folderRepo.On("ExistsByName", uint64(1), (*uint64)(nil), "工作").Return(true, nil)
+
+		_, err := s.Create(1, &model.FolderCreateRequest{Name: "工作"})
+		assert.EqualError(t, err, "文件夹已存在")
+		folderRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("上级文件夹属于他人", func(t *testing.T) {
+		s, folderRepo := newTestFolderService()
+
+		_, err := s.Create(1, &model.FolderCreateRequest{Name: "月报", ParentID: uint64Ptr(9)})
+		assert.EqualError(t, err, "文件夹不存在")
+		folderRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("名称为空", func(t *testing.T) {
+		s, _ := newTestFolderService()
+
+		_, err := s.Create(1, &model.FolderCreateRequest{Name: "  "})
+		assert.EqualError(t, err, "参数验证失败")
+	})
+}
+
+func TestFolderService_Rename(t *testing.T) {
+	t.Run("重命名", func(t *testing.T) {
+		s, folderRepo := newTestFolderService()
+		folderRepo.On("ExistsByName", uint64(1), uint64Ptr(1), "日报").Return(false, nil)
+		folderRepo.On("Rename", uint64(2), "日报").Return(nil)
+
+		folder, err := s.Rename(1, 2, &model.FolderRenameRequest{Name: "日报"})
+		require.NoError(t, err)
+		assert.Equal(t, "日报", folder.Name)
+		folderRepo.AssertExpectations(t)
+	})
+
+	t.Run("名称未变化", func(t *testing.T) {
+		s, folderRepo := newTestFolderService()
+
+		folder, err := s.Rename(1, 2, &model.FolderRenameRequest{Name: " 周报 "})
+		require.NoError(t, err)
+		assert.Equal(t, "周报", folder.Name)
+		folderRepo.AssertNotCalled(t, "ExistsByName", mock.Anything, mock.Anything, mock.Anything)
+		folderRepo.AssertNotCalled(t, "Rename", mock.Anything, mock.Anything)
+	})
+
+	t.Run("与同级文件夹重名", func(t *testing.T) {
+		s, folderRepo := newTestFolderService()
+		folderRepo.On("ExistsByName", uint64(1), (*uint64)(nil), "个人").Return(true, nil)
+
+		_, err := s.Rename(1, 1, &model.FolderRenameRequest{Name: "个人"})
+		assert.EqualError(t, err, "文件夹已存在")
+		folderRepo.AssertNotCalled(t, "Rename", mock.Anything, mock.Anything)
+	})
+}
+
+func TestFolderService_Move(t *testing.T) {
+	t.Run("移动到其他文件夹下", func(t *testing.T) {
+		s, folderRepo := newTestFolderService()
+		folderRepo.On("ExistsByName", uint64(1), uint64Ptr(4), "草稿").Return(false, nil)
+		folderRepo.On("Move", uint64(3), uint64Ptr(4)).Return(nil)
+
+		folder, err := s.Move(1, 3, &model.FolderMoveRequest{ParentID: uint64Ptr(4)})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(4), *folder.ParentID)
+		folderRepo.AssertExpectations(t)
+	})
+
+	t.Run("移动到根目录", func(t *testing.T) {
+		s, folderRepo := newTestFolderService()
+		folderRepo.On("ExistsByName", uint64(1), (*uint64)(nil), "周报").Return(false, nil)
+		folderRepo.On("Move", uint64(2), (*uint64)(nil)).Return(nil)
+
+		folder, err := s.Move(1, 2, &model.FolderMoveRequest{})
+		require.NoError(t, err)
+		assert.Nil(t, folder.ParentID)
+	})
+
+	t.Run("已在目标文件夹下时不做修改", func(t *testing.T) {
+		s, folderRepo := newTestFolderService()
+
+		folder, err := s.Move(1, 2, &model.FolderMoveRequest{ParentID: uint64Ptr(1)})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), *folder.ParentID)
+
+		folder, err = s.Move(1, 4, &model.FolderMoveRequest{})
+		require.NoError(t, err)
+		assert.Nil(t, folder.ParentID)
+
+		folderRepo.AssertNotCalled(t, "ExistsByName", mock.Anything, mock.Anything, mock.Anything)
+		folderRepo.AssertNotCalled(t, "Move", mock.Anything, mock.Anything)
+	})
+
+	t.Run("不能移动到自身或子孙文件夹下", func(t *testing.T) {
+		s, folderRepo := newTestFolderService()
+
+		_, err := s.Move(1, 1, &model.FolderMoveRequest{ParentID: uint64Ptr(3)})
+		assert.EqualError(t, err, "不能移动到自身或子文件夹下")
+		_, err = s.Move(1, 1, &model.FolderMoveRequest{ParentID: uint64Ptr(1)})
+		assert.EqualError(t, err, "不能移动到自身或子文件夹下")
+		folderRepo.AssertNotCalled(t, "Move", mock.Anything, mock.Anything)
+	})
+
+	t.Run("目标文件夹下已有同名文件夹", func(t *testing.T) {
+		s, folderRepo := newTestFolderService()
+		folderRepo.On("ExistsByName", uint64(1), (*uint64)(nil), "草稿").Return(true, nil)
+
+		_, err := s.Move(1, 3, &model.FolderMoveRequest{})
+		assert.EqualError(t, err, "文件夹已存在")
+		folderRepo.AssertNotCalled(t, "Move", mock.Anything, mock.Anything)
+	})
+}
+
+func TestFolderService_Delete(t *testing.T) {
+	t.Run("默认将子文件夹和六要素移动到上级", func(t *testing.T) {
+		s, folderRepo := newTestFolderService()
+		folderRepo.On("DeleteReparent", mock.MatchedBy(func(f *model.Folder) bool { return f.ID == 2 })).Return(nil)
+
+		require.NoError(t, s.Delete(1, 2, &model.FolderDeleteRequest{}))
+		folderRepo.AssertExpectations(t)
+	})
+
+	t.Run("子文件夹与上级中的文件夹重名", func(t *testing.T) {
+		folderRepo := new(MockFolderRepository)
+		folders := append(testFolders(), &model.Folder{ID: 5, UserID: 1, ParentID: uint64Ptr(1), Name: "草稿"})
+		folderRepo.On("GetByID", uint64(2)).Return(folders[1], nil)
+		folderRepo.On("GetByUserID", uint64(1)).Return(folders, nil)
+		s := NewFolderService(folderRepo)
+
+		assert.EqualError(t, s.Delete(1, 2, &model.FolderDeleteRequest{}), "上级文件夹中已有同名文件夹: 草稿")
+		folderRepo.AssertNotCalled(t, "DeleteReparent", mock.Anything)
+	})
+
+	t.Run("名称比较不区分大小写", func(t *testing.T) {
+		folderRepo := new(MockFolderRepository)
+		folders := []*model.Folder{
+			{ID: 1, UserID: 1, Name: "Work"},
+			{ID: 2, UserID: 1, Name: "归档"},
+			{ID: 3, UserID: 1, ParentID: uint64Ptr(2), Name: "work"},
+		}
+		folderRepo.On("GetByID", uint64(2)).Return(folders[1], nil)
+		folderRepo.On("GetByUserID", uint64(1)).Return(folders, nil)
+		s := NewFolderService(folderRepo)
+
+		assert.EqualError(t, s.Delete(1, 2, &model.FolderDeleteRequest{}), "上级文件夹中已有同名文件夹: work")
+	})
+
+	t.Run("级联删除子孙文件夹", func(t *testing.T) {
+		s, folderRepo := newTestFolderService()
+		folderRepo.On("DeleteCascade", uint64(1), []uint64{1, 2, 3}).Return(nil)
+
+		require.NoError(t, s.Delete(1, 1, &model.FolderDeleteRequest{Mode: model.FolderDeleteCascade}))
+		folderRepo.AssertExpectations(t)
+	})
+
+	t.Run("删除他人的文件夹", func(t *testing.T) {
+		s, folderRepo := newTestFolderService()
+
+		assert.EqualError(t, s.Delete(1, 9, &model.FolderDeleteRequest{}), "文件夹不存在")
+		folderRepo.AssertNotCalled(t, "DeleteReparent", mock.Anything)
+	})
+
+	t.Run("无效的删除方式", func(t *testing.T) {
+		s, _ := newTestFolderService()
+
+		assert.EqualError(t, s.Delete(1, 2, &model.FolderDeleteRequest{Mode: "archive"}), "参数验证失败")
+	})
+}
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"cese-backend/internal/model"
+	"cese-backend/pkg/validator"
+)
+
+// comparisonVariant 准备好的对比变体
+type comparisonVariant struct {
+	variant *model.ComparisonVariant
+	call    *generationCall
+}
+
+// Compare 创建对比运行：每个变体使用相同的模型服务、采样参数和变量执行 repeat 次，保存全部结果
+//
+// 在用户剩余的生成并发名额内（最多每个变体一个）并发执行，按轮次依次取出各变体的执行，
+// 减少模型服务负载变化对耗时的影响。单次执行失败不影响其他执行，
+// 客户端断开时停止尚未开始的执行，已完成的结果仍会保存。
+func (s *generationService) Compare(ctx context.Context, userID uint64, req *model.ComparisonCreateRequest) (*model.ComparisonResponse, error) {
+	req.Name = strings.TrimSpace(req.Name)
+
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("参数验证失败: %v", describeValidationError(err))
+	}
+	if req.Repeat == 0 {
+		req.Repeat = 1
+	}
+
+	variants := make([]*comparisonVariant, len(req.Variants))
+	for i, v := range req.Variants {
+		label := strings.TrimSpace(v.Label)
+		if label == "" {
+			label = string(rune('A' + i))
+		}
+
+		call, err := s.prepare(userID, v.ElementID, &req.GenerateRequest, v.Overrides)
+		if err != nil {
+			return nil, err
+		}
+		if errs := call.response.Errors; len(errs) > 0 {
+			return nil, fmt.Errorf("参数验证失败: 变体 %s 的变量 %s %s", label, errs[0].Name, errs[0].Message)
+		}
+
+		variant := &model.ComparisonVariant{
+			Position:       i + 1,
+			Label:          label,
+			ElementID:      v.ElementID,
+			ElementVersion: call.response.ElementVersion,
+			Prompt:         call.response.Prompt,
+		}
+		variant.SetOverrides(v.Overrides)
+		variants[i] = &comparisonVariant{variant: variant, call: call}
+	}
+
+	first := variants[0].call
+	run := model.NewComparisonRun(userID, req, first.response.Provider, first.request.Model, first.response.Format, first.request.MaxTokens)
+	for _, v := range variants {
+		run.Variants = append(run.Variants, v.variant)
+	}
+
+	workers := s.limiter.acquireUpTo(userID, len(variants))
+	if workers == 0 {
+		return nil, errors.New("生成任务过多，请稍后再试")
+	}
+	defer func() {
+		for i := 0; i < workers; i++ {
+			s.limiter.release(userID)
+		}
+	}()
+
+	outputs := s.executeComparison(ctx, variants, req.Repeat, workers)
+	for i, v := range variants {
+		for _, output := range outputs[i] {
+			if output != nil {
+				v.variant.Outputs = append(v.variant.Outputs, output)
+			}
+		}
+	}
+
+	if err := s.comparisonRepo.Create(run); err != nil {
+		return nil, errors.New("保存对比运行失败")
+	}
+	return run.ToResponse(true), nil
+}
+
+// executeComparison 用 workers 个并发按轮次执行各变体，返回每个变体按执行次数排列的结果，
+// ctx 取消后未开始的执行结果为 nil
+func (s *generationService) executeComparison(ctx context.Context, variants []*comparisonVariant, repeat, workers int) [][]*model.ComparisonOutput {
+	type job struct {
+		variant int
+		attempt int
+	}
+
+	outputs := make([][]*model.ComparisonOutput, len(variants))
+	jobs := make(chan job, len(variants)*repeat)
+	for i := range variants {
+		outputs[i] = make([]*model.ComparisonOutput, repeat)
+	}
+	for attempt := 1; attempt <= repeat; attempt++ {
+		for i := range variants {
+			jobs <- job{variant: i, attempt: attempt}
+		}
+	}
+	close(jobs)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				if ctx.Err() != nil {
+					return
+				}
+				result, err := s.execute(ctx, variants[j.variant].call)
+				output := model.NewComparisonOutput(j.attempt, result)
+				output.Status, output.Error = generationStatus(err)
+				outputs[j.variant][j.attempt-1] = output
+			}
+		}()
+	}
+	wg.Wait()
+	return outputs
+}
+
+// execute 在超时限制下执行一次生成调用，返回生成结果（出错时包含已生成的部分内容）和错误
+func (s *generationService) execute(ctx context.Context, call *generationCall) (*model.GenerateResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.config.LLM.GetTimeout())
+	defer cancel()
+
+	start := time.Now()
+	result, err := call.provider.Generate(ctx, call.request)
+	response := &model.GenerateResponse{DurationMs: time.Since(start).Milliseconds()}
+	if result != nil {
+		response.Content = result.Content
+		response.FinishReason = result.FinishReason
+		response.Usage = result.Usage
+	}
+	return response, err
+}
+
+// GetComparisons 分页查询对比运行，按创建时间倒序，只返回统计不返回执行结果
+func (s *generationService) GetComparisons(userID uint64, req *model.ComparisonQueryRequest) ([]*model.ComparisonResponse, int64, error) {
+	s.setDefaultComparisonQueryParams(req)
+
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, 0, errors.New("参数验证失败")
+	}
+
+	runs, total, err := s.comparisonRepo.GetList(userID, req)
+	if err != nil {
+		return nil, 0, errors.New("查询对比运行失败")
+	}
+
+	responses := make([]*model.ComparisonResponse, len(runs))
+	for i, run := range runs {
+		responses[i] = run.ToResponse(false)
+	}
+
+	return responses, total, nil
+}
+
+// GetComparison 获取对比视图，包括每个变体的提示词、全部执行结果和统计
+func (s *generationService) GetComparison(userID, runID uint64) (*model.ComparisonResponse, error) {
+	run, err := s.getOwnedComparison(userID, runID)
+	if err != nil {
+		return nil, err
+	}
+	return run.ToResponse(true), nil
+}
+
+// UpdateComparison 记录胜出的变体和备注
+func (s *generationService) UpdateComparison(userID, runID uint64, req *model.ComparisonUpdateRequest) (*model.ComparisonResponse, error) {
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, errors.New("参数验证失败")
+	}
+
+	run, err := s.getOwnedComparison(userID, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.WinnerVariantID != nil {
+		switch variantID := *req.WinnerVariantID; {
+		case variantID == 0:
+			run.WinnerVariantID = nil
+		case run.HasVariant(variantID):
+			run.WinnerVariantID = &variantID
+		default:
+			return nil, errors.New("参数验证失败: 变体不属于该对比运行")
+		}
+	}
+	if req.Note != nil {
+		run.Note = *req.Note
+	}
+	if err := s.comparisonRepo.Update(run); err != nil {
+		return nil, errors.New("更新对比运行失败")
+	}
+	return run.ToResponse(true), nil
+}
+
+// DeleteComparison 删除对比运行及其全部执行结果
+func (s *generationService) DeleteComparison(userID, runID uint64) error {
+	if _, err := s.getOwnedComparison(userID, runID); err != nil {
+		return err
+	}
+
+	if err := s.comparisonRepo.Delete(runID); err != nil {
+		return errors.New("删除对比运行失败")
+	}
+	return nil
+}
+
+// getOwnedComparison 获取当前用户的对比运行
+func (s *generationService) getOwnedComparison(userID, runID uint64) (*model.ComparisonRun, error) {
+	run, err := s.comparisonRepo.GetByID(runID)
+	if err != nil {
+		return nil, errors.New("查询对比运行失败")
+	}
+	if run == nil {
+		return nil, errors.New("对比运行不存在")
+	}
+
+	// 检查权限：只能访问自己的对比运行
+	if run.UserID != userID {
+		return nil, errors.New("无权访问该记录")
+	}
+
+	return run, nil
+}
+
+// setDefaultComparisonQueryParams 设置默认查询参数
+func (s *generationService) setDefaultComparisonQueryParams(req *model.ComparisonQueryRequest) {
+	if req.Page <= 0 {
+		req.Page = s.config.Pagination.DefaultPage
+	}
+	if req.Size <= 0 {
+		req.Size = s.config.Pagination.DefaultSize
+	}
+	if req.Size > s.config.Pagination.MaxSize {
+		req.Size = s.config.Pagination.MaxSize
+	}
+}
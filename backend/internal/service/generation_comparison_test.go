@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cese-backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockComparisonRepository 对比运行Repository模拟
+type MockComparisonRepository struct {
+	mock.Mock
+}
+
+func (m *MockComparisonRepository) Create(run *model.ComparisonRun) error {
+	args := m.Called(run)
+	return args.Error(0)
+}
+
+func (m *MockComparisonRepository) GetByID(id uint64) (*model.ComparisonRun, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ComparisonRun), args.Error(1)
+}
+
+func (m *MockComparisonRepository) GetList(userID uint64, req *model.ComparisonQueryRequest) ([]*model.ComparisonRun, int64, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*model.ComparisonRun), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockComparisonRepository) Update(run *model.ComparisonRun) error {
+	args := m.Called(run)
+	return args.Error(0)
+}
+
+func (m *MockComparisonRepository) Delete(id uint64) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// stubComparisonCompletion 提示词包含“不超过十个字”时返回短回复，否则返回长回复；第三次调用失败
+func stubComparisonCompletion(t *testing.T) http.HandlerFunc {
+	var calls int32
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Messages []struct {
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		if atomic.AddInt32(&calls, 1) == 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"error": {"message": "upstream error"}}`))
+			return
+		}
+		content := "本周完成了三项工作，分别是接口设计、数据迁移和联调测试"
+		if strings.Contains(body.Messages[0].Content, "不超过十个字") {
+			content = "完成三项工作"
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"model":   "stub-model",
+			"choices": []interface{}{map[string]interface{}{"message": map[string]string{"role": "assistant", "content": content}, "finish_reason": "stop"}},
+			"usage":   map[string]int{"prompt_tokens": 20, "completion_tokens": len([]rune(content)), "total_tokens": 20 + len([]rune(content))},
+		})
+	}
+}
+
+func TestGenerationService_Compare(t *testing.T) {
+	s := newTestGenerationService(t, stubComparisonCompletion(t))
+	comparisonRepo := new(MockComparisonRepository)
+	comparisonRepo.On("Create", mock.Anything).Return(nil)
+	s.comparisonRepo = comparisonRepo
+
+	rule := "回答不超过十个字"
+	result, err := s.Compare(context.Background(), 1, &model.ComparisonCreateRequest{
+		GenerateRequest: model.GenerateRequest{Variables: map[string]interface{}{"product": "CESE"}},
+		Name:            "行为规则措辞",
+		Variants: []*model.ComparisonVariantRequest{
+			{ElementID: 1},
+			{ElementID: 1, Label: "简洁", Overrides: &model.ContextElementOverrides{BehaviorRule: &rule}},
+		},
+		Repeat: 2,
+	})
+	require.NoError(t, err)
+	comparisonRepo.AssertNumberOfCalls(t, "Create", 1)
+
+	assert.Equal(t, "stub", result.Provider)
+	assert.Equal(t, "stub-model", result.Model)
+	assert.Equal(t, 2, result.Repeat)
+	assert.Equal(t, 200, result.Config.MaxTokens)
+	require.Len(t, result.Variants, 2)
+
+	// 覆盖的字段只用于本次渲染
+	a, b := result.Variants[0], result.Variants[1]
+	assert.Equal(t, "A", a.Label)
+	assert.Equal(t, "简洁", b.Label)
+	assert.Equal(t, uint64(3), b.ElementVersion)
+	assert.NotContains(t, a.Prompt, rule)
+	assert.Contains(t, b.Prompt, rule)
+	assert.Nil(t, a.Overrides)
+	assert.Equal(t, rule, *b.Overrides.BehaviorRule)
+
+	// 变体轮流执行：A1、B1、A2（失败）、B2
+	require.Len(t, a.Outputs, 2)
+	require.Len(t, b.Outputs, 2)
+	assert.Equal(t, model.GenerationStatusCompleted, a.Outputs[0].Status)
+	assert.Equal(t, model.GenerationStatusFailed, a.Outputs[1].Status)
+	assert.Equal(t, "调用模型服务失败: upstream error", a.Outputs[1].Error)
+	assert.Equal(t, 2, b.Outputs[1].Attempt)
+
+	// 统计只计算成功的执行
+	assert.Equal(t, 2, a.Stats.Runs)
+	assert.Equal(t, 1, a.Stats.Completed)
+	assert.Equal(t, 27, a.Stats.MaxLength)
+	assert.Equal(t, 2, b.Stats.Completed)
+	assert.Equal(t, 6, b.Stats.MinLength)
+	assert.Equal(t, float64(6), b.Stats.AvgLength)
+	assert.Equal(t, float64(6), b.Stats.AvgCompletionTokens)
+	assert.Equal(t, 52, b.Stats.TotalTokens)
+}
+
+func TestGenerationService_CompareConcurrently(t *testing.T) {
+	var inFlight, maxInFlight int32
+	s := newTestGenerationService(t, func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			peak := atomic.LoadInt32(&maxInFlight)
+			if current <= peak || atomic.CompareAndSwapInt32(&maxInFlight, peak, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		stubComparisonCompletion(t)(w, r)
+	})
+	comparisonRepo := new(MockComparisonRepository)
+	comparisonRepo.On("Create", mock.Anything).Return(nil)
+	s.comparisonRepo = comparisonRepo
+	s.limiter = newConcurrencyLimiter(3)
+
+	req := func() *model.ComparisonCreateRequest {
+		return &model.ComparisonCreateRequest{
+			GenerateRequest: model.GenerateRequest{Variables: map[string]interface{}{"product": "CESE"}},
+			Variants:        []*model.ComparisonVariantRequest{{ElementID: 1}, {ElementID: 1}},
+			Repeat:          3,
+		}
+	}
+
+	t.Run("每个变体占用一个名额并发执行", func(t *testing.T) {
+		result, err := s.Compare(context.Background(), 1, req())
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&maxInFlight))
+		for _, v := range result.Variants {
+			require.Len(t, v.Outputs, 3)
+			for i, output := range v.Outputs {
+				assert.Equal(t, i+1, output.Attempt)
+			}
+		}
+		// 名额全部释放
+		assert.Equal(t, 3, s.limiter.acquireUpTo(1, 5))
+		for i := 0; i < 3; i++ {
+			s.limiter.release(1)
+		}
+	})
+
+	t.Run("只使用剩余的名额", func(t *testing.T) {
+		atomic.StoreInt32(&maxInFlight, 0)
+		require.Equal(t, 2, s.limiter.acquireUpTo(1, 2))
+		defer s.limiter.release(1)
+		defer s.limiter.release(1)
+
+		_, err := s.Compare(context.Background(), 1, req())
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&maxInFlight))
+
+		require.True(t, s.limiter.acquire(1))
+		defer s.limiter.release(1)
+		_, err = s.Compare(context.Background(), 1, req())
+		assert.EqualError(t, err, "生成任务过多，请稍后再试")
+	})
+}
+
+func TestGenerationService_CompareErrors(t *testing.T) {
+	s := newTestGenerationService(t, stubComparisonCompletion(t))
+	s.comparisonRepo = new(MockComparisonRepository)
+	variables := map[string]interface{}{"product": "CESE"}
+
+	// 至少需要两个变体
+	_, err := s.Compare(context.Background(), 1, &model.ComparisonCreateRequest{
+		GenerateRequest: model.GenerateRequest{Variables: variables},
+		Variants:        []*model.ComparisonVariantRequest{{ElementID: 1}},
+	})
+	assert.ErrorContains(t, err, "参数验证失败")
+
+	// 变量校验失败时不调用模型服务
+	_, err = s.Compare(context.Background(), 1, &model.ComparisonCreateRequest{
+		Variants: []*model.ComparisonVariantRequest{{ElementID: 1}, {ElementID: 1}},
+	})
+	assert.ErrorContains(t, err, "参数验证失败: 变体 A 的变量 product")
+
+	_, err = s.Compare(context.Background(), 2, &model.ComparisonCreateRequest{
+		GenerateRequest: model.GenerateRequest{Variables: variables},
+		Variants:        []*model.ComparisonVariantRequest{{ElementID: 1}, {ElementID: 1}},
+	})
+	assert.EqualError(t, err, "无权访问该记录")
+}
+
+func TestGenerationService_UpdateComparison(t *testing.T) {
+	s := newTestGenerationService(t, stubComparisonCompletion(t))
+	comparisonRepo := new(MockComparisonRepository)
+	s.comparisonRepo = comparisonRepo
+
+	run := &model.ComparisonRun{
+		ID:     5,
+		UserID: 1,
+		Variants: []*model.ComparisonVariant{
+			{ID: 11, RunID: 5, Position: 1, Label: "A"},
+			{ID: 12, RunID: 5, Position: 2, Label: "B"},
+		},
+	}
+	comparisonRepo.On("GetByID", uint64(5)).Return(run, nil)
+	comparisonRepo.On("GetByID", uint64(6)).Return(nil, nil)
+	comparisonRepo.On("Update", run).Return(nil)
+
+	winner := uint64(12)
+	note := "B 更符合格式要求"
+	result, err := s.UpdateComparison(1, 5, &model.ComparisonUpdateRequest{WinnerVariantID: &winner, Note: &note})
+	require.NoError(t, err)
+	assert.Equal(t, winner, *result.WinnerVariantID)
+	assert.False(t, result.Variants[0].Winner)
+	assert.True(t, result.Variants[1].Winner)
+	assert.Equal(t, note, result.Note)
+
+	// 其他对比运行的变体不能作为胜出变体
+	other := uint64(21)
+	_, err = s.UpdateComparison(1, 5, &model.ComparisonUpdateRequest{WinnerVariantID: &other})
+	assert.EqualError(t, err, "参数验证失败: 变体不属于该对比运行")
+
+	none := uint64(0)
+	result, err = s.UpdateComparison(1, 5, &model.ComparisonUpdateRequest{WinnerVariantID: &none})
+	require.NoError(t, err)
+	assert.Nil(t, result.WinnerVariantID)
+	assert.Equal(t, note, result.Note)
+
+	_, err = s.UpdateComparison(2, 5, &model.ComparisonUpdateRequest{Note: &note})
+	assert.EqualError(t, err, "无权访问该记录")
+
+	_, err = s.GetComparison(1, 6)
+	assert.EqualError(t, err, "对比运行不存在")
+}
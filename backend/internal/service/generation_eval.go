@@ -0,0 +1,412 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"cese-backend/internal/model"
+	"cese-backend/internal/repository"
+	"cese-backend/pkg/eval"
+	"cese-backend/pkg/logger"
+	"cese-backend/pkg/provider"
+	"cese-backend/pkg/validator"
+)
+
+// GetEvalDatasets 获取用户的评估数据集（不含用例）
+func (s *generationService) GetEvalDatasets(userID uint64, req *model.EvalDatasetQueryRequest) ([]*model.EvalDatasetResponse, error) {
+	datasets, err := s.evalRepo.GetDatasets(userID, req)
+	if err != nil {
+		return nil, errors.New("查询评估数据集失败")
+	}
+
+	responses := make([]*model.EvalDatasetResponse, len(datasets))
+	for i, dataset := range datasets {
+		responses[i] = dataset.ToResponse(false)
+	}
+	return responses, nil
+}
+
+// GetEvalDataset 获取评估数据集及其用例
+func (s *generationService) GetEvalDataset(userID, datasetID uint64) (*model.EvalDatasetResponse, error) {
+	dataset, err := s.getOwnedDataset(userID, datasetID)
+	if err != nil {
+		return nil, err
+	}
+	return dataset.ToResponse(true), nil
+}
+
+// CreateEvalDataset 为六要素创建评估数据集
+func (s *generationService) CreateEvalDataset(userID uint64, req *model.EvalDatasetCreateRequest) (*model.EvalDatasetResponse, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if err := validateEvalCases(req, req.Cases); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.elementService.GetByID(userID, req.ElementID); err != nil {
+		return nil, err
+	}
+
+	dataset := &model.EvalDataset{
+		UserID:      userID,
+		ElementID:   req.ElementID,
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	dataset.SetCases(req.Cases)
+	if err := s.evalRepo.CreateDataset(dataset); err != nil {
+		return nil, errors.New("创建评估数据集失败")
+	}
+	return dataset.ToResponse(true), nil
+}
+
+// UpdateEvalDataset 更新评估数据集，整体替换用例
+func (s *generationService) UpdateEvalDataset(userID, datasetID uint64, req *model.EvalDatasetUpdateRequest) (*model.EvalDatasetResponse, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if err := validateEvalCases(req, req.Cases); err != nil {
+		return nil, err
+	}
+
+	dataset, err := s.getOwnedDataset(userID, datasetID)
+	if err != nil {
+		return nil, err
+	}
+
+	dataset.Name = req.Name
+	dataset.Description = req.Description
+	dataset.SetCases(req.Cases)
+	if err := s.evalRepo.UpdateDataset(dataset); err != nil {
+		return nil, errors.New("更新评估数据集失败")
+	}
+	return dataset.ToResponse(true), nil
+}
+
+// DeleteEvalDataset 删除评估数据集及其评估运行
+func (s *generationService) DeleteEvalDataset(userID, datasetID uint64) error {
+	if _, err := s.getOwnedDataset(userID, datasetID); err != nil {
+		return err
+	}
+
+	if err := s.evalRepo.DeleteDataset(datasetID); err != nil {
+		return errors.New("删除评估数据集失败")
+	}
+	return nil
+}
+
+// RunEval 创建评估运行并在后台依次执行数据集的每个用例，立即返回状态为 running 的评估运行
+//
+// 客户端通过 GetEvalRun 查询进度和结果。生成失败、变量校验失败或评审模型出错的用例记为 error。
+// 执行期间占用一个生成并发名额；服务关闭时停止执行剩余的用例，运行状态为 canceled。
+func (s *generationService) RunEval(userID, datasetID uint64, req *model.EvalRunCreateRequest) (*model.EvalRunResponse, error) {
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("参数验证失败: %v", describeValidationError(err))
+	}
+
+	dataset, err := s.getOwnedDataset(userID, datasetID)
+	if err != nil {
+		return nil, err
+	}
+	if len(dataset.Cases) == 0 {
+		return nil, errors.New("参数验证失败: 评估数据集没有用例")
+	}
+
+	run := &model.EvalRun{
+		UserID:    userID,
+		DatasetID: dataset.ID,
+		ElementID: dataset.ElementID,
+		Status:    model.EvalRunStatusRunning,
+		Total:     len(dataset.Cases),
+	}
+
+	calls := make([]*generationCall, len(dataset.Cases))
+	for i, c := range dataset.Cases {
+		caseReq := req.GenerateRequest
+		caseReq.Variables = mergeVariables(req.Variables, c.VariableValues())
+		if calls[i], err = s.prepare(userID, dataset.ElementID, &caseReq, nil); err != nil {
+			return nil, err
+		}
+	}
+	first := calls[0]
+	run.ElementVersion = first.response.ElementVersion
+	run.Provider = first.response.Provider
+	run.Model = first.request.Model
+	run.Format = first.response.Format
+	config, err := json.Marshal(req.SamplingConfig(first.request.MaxTokens))
+	if err != nil {
+		return nil, fmt.Errorf("参数验证失败: 采样参数无效: %v", err)
+	}
+	run.Config = string(config)
+
+	var judge eval.Judge
+	if datasetUsesJudge(dataset) {
+		if judge, err = s.newJudge(req, run); err != nil {
+			return nil, err
+		}
+	}
+
+	if !s.limiter.acquire(userID) {
+		return nil, errors.New("生成任务过多，请稍后再试")
+	}
+	if err := s.evalRepo.CreateRun(run); err != nil {
+		s.limiter.release(userID)
+		return nil, errors.New("保存评估运行失败")
+	}
+
+	response := run.ToResponse(true)
+	s.jobs.Add(1)
+	go func() {
+		defer s.jobs.Done()
+		defer s.limiter.release(userID)
+		s.executeEvalRun(s.jobCtx, run, dataset.Cases, calls, judge)
+	}()
+	return response, nil
+}
+
+// executeEvalRun 依次执行评估用例并逐个保存结果，ctx 取消时停止执行剩余的用例
+func (s *generationService) executeEvalRun(ctx context.Context, run *model.EvalRun, cases []*model.EvalCase, calls []*generationCall, judge eval.Judge) {
+	start := time.Now()
+	run.Status = model.EvalRunStatusCompleted
+	for i, c := range cases {
+		if ctx.Err() != nil {
+			run.Status = model.EvalRunStatusCanceled
+			break
+		}
+		result := s.runEvalCase(ctx, c, calls[i], judge)
+		if ctx.Err() != nil {
+			// 执行中途被取消的用例不计入结果
+			run.Status = model.EvalRunStatusCanceled
+			break
+		}
+		run.AddResult(result)
+		if err := s.evalRepo.AddRunResult(run, result); err != nil {
+			if !errors.Is(err, repository.ErrEvalRunDeleted) {
+				logger.GetLogger().Errorf("保存评估结果失败: run=%d: %v", run.ID, err)
+			}
+			return
+		}
+	}
+	run.DurationMs = time.Since(start).Milliseconds()
+
+	if err := s.evalRepo.FinishRun(run); err != nil {
+		logger.GetLogger().Errorf("保存评估运行状态失败: run=%d: %v", run.ID, err)
+	}
+}
+
+// CancelInterruptedEvalRuns 将服务上次退出时仍在执行的评估运行标记为已取消
+func (s *generationService) CancelInterruptedEvalRuns() (int64, error) {
+	canceled, err := s.evalRepo.CancelRunningRuns()
+	if err != nil {
+		return 0, errors.New("更新评估运行失败")
+	}
+	return canceled, nil
+}
+
+// runEvalCase 执行一个用例并评估输出
+func (s *generationService) runEvalCase(ctx context.Context, c *model.EvalCase, call *generationCall, judge eval.Judge) *model.EvalResult {
+	result := &model.EvalResult{
+		CaseID:   c.ID,
+		CaseName: c.Name,
+		Prompt:   call.response.Prompt,
+		Status:   eval.StatusError,
+	}
+	if errs := call.response.Errors; len(errs) > 0 {
+		messages := make([]string, len(errs))
+		for i, e := range errs {
+			messages[i] = e.Name + " " + e.Message
+		}
+		result.Error = "变量校验失败: " + strings.Join(messages, "；")
+		return result
+	}
+
+	response, err := s.execute(ctx, call)
+	result.Content = response.Content
+	result.PromptTokens = response.Usage.PromptTokens
+	result.CompletionTokens = response.Usage.CompletionTokens
+	result.TotalTokens = response.Usage.TotalTokens
+	result.DurationMs = response.DurationMs
+	if err != nil {
+		result.Error = describeProviderError(err).Error()
+		return result
+	}
+
+	outcome := eval.Evaluate(ctx, c.AssertionList(), response.Content, judge)
+	result.Status = outcome.Status
+	result.SetChecks(outcome.Checks)
+	return result
+}
+
+// newJudge 创建 llm_judge 断言使用的评审函数，未指定评审模型时使用生成的模型
+func (s *generationService) newJudge(req *model.EvalRunCreateRequest, run *model.EvalRun) (eval.Judge, error) {
+	providerName, modelName := req.JudgeProvider, req.JudgeModel
+	if providerName == "" && modelName == "" {
+		providerName, modelName = run.Provider, run.Model
+	} else if providerName == "" {
+		providerName = run.Provider
+	}
+
+	providerConfig, modelName, err := s.resolveProvider(providerName, modelName)
+	if err != nil {
+		return nil, err
+	}
+	judgeProvider, err := newProvider(providerConfig)
+	if err != nil {
+		return nil, err
+	}
+	run.JudgeProvider, run.JudgeModel = providerConfig.Name, modelName
+
+	temperature := 0.0
+	return func(ctx context.Context, rubric, output string) (*eval.Verdict, error) {
+		ctx, cancel := context.WithTimeout(ctx, s.config.LLM.GetTimeout())
+		defer cancel()
+
+		result, err := judgeProvider.Generate(ctx, &provider.Request{
+			Model:       modelName,
+			Messages:    []provider.Message{{Role: provider.RoleUser, Content: eval.JudgePrompt(rubric, output)}},
+			Temperature: &temperature,
+			MaxTokens:   s.config.LLM.DefaultMaxTokens,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("评审失败: %v", describeProviderError(err))
+		}
+		return eval.ParseVerdict(result.Content)
+	}, nil
+}
+
+// GetEvalRuns 分页查询评估运行，按创建时间倒序，只返回统计不返回用例结果
+func (s *generationService) GetEvalRuns(userID uint64, req *model.EvalRunQueryRequest) ([]*model.EvalRunResponse, int64, error) {
+	s.setDefaultEvalRunQueryParams(req)
+
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, 0, errors.New("参数验证失败")
+	}
+
+	runs, total, err := s.evalRepo.GetRuns(userID, req)
+	if err != nil {
+		return nil, 0, errors.New("查询评估运行失败")
+	}
+
+	responses := make([]*model.EvalRunResponse, len(runs))
+	for i, run := range runs {
+		responses[i] = run.ToResponse(false)
+	}
+	return responses, total, nil
+}
+
+// GetEvalRun 获取评估运行及每个用例的结果
+func (s *generationService) GetEvalRun(userID, runID uint64) (*model.EvalRunResponse, error) {
+	run, err := s.getOwnedEvalRun(userID, runID)
+	if err != nil {
+		return nil, err
+	}
+	return run.ToResponse(true), nil
+}
+
+// DeleteEvalRun 删除评估运行
+func (s *generationService) DeleteEvalRun(userID, runID uint64) error {
+	if _, err := s.getOwnedEvalRun(userID, runID); err != nil {
+		return err
+	}
+
+	if err := s.evalRepo.DeleteRun(runID); err != nil {
+		return errors.New("删除评估运行失败")
+	}
+	return nil
+}
+
+// getOwnedDataset 获取当前用户的评估数据集
+func (s *generationService) getOwnedDataset(userID, datasetID uint64) (*model.EvalDataset, error) {
+	dataset, err := s.evalRepo.GetDatasetByID(datasetID)
+	if err != nil {
+		return nil, errors.New("查询评估数据集失败")
+	}
+	if dataset == nil {
+		return nil, errors.New("评估数据集不存在")
+	}
+
+	// 检查权限：只能访问自己的评估数据集
+	if dataset.UserID != userID {
+		return nil, errors.New("无权访问该记录")
+	}
+
+	return dataset, nil
+}
+
+// getOwnedEvalRun 获取当前用户的评估运行
+func (s *generationService) getOwnedEvalRun(userID, runID uint64) (*model.EvalRun, error) {
+	run, err := s.evalRepo.GetRunByID(runID)
+	if err != nil {
+		return nil, errors.New("查询评估运行失败")
+	}
+	if run == nil {
+		return nil, errors.New("评估运行不存在")
+	}
+
+	// 检查权限：只能访问自己的评估运行
+	if run.UserID != userID {
+		return nil, errors.New("无权访问该记录")
+	}
+
+	return run, nil
+}
+
+// setDefaultEvalRunQueryParams 设置默认查询参数
+func (s *generationService) setDefaultEvalRunQueryParams(req *model.EvalRunQueryRequest) {
+	if req.Page <= 0 {
+		req.Page = s.config.Pagination.DefaultPage
+	}
+	if req.Size <= 0 {
+		req.Size = s.config.Pagination.DefaultSize
+	}
+	if req.Size > s.config.Pagination.MaxSize {
+		req.Size = s.config.Pagination.MaxSize
+	}
+}
+
+// validateEvalCases 校验评估数据集请求和每个用例的断言
+func validateEvalCases(req interface{}, cases []*model.EvalCaseRequest) error {
+	if err := validator.ValidateStruct(req); err != nil {
+		return fmt.Errorf("参数验证失败: %v", describeValidationError(err))
+	}
+
+	for i, c := range cases {
+		c.Name = strings.TrimSpace(c.Name)
+		if c.Name == "" {
+			c.Name = fmt.Sprintf("用例%d", i+1)
+		}
+		for _, a := range c.Assertions {
+			if err := a.Validate(); err != nil {
+				return fmt.Errorf("参数验证失败: %s: %v", c.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// datasetUsesJudge 判断数据集是否包含 llm_judge 断言
+func datasetUsesJudge(dataset *model.EvalDataset) bool {
+	for _, c := range dataset.Cases {
+		for _, a := range c.AssertionList() {
+			if a.Type == eval.TypeLLMJudge {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mergeVariables 合并共用变量和用例变量，用例变量优先
+func mergeVariables(shared, values map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(shared)+len(values))
+	for name, value := range shared {
+		merged[name] = value
+	}
+	for name, value := range values {
+		merged[name] = value
+	}
+	return merged
+}
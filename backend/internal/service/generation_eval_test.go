@@ -0,0 +1,347 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"cese-backend/internal/model"
+	"cese-backend/internal/repository"
+	"cese-backend/pkg/eval"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockEvalRepository 评估Repository模拟
+type MockEvalRepository struct {
+	mock.Mock
+}
+
+func (m *MockEvalRepository) CreateDataset(dataset *model.EvalDataset) error {
+	args := m.Called(dataset)
+	return args.Error(0)
+}
+
+func (m *MockEvalRepository) GetDatasetByID(id uint64) (*model.EvalDataset, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.EvalDataset), args.Error(1)
+}
+
+func (m *MockEvalRepository) GetDatasets(userID uint64, req *model.EvalDatasetQueryRequest) ([]*model.EvalDataset, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.EvalDataset), args.Error(1)
+}
+
+func (m *MockEvalRepository) UpdateDataset(dataset *model.EvalDataset) error {
+	args := m.Called(dataset)
+	return args.Error(0)
+}
+
+func (m *MockEvalRepository) DeleteDataset(id uint64) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockEvalRepository) CreateRun(run *model.EvalRun) error {
+	args := m.Called(run)
+	return args.Error(0)
+}
+
+func (m *MockEvalRepository) AddRunResult(run *model.EvalRun, result *model.EvalResult) error {
+	args := m.Called(run, result)
+	return args.Error(0)
+}
+
+func (m *MockEvalRepository) FinishRun(run *model.EvalRun) error {
+	args := m.Called(run)
+	return args.Error(0)
+}
+
+func (m *MockEvalRepository) CancelRunningRuns() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockEvalRepository) GetRunByID(id uint64) (*model.EvalRun, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.EvalRun), args.Error(1)
+}
+
+func (m *MockEvalRepository) GetRuns(userID uint64, req *model.EvalRunQueryRequest) ([]*model.EvalRun, int64, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*model.EvalRun), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockEvalRepository) DeleteRun(id uint64) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// stubEvalCompletion 本地模拟的模型服务：生成时按提示词中的产品名回复，评审时输出包含“您好”才判为通过
+func stubEvalCompletion(t *testing.T, judgeRequests *[]map[string]interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		prompt := body["messages"].([]interface{})[0].(map[string]interface{})["content"].(string)
+
+		content := "CESE 周报：本周完成了三项工作"
+		switch {
+		case strings.Contains(prompt, "## 评分标准"):
+			*judgeRequests = append(*judgeRequests, body)
+			content = `{"pass": false, "reason": "没有使用敬语"}`
+			if output := prompt[strings.Index(prompt, "## 待评审的输出"):]; strings.Contains(output, "您好") {
+				content = `{"pass": true, "reason": "语气礼貌"}`
+			}
+		case strings.Contains(prompt, "为Beta整理"):
+			content = "Beta 周报：暂无进展"
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"model":   "stub-model",
+			"choices": []interface{}{map[string]interface{}{"message": map[string]string{"role": "assistant", "content": content}, "finish_reason": "stop"}},
+			"usage":   map[string]int{"prompt_tokens": 20, "completion_tokens": 10, "total_tokens": 30},
+		})
+	}
+}
+
+// newTestEvalDataset 创建测试用的评估数据集
+func newTestEvalDataset(t *testing.T, cases ...*model.EvalCaseRequest) *model.EvalDataset {
+	dataset := &model.EvalDataset{ID: 7, UserID: 1, ElementID: 1, Name: "周报回归"}
+	require.NoError(t, validateEvalCases(&model.EvalDatasetUpdateRequest{Name: dataset.Name, Cases: cases}, cases))
+	dataset.SetCases(cases)
+	for i, c := range dataset.Cases {
+		c.ID = uint64(i + 1)
+	}
+	return dataset
+}
+
+func TestGenerationService_RunEval(t *testing.T) {
+	var judgeRequests []map[string]interface{}
+	s := newTestGenerationService(t, stubEvalCompletion(t, &judgeRequests))
+	evalRepo := new(MockEvalRepository)
+	s.evalRepo = evalRepo
+
+	dataset := newTestEvalDataset(t,
+		&model.EvalCaseRequest{
+			Assertions: []*eval.Assertion{
+				{Type: eval.TypeContains, Value: "CESE"},
+				{Type: eval.TypeMaxLength, Max: 50},
+			},
+		},
+		&model.EvalCaseRequest{
+			Name:      "其他产品",
+			Variables: map[string]interface{}{"product": "Beta"},
+			Assertions: []*eval.Assertion{
+				{Type: eval.TypeNotContains, Value: "暂无进展"},
+				{Type: eval.TypeRegex, Value: `^Beta`},
+			},
+		},
+		&model.EvalCaseRequest{
+			Name:       "礼貌",
+			Assertions: []*eval.Assertion{{Type: eval.TypeLLMJudge, Value: "开头使用“您好”"}},
+		},
+		&model.EvalCaseRequest{
+			Name:       "结构化",
+			Assertions: []*eval.Assertion{{Type: eval.TypeJSONSchema}},
+		},
+	)
+	evalRepo.On("GetDatasetByID", uint64(7)).Return(dataset, nil)
+	run := expectEvalRun(evalRepo)
+
+	started, err := s.RunEval(1, 7, &model.EvalRunCreateRequest{
+		GenerateRequest: model.GenerateRequest{Variables: map[string]interface{}{"product": "CESE"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(12), started.ID)
+	assert.Equal(t, model.EvalRunStatusRunning, started.Status)
+	assert.Empty(t, started.Results)
+
+	s.jobs.Wait()
+	evalRepo.AssertNumberOfCalls(t, "CreateRun", 1)
+	evalRepo.AssertNumberOfCalls(t, "AddRunResult", 4)
+	evalRepo.AssertNumberOfCalls(t, "FinishRun", 1)
+	result := (*run).ToResponse(true)
+
+	assert.Equal(t, model.EvalRunStatusCompleted, result.Status)
+	assert.Equal(t, uint64(3), result.ElementVersion)
+	assert.Equal(t, "stub", result.JudgeProvider)
+	assert.Equal(t, "stub-model", result.JudgeModel)
+	assert.Equal(t, 4, result.Total)
+	assert.Equal(t, 1, result.Passed)
+	assert.Equal(t, 3, result.Failed)
+	assert.Equal(t, 0.25, result.PassRate)
+	assert.Equal(t, 120, result.TotalTokens)
+
+	require.Len(t, result.Results, 4)
+	assert.Equal(t, "用例1", result.Results[0].CaseName)
+	assert.Equal(t, eval.StatusPassed, result.Results[0].Status)
+	assert.Contains(t, result.Results[0].Prompt, "为CESE整理")
+
+	// 用例中的变量优先于共用变量
+	beta := result.Results[1]
+	assert.Contains(t, beta.Prompt, "为Beta整理")
+	assert.Equal(t, eval.StatusFailed, beta.Status)
+	assert.False(t, beta.Checks[0].Passed)
+	assert.True(t, beta.Checks[1].Passed)
+
+	// 评审模型使用评分标准判断，温度为0
+	judged := result.Results[2]
+	assert.Equal(t, eval.StatusFailed, judged.Status)
+	assert.Equal(t, "没有使用敬语", judged.Checks[0].Message)
+	require.Len(t, judgeRequests, 1)
+	assert.Equal(t, float64(0), judgeRequests[0]["temperature"])
+
+	assert.Equal(t, "输出不是合法的JSON", result.Results[3].Checks[0].Message)
+}
+
+func TestGenerationService_RunEvalErrors(t *testing.T) {
+	var judgeRequests []map[string]interface{}
+	s := newTestGenerationService(t, stubEvalCompletion(t, &judgeRequests))
+	evalRepo := new(MockEvalRepository)
+	s.evalRepo = evalRepo
+
+	dataset := newTestEvalDataset(t,
+		&model.EvalCaseRequest{
+			Variables:  map[string]interface{}{"product": "CESE"},
+			Assertions: []*eval.Assertion{{Type: eval.TypeContains, Value: "CESE"}},
+		},
+		&model.EvalCaseRequest{Assertions: []*eval.Assertion{{Type: eval.TypeContains, Value: "CESE"}}},
+	)
+	evalRepo.On("GetDatasetByID", uint64(7)).Return(dataset, nil)
+	evalRepo.On("GetDatasetByID", uint64(8)).Return(nil, nil)
+	run := expectEvalRun(evalRepo)
+
+	// 缺少变量的用例记为无法评估，不影响其他用例
+	_, err := s.RunEval(1, 7, &model.EvalRunCreateRequest{})
+	require.NoError(t, err)
+	s.jobs.Wait()
+	result := (*run).ToResponse(true)
+	assert.Equal(t, 1, result.Passed)
+	assert.Equal(t, 1, result.Errored)
+	assert.Equal(t, eval.StatusError, result.Results[1].Status)
+	assert.Contains(t, result.Results[1].Error, "变量校验失败: product")
+	assert.Empty(t, judgeRequests)
+
+	_, err = s.RunEval(2, 7, &model.EvalRunCreateRequest{})
+	assert.EqualError(t, err, "无权访问该记录")
+
+	_, err = s.RunEval(1, 8, &model.EvalRunCreateRequest{})
+	assert.EqualError(t, err, "评估数据集不存在")
+
+	_, err = s.RunEval(1, 7, &model.EvalRunCreateRequest{JudgeProvider: "disabled"})
+	require.NoError(t, err) // 没有 llm_judge 断言时不需要评审模型
+	s.jobs.Wait()
+}
+
+func TestGenerationService_RunEvalInBackground(t *testing.T) {
+	dataset := newTestEvalDataset(t,
+		&model.EvalCaseRequest{
+			Variables:  map[string]interface{}{"product": "CESE"},
+			Assertions: []*eval.Assertion{{Type: eval.TypeContains, Value: "CESE"}},
+		},
+		&model.EvalCaseRequest{
+			Variables:  map[string]interface{}{"product": "CESE"},
+			Assertions: []*eval.Assertion{{Type: eval.TypeContains, Value: "CESE"}},
+		},
+	)
+
+	t.Run("服务关闭时停止执行剩余的用例", func(t *testing.T) {
+		release := make(chan struct{})
+		s := newTestGenerationService(t, func(w http.ResponseWriter, r *http.Request) {
+			<-release
+			stubEvalCompletion(t, new([]map[string]interface{}))(w, r)
+		})
+		evalRepo := new(MockEvalRepository)
+		s.evalRepo = evalRepo
+		evalRepo.On("GetDatasetByID", uint64(7)).Return(dataset, nil)
+		run := expectEvalRun(evalRepo)
+
+		_, err := s.RunEval(1, 7, &model.EvalRunCreateRequest{})
+		require.NoError(t, err)
+
+		// 后台执行期间占用并发名额
+		_, err = s.RunEval(1, 7, &model.EvalRunCreateRequest{})
+		assert.EqualError(t, err, "生成任务过多，请稍后再试")
+
+		s.Close()
+		close(release)
+		assert.Equal(t, model.EvalRunStatusCanceled, (*run).Status)
+		evalRepo.AssertNotCalled(t, "AddRunResult", mock.Anything, mock.Anything)
+		evalRepo.AssertNumberOfCalls(t, "FinishRun", 1)
+		assert.True(t, s.limiter.acquire(1))
+	})
+
+	t.Run("评估运行被删除后停止执行", func(t *testing.T) {
+		s := newTestGenerationService(t, stubEvalCompletion(t, new([]map[string]interface{})))
+		evalRepo := new(MockEvalRepository)
+		s.evalRepo = evalRepo
+		evalRepo.On("GetDatasetByID", uint64(7)).Return(dataset, nil)
+		evalRepo.On("CreateRun", mock.Anything).Return(nil)
+		evalRepo.On("AddRunResult", mock.Anything, mock.Anything).Return(repository.ErrEvalRunDeleted)
+
+		_, err := s.RunEval(1, 7, &model.EvalRunCreateRequest{})
+		require.NoError(t, err)
+		s.jobs.Wait()
+		evalRepo.AssertNumberOfCalls(t, "AddRunResult", 1)
+		evalRepo.AssertNotCalled(t, "FinishRun", mock.Anything)
+	})
+}
+
+// expectEvalRun 模拟保存评估运行，返回指向后台执行的评估运行的指针
+func expectEvalRun(evalRepo *MockEvalRepository) **model.EvalRun {
+	run := new(*model.EvalRun)
+	evalRepo.On("CreateRun", mock.Anything).Run(func(args mock.Arguments) {
+		*run = args.Get(0).(*model.EvalRun)
+		(*run).ID = 12
+	}).Return(nil)
+	evalRepo.On("AddRunResult", mock.Anything, mock.Anything).Return(nil)
+	evalRepo.On("FinishRun", mock.Anything).Return(nil)
+	return run
+}
+
+func TestGenerationService_CreateEvalDataset(t *testing.T) {
+	s := newTestGenerationService(t, stubEvalCompletion(t, new([]map[string]interface{})))
+	evalRepo := new(MockEvalRepository)
+	evalRepo.On("CreateDataset", mock.Anything).Return(nil)
+	s.evalRepo = evalRepo
+
+	result, err := s.CreateEvalDataset(1, &model.EvalDatasetCreateRequest{
+		ElementID: 1,
+		Name:      " 周报回归 ",
+		Cases: []*model.EvalCaseRequest{{
+			Variables:  map[string]interface{}{"product": "CESE"},
+			Assertions: []*eval.Assertion{{Type: eval.TypeJSONSchema, Schema: json.RawMessage(`{"type": "object"}`)}},
+		}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "周报回归", result.Name)
+	assert.Equal(t, 1, result.CaseCount)
+	assert.Equal(t, "用例1", result.Cases[0].Name)
+	assert.Equal(t, "CESE", result.Cases[0].Variables["product"])
+	assert.JSONEq(t, `{"type": "object"}`, string(result.Cases[0].Assertions[0].Schema))
+
+	_, err = s.CreateEvalDataset(1, &model.EvalDatasetCreateRequest{
+		ElementID: 1,
+		Name:      "周报回归",
+		Cases:     []*model.EvalCaseRequest{{Assertions: []*eval.Assertion{{Type: eval.TypeRegex, Value: "("}}}},
+	})
+	assert.EqualError(t, err, "参数验证失败: 用例1: 无效的正则表达式: (")
+
+	_, err = s.CreateEvalDataset(2, &model.EvalDatasetCreateRequest{ElementID: 1, Name: "周报回归"})
+	assert.EqualError(t, err, "无权访问该记录")
+	evalRepo.AssertNumberOfCalls(t, "CreateDataset", 1)
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"errors"
+
+	"cese-backend/internal/model"
+	"cese-backend/pkg/validator"
+)
+
+// record 保存生成记录（包括失败和取消的生成），保存失败不影响本次生成的结果
+func (s *generationService) record(userID uint64, req *model.GenerateRequest, call *generationCall, err error) {
+	record := model.NewGenerationRecord(userID, req, call.response, call.request.MaxTokens)
+	record.Status, record.Error = generationStatus(err)
+
+	if err := s.recordRepo.Create(record); err == nil {
+		call.response.RecordID = record.ID
+	}
+}
+
+// GetRecords 分页查询生成记录，默认按生成时间倒序
+func (s *generationService) GetRecords(userID uint64, req *model.GenerationRecordQueryRequest) ([]*model.GenerationRecordResponse, int64, error) {
+	s.setDefaultRecordQueryParams(req)
+
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, 0, errors.New("参数验证失败")
+	}
+
+	records, total, err := s.recordRepo.GetList(userID, req)
+	if err != nil {
+		return nil, 0, errors.New("查询生成记录失败")
+	}
+
+	responses := make([]*model.GenerationRecordResponse, len(records))
+	for i, record := range records {
+		responses[i] = record.ToResponse()
+	}
+
+	return responses, total, nil
+}
+
+// GetRecord 获取生成记录详情
+func (s *generationService) GetRecord(userID, recordID uint64) (*model.GenerationRecordResponse, error) {
+	record, err := s.getOwnedRecord(userID, recordID)
+	if err != nil {
+		return nil, err
+	}
+	return record.ToResponse(), nil
+}
+
+// UpdateRecord 收藏或取消收藏生成记录，修改备注
+func (s *generationService) UpdateRecord(userID, recordID uint64, req *model.GenerationRecordUpdateRequest) (*model.GenerationRecordResponse, error) {
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, errors.New("参数验证失败")
+	}
+
+	record, err := s.getOwnedRecord(userID, recordID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Starred != nil {
+		record.Starred = *req.Starred
+	}
+	if req.Note != nil {
+		record.Note = *req.Note
+	}
+	if err := s.recordRepo.Update(record); err != nil {
+		return nil, errors.New("更新生成记录失败")
+	}
+
+	return record.ToResponse(), nil
+}
+
+// DeleteRecord 删除生成记录
+func (s *generationService) DeleteRecord(userID, recordID uint64) error {
+	if _, err := s.getOwnedRecord(userID, recordID); err != nil {
+		return err
+	}
+
+	if err := s.recordRepo.Delete(recordID); err != nil {
+		return errors.New("删除生成记录失败")
+	}
+	return nil
+}
+
+// getOwnedRecord 获取当前用户的生成记录
+func (s *generationService) getOwnedRecord(userID, recordID uint64) (*model.GenerationRecord, error) {
+	record, err := s.recordRepo.GetByID(recordID)
+	if err != nil {
+		return nil, errors.New("查询生成记录失败")
+	}
+	if record == nil {
+		return nil, errors.New("生成记录不存在")
+	}
+
+	// 检查权限：只能访问自己的记录
+	if record.UserID != userID {
+		return nil, errors.New("无权访问该记录")
+	}
+
+	return record, nil
+}
+
+// setDefaultRecordQueryParams 设置默认查询参数
+func (s *generationService) setDefaultRecordQueryParams(req *model.GenerationRecordQueryRequest) {
+	if req.Page <= 0 {
+		req.Page = s.config.Pagination.DefaultPage
+	}
+	if req.Size <= 0 {
+		req.Size = s.config.Pagination.DefaultSize
+	}
+	if req.Size > s.config.Pagination.MaxSize {
+		req.Size = s.config.Pagination.MaxSize
+	}
+	if req.SortBy == "" {
+		req.SortBy = "created_at"
+		req.SortDesc = true // 默认按生成时间倒序
+	}
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"cese-backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockGenerationRecordRepository 生成记录Repository模拟
+type MockGenerationRecordRepository struct {
+	mock.Mock
+}
+
+func (m *MockGenerationRecordRepository) Create(record *model.GenerationRecord) error {
+	args := m.Called(record)
+	return args.Error(0)
+}
+
+func (m *MockGenerationRecordRepository) GetByID(id uint64) (*model.GenerationRecord, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.GenerationRecord), args.Error(1)
+}
+
+func (m *MockGenerationRecordRepository) GetList(userID uint64, req *model.GenerationRecordQueryRequest) ([]*model.GenerationRecord, int64, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*model.GenerationRecord), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockGenerationRecordRepository) Update(record *model.GenerationRecord) error {
+	args := m.Called(record)
+	return args.Error(0)
+}
+
+func (m *MockGenerationRecordRepository) Delete(id uint64) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// createdRecords 返回已保存的生成记录
+func createdRecords(s *generationService) []*model.GenerationRecord {
+	var records []*model.GenerationRecord
+	for _, call := range s.recordRepo.(*MockGenerationRecordRepository).Calls {
+		if call.Method == "Create" {
+			records = append(records, call.Arguments.Get(0).(*model.GenerationRecord))
+		}
+	}
+	return records
+}
+
+func TestGenerationService_RecordsResult(t *testing.T) {
+	var received map[string]interface{}
+	s := newTestGenerationService(t, stubCompletion(t, &received))
+
+	temperature := 0.3
+	result, err := s.Generate(context.Background(), 1, 1, &model.GenerateRequest{
+		Temperature: &temperature,
+		Format:      "xml",
+		Variables:   map[string]interface{}{"product": "CESE"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), result.RecordID)
+
+	records := createdRecords(s)
+	require.Len(t, records, 1)
+	record := records[0].ToResponse()
+	assert.Equal(t, uint64(1), records[0].UserID)
+	assert.Equal(t, uint64(3), record.ElementVersion)
+	assert.Equal(t, "xml", record.Format)
+	assert.Equal(t, result.Prompt, record.Prompt)
+	assert.Equal(t, "本周完成了三项工作", record.Content)
+	assert.Equal(t, model.GenerationStatusCompleted, record.Status)
+	assert.Equal(t, map[string]interface{}{"product": "CESE"}, record.Variables)
+	assert.Equal(t, &model.GenerationConfig{Temperature: &temperature, MaxTokens: 200}, record.Config)
+	assert.Equal(t, 28, record.Usage.TotalTokens)
+
+	// 变量校验失败时不保存
+	_, err = s.Generate(context.Background(), 1, 1, &model.GenerateRequest{})
+	require.NoError(t, err)
+	assert.Len(t, createdRecords(s), 1)
+}
+
+func TestGenerationService_RecordsFailure(t *testing.T) {
+	variables := map[string]interface{}{"product": "CESE"}
+
+	s := newTestGenerationService(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"error": {"message": "Rate limit reached"}}`))
+	})
+	_, err := s.Generate(context.Background(), 1, 1, &model.GenerateRequest{Variables: variables})
+	require.Error(t, err)
+	records := createdRecords(s)
+	require.Len(t, records, 1)
+	assert.Equal(t, model.GenerationStatusFailed, records[0].Status)
+	assert.Equal(t, "调用模型服务失败: Rate limit reached", records[0].Error)
+
+	// 客户端取消时保存已生成的部分内容
+	release := make(chan struct{})
+	defer close(release)
+	s = newTestGenerationService(t, stubStreamCompletion([]string{
+		`{"model":"stub-model","choices":[{"delta":{"content":"本周"}}]}`,
+	}, release))
+	result, err := s.GenerateStream(context.Background(), 1, 1, &model.GenerateRequest{Variables: variables}, func(delta string) error {
+		return context.Canceled
+	})
+	require.Error(t, err)
+	records = createdRecords(s)
+	require.Len(t, records, 1)
+	assert.Equal(t, model.GenerationStatusCanceled, records[0].Status)
+	assert.Equal(t, "本周", records[0].Content)
+	assert.Equal(t, uint64(1), result.RecordID)
+}
+
+func TestGenerationService_ManageRecords(t *testing.T) {
+	s := newTestGenerationService(t, func(w http.ResponseWriter, r *http.Request) {})
+	recordRepo := s.recordRepo.(*MockGenerationRecordRepository)
+
+	record := &model.GenerationRecord{ID: 7, UserID: 1, ElementID: 1, Content: "本周完成了三项工作", Status: model.GenerationStatusCompleted}
+	recordRepo.On("GetByID", uint64(7)).Return(record, nil)
+	recordRepo.On("GetByID", uint64(8)).Return(nil, nil)
+
+	t.Run("默认按生成时间倒序分页", func(t *testing.T) {
+		req := &model.GenerationRecordQueryRequest{ElementID: 1, StartDate: "2024-10-01", EndDate: "2024-10-07"}
+		recordRepo.On("GetList", uint64(1), req).Return([]*model.GenerationRecord{record}, int64(1), nil).Once()
+
+		records, total, err := s.GetRecords(1, req)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		require.Len(t, records, 1)
+		assert.Equal(t, uint64(7), records[0].ID)
+		assert.Equal(t, 15, req.Size)
+		assert.Equal(t, "created_at", req.SortBy)
+		assert.True(t, req.SortDesc)
+
+		since, before := req.DateRange()
+		assert.Equal(t, "2024-10-01", since.Format("2006-01-02"))
+		assert.Equal(t, "2024-10-08", before.Format("2006-01-02"))
+
+		_, _, err = s.GetRecords(1, &model.GenerationRecordQueryRequest{StartDate: "10/01/2024"})
+		assert.EqualError(t, err, "参数验证失败")
+	})
+
+	t.Run("只能访问自己的记录", func(t *testing.T) {
+		_, err := s.GetRecord(2, 7)
+		assert.EqualError(t, err, "无权访问该记录")
+		_, err = s.GetRecord(1, 8)
+		assert.EqualError(t, err, "生成记录不存在")
+		assert.EqualError(t, s.DeleteRecord(2, 7), "无权访问该记录")
+	})
+
+	t.Run("收藏和备注", func(t *testing.T) {
+		recordRepo.On("Update", record).Return(nil).Once()
+
+		starred := true
+		note := "比上周的版本更简洁"
+		resp, err := s.UpdateRecord(1, 7, &model.GenerationRecordUpdateRequest{Starred: &starred, Note: &note})
+		require.NoError(t, err)
+		assert.True(t, resp.Starred)
+		assert.Equal(t, note, resp.Note)
+	})
+
+	t.Run("删除", func(t *testing.T) {
+		recordRepo.On("Delete", uint64(7)).Return(nil).Once()
+		require.NoError(t, s.DeleteRecord(1, 7))
+		recordRepo.AssertCalled(t, "Delete", uint64(7))
+	})
+}
@@ -0,0 +1,343 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"cese-backend/internal/config"
+	"cese-backend/internal/model"
+	"cese-backend/internal/repository"
+	"cese-backend/pkg/provider"
+	"cese-backend/pkg/validator"
+)
+
+// GenerationService 大模型生成服务接口
+type GenerationService interface {
+	Generate(ctx context.Context, userID, elementID uint64, req *model.GenerateRequest) (*model.GenerateResponse, error)
+	GenerateStream(ctx context.Context, userID, elementID uint64, req *model.GenerateRequest, onDelta provider.DeltaFunc) (*model.GenerateResponse, error)
+	ListProviders() []*model.ProviderResponse
+	GetRecords(userID uint64, req *model.GenerationRecordQueryRequest) ([]*model.GenerationRecordResponse, int64, error)
+	GetRecord(userID, recordID uint64) (*model.GenerationRecordResponse, error)
+	UpdateRecord(userID, recordID uint64, req *model.GenerationRecordUpdateRequest) (*model.GenerationRecordResponse, error)
+	DeleteRecord(userID, recordID uint64) error
+	Compare(ctx context.Context, userID uint64, req *model.ComparisonCreateRequest) (*model.ComparisonResponse, error)
+	GetComparisons(userID uint64, req *model.ComparisonQueryRequest) ([]*model.ComparisonResponse, int64, error)
+	GetComparison(userID, runID uint64) (*model.ComparisonResponse, error)
+	UpdateComparison(userID, runID uint64, req *model.ComparisonUpdateRequest) (*model.ComparisonResponse, error)
+	DeleteComparison(userID, runID uint64) error
+	GetEvalDatasets(userID uint64, req *model.EvalDatasetQueryRequest) ([]*model.EvalDatasetResponse, error)
+	GetEvalDataset(userID, datasetID uint64) (*model.EvalDatasetResponse, error)
+	CreateEvalDataset(userID uint64, req *model.EvalDatasetCreateRequest) (*model.EvalDatasetResponse, error)
+	UpdateEvalDataset(userID, datasetID uint64, req *model.EvalDatasetUpdateRequest) (*model.EvalDatasetResponse, error)
+	DeleteEvalDataset(userID, datasetID uint64) error
+	RunEval(userID, datasetID uint64, req *model.EvalRunCreateRequest) (*model.EvalRunResponse, error)
+	GetEvalRuns(userID uint64, req *model.EvalRunQueryRequest) ([]*model.EvalRunResponse, int64, error)
+	GetEvalRun(userID, runID uint64) (*model.EvalRunResponse, error)
+	DeleteEvalRun(userID, runID uint64) error
+	CancelInterruptedEvalRuns() (int64, error)
+	Close()
+}
+
+// generationService 大模型生成服务实现
+type generationService struct {
+	elementService ContextElementService
+	recordRepo     repository.GenerationRecordRepository
+	comparisonRepo repository.ComparisonRepository
+	evalRepo       repository.EvalRepository
+	limiter        *concurrencyLimiter
+	config         *config.Config
+
+	// 后台任务（评估运行）在 jobCtx 下执行，Close 时取消并等待其保存状态
+	jobCtx     context.Context
+	cancelJobs context.CancelFunc
+	jobs       sync.WaitGroup
+}
+
+// NewGenerationService 创建大模型生成服务实例
+func NewGenerationService(
+	elementService ContextElementService,
+	recordRepo repository.GenerationRecordRepository,
+	comparisonRepo repository.ComparisonRepository,
+	evalRepo repository.EvalRepository,
+	cfg *config.Config,
+) GenerationService {
+	jobCtx, cancelJobs := context.WithCancel(context.Background())
+	return &generationService{
+		elementService: elementService,
+		recordRepo:     recordRepo,
+		comparisonRepo: comparisonRepo,
+		evalRepo:       evalRepo,
+		limiter:        newConcurrencyLimiter(cfg.LLM.MaxConcurrentPerUser),
+		config:         cfg,
+		jobCtx:         jobCtx,
+		cancelJobs:     cancelJobs,
+	}
+}
+
+// Close 取消后台执行的评估运行，并等待其保存已完成的结果和状态
+func (s *generationService) Close() {
+	s.cancelJobs()
+	s.jobs.Wait()
+}
+
+// generationCall 准备好的生成调用
+type generationCall struct {
+	response *model.GenerateResponse
+	provider provider.Provider
+	request  *provider.Request
+}
+
+// Generate 渲染六要素并发送给模型服务，返回生成的内容；变量校验失败时返回逐个变量的错误
+func (s *generationService) Generate(ctx context.Context, userID, elementID uint64, req *model.GenerateRequest) (*model.GenerateResponse, error) {
+	return s.run(ctx, userID, elementID, req, func(ctx context.Context, call *generationCall) (*provider.Response, error) {
+		return call.provider.Generate(ctx, call.request)
+	})
+}
+
+// GenerateStream 以流式方式生成内容，每收到一段新内容调用 onDelta，生成结束后返回完整结果
+//
+// onDelta 返回错误（如客户端已断开时返回 context.Canceled）时取消上游请求。生成中途出错或被取消时，
+// 同时返回已生成的部分结果和错误。变量校验失败时不调用模型服务，返回逐个变量的错误。
+func (s *generationService) GenerateStream(ctx context.Context, userID, elementID uint64, req *model.GenerateRequest, onDelta provider.DeltaFunc) (*model.GenerateResponse, error) {
+	return s.run(ctx, userID, elementID, req, func(ctx context.Context, call *generationCall) (*provider.Response, error) {
+		return call.provider.Stream(ctx, call.request, onDelta)
+	})
+}
+
+// run 准备生成调用并在并发和超时限制下执行
+func (s *generationService) run(
+	ctx context.Context,
+	userID, elementID uint64,
+	req *model.GenerateRequest,
+	invoke func(ctx context.Context, call *generationCall) (*provider.Response, error),
+) (*model.GenerateResponse, error) {
+	call, err := s.prepare(userID, elementID, req, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(call.response.Errors) > 0 {
+		return call.response, nil
+	}
+
+	if !s.limiter.acquire(userID) {
+		return nil, errors.New("生成任务过多，请稍后再试")
+	}
+	defer s.limiter.release(userID)
+
+	ctx, cancel := context.WithTimeout(ctx, s.config.LLM.GetTimeout())
+	defer cancel()
+
+	start := time.Now()
+	result, err := invoke(ctx, call)
+	if result != nil {
+		call.response.Model = result.Model
+		call.response.Content = result.Content
+		call.response.FinishReason = result.FinishReason
+		call.response.Usage = result.Usage
+	}
+	call.response.DurationMs = time.Since(start).Milliseconds()
+	s.record(userID, req, call, err)
+
+	if err != nil {
+		if result == nil {
+			return nil, describeProviderError(err)
+		}
+		return call.response, describeProviderError(err)
+	}
+	return call.response, nil
+}
+
+// ListProviders 获取已启用的模型服务（不含 API Key）
+func (s *generationService) ListProviders() []*model.ProviderResponse {
+	responses := make([]*model.ProviderResponse, 0, len(s.config.LLM.Providers))
+	for _, p := range s.config.LLM.Providers {
+		if !p.Enabled {
+			continue
+		}
+		responses = append(responses, &model.ProviderResponse{
+			Name:    p.Name,
+			Type:    p.Type,
+			Models:  p.Models,
+			Default: p.Name == s.config.LLM.DefaultProvider,
+		})
+	}
+	return responses
+}
+
+// prepare 校验参数、选择模型服务并渲染提示词，overrides 不为空时用其覆盖六要素的字段
+func (s *generationService) prepare(userID, elementID uint64, req *model.GenerateRequest, overrides *model.ContextElementOverrides) (*generationCall, error) {
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("参数验证失败: %v", describeValidationError(err))
+	}
+
+	providerConfig, modelName, err := s.resolveProvider(req.Provider, req.Model)
+	if err != nil {
+		return nil, err
+	}
+
+	maxTokens := req.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = s.config.LLM.DefaultMaxTokens
+	}
+	if limit := s.config.LLM.MaxTokens; limit > 0 && maxTokens > limit {
+		return nil, fmt.Errorf("参数验证失败: max_tokens不能超过%d", limit)
+	}
+
+	rendered, err := s.elementService.RenderWithVariables(userID, elementID, &model.ContextElementRenderVariablesRequest{
+		Format:    req.Format,
+		OmitEmpty: true,
+		Variables: req.Variables,
+		Overrides: overrides,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	call := &generationCall{
+		response: &model.GenerateResponse{
+			ElementID:      elementID,
+			ElementVersion: rendered.ElementVersion,
+			Provider:       providerConfig.Name,
+			Model:          modelName,
+			Format:         rendered.Format,
+			Prompt:         rendered.Content,
+			Errors:         rendered.Errors,
+		},
+		request: req.ToProviderRequest(modelName, rendered.Content, maxTokens),
+	}
+	if len(rendered.Errors) > 0 {
+		return call, nil
+	}
+
+	call.provider, err = newProvider(providerConfig)
+	if err != nil {
+		return nil, err
+	}
+	return call, nil
+}
+
+// newProvider 根据配置创建模型服务客户端
+func newProvider(providerConfig *config.LLMProviderConfig) (provider.Provider, error) {
+	p, err := provider.New(provider.Config{
+		Type:    providerConfig.Type,
+		BaseURL: providerConfig.BaseURL,
+		APIKey:  providerConfig.APIKey,
+	})
+	if err != nil {
+		return nil, errors.New("创建模型服务失败")
+	}
+	return p, nil
+}
+
+// resolveProvider 查找已启用的模型服务，模型为空时使用其第一个模型
+func (s *generationService) resolveProvider(name, modelName string) (*config.LLMProviderConfig, string, error) {
+	providerConfig := s.config.LLM.FindProvider(name)
+	if providerConfig == nil || !providerConfig.Enabled {
+		return nil, "", errors.New("模型服务不存在或未启用")
+	}
+
+	if modelName == "" {
+		if len(providerConfig.Models) == 0 {
+			return nil, "", errors.New("参数验证失败: 请指定模型")
+		}
+		return providerConfig, providerConfig.Models[0], nil
+	}
+	if len(providerConfig.Models) > 0 && !containsString(providerConfig.Models, modelName) {
+		return nil, "", fmt.Errorf("参数验证失败: 模型服务 %s 不支持模型 %s", providerConfig.Name, modelName)
+	}
+	return providerConfig, modelName, nil
+}
+
+// generationStatus 根据模型服务的调用错误确定生成状态和错误信息
+func generationStatus(err error) (status, message string) {
+	switch {
+	case err == nil:
+		return model.GenerationStatusCompleted, ""
+	case errors.Is(err, context.Canceled):
+		return model.GenerationStatusCanceled, describeProviderError(err).Error()
+	default:
+		return model.GenerationStatusFailed, describeProviderError(err).Error()
+	}
+}
+
+// describeProviderError 转换模型服务的调用错误
+func describeProviderError(err error) error {
+	var providerErr *provider.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return errors.New("模型服务响应超时")
+	case errors.Is(err, context.Canceled):
+		return errors.New("生成已取消")
+	case errors.As(err, &providerErr):
+		return fmt.Errorf("调用模型服务失败: %s", providerErr.Message)
+	default:
+		return fmt.Errorf("调用模型服务失败: %v", err)
+	}
+}
+
+// containsString 判断字符串是否在列表中
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
+
+// concurrencyLimiter 限制每个用户同时进行的任务数
+type concurrencyLimiter struct {
+	limit int // 0 表示不限制
+
+	mu      sync.Mutex
+	running map[uint64]int
+}
+
+// newConcurrencyLimiter 创建并发限制器
+func newConcurrencyLimiter(limit int) *concurrencyLimiter {
+	return &concurrencyLimiter{limit: limit, running: make(map[uint64]int)}
+}
+
+// acquire 占用一个名额，已达上限时返回 false
+func (l *concurrencyLimiter) acquire(userID uint64) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.limit > 0 && l.running[userID] >= l.limit {
+		return false
+	}
+	l.running[userID]++
+	return true
+}
+
+// acquireUpTo 占用最多 n 个名额，返回实际占用的数量，一个都没有时返回0
+func (l *concurrencyLimiter) acquireUpTo(userID uint64, n int) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	acquired := n
+	if l.limit > 0 && l.running[userID]+n > l.limit {
+		acquired = l.limit - l.running[userID]
+	}
+	if acquired <= 0 {
+		return 0
+	}
+	l.running[userID] += acquired
+	return acquired
+}
+
+// release 释放名额
+func (l *concurrencyLimiter) release(userID uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.running[userID] <= 1 {
+		delete(l.running, userID)
+		return
+	}
+	l.running[userID]--
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cese-backend/internal/config"
+	"cese-backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestGenerationService 创建连接到本地模拟模型服务的生成服务
+func newTestGenerationService(t *testing.T, handler http.HandlerFunc) *generationService {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	elementRepo := new(MockContextElementRepository)
+	variableRepo := new(MockContextElementVariableRepository)
+	elementService := newTestElementService(elementRepo)
+	elementService.variableRepo = variableRepo
+
+	element := testPatchElement()
+	element.TaskGoal = "为{{product}}整理本周工作"
+	element.Version = 3
+	elementRepo.On("GetByID", uint64(1)).Return(element, nil)
+	variableRepo.On("GetByElementID", uint64(1)).Return([]*model.ContextElementVariable{
+		{ElementID: 1, Name: "product", Type: "string", Required: true},
+	}, nil)
+
+	elementService.config.LLM = config.LLMConfig{
+		DefaultProvider:      "stub",
+		Timeout:              "1s",
+		MaxTokens:            1000,
+		DefaultMaxTokens:     200,
+		MaxConcurrentPerUser: 1,
+		Providers: []config.LLMProviderConfig{
+			{Name: "stub", Type: "openai", BaseURL: server.URL, APIKey: "sk-test", Models: []string{"stub-model", "stub-large"}, Enabled: true},
+			{Name: "disabled", Type: "ollama", BaseURL: server.URL, Enabled: false},
+		},
+	}
+	recordRepo := new(MockGenerationRecordRepository)
+	recordRepo.On("Create", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(0).(*model.GenerationRecord).ID = uint64(len(recordRepo.Calls))
+	})
+	return NewGenerationService(elementService, recordRepo, nil, nil, elementService.config).(*generationService)
+}
+
+// stubCompletion 返回固定内容的 Chat Completions 接口
+func stubCompletion(t *testing.T, received *map[string]interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer sk-test", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(received))
+		_, _ = w.Write([]byte(`{
+			"model": "stub-model",
+			"choices": [{"message": {"role": "assistant", "content": "本周完成了三项工作"}, "finish_reason": "stop"}],
+			"usage": {"prompt_tokens": 20, "completion_tokens": 8, "total_tokens": 28}
+		}`))
+	}
+}
+
+func TestGenerationService_Generate(t *testing.T) {
+	var received map[string]interface{}
+	s := newTestGenerationService(t, stubCompletion(t, &received))
+
+	temperature := 0.3
+	result, err := s.Generate(context.Background(), 1, 1, &model.GenerateRequest{
+		Temperature: &temperature,
+		Variables:   map[string]interface{}{"product": "CESE"},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, "stub", result.Provider)
+	assert.Equal(t, "stub-model", result.Model)
+	assert.Equal(t, "本周完成了三项工作", result.Content)
+	assert.Equal(t, "stop", result.FinishReason)
+	assert.Equal(t, 28, result.Usage.TotalTokens)
+	assert.Contains(t, result.Prompt, "为CESE整理本周工作")
+
+	// 渲染后的提示词作为用户消息发送，未指定 max_tokens 时使用默认值
+	messages := received["messages"].([]interface{})
+	require.Len(t, messages, 1)
+	assert.Equal(t, result.Prompt, messages[0].(map[string]interface{})["content"])
+	assert.Equal(t, float64(200), received["max_tokens"])
+	assert.Equal(t, 0.3, received["temperature"])
+	assert.NotContains(t, received, "top_p")
+}
+
+func TestGenerationService_GenerateErrors(t *testing.T) {
+	var received map[string]interface{}
+	s := newTestGenerationService(t, stubCompletion(t, &received))
+	variables := map[string]interface{}{"product": "CESE"}
+
+	// 变量校验失败时不调用模型服务
+	result, err := s.Generate(context.Background(), 1, 1, &model.GenerateRequest{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, result.Errors)
+	assert.Nil(t, received)
+
+	_, err = s.Generate(context.Background(), 1, 1, &model.GenerateRequest{Provider: "disabled", Variables: variables})
+	assert.EqualError(t, err, "模型服务不存在或未启用")
+
+	_, err = s.Generate(context.Background(), 1, 1, &model.GenerateRequest{Model: "gpt-4o", Variables: variables})
+	assert.EqualError(t, err, "参数验证失败: 模型服务 stub 不支持模型 gpt-4o")
+
+	_, err = s.Generate(context.Background(), 1, 1, &model.GenerateRequest{MaxTokens: 2000, Variables: variables})
+	assert.EqualError(t, err, "参数验证失败: max_tokens不能超过1000")
+
+	_, err = s.Generate(context.Background(), 2, 1, &model.GenerateRequest{Variables: variables})
+	assert.EqualError(t, err, "无权访问该记录")
+
+	// 已达到并发上限
+	require.True(t, s.limiter.acquire(1))
+	_, err = s.Generate(context.Background(), 1, 1, &model.GenerateRequest{Variables: variables})
+	assert.EqualError(t, err, "生成任务过多，请稍后再试")
+	s.limiter.release(1)
+}
+
+func TestGenerationService_ProviderFailure(t *testing.T) {
+	variables := map[string]interface{}{"product": "CESE"}
+
+	s := newTestGenerationService(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"error": {"message": "Rate limit reached"}}`))
+	})
+	_, err := s.Generate(context.Background(), 1, 1, &model.GenerateRequest{Variables: variables})
+	assert.EqualError(t, err, "调用模型服务失败: Rate limit reached")
+
+	release := make(chan struct{})
+	s = newTestGenerationService(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	})
+	defer close(release)
+	s.config.LLM.Timeout = "50ms"
+	start := time.Now()
+	_, err = s.Generate(context.Background(), 1, 1, &model.GenerateRequest{Variables: variables})
+	assert.EqualError(t, err, "模型服务响应超时")
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestGenerationService_ListProviders(t *testing.T) {
+	s := newTestGenerationService(t, func(w http.ResponseWriter, r *http.Request) {})
+
+	providers := s.ListProviders()
+	require.Len(t, providers, 1)
+	assert.Equal(t, &model.ProviderResponse{Name: "stub", Type: "openai", Models: []string{"stub-model", "stub-large"}, Default: true}, providers[0])
+}
+
+// stubStreamCompletion 以 SSE 逐段返回内容的 Chat Completions 接口，写完 chunks 后等待 release 关闭
+func stubStreamCompletion(chunks []string, release <-chan struct{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range chunks {
+			_, _ = w.Write([]byte("data: " + chunk + "\n\n"))
+			w.(http.Flusher).Flush()
+		}
+		if release != nil {
+			<-release
+		}
+	}
+}
+
+func TestGenerationService_GenerateStream(t *testing.T) {
+	s := newTestGenerationService(t, stubStreamCompletion([]string{
+		`{"model":"stub-model","choices":[{"delta":{"content":"本周"}}]}`,
+		`{"choices":[{"delta":{"content":"完成了三项工作"},"finish_reason":"stop"}]}`,
+		`{"choices":[],"usage":{"prompt_tokens":20,"completion_tokens":8,"total_tokens":28}}`,
+		`[DONE]`,
+	}, nil))
+
+	var deltas []string
+	result, err := s.GenerateStream(context.Background(), 1, 1, &model.GenerateRequest{
+		Variables: map[string]interface{}{"product": "CESE"},
+	}, func(delta string) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"本周", "完成了三项工作"}, deltas)
+	assert.Equal(t, "本周完成了三项工作", result.Content)
+	assert.Equal(t, "stop", result.FinishReason)
+	assert.Equal(t, 28, result.Usage.TotalTokens)
+	assert.Contains(t, result.Prompt, "为CESE整理本周工作")
+
+	// 变量校验失败时不调用模型服务
+	result, err = s.GenerateStream(context.Background(), 1, 1, &model.GenerateRequest{}, func(delta string) error {
+		t.Fatal("不应输出内容")
+		return nil
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, result.Errors)
+}
+
+func TestGenerationService_GenerateStreamCanceled(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	s := newTestGenerationService(t, stubStreamCompletion([]string{
+		`{"model":"stub-model","choices":[{"delta":{"content":"本周"}}]}`,
+	}, release))
+	variables := map[string]interface{}{"product": "CESE"}
+
+	// 客户端断开时取消上游请求，并返回已生成的部分结果
+	result, err := s.GenerateStream(context.Background(), 1, 1, &model.GenerateRequest{Variables: variables}, func(delta string) error {
+		return context.Canceled
+	})
+	assert.EqualError(t, err, "生成已取消")
+	require.NotNil(t, result)
+	assert.Equal(t, "本周", result.Content)
+
+	// 等待上游输出期间客户端断开（请求的 context 被取消）时立即停止，不等到超时
+	ctx, disconnect := context.WithCancel(context.Background())
+	start := time.Now()
+	result, err = s.GenerateStream(ctx, 1, 1, &model.GenerateRequest{Variables: variables}, func(delta string) error {
+		disconnect()
+		return nil
+	})
+	assert.EqualError(t, err, "生成已取消")
+	assert.Less(t, time.Since(start), s.config.LLM.GetTimeout())
+	require.NotNil(t, result)
+	assert.Equal(t, "本周", result.Content)
+
+	// 上游中途停止响应时超时
+	s.config.LLM.Timeout = "50ms"
+	result, err = s.GenerateStream(context.Background(), 1, 1, &model.GenerateRequest{Variables: variables}, func(delta string) error {
+		return nil
+	})
+	assert.EqualError(t, err, "模型服务响应超时")
+	require.NotNil(t, result)
+	assert.Equal(t, "本周", result.Content)
+
+	// 结束后释放并发名额
+	assert.Empty(t, s.limiter.running)
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"cese-backend/internal/config"
+	"cese-backend/internal/model"
+	"cese-backend/internal/repository"
+	"cese-backend/pkg/secret"
+	"cese-backend/pkg/validator"
+)
+
+// ProviderConfigService 用户模型服务配置服务接口
+type ProviderConfigService interface {
+	GetList(userID uint64) ([]*model.ProviderConfigResponse, error)
+	GetByID(userID, configID uint64) (*model.ProviderConfigResponse, error)
+	Create(userID uint64, req *model.ProviderConfigCreateRequest) (*model.ProviderConfigResponse, error)
+	Update(userID, configID uint64, req *model.ProviderConfigUpdateRequest) (*model.ProviderConfigResponse, error)
+	Delete(userID, configID uint64) error
+	RotateKeys() (int, error)
+}
+
+// providerConfigService 用户模型服务配置服务实现
+type providerConfigService struct {
+	configRepo repository.ProviderConfigRepository
+	keyring    *secret.Keyring // 未配置主密钥时为 nil，此时不能保存 API Key
+}
+
+// NewProviderConfigService 创建用户模型服务配置服务实例
+func NewProviderConfigService(configRepo repository.ProviderConfigRepository, cfg *config.Config) ProviderConfigService {
+	s := &providerConfigService{configRepo: configRepo}
+	if cfg.Encryption.MasterKey != "" {
+		// 加载配置时已校验密钥
+		s.keyring, _ = secret.NewKeyring(cfg.Encryption.MasterKey, cfg.Encryption.PreviousKeys...)
+	}
+	return s
+}
+
+// GetList 获取用户的全部模型服务配置
+func (s *providerConfigService) GetList(userID uint64) ([]*model.ProviderConfigResponse, error) {
+	configs, err := s.configRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, errors.New("查询模型服务配置失败")
+	}
+
+	responses := make([]*model.ProviderConfigResponse, len(configs))
+	for i, c := range configs {
+		responses[i] = c.ToResponse()
+	}
+	return responses, nil
+}
+
+// GetByID 获取模型服务配置
+func (s *providerConfigService) GetByID(userID, configID uint64) (*model.ProviderConfigResponse, error) {
+	c, err := s.getOwnedConfig(userID, configID)
+	if err != nil {
+		return nil, err
+	}
+	return c.ToResponse(), nil
+}
+
+// Create 创建模型服务配置，API Key 加密后保存
+func (s *providerConfigService) Create(userID uint64, req *model.ProviderConfigCreateRequest) (*model.ProviderConfigResponse, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	req.APIKey = strings.TrimSpace(req.APIKey)
+
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, errors.New("参数验证失败")
+	}
+
+	if err := s.checkNameAvailable(userID, req.Name, 0); err != nil {
+		return nil, err
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	c := &model.ProviderConfig{UserID: userID}
+	c.Apply(req.Name, req.Type, req.BaseURL, req.Models, enabled, req.ExtraConfig)
+	if err := s.setAPIKey(c, req.APIKey); err != nil {
+		return nil, err
+	}
+
+	if err := s.configRepo.Create(c); err != nil {
+		return nil, errors.New("创建模型服务配置失败")
+	}
+	return c.ToResponse(), nil
+}
+
+// Update 整体替换模型服务配置，未提供 API Key 时保持原值
+func (s *providerConfigService) Update(userID, configID uint64, req *model.ProviderConfigUpdateRequest) (*model.ProviderConfigResponse, error) {
+	req.Name = strings.TrimSpace(req.Name)
+
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, errors.New("参数验证失败")
+	}
+
+	c, err := s.getOwnedConfig(userID, configID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != c.Name {
+		if err := s.checkNameAvailable(userID, req.Name, configID); err != nil {
+			return nil, err
+		}
+	}
+
+	c.Apply(req.Name, req.Type, req.BaseURL, req.Models, req.Enabled, req.ExtraConfig)
+	if req.APIKey != nil {
+		if err := s.setAPIKey(c, strings.TrimSpace(*req.APIKey)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.configRepo.Update(c); err != nil {
+		return nil, errors.New("更新模型服务配置失败")
+	}
+	return c.ToResponse(), nil
+}
+
+// Delete 删除模型服务配置
+func (s *providerConfigService) Delete(userID, configID uint64) error {
+	if _, err := s.getOwnedConfig(userID, configID); err != nil {
+		return err
+	}
+
+	if err := s.configRepo.Delete(configID); err != nil {
+		return errors.New("删除模型服务配置失败")
+	}
+	return nil
+}
+
+// RotateKeys 将用旧密钥加密的 API Key 用当前主密钥重新加密，返回重新加密的数量
+//
+// 无法解密的记录（旧密钥已从配置中移除）会被跳过，并在错误中报告数量。
+func (s *providerConfigService) RotateKeys() (int, error) {
+	if s.keyring == nil {
+		return 0, nil
+	}
+
+	configs, err := s.configRepo.GetByStaleKey(s.keyring.CurrentKeyID())
+	if err != nil {
+		return 0, errors.New("查询模型服务配置失败")
+	}
+
+	rotated, failed := 0, 0
+	for _, c := range configs {
+		apiKey, err := s.keyring.Decrypt(c.APIKeyCipher, c.APIKeyID, associatedData(c.UserID))
+		if err != nil {
+			failed++
+			continue
+		}
+		if c.APIKeyCipher, c.APIKeyID, err = s.keyring.Encrypt(apiKey, associatedData(c.UserID)); err != nil {
+			failed++
+			continue
+		}
+		if err := s.configRepo.UpdateAPIKey(c); err != nil {
+			failed++
+			continue
+		}
+		rotated++
+	}
+
+	if failed > 0 {
+		return rotated, fmt.Errorf("%d个API Key重新加密失败", failed)
+	}
+	return rotated, nil
+}
+
+// setAPIKey 加密并设置 API Key，为空时清除
+func (s *providerConfigService) setAPIKey(c *model.ProviderConfig, apiKey string) error {
+	if apiKey == "" {
+		c.APIKeyCipher, c.APIKeyID, c.APIKeyMask = "", "", ""
+		return nil
+	}
+	if s.keyring == nil {
+		return errors.New("未配置加密主密钥，无法保存API Key")
+	}
+
+	ciphertext, keyID, err := s.keyring.Encrypt(apiKey, associatedData(c.UserID))
+	if err != nil {
+		return errors.New("加密API Key失败")
+	}
+	c.APIKeyCipher, c.APIKeyID, c.APIKeyMask = ciphertext, keyID, secret.Mask(apiKey)
+	return nil
+}
+
+// associatedData API Key 密文绑定的用户，防止密文被复制到其他用户的记录中使用
+func associatedData(userID uint64) []byte {
+	return []byte(fmt.Sprintf("cese:provider-config:user:%d", userID))
+}
+
+// checkNameAvailable 检查名称是否已被用户的其他配置使用
+func (s *providerConfigService) checkNameAvailable(userID uint64, name string, configID uint64) error {
+	existing, err := s.configRepo.GetByName(userID, name)
+	if err != nil {
+		return errors.New("查询模型服务配置失败")
+	}
+	if existing != nil && existing.ID != configID {
+		return errors.New("模型服务配置已存在")
+	}
+	return nil
+}
+
+// getOwnedConfig 获取当前用户的模型服务配置
+func (s *providerConfigService) getOwnedConfig(userID, configID uint64) (*model.ProviderConfig, error) {
+	c, err := s.configRepo.GetByID(configID)
+	if err != nil {
+		return nil, errors.New("查询模型服务配置失败")
+	}
+	if c == nil {
+		return nil, errors.New("模型服务配置不存在")
+	}
+
+	// 检查权限：只能访问自己的配置
+	if c.UserID != userID {
+		return nil, errors.New("无权访问该记录")
+	}
+
+	return c, nil
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"testing"
+
+	"cese-backend/internal/config"
+	"cese-backend/internal/model"
+	"cese-backend/pkg/secret"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockProviderConfigRepository 模型服务配置Repository模拟
+type MockProviderConfigRepository struct {
+	mock.Mock
+}
+
+func (m *MockProviderConfigRepository) Create(c *model.ProviderConfig) error {
+	args := m.Called(c)
+	return args.Error(0)
+}
+
+func (m *MockProviderConfigRepository) GetByID(id uint64) (*model.ProviderConfig, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ProviderConfig), args.Error(1)
+}
+
+func (m *MockProviderConfigRepository) GetByName(userID uint64, name string) (*model.ProviderConfig, error) {
+	args := m.Called(userID, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ProviderConfig), args.Error(1)
+}
+
+func (m *MockProviderConfigRepository) GetByUserID(userID uint64) ([]*model.ProviderConfig, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.ProviderConfig), args.Error(1)
+}
+
+func (m *MockProviderConfigRepository) GetByStaleKey(currentKeyID string) ([]*model.ProviderConfig, error) {
+	args := m.Called(currentKeyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.ProviderConfig), args.Error(1)
+}
+
+func (m *MockProviderConfigRepository) Update(c *model.ProviderConfig) error {
+	args := m.Called(c)
+	return args.Error(0)
+}
+
+func (m *MockProviderConfigRepository) UpdateAPIKey(c *model.ProviderConfig) error {
+	args := m.Called(c)
+	return args.Error(0)
+}
+
+func (m *MockProviderConfigRepository) Delete(id uint64) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// newTestProviderConfigService 创建使用指定主密钥的模型服务配置服务
+func newTestProviderConfigService(repo *MockProviderConfigRepository, masterKey string, previousKeys ...string) *providerConfigService {
+	cfg := &config.Config{Encryption: config.EncryptionConfig{MasterKey: masterKey, PreviousKeys: previousKeys}}
+	return NewProviderConfigService(repo, cfg).(*providerConfigService)
+}
+
+func testMasterKey(t *testing.T) string {
+	key, err := secret.GenerateKey()
+	require.NoError(t, err)
+	return key
+}
+
+func TestProviderConfigService_Create(t *testing.T) {
+	repo := new(MockProviderConfigRepository)
+	s := newTestProviderConfigService(repo, testMasterKey(t))
+
+	repo.On("GetByName", uint64(1), "我的OpenAI").Return(nil, nil)
+	repo.On("Create", mock.AnythingOfType("*model.ProviderConfig")).Return(nil)
+
+	resp, err := s.Create(1, &model.ProviderConfigCreateRequest{
+		Name:    " 我的OpenAI ",
+		Type:    "openai",
+		BaseURL: "https://api.openai.com/v1",
+		APIKey:  "sk-proj-a1b2c3d4",
+		Models:  []string{"gpt-4o-mini"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "我的OpenAI", resp.Name)
+	assert.True(t, resp.Enabled)
+	assert.True(t, resp.HasAPIKey)
+	assert.Equal(t, "****c3d4", resp.APIKeyMasked)
+	assert.Equal(t, []string{"gpt-4o-mini"}, resp.Models)
+
+	// 数据库中只保存密文，且只能以所属用户解密
+	saved := repo.Calls[1].Arguments.Get(0).(*model.ProviderConfig)
+	assert.NotContains(t, saved.APIKeyCipher, "sk-proj")
+	assert.Equal(t, s.keyring.CurrentKeyID(), saved.APIKeyID)
+	apiKey, err := s.keyring.Decrypt(saved.APIKeyCipher, saved.APIKeyID, associatedData(1))
+	require.NoError(t, err)
+	assert.Equal(t, "sk-proj-a1b2c3d4", apiKey)
+	_, err = s.keyring.Decrypt(saved.APIKeyCipher, saved.APIKeyID, associatedData(2))
+	assert.Error(t, err)
+}
+
+func TestProviderConfigService_CreateErrors(t *testing.T) {
+	repo := new(MockProviderConfigRepository)
+	repo.On("GetByName", uint64(1), "ollama").Return(&model.ProviderConfig{ID: 3, UserID: 1, Name: "ollama"}, nil)
+	repo.On("GetByName", uint64(1), "openai").Return(nil, nil)
+	repo.On("Create", mock.AnythingOfType("*model.ProviderConfig")).Return(nil)
+
+	// 未配置主密钥时只能保存不含 API Key 的配置
+	s := newTestProviderConfigService(repo, "")
+	_, err := s.Create(1, &model.ProviderConfigCreateRequest{Name: "openai", Type: "openai", BaseURL: "https://api.openai.com/v1", APIKey: "sk-test"})
+	assert.EqualError(t, err, "未配置加密主密钥，无法保存API Key")
+	resp, err := s.Create(1, &model.ProviderConfigCreateRequest{Name: "openai", Type: "openai", BaseURL: "https://api.openai.com/v1"})
+	require.NoError(t, err)
+	assert.False(t, resp.HasAPIKey)
+
+	_, err = s.Create(1, &model.ProviderConfigCreateRequest{Name: "ollama", Type: "ollama", BaseURL: "http://localhost:11434/api"})
+	assert.EqualError(t, err, "模型服务配置已存在")
+
+	_, err = s.Create(1, &model.ProviderConfigCreateRequest{Name: "openai", Type: "unknown", BaseURL: "https://api.openai.com/v1"})
+	assert.EqualError(t, err, "参数验证失败")
+	_, err = s.Create(1, &model.ProviderConfigCreateRequest{Name: "openai", Type: "openai", BaseURL: "not a url"})
+	assert.EqualError(t, err, "参数验证失败")
+}
+
+func TestProviderConfigService_Update(t *testing.T) {
+	repo := new(MockProviderConfigRepository)
+	s := newTestProviderConfigService(repo, testMasterKey(t))
+
+	existing := &model.ProviderConfig{ID: 5, UserID: 1, Name: "qwen"}
+	require.NoError(t, s.setAPIKey(existing, "sk-old-12345678"))
+	oldCipher := existing.APIKeyCipher
+
+	repo.On("GetByID", uint64(5)).Return(existing, nil)
+	repo.On("Update", existing).Return(nil)
+
+	// 省略 api_key 时保持不变
+	resp, err := s.Update(1, 5, &model.ProviderConfigUpdateRequest{
+		Name: "qwen", Type: "alibaba", BaseURL: "https://dashscope.aliyuncs.com/compatible-mode/v1", Enabled: false,
+	})
+	require.NoError(t, err)
+	assert.False(t, resp.Enabled)
+	assert.Equal(t, "****5678", resp.APIKeyMasked)
+	assert.Equal(t, oldCipher, existing.APIKeyCipher)
+
+	// 提供新值时重新加密，空字符串清除
+	newKey := "sk-new-87654321"
+	resp, err = s.Update(1, 5, &model.ProviderConfigUpdateRequest{Name: "qwen", Type: "alibaba", BaseURL: "https://dashscope.aliyuncs.com/compatible-mode/v1", APIKey: &newKey})
+	require.NoError(t, err)
+	assert.Equal(t, "****4321", resp.APIKeyMasked)
+	assert.NotEqual(t, oldCipher, existing.APIKeyCipher)
+
+	empty := ""
+	resp, err = s.Update(1, 5, &model.ProviderConfigUpdateRequest{Name: "qwen", Type: "alibaba", BaseURL: "https://dashscope.aliyuncs.com/compatible-mode/v1", APIKey: &empty})
+	require.NoError(t, err)
+	assert.False(t, resp.HasAPIKey)
+	assert.Empty(t, existing.APIKeyCipher)
+
+	_, err = s.Update(2, 5, &model.ProviderConfigUpdateRequest{Name: "qwen", Type: "alibaba", BaseURL: "https://dashscope.aliyuncs.com/compatible-mode/v1"})
+	assert.EqualError(t, err, "无权访问该记录")
+}
+
+func TestProviderConfigService_RotateKeys(t *testing.T) {
+	oldKey, newKey, lostKey := testMasterKey(t), testMasterKey(t), testMasterKey(t)
+
+	old := newTestProviderConfigService(new(MockProviderConfigRepository), oldKey)
+	lost := newTestProviderConfigService(new(MockProviderConfigRepository), lostKey)
+	first := &model.ProviderConfig{ID: 1, UserID: 1}
+	second := &model.ProviderConfig{ID: 2, UserID: 2}
+	orphan := &model.ProviderConfig{ID: 3, UserID: 3}
+	require.NoError(t, old.setAPIKey(first, "sk-first-0001"))
+	require.NoError(t, old.setAPIKey(second, "sk-second-0002"))
+	require.NoError(t, lost.setAPIKey(orphan, "sk-orphan-0003"))
+
+	repo := new(MockProviderConfigRepository)
+	s := newTestProviderConfigService(repo, newKey, oldKey)
+	repo.On("GetByStaleKey", s.keyring.CurrentKeyID()).Return([]*model.ProviderConfig{first, second, orphan}, nil)
+	repo.On("UpdateAPIKey", mock.AnythingOfType("*model.ProviderConfig")).Return(nil)
+
+	rotated, err := s.RotateKeys()
+	assert.Equal(t, 2, rotated)
+	assert.EqualError(t, err, "1个API Key重新加密失败")
+	repo.AssertNumberOfCalls(t, "UpdateAPIKey", 2)
+
+	// 重新加密后只需当前密钥即可解密
+	current, err := secret.NewKeyring(newKey)
+	require.NoError(t, err)
+	apiKey, err := current.Decrypt(second.APIKeyCipher, second.APIKeyID, associatedData(2))
+	require.NoError(t, err)
+	assert.Equal(t, "sk-second-0002", apiKey)
+	assert.Equal(t, "****0002", second.APIKeyMask)
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"cese-backend/internal/model"
+	"cese-backend/internal/repository"
+	"cese-backend/pkg/validator"
+)
+
+// TagService 标签服务接口
+type TagService interface {
+	GetList(userID uint64) ([]*model.TagResponse, error)
+	Create(userID uint64, req *model.TagCreateRequest) (*model.TagResponse, error)
+	Rename(userID, tagID uint64, req *model.TagRenameRequest) (*model.TagResponse, error)
+	Merge(userID uint64, req *model.TagMergeRequest) (*model.TagResponse, error)
+	Delete(userID, tagID uint64) error
+}
+
+// tagService 标签服务实现
+type tagService struct {
+	tagRepo repository.TagRepository
+}
+
+// NewTagService 创建标签服务实例
+func NewTagService(tagRepo repository.TagRepository) TagService {
+	return &tagService{
+		tagRepo: tagRepo,
+	}
+}
+
+// GetList 获取用户的全部标签及使用次数
+func (s *tagService) GetList(userID uint64) ([]*model.TagResponse, error) {
+	tags, err := s.tagRepo.GetByUserIDWithCount(userID)
+	if err != nil {
+		return nil, errors.New("查询标签列表失败")
+	}
+
+	responses := make([]*model.TagResponse, len(tags))
+	for i, tag := range tags {
+		responses[i] = tag.ToResponse()
+	}
+
+	return responses, nil
+}
+
+// Create 创建标签
+func (s *tagService) Create(userID uint64, req *model.TagCreateRequest) (*model.TagResponse, error) {
+	req.Name = strings.TrimSpace(req.Name)
+
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, errors.New("参数验证失败")
+	}
+
+	existing, err := s.tagRepo.GetByName(userID, req.Name)
+	if err != nil {
+		return nil, errors.New("查询标签失败")
+	}
+	if existing != nil {
+		return nil, errors.New("标签已存在")
+	}
+
+	tag := &model.Tag{UserID: userID, Name: req.Name}
+	if err := s.tagRepo.Create(tag); err != nil {
+		return nil, errors.New("创建标签失败")
+	}
+
+	return tag.ToResponse(), nil
+}
+
+// Rename 重命名标签（新名称已存在时应使用合并）
+func (s *tagService) Rename(userID, tagID uint64, req *model.TagRenameRequest) (*model.TagResponse, error) {
+	req.Name = strings.TrimSpace(req.Name)
+
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, errors.New("参数验证失败")
+	}
+
+	tag, err := s.getOwnedTag(userID, tagID)
+	if err != nil {
+		return nil, err
+	}
+	if tag.Name == req.Name {
+		return tag.ToResponse(), nil
+	}
+
+	existing, err := s.tagRepo.GetByName(userID, req.Name)
+	if err != nil {
+		return nil, errors.New("查询标签失败")
+	}
+	if existing != nil {
+		return nil, errors.New("标签已存在")
+	}
+
+	if err := s.tagRepo.Rename(tagID, req.Name); err != nil {
+		return nil, errors.New("重命名标签失败")
+	}
+
+	tag.Name = req.Name
+	return tag.ToResponse(), nil
+}
+
+// Merge 将多个标签合并到目标标签
+func (s *tagService) Merge(userID uint64, req *model.TagMergeRequest) (*model.TagResponse, error) {
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, errors.New("参数验证失败")
+	}
+
+	target, err := s.getOwnedTag(userID, req.TargetID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 去除目标标签自身及重复ID
+	seen := map[uint64]bool{req.TargetID: true}
+	sourceIDs := make([]uint64, 0, len(req.SourceIDs))
+	for _, id := range req.SourceIDs {
+		if !seen[id] {
+			seen[id] = true
+			sourceIDs = append(sourceIDs, id)
+		}
+	}
+	if len(sourceIDs) == 0 {
+		return target.ToResponse(), nil
+	}
+
+	sources, err := s.tagRepo.GetByIDs(userID, sourceIDs)
+	if err != nil {
+		return nil, errors.New("查询标签失败")
+	}
+	if len(sources) != len(sourceIDs) {
+		return nil, errors.New("标签不存在")
+	}
+
+	if err := s.tagRepo.Merge(target.ID, sourceIDs); err != nil {
+		return nil, errors.New("合并标签失败")
+	}
+
+	return target.ToResponse(), nil
+}
+
+// Delete 删除标签（同时解除与六要素的关联）
+func (s *tagService) Delete(userID, tagID uint64) error {
+	if _, err := s.getOwnedTag(userID, tagID); err != nil {
+		return err
+	}
+
+	if err := s.tagRepo.Delete(tagID); err != nil {
+		return errors.New("删除标签失败")
+	}
+
+	return nil
+}
+
+// getOwnedTag 获取当前用户拥有的标签
+func (s *tagService) getOwnedTag(userID, tagID uint64) (*model.Tag, error) {
+	tag, err := s.tagRepo.GetByID(tagID)
+	if err != nil {
+		return nil, errors.New("查询标签失败")
+	}
+	if tag == nil || tag.UserID != userID {
+		return nil, errors.New("标签不存在")
+	}
+	return tag, nil
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"testing"
+
+	"cese-backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTagRepository 标签Repository模拟
+type MockTagRepository struct {
+	mock.Mock
+}
+
+func (m *MockTagRepository) Create(tag *model.Tag) error {
+	args := m.Called(tag)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) GetByID(id uint64) (*model.Tag, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) GetByIDs(userID uint64, ids []uint64) ([]*model.Tag, error) {
+	args := m.Called(userID, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) GetByName(userID uint64, name string) (*model.Tag, error) {
+	args := m.Called(userID, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) GetByUserIDWithCount(userID uint64) ([]*model.TagWithCount, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.TagWithCount), args.Error(1)
+}
+
+func (m *MockTagRepository) Rename(id uint64, name string) error {
+	args := m.Called(id, name)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) Merge(targetID uint64, sourceIDs []uint64) error {
+	args := m.Called(targetID, sourceIDs)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) Delete(id uint64) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func TestTagService_Rename(t *testing.T) {
+	mockRepo := new(MockTagRepository)
+	service := NewTagService(mockRepo)
+
+	tests := []struct {
+		name    string
+		tagID   uint64
+		req     *model.TagRenameRequest
+		setup   func()
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:  "成功重命名",
+			tagID: 1,
+			req:   &model.TagRenameRequest{Name: " 营销 "},
+			setup: func() {
+				mockRepo.On("GetByID", uint64(1)).Return(&model.Tag{ID: 1, UserID: 1, Name: "市场"}, nil)
+				mockRepo.On("GetByName", uint64(1), "营销").Return(nil, nil)
+				mockRepo.On("Rename", uint64(1), "营销").Return(nil)
+			},
+		},
+		{
+			name:  "名称已存在",
+			tagID: 1,
+			req:   &model.TagRenameRequest{Name: "营销"},
+			setup: func() {
+				mockRepo.On("GetByID", uint64(1)).Return(&model.Tag{ID: 1, UserID: 1, Name: "市场"}, nil)
+				mockRepo.On("GetByName", uint64(1), "营销").Return(&model.Tag{ID: 2, UserID: 1, Name: "营销"}, nil)
+			},
+			wantErr: true,
+			errMsg:  "标签已存在",
+		},
+		{
+			name:  "他人的标签",
+			tagID: 3,
+			req:   &model.TagRenameRequest{Name: "营销"},
+			setup: func() {
+				mockRepo.On("GetByID", uint64(3)).Return(&model.Tag{ID: 3, UserID: 2, Name: "市场"}, nil)
+			},
+			wantErr: true,
+			errMsg:  "标签不存在",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo.ExpectedCalls = nil
+			tt.setup()
+
+			tag, err := service.Rename(1, tt.tagID, tt.req)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				assert.Nil(t, tag)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "营销", tag.Name)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTagService_Merge(t *testing.T) {
+	t.Run("忽略目标标签自身和重复ID", func(t *testing.T) {
+		mockRepo := new(MockTagRepository)
+		service := NewTagService(mockRepo)
+		mockRepo.On("GetByID", uint64(1)).Return(&model.Tag{ID: 1, UserID: 1, Name: "营销"}, nil)
+		mockRepo.On("GetByIDs", uint64(1), []uint64{2, 3}).Return([]*model.Tag{{ID: 2, UserID: 1}, {ID: 3, UserID: 1}}, nil)
+		mockRepo.On("Merge", uint64(1), []uint64{2, 3}).Return(nil)
+
+		tag, err := service.Merge(1, &model.TagMergeRequest{SourceIDs: []uint64{2, 1, 3, 2}, TargetID: 1})
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), tag.ID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("源标签不属于当前用户", func(t *testing.T) {
+		mockRepo := new(MockTagRepository)
+		service := NewTagService(mockRepo)
+		mockRepo.On("GetByID", uint64(1)).Return(&model.Tag{ID: 1, UserID: 1, Name: "营销"}, nil)
+		mockRepo.On("GetByIDs", uint64(1), []uint64{9}).Return([]*model.Tag{}, nil)
+
+		tag, err := service.Merge(1, &model.TagMergeRequest{SourceIDs: []uint64{9}, TargetID: 1})
+
+		assert.EqualError(t, err, "标签不存在")
+		assert.Nil(t, tag)
+		mockRepo.AssertNotCalled(t, "Merge", mock.Anything, mock.Anything)
+	})
+}
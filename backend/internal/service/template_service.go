@@ -0,0 +1,264 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"cese-backend/internal/config"
+	"cese-backend/internal/model"
+	"cese-backend/internal/repository"
+	"cese-backend/pkg/validator"
+)
+
+// maxLineageDepth 模板来源链的最大追溯深度
+const maxLineageDepth = 20
+
+// TemplateService 公共模板服务接口
+type TemplateService interface {
+	Publish(userID, elementID uint64, req *model.TemplatePublishRequest) (*model.TemplateResponse, error)
+	Unpublish(userID, elementID uint64) error
+	GetList(userID uint64, req *model.TemplateQueryRequest) ([]*model.TemplateResponse, int64, error)
+	GetByID(templateID uint64) (*model.TemplateResponse, error)
+	GetCategories() ([]*model.TemplateCategoryResponse, error)
+	GetLineage(templateID uint64) ([]*model.TemplateResponse, error)
+	Fork(userID, templateID uint64, req *model.TemplateForkRequest) (*model.ContextElementResponse, error)
+}
+
+// templateService 公共模板服务实现
+type templateService struct {
+	templateRepo repository.TemplateRepository
+	elementRepo  repository.ContextElementRepository
+	folderRepo   repository.FolderRepository
+	variableRepo repository.ContextElementVariableRepository
+	config       *config.Config
+}
+
+// NewTemplateService 创建公共模板服务实例
+func NewTemplateService(
+	templateRepo repository.TemplateRepository,
+	elementRepo repository.ContextElementRepository,
+	folderRepo repository.FolderRepository,
+	variableRepo repository.ContextElementVariableRepository,
+	cfg *config.Config,
+) TemplateService {
+	return &templateService{
+		templateRepo: templateRepo,
+		elementRepo:  elementRepo,
+		folderRepo:   folderRepo,
+		variableRepo: variableRepo,
+		config:       cfg,
+	}
+}
+
+// Publish 将六要素发布为公共模板，已发布时用当前内容刷新模板快照
+func (s *templateService) Publish(userID, elementID uint64, req *model.TemplatePublishRequest) (*model.TemplateResponse, error) {
+	req.Category = strings.TrimSpace(req.Category)
+
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, errors.New("参数验证失败")
+	}
+
+	element, err := s.elementRepo.GetByID(elementID)
+	if err != nil {
+		return nil, errors.New("查询六要素记录失败")
+	}
+	if element == nil {
+		return nil, errors.New("六要素记录不存在")
+	}
+
+	// 检查权限：只能发布自己的记录
+	if element.UserID != userID {
+		return nil, errors.New("无权访问该记录")
+	}
+
+	template, err := s.templateRepo.GetByElementID(elementID)
+	if err != nil {
+		return nil, errors.New("查询模板失败")
+	}
+	if template == nil {
+		template = &model.Template{}
+	}
+
+	variables, err := s.variableRepo.GetByElementID(elementID)
+	if err != nil {
+		return nil, errors.New("查询变量定义失败")
+	}
+
+	template.PublishFrom(element, variables, req)
+	if err := s.templateRepo.Save(template); err != nil {
+		return nil, errors.New("发布模板失败")
+	}
+
+	return template.ToResponse(), nil
+}
+
+// Unpublish 取消发布六要素对应的公共模板
+func (s *templateService) Unpublish(userID, elementID uint64) error {
+	template, err := s.templateRepo.GetByElementID(elementID)
+	if err != nil {
+		return errors.New("查询模板失败")
+	}
+	if template == nil || template.DeletedAt.Valid {
+		return errors.New("模板不存在")
+	}
+
+	// 检查权限：只能取消发布自己的模板
+	if template.UserID != userID {
+		return errors.New("无权访问该记录")
+	}
+
+	if err := s.templateRepo.Delete(template.ID); err != nil {
+		return errors.New("取消发布模板失败")
+	}
+
+	return nil
+}
+
+// GetList 浏览公共模板（mine为true时仅返回当前用户发布的模板）
+func (s *templateService) GetList(userID uint64, req *model.TemplateQueryRequest) ([]*model.TemplateResponse, int64, error) {
+	// 设置默认值
+	s.setDefaultQueryParams(req)
+
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, 0, errors.New("参数验证失败")
+	}
+
+	var authorID uint64
+	if req.Mine {
+		if userID == 0 {
+			return nil, 0, errors.New("未登录")
+		}
+		authorID = userID
+	}
+
+	templates, total, err := s.templateRepo.GetList(authorID, req)
+	if err != nil {
+		return nil, 0, errors.New("查询模板列表失败")
+	}
+
+	responses := make([]*model.TemplateResponse, len(templates))
+	for i, template := range templates {
+		responses[i] = template.ToResponse()
+	}
+
+	return responses, total, nil
+}
+
+// GetByID 获取公共模板详情
+func (s *templateService) GetByID(templateID uint64) (*model.TemplateResponse, error) {
+	template, err := s.getTemplate(templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	return template.ToResponse(), nil
+}
+
+// GetCategories 获取模板分类及数量
+func (s *templateService) GetCategories() ([]*model.TemplateCategoryResponse, error) {
+	categories, err := s.templateRepo.GetCategories()
+	if err != nil {
+		return nil, errors.New("查询模板分类失败")
+	}
+	if categories == nil {
+		categories = []*model.TemplateCategoryResponse{}
+	}
+
+	return categories, nil
+}
+
+// GetLineage 获取模板的来源链（从当前模板开始，依次追溯到最早的已发布来源模板）
+func (s *templateService) GetLineage(templateID uint64) ([]*model.TemplateResponse, error) {
+	template, err := s.getTemplate(templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	lineage := []*model.TemplateResponse{template.ToResponse()}
+	visited := map[uint64]bool{template.ID: true}
+	for template.SourceTemplateID != nil && len(lineage) < maxLineageDepth {
+		sourceID := *template.SourceTemplateID
+		if visited[sourceID] {
+			break
+		}
+		visited[sourceID] = true
+
+		template, err = s.templateRepo.GetByID(sourceID)
+		if err != nil {
+			return nil, errors.New("查询模板失败")
+		}
+		// 来源模板已取消发布时来源链到此为止
+		if template == nil {
+			break
+		}
+		lineage = append(lineage, template.ToResponse())
+	}
+
+	return lineage, nil
+}
+
+// Fork 将公共模板复刻为当前用户的六要素，并记录来源模板
+func (s *templateService) Fork(userID, templateID uint64, req *model.TemplateForkRequest) (*model.ContextElementResponse, error) {
+	req.Subject = strings.TrimSpace(req.Subject)
+
+	// 参数验证
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, errors.New("参数验证失败")
+	}
+
+	template, err := s.getTemplate(templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 检查文件夹归属
+	if req.FolderID != nil {
+		folder, err := s.folderRepo.GetByID(*req.FolderID)
+		if err != nil {
+			return nil, errors.New("查询文件夹失败")
+		}
+		if folder == nil || folder.UserID != userID {
+			return nil, errors.New("文件夹不存在")
+		}
+	}
+
+	element := template.ToContextElement(userID, req)
+	element.Tags = model.NewTags(userID, model.NormalizeTagNames(template.Tags))
+	variables := model.NewContextElementVariables(0, template.Variables)
+	if err := s.templateRepo.Fork(element, variables, templateID); err != nil {
+		return nil, errors.New("复刻模板失败")
+	}
+
+	return element.ToResponse(), nil
+}
+
+// getTemplate 获取已发布的模板
+func (s *templateService) getTemplate(templateID uint64) (*model.Template, error) {
+	template, err := s.templateRepo.GetByID(templateID)
+	if err != nil {
+		return nil, errors.New("查询模板失败")
+	}
+	if template == nil {
+		return nil, errors.New("模板不存在")
+	}
+	return template, nil
+}
+
+// setDefaultQueryParams 设置默认查询参数
+func (s *templateService) setDefaultQueryParams(req *model.TemplateQueryRequest) {
+	if req.Page <= 0 {
+		req.Page = s.config.Pagination.DefaultPage
+	}
+	if req.Size <= 0 {
+		req.Size = s.config.Pagination.DefaultSize
+	}
+	if req.Size > s.config.Pagination.MaxSize {
+		req.Size = s.config.Pagination.MaxSize
+	}
+	if req.SortBy == "" {
+		req.SortBy = "created_at"
+		req.SortDesc = true // 默认按发布时间倒序
+	}
+}
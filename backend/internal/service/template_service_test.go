@@ -0,0 +1,152 @@
+package service
+
+import (
+	"testing"
+
+	"cese-backend/internal/config"
+	"cese-backend/internal/model"
+	"cese-backend/pkg/prompt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockTemplateRepository 公共模板Repository模拟
+type MockTemplateRepository struct {
+	mock.Mock
+}
+
+func (m *MockTemplateRepository) GetByID(id uint64) (*model.Template, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Template), args.Error(1)
+}
+
+func (m *MockTemplateRepository) GetByElementID(elementID uint64) (*model.Template, error) {
+	args := m.Called(elementID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Template), args.Error(1)
+}
+
+func (m *MockTemplateRepository) GetList(userID uint64, req *model.TemplateQueryRequest) ([]*model.Template, int64, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*model.Template), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockTemplateRepository) GetCategories() ([]*model.TemplateCategoryResponse, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.TemplateCategoryResponse), args.Error(1)
+}
+
+func (m *MockTemplateRepository) Save(template *model.Template) error {
+	args := m.Called(template)
+	return args.Error(0)
+}
+
+func (m *MockTemplateRepository) Delete(id uint64) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockTemplateRepository) Fork(element *model.ContextElement, variables []*model.ContextElementVariable, templateID uint64) error {
+	args := m.Called(element, variables, templateID)
+	return args.Error(0)
+}
+
+func newTestTemplateService(templateRepo *MockTemplateRepository) TemplateService {
+	cfg := &config.Config{
+		Pagination: config.PaginationConfig{DefaultPage: 1, DefaultSize: 15, MaxSize: 100},
+	}
+	return NewTemplateService(templateRepo, nil, nil, nil, cfg)
+}
+
+func TestTemplateService_Fork(t *testing.T) {
+	t.Run("复刻模板并记录来源", func(t *testing.T) {
+		templateRepo := new(MockTemplateRepository)
+		service := newTestTemplateService(templateRepo)
+
+		template := &model.Template{ID: 7, UserID: 2, Subject: "周报生成", TaskGoal: "整理{{week}}工作", Tags: []string{"办公", " 周报 ", "办公"},
+			Variables: []prompt.Variable{{Name: "week", Type: prompt.VarTypeEnum, Default: "本周", Options: []string{"本周", "上周"}}}}
+		templateRepo.On("GetByID", uint64(7)).Return(template, nil)
+		// 标签和变量定义由Repository在复刻的事务中创建
+		templateRepo.On("Fork", mock.MatchedBy(func(element *model.ContextElement) bool {
+			return element.UserID == 1 && element.SourceTemplateID != nil && *element.SourceTemplateID == 7 &&
+				assert.ObjectsAreEqual(model.NewTags(1, []string{"办公", "周报"}), element.Tags)
+		}), mock.MatchedBy(func(variables []*model.ContextElementVariable) bool {
+			return len(variables) == 1 && variables[0].Name == "week" && variables[0].Type == prompt.VarTypeEnum &&
+				variables[0].DefaultValue == "本周" && assert.ObjectsAreEqual([]string{"本周", "上周"}, variables[0].Options)
+		}), uint64(7)).Return(nil)
+
+		element, err := service.Fork(1, 7, &model.TemplateForkRequest{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "周报生成", element.Subject)
+		assert.Equal(t, "整理{{week}}工作", element.TaskGoal)
+		assert.Equal(t, uint64(7), *element.SourceTemplateID)
+		assert.Equal(t, []string{"办公", "周报"}, element.Tags)
+		templateRepo.AssertExpectations(t)
+	})
+
+	t.Run("模板不存在", func(t *testing.T) {
+		templateRepo := new(MockTemplateRepository)
+		service := newTestTemplateService(templateRepo)
+		templateRepo.On("GetByID", uint64(8)).Return(nil, nil)
+
+		element, err := service.Fork(1, 8, &model.TemplateForkRequest{Subject: "我的周报"})
+
+		assert.EqualError(t, err, "模板不存在")
+		assert.Nil(t, element)
+		templateRepo.AssertNotCalled(t, "Fork", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTemplateService_PublishVariables(t *testing.T) {
+	templateRepo := new(MockTemplateRepository)
+	elementRepo := new(MockContextElementRepository)
+	variableRepo := new(MockContextElementVariableRepository)
+	service := NewTemplateService(templateRepo, elementRepo, nil, variableRepo, &config.Config{})
+
+	elementRepo.On("GetByID", uint64(3)).Return(&model.ContextElement{ID: 3, UserID: 1, Subject: "周报生成", TaskGoal: "整理{{week}}工作"}, nil)
+	templateRepo.On("GetByElementID", uint64(3)).Return(nil, nil)
+	variableRepo.On("GetByElementID", uint64(3)).Return([]*model.ContextElementVariable{
+		{ElementID: 3, Name: "week", Type: prompt.VarTypeString, DefaultValue: "本周", Required: true},
+	}, nil)
+	templateRepo.On("Save", mock.MatchedBy(func(template *model.Template) bool {
+		return template.ElementID == 3 && len(template.Variables) == 1 && template.Variables[0].Name == "week"
+	})).Return(nil)
+
+	result, err := service.Publish(1, 3, &model.TemplatePublishRequest{Category: "办公"})
+	require.NoError(t, err)
+	assert.Equal(t, []prompt.Variable{{Name: "week", Type: prompt.VarTypeString, Default: "本周", Required: true}}, result.Variables)
+	templateRepo.AssertExpectations(t)
+}
+
+func TestTemplateService_GetLineage(t *testing.T) {
+	templateRepo := new(MockTemplateRepository)
+	service := newTestTemplateService(templateRepo)
+
+	first, second := uint64(1), uint64(2)
+	templateRepo.On("GetByID", uint64(3)).Return(&model.Template{ID: 3, SourceTemplateID: &second}, nil)
+	templateRepo.On("GetByID", uint64(2)).Return(&model.Template{ID: 2, SourceTemplateID: &first}, nil)
+	// 最早的来源模板已取消发布
+	templateRepo.On("GetByID", uint64(1)).Return(nil, nil)
+
+	lineage, err := service.GetLineage(3)
+
+	assert.NoError(t, err)
+	assert.Len(t, lineage, 2)
+	assert.Equal(t, uint64(3), lineage[0].ID)
+	assert.Equal(t, uint64(2), lineage[1].ID)
+	templateRepo.AssertExpectations(t)
+}
@@ -0,0 +1,38 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// ErrInvalidCursor 游标无法解析
+var ErrInvalidCursor = errors.New("无效的游标")
+
+// Cursor 键集分页游标，记录排序方式和边界记录的排序键
+type Cursor struct {
+	SortBy   string `json:"s"`           // 排序字段
+	Desc     bool   `json:"d,omitempty"` // 是否倒序
+	Value    string `json:"v"`           // 边界记录的排序字段值
+	ID       uint64 `json:"i"`           // 边界记录的ID，排序字段值相同时用于确定顺序
+	Backward bool   `json:"b,omitempty"` // 是否向前翻页（获取边界记录之前的数据）
+}
+
+// Encode 将游标编码为不透明字符串
+func Encode(c Cursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Decode 解析游标字符串
+func Decode(s string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.SortBy == "" || c.ID == 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
@@ -0,0 +1,27 @@
+package cursor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	original := Cursor{SortBy: "subject", Desc: true, Value: "周报/生成 #1", ID: 42, Backward: true}
+
+	encoded := Encode(original)
+	assert.NotContains(t, encoded, "=")
+	assert.NotContains(t, encoded, "/")
+
+	decoded, err := Decode(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, original, *decoded)
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	for _, s := range []string{"", "not base64!", Encode(Cursor{SortBy: "created_at"}), "e30"} {
+		_, err := Decode(s)
+		assert.ErrorIs(t, err, ErrInvalidCursor, s)
+	}
+}
@@ -0,0 +1,59 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want []Line
+	}{
+		{
+			name: "内容相同",
+			from: "保持专业\n语气友好",
+			to:   "保持专业\n语气友好",
+			want: []Line{
+				{Op: OpEqual, Text: "保持专业"},
+				{Op: OpEqual, Text: "语气友好"},
+			},
+		},
+		{
+			name: "修改一行",
+			from: "保持专业\n语气友好\n不要编造",
+			to:   "保持专业\n语气简洁\n不要编造",
+			want: []Line{
+				{Op: OpEqual, Text: "保持专业"},
+				{Op: OpDelete, Text: "语气友好"},
+				{Op: OpInsert, Text: "语气简洁"},
+				{Op: OpEqual, Text: "不要编造"},
+			},
+		},
+		{
+			name: "从空到有",
+			from: "",
+			to:   "新增规则",
+			want: []Line{
+				{Op: OpInsert, Text: "新增规则"},
+			},
+		},
+		{
+			name: "清空内容",
+			from: "旧规则",
+			to:   "",
+			want: []Line{
+				{Op: OpDelete, Text: "旧规则"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Lines(tt.from, tt.to))
+		})
+	}
+}
@@ -0,0 +1,180 @@
+// Package eval 按预期断言评估模型的输出：文本包含、正则、JSON Schema、长度限制和大模型评审
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// 断言类型
+const (
+	TypeContains    = "contains"     // 输出包含指定文本
+	TypeNotContains = "not_contains" // 输出不包含指定文本
+	TypeRegex       = "regex"        // 输出匹配正则表达式
+	TypeJSONSchema  = "json_schema"  // 输出是合法的 JSON，并符合 schema（可选）
+	TypeMaxLength   = "max_length"   // 输出的字符数不超过上限
+	TypeLLMJudge    = "llm_judge"    // 由评审模型按评分标准判断
+)
+
+// Types 支持的断言类型
+var Types = []string{TypeContains, TypeNotContains, TypeRegex, TypeJSONSchema, TypeMaxLength, TypeLLMJudge}
+
+// 用例评估结果
+const (
+	StatusPassed = "passed" // 全部断言通过
+	StatusFailed = "failed" // 有断言未通过
+	StatusError  = "error"  // 无法评估，如生成失败或评审模型出错
+)
+
+// Assertion 对输出的一条预期
+type Assertion struct {
+	Type       string          `json:"type"`
+	Value      string          `json:"value,omitempty"`       // contains、not_contains 的文本，regex 的正则，llm_judge 的评分标准
+	IgnoreCase bool            `json:"ignore_case,omitempty"` // contains、not_contains 忽略大小写
+	Schema     json.RawMessage `json:"schema,omitempty"`      // json_schema 的 schema，为空时只检查是否为合法 JSON
+	Max        int             `json:"max,omitempty"`         // max_length 的字符数上限
+}
+
+// Result 单条断言的评估结果
+type Result struct {
+	Type    string `json:"type"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message,omitempty"` // 未通过的原因或评审意见
+	Error   string `json:"error,omitempty"`   // 无法评估的原因
+}
+
+// Outcome 一个用例的评估结果
+type Outcome struct {
+	Status string    `json:"status"`
+	Checks []*Result `json:"checks"`
+}
+
+// Verdict 评审模型的判断
+type Verdict struct {
+	Pass   bool   `json:"pass"`
+	Reason string `json:"reason"`
+}
+
+// Judge 调用评审模型按评分标准判断输出
+type Judge func(ctx context.Context, rubric, output string) (*Verdict, error)
+
+// Validate 检查断言的参数
+func (a *Assertion) Validate() error {
+	switch a.Type {
+	case TypeContains, TypeNotContains, TypeLLMJudge:
+		if strings.TrimSpace(a.Value) == "" {
+			return fmt.Errorf("%s 断言缺少 value", a.Type)
+		}
+	case TypeRegex:
+		if _, err := regexp.Compile(a.Value); err != nil || a.Value == "" {
+			return fmt.Errorf("无效的正则表达式: %s", a.Value)
+		}
+	case TypeJSONSchema:
+		if len(a.Schema) > 0 {
+			var schema map[string]interface{}
+			if err := json.Unmarshal(a.Schema, &schema); err != nil {
+				return errors.New("schema 必须是 JSON 对象")
+			}
+		}
+	case TypeMaxLength:
+		if a.Max <= 0 {
+			return errors.New("max_length 断言的 max 必须大于0")
+		}
+	default:
+		return fmt.Errorf("未知的断言类型: %s", a.Type)
+	}
+	return nil
+}
+
+// Evaluate 依次评估全部断言；llm_judge 断言在 judge 为 nil 时记为无法评估
+func Evaluate(ctx context.Context, assertions []*Assertion, output string, judge Judge) *Outcome {
+	outcome := &Outcome{Status: StatusPassed, Checks: make([]*Result, len(assertions))}
+	for i, a := range assertions {
+		var result *Result
+		if a.Type == TypeLLMJudge {
+			result = judgeOutput(ctx, a, output, judge)
+		} else {
+			result = Check(a, output)
+		}
+		outcome.Checks[i] = result
+
+		switch {
+		case result.Error != "":
+			outcome.Status = StatusError
+		case !result.Passed && outcome.Status == StatusPassed:
+			outcome.Status = StatusFailed
+		}
+	}
+	return outcome
+}
+
+// Check 评估不需要评审模型的断言
+func Check(a *Assertion, output string) *Result {
+	result := &Result{Type: a.Type}
+	switch a.Type {
+	case TypeContains, TypeNotContains:
+		text, value := output, a.Value
+		if a.IgnoreCase {
+			text, value = strings.ToLower(text), strings.ToLower(value)
+		}
+		found := strings.Contains(text, value)
+		result.Passed = found == (a.Type == TypeContains)
+		if !result.Passed && found {
+			result.Message = fmt.Sprintf("输出包含了“%s”", a.Value)
+		} else if !result.Passed {
+			result.Message = fmt.Sprintf("输出不包含“%s”", a.Value)
+		}
+	case TypeRegex:
+		re, err := regexp.Compile(a.Value)
+		if err != nil {
+			result.Error = fmt.Sprintf("无效的正则表达式: %s", a.Value)
+			break
+		}
+		result.Passed = re.MatchString(output)
+		if !result.Passed {
+			result.Message = fmt.Sprintf("输出不匹配 %s", a.Value)
+		}
+	case TypeJSONSchema:
+		problems, err := ValidateJSON(a.Schema, output)
+		if err != nil {
+			result.Error = err.Error()
+			break
+		}
+		result.Passed = len(problems) == 0
+		result.Message = strings.Join(problems, "；")
+	case TypeMaxLength:
+		length := utf8.RuneCountInString(output)
+		result.Passed = length <= a.Max
+		if !result.Passed {
+			result.Message = fmt.Sprintf("输出%d个字符，超过上限%d", length, a.Max)
+		}
+	case TypeLLMJudge:
+		result.Error = "llm_judge 断言需要评审模型"
+	default:
+		result.Error = fmt.Sprintf("未知的断言类型: %s", a.Type)
+	}
+	return result
+}
+
+// judgeOutput 调用评审模型评估 llm_judge 断言
+func judgeOutput(ctx context.Context, a *Assertion, output string, judge Judge) *Result {
+	result := &Result{Type: a.Type}
+	if judge == nil {
+		result.Error = "未配置评审模型"
+		return result
+	}
+
+	verdict, err := judge(ctx, a.Value, output)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Passed = verdict.Pass
+	result.Message = verdict.Reason
+	return result
+}
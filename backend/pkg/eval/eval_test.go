@@ -0,0 +1,136 @@
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	output := "本周完成了 Payment 模块重构，下周上线"
+
+	tests := []struct {
+		name      string
+		assertion *Assertion
+		passed    bool
+	}{
+		{"包含", &Assertion{Type: TypeContains, Value: "重构"}, true},
+		{"不包含", &Assertion{Type: TypeContains, Value: "延期"}, false},
+		{"区分大小写", &Assertion{Type: TypeContains, Value: "payment"}, false},
+		{"忽略大小写", &Assertion{Type: TypeContains, Value: "payment", IgnoreCase: true}, true},
+		{"排除的文本", &Assertion{Type: TypeNotContains, Value: "延期"}, true},
+		{"出现排除的文本", &Assertion{Type: TypeNotContains, Value: "上线"}, false},
+		{"正则", &Assertion{Type: TypeRegex, Value: `^本周.+上线$`}, true},
+		{"正则不匹配", &Assertion{Type: TypeRegex, Value: `\d+`}, false},
+		{"长度以字符计", &Assertion{Type: TypeMaxLength, Max: 25}, true},
+		{"超过长度", &Assertion{Type: TypeMaxLength, Max: 10}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Check(tt.assertion, output)
+			assert.Empty(t, result.Error)
+			assert.Equal(t, tt.passed, result.Passed)
+			assert.Equal(t, tt.passed, result.Message == "")
+		})
+	}
+}
+
+func TestValidateJSON(t *testing.T) {
+	schema := json.RawMessage(`{
+		"type": "object",
+		"required": ["title", "items"],
+		"additionalProperties": false,
+		"properties": {
+			"title": {"type": "string", "maxLength": 10},
+			"priority": {"enum": ["high", "low"]},
+			"items": {"type": "array", "minItems": 1, "items": {"type": "integer", "minimum": 0}}
+		}
+	}`)
+
+	problems, err := ValidateJSON(schema, "```json\n{\"title\": \"周报\", \"items\": [1, 2]}\n```")
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+
+	problems, err = ValidateJSON(schema, `{"title": "这是一个超过十个字的很长的标题", "priority": "medium", "items": [1.5, -1], "extra": true}`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"$: 不允许的字段 extra",
+		"$.items[0]: 类型应为integer",
+		"$.items[1]: 不能小于0",
+		"$.priority: 不是允许的值",
+		"$.title: 长度不能超过10",
+	}, problems)
+
+	problems, err = ValidateJSON(schema, `{"title": "周报"}`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"$: 缺少字段 items"}, problems)
+
+	// 未指定 schema 时只检查是否为合法 JSON
+	problems, err = ValidateJSON(nil, "[1, 2, 3]")
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+
+	problems, err = ValidateJSON(schema, "周报如下：{")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"输出不是合法的JSON"}, problems)
+
+	_, err = ValidateJSON(json.RawMessage(`[]`), "{}")
+	assert.Error(t, err)
+}
+
+func TestEvaluate(t *testing.T) {
+	assertions := []*Assertion{
+		{Type: TypeContains, Value: "周报"},
+		{Type: TypeLLMJudge, Value: "语气正式"},
+	}
+
+	var rubric string
+	judge := func(ctx context.Context, r, output string) (*Verdict, error) {
+		rubric = r
+		return &Verdict{Pass: false, Reason: "使用了口语"}, nil
+	}
+	outcome := Evaluate(context.Background(), assertions, "周报：搞定了", judge)
+	assert.Equal(t, StatusFailed, outcome.Status)
+	assert.Equal(t, "语气正式", rubric)
+	require.Len(t, outcome.Checks, 2)
+	assert.True(t, outcome.Checks[0].Passed)
+	assert.Equal(t, "使用了口语", outcome.Checks[1].Message)
+
+	// 评审模型出错时无法评估
+	outcome = Evaluate(context.Background(), assertions, "周报", func(ctx context.Context, rubric, output string) (*Verdict, error) {
+		return nil, errors.New("评审模型响应超时")
+	})
+	assert.Equal(t, StatusError, outcome.Status)
+	assert.Equal(t, "评审模型响应超时", outcome.Checks[1].Error)
+
+	outcome = Evaluate(context.Background(), assertions[:1], "周报", nil)
+	assert.Equal(t, StatusPassed, outcome.Status)
+}
+
+func TestAssertionValidate(t *testing.T) {
+	assert.NoError(t, (&Assertion{Type: TypeJSONSchema}).Validate())
+	assert.NoError(t, (&Assertion{Type: TypeRegex, Value: `\d+`}).Validate())
+	assert.Error(t, (&Assertion{Type: TypeRegex, Value: `(`}).Validate())
+	assert.Error(t, (&Assertion{Type: TypeContains}).Validate())
+	assert.Error(t, (&Assertion{Type: TypeMaxLength}).Validate())
+	assert.Error(t, (&Assertion{Type: TypeJSONSchema, Schema: json.RawMessage(`"object"`)}).Validate())
+	assert.Error(t, (&Assertion{Type: "similarity"}).Validate())
+}
+
+func TestParseVerdict(t *testing.T) {
+	verdict, err := ParseVerdict("```json\n{\"pass\": true, \"reason\": \"结构完整\"}\n```")
+	require.NoError(t, err)
+	assert.True(t, verdict.Pass)
+	assert.Equal(t, "结构完整", verdict.Reason)
+
+	_, err = ParseVerdict("通过")
+	assert.Error(t, err)
+	_, err = ParseVerdict(`{"reason": "结构完整"}`)
+	assert.Error(t, err)
+
+	assert.Contains(t, JudgePrompt("语气正式", "周报"), "## 评分标准\n\n语气正式")
+}
@@ -0,0 +1,42 @@
+package eval
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// judgeInstructions 评审模型的说明，要求以 JSON 回复判断结果
+const judgeInstructions = `你是一名严格的评审员，负责判断一段AI输出是否满足评分标准。
+只根据评分标准判断，不要考虑标准以外的因素。
+只回复一个JSON对象，不要包含其他内容，格式为：{"pass": true或false, "reason": "一句话说明理由"}`
+
+// JudgePrompt 生成发送给评审模型的提示词
+func JudgePrompt(rubric, output string) string {
+	var b strings.Builder
+	b.WriteString(judgeInstructions)
+	b.WriteString("\n\n## 评分标准\n\n")
+	b.WriteString(strings.TrimSpace(rubric))
+	b.WriteString("\n\n## 待评审的输出\n\n")
+	b.WriteString(output)
+	b.WriteString("\n")
+	return b.String()
+}
+
+// ParseVerdict 解析评审模型的回复，取回复中的第一个 JSON 对象
+func ParseVerdict(content string) (*Verdict, error) {
+	start := strings.Index(content, "{")
+	end := strings.LastIndex(content, "}")
+	if start < 0 || end < start {
+		return nil, errors.New("评审模型的回复不是JSON")
+	}
+
+	var verdict struct {
+		Pass   *bool  `json:"pass"`
+		Reason string `json:"reason"`
+	}
+	if err := json.Unmarshal([]byte(content[start:end+1]), &verdict); err != nil || verdict.Pass == nil {
+		return nil, errors.New("评审模型的回复缺少 pass 字段")
+	}
+	return &Verdict{Pass: *verdict.Pass, Reason: verdict.Reason}, nil
+}
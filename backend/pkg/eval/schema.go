@@ -0,0 +1,201 @@
+package eval
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidateJSON 解析输出中的 JSON 并按 schema 校验，返回不符合的原因；schema 为空时只检查是否为合法 JSON
+//
+// 支持 JSON Schema 的常用关键字：type、enum、const、properties、required、additionalProperties、
+// items、minItems、maxItems、minLength、maxLength、pattern、minimum、maximum。
+// 输出被 ``` 代码块包裹时取代码块中的内容。
+func ValidateJSON(rawSchema json.RawMessage, output string) ([]string, error) {
+	var value interface{}
+	if err := json.Unmarshal([]byte(extractJSON(output)), &value); err != nil {
+		return []string{"输出不是合法的JSON"}, nil
+	}
+	if len(rawSchema) == 0 {
+		return nil, nil
+	}
+
+	var schema map[string]interface{}
+	if err := json.Unmarshal(rawSchema, &schema); err != nil {
+		return nil, errors.New("schema 必须是 JSON 对象")
+	}
+
+	var problems []string
+	validateValue(schema, value, "$", &problems)
+	return problems, nil
+}
+
+// extractJSON 去掉输出首尾的空白和 Markdown 代码块标记
+func extractJSON(output string) string {
+	output = strings.TrimSpace(output)
+	if !strings.HasPrefix(output, "```") {
+		return output
+	}
+	if i := strings.Index(output, "\n"); i >= 0 {
+		output = output[i+1:]
+	} else {
+		output = strings.TrimPrefix(output, "```")
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(output), "```"))
+}
+
+// validateValue 按 schema 校验值，把不符合的原因追加到 problems
+func validateValue(schema map[string]interface{}, value interface{}, path string, problems *[]string) {
+	report := func(format string, args ...interface{}) {
+		*problems = append(*problems, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if t, ok := schema["type"]; ok && !matchesType(t, value) {
+		report("类型应为%s", describeType(t))
+		return
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && !containsValue(enum, value) {
+		report("不是允许的值")
+	}
+	if c, ok := schema["const"]; ok && !reflect.DeepEqual(c, value) {
+		report("应为固定值 %v", c)
+	}
+
+	switch v := value.(type) {
+	case string:
+		length := utf8.RuneCountInString(v)
+		if min, ok := number(schema["minLength"]); ok && float64(length) < min {
+			report("长度不能少于%v", min)
+		}
+		if max, ok := number(schema["maxLength"]); ok && float64(length) > max {
+			report("长度不能超过%v", max)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(pattern); err != nil || !re.MatchString(v) {
+				report("不匹配 %s", pattern)
+			}
+		}
+	case float64:
+		if min, ok := number(schema["minimum"]); ok && v < min {
+			report("不能小于%v", min)
+		}
+		if max, ok := number(schema["maximum"]); ok && v > max {
+			report("不能大于%v", max)
+		}
+	case []interface{}:
+		if min, ok := number(schema["minItems"]); ok && float64(len(v)) < min {
+			report("元素不能少于%v个", min)
+		}
+		if max, ok := number(schema["maxItems"]); ok && float64(len(v)) > max {
+			report("元素不能超过%v个", max)
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				validateValue(items, item, fmt.Sprintf("%s[%d]", path, i), problems)
+			}
+		}
+	case map[string]interface{}:
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, name := range required {
+				if key, ok := name.(string); ok {
+					if _, exists := v[key]; !exists {
+						report("缺少字段 %s", key)
+					}
+				}
+			}
+		}
+
+		properties, _ := schema["properties"].(map[string]interface{})
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if property, ok := properties[key].(map[string]interface{}); ok {
+				validateValue(property, v[key], path+"."+key, problems)
+			} else if additional, ok := schema["additionalProperties"].(bool); ok && !additional {
+				report("不允许的字段 %s", key)
+			}
+		}
+	}
+}
+
+// matchesType 判断值是否符合 type 关键字（字符串或字符串数组）
+func matchesType(t interface{}, value interface{}) bool {
+	switch t := t.(type) {
+	case string:
+		return matchesTypeName(t, value)
+	case []interface{}:
+		for _, name := range t {
+			if s, ok := name.(string); ok && matchesTypeName(s, value) {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+// matchesTypeName 判断值是否为指定的 JSON 类型
+func matchesTypeName(name string, value interface{}) bool {
+	switch name {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	default:
+		return true
+	}
+}
+
+// describeType 描述 type 关键字
+func describeType(t interface{}) string {
+	if names, ok := t.([]interface{}); ok {
+		parts := make([]string, len(names))
+		for i, name := range names {
+			parts[i] = fmt.Sprint(name)
+		}
+		return strings.Join(parts, "或")
+	}
+	return fmt.Sprint(t)
+}
+
+// containsValue 判断值是否在列表中
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// number 读取 schema 中的数值
+func number(v interface{}) (float64, bool) {
+	n, ok := v.(float64)
+	return n, ok
+}
@@ -0,0 +1,91 @@
+package jsonpatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergePatch(t *testing.T) {
+	// RFC 7386 附录A中的示例
+	cases := []struct {
+		doc, patch, want string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, tc := range cases {
+		got, err := MergePatch([]byte(tc.doc), []byte(tc.patch))
+		require.NoError(t, err)
+		assert.JSONEq(t, tc.want, string(got), "%s + %s", tc.doc, tc.patch)
+	}
+
+	_, err := MergePatch([]byte(`{}`), []byte(`{`))
+	assert.Error(t, err)
+}
+
+func TestApplyPatch(t *testing.T) {
+	cases := []struct {
+		name, doc, patch, want string
+	}{
+		{"添加对象成员", `{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`},
+		{"插入数组元素", `{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{"追加数组元素", `{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc"]}]`, `{"foo":["bar",["abc"]]}`},
+		{"删除对象成员", `{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+		{"删除数组元素", `{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		{"替换值", `{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{"替换为null", `{"baz":"qux"}`, `[{"op":"replace","path":"/baz","value":null}]`, `{"baz":null}`},
+		{"移动值", `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`, `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{"移动数组元素", `{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`},
+		{"复制值", `{"a":{"b":1}}`, `[{"op":"copy","from":"/a","path":"/c"},{"op":"replace","path":"/c/b","value":2}]`, `{"a":{"b":1},"c":{"b":2}}`},
+		{"转义路径", `{"a/b":1,"m~n":2}`, `[{"op":"remove","path":"/a~1b"},{"op":"test","path":"/m~0n","value":2}]`, `{"m~n":2}`},
+		{"测试通过", `{"baz":"qux","foo":["a",2,"c"]}`, `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`, `{"baz":"qux","foo":["a",2,"c"]}`},
+		{"替换根节点", `{"a":1}`, `[{"op":"replace","path":"","value":{"b":2}}]`, `{"b":2}`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ApplyPatch([]byte(tc.doc), []byte(tc.patch))
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.want, string(got))
+		})
+	}
+}
+
+func TestApplyPatchErrors(t *testing.T) {
+	cases := []struct {
+		name, doc, patch string
+	}{
+		{"路径不存在", `{"foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`},
+		{"替换不存在的成员", `{"foo":"bar"}`, `[{"op":"replace","path":"/baz","value":1}]`},
+		{"父节点不存在", `{"foo":"bar"}`, `[{"op":"add","path":"/baz/bat","value":"qux"}]`},
+		{"数组下标越界", `{"foo":["bar"]}`, `[{"op":"add","path":"/foo/2","value":"qux"}]`},
+		{"数组下标含前导零", `{"foo":["a","b"]}`, `[{"op":"remove","path":"/foo/01"}]`},
+		{"缺少value", `{"foo":"bar"}`, `[{"op":"add","path":"/baz"}]`},
+		{"不支持的操作", `{"foo":"bar"}`, `[{"op":"merge","path":"/foo","value":1}]`},
+		{"无效的路径", `{"foo":"bar"}`, `[{"op":"remove","path":"foo"}]`},
+		{"移动到子节点", `{"a":{"b":{}}}`, `[{"op":"move","from":"/a","path":"/a/b/c"}]`},
+		{"补丁不是数组", `{}`, `{"op":"add"}`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ApplyPatch([]byte(tc.doc), []byte(tc.patch))
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := ApplyPatch([]byte(`{"baz":"qux"}`), []byte(`[{"op":"add","path":"/x","value":1},{"op":"test","path":"/baz","value":"bar"}]`))
+	assert.ErrorIs(t, err, ErrTestFailed)
+}
@@ -0,0 +1,43 @@
+// Package jsonpatch 实现 JSON Merge Patch（RFC 7386）和 JSON Patch（RFC 6902）
+package jsonpatch
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MergePatch 将 JSON Merge Patch 应用到文档上
+//
+// 补丁中的对象按键递归合并，值为 null 的键会被删除，其他类型的值（包括数组）直接替换。
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	var target interface{}
+	if err := json.Unmarshal(doc, &target); err != nil {
+		return nil, fmt.Errorf("文档格式错误: %w", err)
+	}
+	var p interface{}
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, fmt.Errorf("补丁格式错误: %w", err)
+	}
+	return json.Marshal(mergeValue(target, p))
+}
+
+// mergeValue 按 RFC 7386 的 MergePatch 算法合并
+func mergeValue(target, patch interface{}) interface{} {
+	patchObj, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObj, ok := target.(map[string]interface{})
+	if !ok {
+		targetObj = make(map[string]interface{})
+	}
+	for key, value := range patchObj {
+		if value == nil {
+			delete(targetObj, key)
+			continue
+		}
+		targetObj[key] = mergeValue(targetObj[key], value)
+	}
+	return targetObj
+}
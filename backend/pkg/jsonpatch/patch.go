@@ -0,0 +1,276 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ErrTestFailed test 操作未通过
+var ErrTestFailed = errors.New("test 操作未通过")
+
+// Operation JSON Patch 操作
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// ApplyPatch 将 JSON Patch 操作序列依次应用到文档上，任一操作失败时返回错误且不产生部分结果
+func ApplyPatch(doc, patch []byte) ([]byte, error) {
+	var root interface{}
+	if err := json.Unmarshal(doc, &root); err != nil {
+		return nil, fmt.Errorf("文档格式错误: %w", err)
+	}
+	var ops []Operation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, fmt.Errorf("补丁格式错误: %w", err)
+	}
+
+	for i, op := range ops {
+		var err error
+		root, err = applyOperation(root, op)
+		if err != nil {
+			return nil, fmt.Errorf("第%d个操作（%s %s）失败: %w", i+1, op.Op, op.Path, err)
+		}
+	}
+	return json.Marshal(root)
+}
+
+// applyOperation 应用单个操作并返回新的根节点
+func applyOperation(root interface{}, op Operation) (interface{}, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add":
+		value, err := operationValue(op)
+		if err != nil {
+			return nil, err
+		}
+		return add(root, path, value)
+	case "remove":
+		root, _, err := remove(root, path)
+		return root, err
+	case "replace":
+		value, err := operationValue(op)
+		if err != nil {
+			return nil, err
+		}
+		if root, _, err = remove(root, path); err != nil {
+			return nil, err
+		}
+		return add(root, path, value)
+	case "move":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		if len(path) > len(from) && reflect.DeepEqual(path[:len(from)], from) {
+			return nil, errors.New("不能移动到自身的子节点")
+		}
+		root, value, err := remove(root, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(root, path, value)
+	case "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := get(root, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(root, path, deepCopy(value))
+	case "test":
+		value, err := operationValue(op)
+		if err != nil {
+			return nil, err
+		}
+		actual, err := get(root, path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(actual, value) {
+			return nil, ErrTestFailed
+		}
+		return root, nil
+	default:
+		return nil, fmt.Errorf("不支持的操作 %q", op.Op)
+	}
+}
+
+// operationValue 解析操作中的 value（必填，可以为 null）
+func operationValue(op Operation) (interface{}, error) {
+	if len(op.Value) == 0 {
+		return nil, errors.New("缺少 value")
+	}
+	var value interface{}
+	if err := json.Unmarshal(op.Value, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// parsePointer 解析 JSON Pointer（RFC 6901）
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("无效的路径 %q", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// get 获取路径上的值
+func get(node interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			value, ok := n[token]
+			if !ok {
+				return nil, fmt.Errorf("路径不存在: %s", token)
+			}
+			node = value
+		case []interface{}:
+			index, err := arrayIndex(token, len(n)-1)
+			if err != nil {
+				return nil, err
+			}
+			node = n[index]
+		default:
+			return nil, fmt.Errorf("路径不存在: %s", token)
+		}
+	}
+	return node, nil
+}
+
+// add 在路径上添加值：对象成员存在时替换，数组按下标插入（"-" 表示末尾）
+func add(root interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	parent, err := get(root, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	token := path[len(path)-1]
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		p[token] = value
+		return root, nil
+	case []interface{}:
+		index := len(p)
+		if token != "-" {
+			if index, err = arrayIndex(token, len(p)); err != nil {
+				return nil, err
+			}
+		}
+		updated := append(p[:index:index], append([]interface{}{value}, p[index:]...)...)
+		return replaceAt(root, path[:len(path)-1], updated)
+	default:
+		return nil, fmt.Errorf("路径不存在: %s", token)
+	}
+}
+
+// remove 删除路径上的值，返回新的根节点和被删除的值
+func remove(root interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, root, nil
+	}
+	parent, err := get(root, path[:len(path)-1])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	token := path[len(path)-1]
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		value, ok := p[token]
+		if !ok {
+			return nil, nil, fmt.Errorf("路径不存在: %s", token)
+		}
+		delete(p, token)
+		return root, value, nil
+	case []interface{}:
+		index, err := arrayIndex(token, len(p)-1)
+		if err != nil {
+			return nil, nil, err
+		}
+		value := p[index]
+		updated := append(p[:index:index], p[index+1:]...)
+		root, err = replaceAt(root, path[:len(path)-1], updated)
+		return root, value, err
+	default:
+		return nil, nil, fmt.Errorf("路径不存在: %s", token)
+	}
+}
+
+// replaceAt 用新值替换路径上的节点（用于数组长度变化后回写）
+func replaceAt(root interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	parent, err := get(root, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	token := path[len(path)-1]
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		p[token] = value
+	case []interface{}:
+		index, err := arrayIndex(token, len(p)-1)
+		if err != nil {
+			return nil, err
+		}
+		p[index] = value
+	}
+	return root, nil
+}
+
+// arrayIndex 解析数组下标并检查范围 [0, max]
+func arrayIndex(token string, max int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("无效的数组下标 %q", token)
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || index > max {
+		return 0, fmt.Errorf("无效的数组下标 %q", token)
+	}
+	return index, nil
+}
+
+// deepCopy 复制 JSON 值，避免 copy 操作后两处共享同一对象
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			copied[key] = deepCopy(item)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = deepCopy(item)
+		}
+		return copied
+	default:
+		return v
+	}
+}
@@ -0,0 +1,156 @@
+// Package lint 基于规则检查六要素提示词的质量，给出评分和逐字段的改进建议
+package lint
+
+import (
+	"fmt"
+	"sort"
+)
+
+// 问题严重程度
+const (
+	SeverityError   = "error"   // 会明显影响输出质量，应当修改
+	SeverityWarning = "warning" // 可能影响输出质量，建议修改
+	SeverityInfo    = "info"    // 可选的改进建议
+)
+
+// 各严重程度在评分中扣除的分数
+var severityPenalty = map[string]int{
+	SeverityError:   15,
+	SeverityWarning: 5,
+	SeverityInfo:    1,
+}
+
+// 六要素字段
+const (
+	FieldSubject        = "subject"
+	FieldTaskGoal       = "task_goal"
+	FieldAIRole         = "ai_role"
+	FieldMyRole         = "my_role"
+	FieldKeyInfo        = "key_info"
+	FieldBehaviorRule   = "behavior_rule"
+	FieldDeliveryFormat = "delivery_format"
+)
+
+// Fields 参与检查的字段（按模板顺序）
+var Fields = []string{
+	FieldSubject, FieldTaskGoal, FieldAIRole, FieldMyRole, FieldKeyInfo, FieldBehaviorRule, FieldDeliveryFormat,
+}
+
+// Document 待检查的提示词
+type Document struct {
+	Fields    map[string]string // 字段名到内容
+	Variables []string          // 已定义的变量名，为 nil 时不检查变量是否已定义
+}
+
+// Finding 检查发现的问题
+type Finding struct {
+	Rule       string `json:"rule"`
+	Field      string `json:"field,omitempty"`
+	Severity   string `json:"severity"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion,omitempty"`
+	Excerpt    string `json:"excerpt,omitempty"` // 命中的原文片段
+}
+
+// Report 检查结果
+type Report struct {
+	Score    int        `json:"score"` // 0-100
+	Errors   int        `json:"errors"`
+	Warnings int        `json:"warnings"`
+	Infos    int        `json:"infos"`
+	Findings []*Finding `json:"findings"`
+}
+
+// Rule 检查规则
+type Rule struct {
+	ID          string
+	Description string
+	Check       func(doc *Document) []*Finding
+}
+
+// Linter 提示词检查器
+type Linter struct {
+	rules []*Rule
+}
+
+// New 创建检查器，enabled 按规则ID覆盖默认的启用状态（未列出的规则默认启用）
+func New(enabled map[string]bool) (*Linter, error) {
+	for id := range enabled {
+		if findRule(id) == nil {
+			return nil, fmt.Errorf("未知的检查规则: %s", id)
+		}
+	}
+
+	linter := &Linter{}
+	for _, rule := range rules {
+		if on, ok := enabled[rule.ID]; ok && !on {
+			continue
+		}
+		linter.rules = append(linter.rules, rule)
+	}
+	return linter, nil
+}
+
+// Rules 获取已启用的规则
+func (l *Linter) Rules() []*Rule {
+	return l.rules
+}
+
+// Lint 检查提示词并计算评分
+func (l *Linter) Lint(doc *Document) *Report {
+	report := &Report{Score: 100, Findings: []*Finding{}}
+	for _, rule := range l.rules {
+		for _, finding := range rule.Check(doc) {
+			finding.Rule = rule.ID
+			report.Findings = append(report.Findings, finding)
+		}
+	}
+
+	// 按严重程度和字段顺序排列
+	order := make(map[string]int, len(Fields))
+	for i, field := range Fields {
+		order[field] = i
+	}
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if severityPenalty[a.Severity] != severityPenalty[b.Severity] {
+			return severityPenalty[a.Severity] > severityPenalty[b.Severity]
+		}
+		return order[a.Field] < order[b.Field]
+	})
+
+	for _, finding := range report.Findings {
+		switch finding.Severity {
+		case SeverityError:
+			report.Errors++
+		case SeverityWarning:
+			report.Warnings++
+		default:
+			report.Infos++
+		}
+		report.Score -= severityPenalty[finding.Severity]
+	}
+	if report.Score < 0 {
+		report.Score = 0
+	}
+	return report
+}
+
+// RuleIDs 获取全部规则ID
+func RuleIDs() []string {
+	ids := make([]string, len(rules))
+	for i, rule := range rules {
+		ids[i] = rule.ID
+	}
+	return ids
+}
+
+// findRule 根据ID查找规则
+func findRule(id string) *Rule {
+	for _, rule := range rules {
+		if rule.ID == id {
+			return rule
+		}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// goodDocument 各项检查都能通过的提示词
+func goodDocument() *Document {
+	return &Document{
+		Fields: map[string]string{
+			FieldSubject:        "周报生成",
+			FieldTaskGoal:       "根据本周的工作记录整理一份周报，突出进展和风险",
+			FieldAIRole:         "你是一位有10年经验的项目经理",
+			FieldMyRole:         "我是研发团队负责人，周报面向公司管理层",
+			FieldKeyInfo:        "本周完成了支付模块重构，{{week}}的上线计划推迟两天",
+			FieldBehaviorRule:   "不要编造数据\n使用正式的书面语",
+			FieldDeliveryFormat: "Markdown格式，包含本周进展、风险、下周计划三个部分",
+		},
+		Variables: []string{"week"},
+	}
+}
+
+// findingsOf 获取指定规则的检查结果
+func findingsOf(report *Report, rule string) []*Finding {
+	var findings []*Finding
+	for _, finding := range report.Findings {
+		if finding.Rule == rule {
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+func newLinter(t *testing.T) *Linter {
+	linter, err := New(nil)
+	require.NoError(t, err)
+	return linter
+}
+
+func TestLint_GoodDocument(t *testing.T) {
+	report := newLinter(t).Lint(goodDocument())
+	assert.Empty(t, report.Findings)
+	assert.Equal(t, 100, report.Score)
+}
+
+func TestLint_MissingAndShortSections(t *testing.T) {
+	doc := goodDocument()
+	doc.Fields[FieldTaskGoal] = "  "
+	doc.Fields[FieldBehaviorRule] = ""
+	doc.Fields[FieldDeliveryFormat] = "表格"
+
+	report := newLinter(t).Lint(doc)
+
+	missing := findingsOf(report, RuleMissingSection)
+	require.Len(t, missing, 2)
+	assert.Equal(t, FieldTaskGoal, missing[0].Field)
+	assert.Equal(t, SeverityError, missing[0].Severity)
+	assert.Equal(t, FieldBehaviorRule, missing[1].Field)
+	assert.Equal(t, SeverityInfo, missing[1].Severity)
+
+	short := findingsOf(report, RuleShortSection)
+	require.Len(t, short, 1)
+	assert.Equal(t, FieldDeliveryFormat, short[0].Field)
+	assert.Equal(t, "交付格式只有2个字，内容可能不足", short[0].Message)
+
+	assert.Equal(t, 1, report.Errors)
+	assert.Equal(t, 1, report.Warnings)
+	assert.Equal(t, 1, report.Infos)
+	assert.Equal(t, 100-15-5-1, report.Score)
+
+	// 按严重程度排序
+	assert.Equal(t, SeverityError, report.Findings[0].Severity)
+	assert.Equal(t, SeverityInfo, report.Findings[len(report.Findings)-1].Severity)
+}
+
+func TestLint_VagueWording(t *testing.T) {
+	doc := goodDocument()
+	doc.Fields[FieldBehaviorRule] = "回答尽量简洁，适当使用例子，尽可能引用数据"
+	doc.Fields[FieldKeyInfo] = "Please try to cover pricing, features etc."
+
+	findings := findingsOf(newLinter(t).Lint(doc), RuleVagueWording)
+	require.Len(t, findings, 3)
+	assert.Equal(t, FieldKeyInfo, findings[0].Field)
+	assert.Equal(t, "关键信息中使用了含糊的措辞“try to”", findings[0].Message)
+	assert.Equal(t, FieldBehaviorRule, findings[1].Field)
+	assert.Equal(t, "行为规则中使用了含糊的措辞“尽量”", findings[1].Message)
+	assert.Equal(t, "回答尽量简洁，适当使用例子，…", findings[1].Excerpt)
+	assert.Equal(t, "行为规则中使用了含糊的措辞“适当”", findings[2].Message)
+}
+
+func TestLint_ContradictoryRules(t *testing.T) {
+	doc := goodDocument()
+	doc.Fields[FieldBehaviorRule] = "1. 必须使用表格展示数据\n2. 不要使用表格\n3. 回答要简洁\n4. 解释要详细"
+
+	findings := findingsOf(newLinter(t).Lint(doc), RuleContradictoryRules)
+	require.Len(t, findings, 2)
+	assert.Equal(t, SeverityError, findings[0].Severity)
+	assert.Equal(t, "必须使用表格展示数据 / 不要使用表格", findings[0].Excerpt)
+	assert.Equal(t, SeverityWarning, findings[1].Severity)
+	assert.Equal(t, "行为规则可能矛盾：同时要求“简洁”和“详细”", findings[1].Message)
+}
+
+func TestLint_FormatAndRoles(t *testing.T) {
+	doc := goodDocument()
+	doc.Fields[FieldDeliveryFormat] = "写得清楚明白一点就行"
+	doc.Fields[FieldAIRole] = "你是一个乐于助人的助手"
+	doc.Fields[FieldMyRole] = "我负责这个项目"
+
+	report := newLinter(t).Lint(doc)
+	assert.Len(t, findingsOf(report, RuleUnstructuredFormat), 1)
+	assert.Len(t, findingsOf(report, RuleRoleExpertise), 1)
+
+	audience := findingsOf(report, RuleRoleAudience)
+	require.Len(t, audience, 1)
+	assert.Equal(t, FieldMyRole, audience[0].Field)
+
+	doc.Fields[FieldDeliveryFormat] = "- 结论\n- 依据"
+	assert.Empty(t, findingsOf(newLinter(t).Lint(doc), RuleUnstructuredFormat))
+}
+
+func TestLint_UnresolvedVariables(t *testing.T) {
+	doc := goodDocument()
+	doc.Fields[FieldTaskGoal] = "为{{product}}撰写介绍，参考{{ 1st }}和{{week}}的数据，截止到{{deadline"
+	doc.Variables = []string{"week", "tone"}
+
+	findings := findingsOf(newLinter(t).Lint(doc), RuleUnresolvedVariables)
+	require.Len(t, findings, 4)
+	assert.Equal(t, "变量占位符 {{ 1st }} 格式错误", findings[0].Message)
+	assert.Equal(t, "任务目标中有未闭合的变量占位符", findings[1].Message)
+	assert.Equal(t, "变量 product 没有定义", findings[2].Message)
+	assert.Equal(t, FieldTaskGoal, findings[2].Field)
+	assert.Equal(t, "变量 tone 已定义但没有使用", findings[3].Message)
+
+	// 未提供变量定义时只检查格式
+	doc.Variables = nil
+	assert.Len(t, findingsOf(newLinter(t).Lint(doc), RuleUnresolvedVariables), 2)
+}
+
+func TestNew_RuleToggles(t *testing.T) {
+	linter, err := New(map[string]bool{RuleRoleAudience: false, RuleVagueWording: true})
+	require.NoError(t, err)
+	assert.Len(t, linter.Rules(), len(RuleIDs())-1)
+
+	doc := goodDocument()
+	doc.Fields[FieldMyRole] = "我负责这个项目"
+	assert.Empty(t, linter.Lint(doc).Findings)
+
+	_, err = New(map[string]bool{"unknown": false})
+	assert.EqualError(t, err, "未知的检查规则: unknown")
+}
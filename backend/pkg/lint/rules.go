@@ -0,0 +1,473 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"cese-backend/pkg/prompt"
+)
+
+// 规则ID
+const (
+	RuleMissingSection      = "missing_section"
+	RuleShortSection        = "short_section"
+	RuleVagueWording        = "vague_wording"
+	RuleContradictoryRules  = "contradictory_rules"
+	RuleUnstructuredFormat  = "unstructured_format"
+	RuleRoleExpertise       = "role_expertise"
+	RuleRoleAudience        = "role_audience"
+	RuleUnresolvedVariables = "unresolved_variables"
+)
+
+// rules 全部检查规则（按执行顺序）
+var rules = []*Rule{
+	{ID: RuleMissingSection, Description: "段落为空", Check: checkMissingSection},
+	{ID: RuleShortSection, Description: "段落内容过短", Check: checkShortSection},
+	{ID: RuleVagueWording, Description: "含糊的措辞", Check: checkVagueWording},
+	{ID: RuleContradictoryRules, Description: "相互矛盾的行为规则", Check: checkContradictoryRules},
+	{ID: RuleUnstructuredFormat, Description: "交付格式缺少结构", Check: checkUnstructuredFormat},
+	{ID: RuleRoleExpertise, Description: "AI角色缺少专业背景", Check: checkRoleExpertise},
+	{ID: RuleRoleAudience, Description: "未说明受众", Check: checkRoleAudience},
+	{ID: RuleUnresolvedVariables, Description: "变量占位符错误或未定义", Check: checkUnresolvedVariables},
+}
+
+// fieldLabels 字段的中文名称
+var fieldLabels = map[string]string{
+	FieldSubject:        "主题",
+	FieldTaskGoal:       "任务目标",
+	FieldAIRole:         "AI的角色",
+	FieldMyRole:         "我的角色",
+	FieldKeyInfo:        "关键信息",
+	FieldBehaviorRule:   "行为规则",
+	FieldDeliveryFormat: "交付格式",
+}
+
+// missingSeverity 字段为空时的严重程度
+var missingSeverity = map[string]string{
+	FieldSubject:        SeverityError,
+	FieldTaskGoal:       SeverityError,
+	FieldAIRole:         SeverityWarning,
+	FieldDeliveryFormat: SeverityWarning,
+	FieldMyRole:         SeverityInfo,
+	FieldKeyInfo:        SeverityInfo,
+	FieldBehaviorRule:   SeverityInfo,
+}
+
+// missingSuggestions 字段为空时的建议
+var missingSuggestions = map[string]string{
+	FieldSubject:        "用一句话概括这个提示词的用途，便于查找和复用",
+	FieldTaskGoal:       "说明要完成什么任务、期望达到什么结果",
+	FieldAIRole:         "指定AI扮演的身份，如“资深数据分析师”",
+	FieldMyRole:         "说明你的身份和背景，便于AI调整表达方式",
+	FieldKeyInfo:        "提供完成任务所需的背景资料、数据或约束",
+	FieldBehaviorRule:   "列出AI需要遵守的规则，如语气、禁止事项",
+	FieldDeliveryFormat: "说明输出的结构、长度和格式，如“Markdown表格，不超过300字”",
+}
+
+// minLengths 各字段建议的最少字符数（不含空白）
+var minLengths = map[string]int{
+	FieldTaskGoal:       10,
+	FieldAIRole:         6,
+	FieldMyRole:         4,
+	FieldKeyInfo:        10,
+	FieldBehaviorRule:   6,
+	FieldDeliveryFormat: 6,
+}
+
+// vagueWord 含糊的措辞及替代建议
+type vagueWord struct {
+	pattern    *regexp.Regexp
+	suggestion string
+}
+
+// vagueWords 含糊的措辞（英文按单词边界匹配，大小写不敏感）
+var vagueWords = []vagueWord{
+	{regexp.MustCompile(`尽可能|尽量`), "说明必须达到的标准，或给出可以放宽的条件"},
+	{regexp.MustCompile(`适当|适度|酌情|视情况`), "给出具体的数量、程度或判断条件"},
+	{regexp.MustCompile(`一些|若干|少许`), "给出具体的数量或范围"},
+	{regexp.MustCompile(`大概|大约|差不多`), "给出确切的数值或范围"},
+	{regexp.MustCompile(`等等|之类`), "列出完整的项目，或说明判断标准"},
+	{regexp.MustCompile(`合适的|比较好的?`), "说明“合适”“好”的具体标准"},
+	{regexp.MustCompile(`(?i)\b(as appropriate|if possible|try to|maybe|etc)\b`), "replace with a concrete requirement"},
+}
+
+// 行为规则中的否定和肯定标记（较长的标记在前）
+var (
+	negativeMarkers = []string{"不允许", "请不要", "不要", "不能", "不得", "不可", "禁止", "避免", "切勿", "请勿", "无需", "不必", "不用", "never ", "don't ", "do not "}
+	positiveMarkers = []string{"一定要", "必须", "务必", "需要", "应该", "应当", "总是", "始终", "要", "请", "always ", "must "}
+)
+
+// opposites 同时要求时可能矛盾的风格
+var opposites = [][2]string{
+	{"简洁", "详细"},
+	{"简短", "详尽"},
+	{"简短", "详细"},
+	{"正式", "口语化"},
+	{"正式", "随意"},
+	{"严肃", "幽默"},
+}
+
+// clauseSeparator 行为规则的分句符
+var clauseSeparator = regexp.MustCompile(`[\n。；;！!]+`)
+
+// listMarker 行首的列表标记
+var listMarker = regexp.MustCompile(`^\s*(?:[-*•·]|\d+[.、)）]|[（(]\d+[)）])\s*`)
+
+// structureMarkers 交付格式中表示结构的关键词（小写）
+var structureMarkers = []string{
+	"表格", "json", "markdown", "xml", "yaml", "csv", "html", "列表", "清单", "分点", "条目", "要点", "大纲",
+	"标题", "段落", "章节", "步骤", "字数", "字以内", "字以上", "模板", "格式如下", "结构", "代码块", "#",
+}
+
+// expertiseMarkers AI角色中表示专业背景的关键词（小写）
+var expertiseMarkers = []string{
+	"专家", "资深", "专业", "经验", "擅长", "精通", "熟悉", "从业", "顾问", "工程师", "老师", "教师", "教授",
+	"分析师", "医生", "律师", "编辑", "设计师", "经理", "架构师", "研究员", "作家", "写手", "策划",
+	"expert", "senior", "experienced", "specialist", "professional",
+}
+
+// audienceMarkers 表示受众的关键词（小写）
+var audienceMarkers = []string{
+	"面向", "受众", "读者", "听众", "观众", "用户", "客户", "对象", "新手", "初学者", "小白", "管理层", "领导",
+	"老板", "学生", "孩子", "儿童", "家长", "同事", "团队", "开发者", "程序员", "消费者", "粉丝",
+	"audience", "reader", "user", "customer", "beginner",
+}
+
+// placeholderPattern 匹配 {{...}} 形式的占位符
+var placeholderPattern = regexp.MustCompile(`\{\{[^{}]*\}\}`)
+
+// checkMissingSection 检查空段落
+func checkMissingSection(doc *Document) []*Finding {
+	var findings []*Finding
+	for _, field := range Fields {
+		if strings.TrimSpace(doc.Fields[field]) != "" {
+			continue
+		}
+		findings = append(findings, &Finding{
+			Field:      field,
+			Severity:   missingSeverity[field],
+			Message:    fieldLabels[field] + "为空",
+			Suggestion: missingSuggestions[field],
+		})
+	}
+	return findings
+}
+
+// checkShortSection 检查内容过短的段落
+func checkShortSection(doc *Document) []*Finding {
+	var findings []*Finding
+	for _, field := range Fields {
+		min, ok := minLengths[field]
+		if !ok {
+			continue
+		}
+		length := visibleLength(doc.Fields[field])
+		if length == 0 || length >= min {
+			continue
+		}
+		findings = append(findings, &Finding{
+			Field:      field,
+			Severity:   SeverityWarning,
+			Message:    fmt.Sprintf("%s只有%d个字，内容可能不足", fieldLabels[field], length),
+			Suggestion: missingSuggestions[field],
+			Excerpt:    strings.TrimSpace(doc.Fields[field]),
+		})
+	}
+	return findings
+}
+
+// checkVagueWording 检查含糊的措辞，每个字段中同一类措辞只报告一次
+func checkVagueWording(doc *Document) []*Finding {
+	var findings []*Finding
+	for _, field := range Fields[1:] {
+		text := doc.Fields[field]
+		for _, word := range vagueWords {
+			loc := word.pattern.FindStringIndex(text)
+			if loc == nil {
+				continue
+			}
+			findings = append(findings, &Finding{
+				Field:      field,
+				Severity:   SeverityWarning,
+				Message:    fmt.Sprintf("%s中使用了含糊的措辞“%s”", fieldLabels[field], text[loc[0]:loc[1]]),
+				Suggestion: word.suggestion,
+				Excerpt:    excerpt(text, loc[0], loc[1]),
+			})
+		}
+	}
+	return findings
+}
+
+// ruleClause 行为规则中的一条要求
+type ruleClause struct {
+	text     string
+	object   string // 去掉肯定或否定标记后的内容
+	negative bool   // 没有标记的要求视为肯定
+}
+
+// checkContradictoryRules 检查行为规则中相互矛盾的要求
+func checkContradictoryRules(doc *Document) []*Finding {
+	var clauses []*ruleClause
+	for _, part := range clauseSeparator.Split(doc.Fields[FieldBehaviorRule], -1) {
+		text := strings.TrimSpace(listMarker.ReplaceAllString(part, ""))
+		if text != "" {
+			clauses = append(clauses, parseClause(text))
+		}
+	}
+
+	var findings []*Finding
+	report := func(a, b *ruleClause, severity, message string) {
+		findings = append(findings, &Finding{
+			Field:      FieldBehaviorRule,
+			Severity:   severity,
+			Message:    message,
+			Suggestion: "删除其中一条，或说明各自适用的场景",
+			Excerpt:    a.text + " / " + b.text,
+		})
+	}
+
+	for i, a := range clauses {
+		for _, b := range clauses[i+1:] {
+			// 同一内容既要求又禁止
+			if a.negative != b.negative && sameObject(a.object, b.object) {
+				report(a, b, SeverityError, "行为规则相互矛盾：同一内容既被要求又被禁止")
+				continue
+			}
+			// 同时要求相反的风格
+			if a.negative || b.negative {
+				continue
+			}
+			for _, pair := range opposites {
+				if (strings.Contains(a.text, pair[0]) && strings.Contains(b.text, pair[1])) ||
+					(strings.Contains(a.text, pair[1]) && strings.Contains(b.text, pair[0])) {
+					report(a, b, SeverityWarning, fmt.Sprintf("行为规则可能矛盾：同时要求“%s”和“%s”", pair[0], pair[1]))
+					break
+				}
+			}
+		}
+	}
+	return findings
+}
+
+// parseClause 识别一条要求的肯定或否定标记
+func parseClause(text string) *ruleClause {
+	clause := &ruleClause{text: text, object: normalizeObject(text)}
+	lower := strings.ToLower(text)
+	for _, group := range []struct {
+		markers  []string
+		negative bool
+	}{{negativeMarkers, true}, {positiveMarkers, false}} {
+		for _, marker := range group.markers {
+			if strings.HasPrefix(lower, marker) {
+				clause.object = normalizeObject(text[len(marker):])
+				clause.negative = group.negative
+				return clause
+			}
+		}
+	}
+	return clause
+}
+
+// normalizeObject 去掉首尾的标点和空白并转为小写，便于比较
+func normalizeObject(text string) string {
+	return strings.ToLower(strings.TrimFunc(text, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	}))
+}
+
+// sameObject 判断两条要求是否针对同一内容
+func sameObject(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	if a == b {
+		return true
+	}
+	if utf8.RuneCountInString(a) < 2 || utf8.RuneCountInString(b) < 2 {
+		return false
+	}
+	return strings.Contains(a, b) || strings.Contains(b, a)
+}
+
+// checkUnstructuredFormat 检查交付格式是否说明了结构
+func checkUnstructuredFormat(doc *Document) []*Finding {
+	text := strings.TrimSpace(doc.Fields[FieldDeliveryFormat])
+	if text == "" {
+		return nil
+	}
+
+	lines := 0
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines++
+		}
+	}
+	if lines > 1 || listMarker.MatchString(text) || containsAny(text, structureMarkers) {
+		return nil
+	}
+
+	return []*Finding{{
+		Field:      FieldDeliveryFormat,
+		Severity:   SeverityWarning,
+		Message:    "交付格式没有说明输出的结构",
+		Suggestion: "指定输出的形式（如Markdown表格、JSON、分点列表）、组成部分和长度限制",
+		Excerpt:    text,
+	}}
+}
+
+// checkRoleExpertise 检查AI角色是否说明了专业背景
+func checkRoleExpertise(doc *Document) []*Finding {
+	text := strings.TrimSpace(doc.Fields[FieldAIRole])
+	if text == "" || containsAny(text, expertiseMarkers) {
+		return nil
+	}
+	return []*Finding{{
+		Field:      FieldAIRole,
+		Severity:   SeverityWarning,
+		Message:    "AI的角色没有说明专业领域或经验",
+		Suggestion: "补充专业领域和资历，如“有10年经验的B2B营销顾问”",
+		Excerpt:    text,
+	}}
+}
+
+// checkRoleAudience 检查是否说明了输出面向的受众
+func checkRoleAudience(doc *Document) []*Finding {
+	if strings.TrimSpace(doc.Fields[FieldAIRole]) == "" && strings.TrimSpace(doc.Fields[FieldMyRole]) == "" {
+		return nil
+	}
+	for _, field := range Fields[1:] {
+		if containsAny(doc.Fields[field], audienceMarkers) {
+			return nil
+		}
+	}
+	return []*Finding{{
+		Field:      FieldMyRole,
+		Severity:   SeverityInfo,
+		Message:    "没有说明输出面向的受众",
+		Suggestion: "说明读者是谁，如“面向没有技术背景的管理层”",
+	}}
+}
+
+// checkUnresolvedVariables 检查格式错误、未闭合以及未定义的变量占位符
+func checkUnresolvedVariables(doc *Document) []*Finding {
+	var findings []*Finding
+	firstField := make(map[string]string)
+	var used []string
+	for _, field := range Fields {
+		text := doc.Fields[field]
+		for _, placeholder := range placeholderPattern.FindAllString(text, -1) {
+			names := prompt.DetectVariables(placeholder)
+			if len(names) == 0 {
+				findings = append(findings, &Finding{
+					Field:      field,
+					Severity:   SeverityError,
+					Message:    fmt.Sprintf("变量占位符 %s 格式错误", placeholder),
+					Suggestion: "变量名只能包含中英文、数字和下划线，且不能以数字开头，如 {{product_name}}",
+					Excerpt:    placeholder,
+				})
+				continue
+			}
+			if _, ok := firstField[names[0]]; !ok {
+				firstField[names[0]] = field
+				used = append(used, names[0])
+			}
+		}
+
+		rest := placeholderPattern.ReplaceAllString(text, "")
+		if i := strings.Index(rest, "{{"); i >= 0 {
+			findings = append(findings, &Finding{
+				Field:      field,
+				Severity:   SeverityError,
+				Message:    fmt.Sprintf("%s中有未闭合的变量占位符", fieldLabels[field]),
+				Suggestion: "检查 {{ 和 }} 是否成对出现",
+				Excerpt:    excerpt(rest, i, i+2),
+			})
+		}
+	}
+
+	if doc.Variables == nil {
+		return findings
+	}
+
+	declared := make(map[string]bool, len(doc.Variables))
+	for _, name := range doc.Variables {
+		declared[name] = true
+	}
+	for _, name := range used {
+		if !declared[name] {
+			findings = append(findings, &Finding{
+				Field:      firstField[name],
+				Severity:   SeverityWarning,
+				Message:    fmt.Sprintf("变量 %s 没有定义", name),
+				Suggestion: "在变量定义中声明类型、默认值和说明，渲染时才能校验取值",
+				Excerpt:    "{{" + name + "}}",
+			})
+		}
+	}
+
+	var unused []string
+	for _, name := range doc.Variables {
+		if _, ok := firstField[name]; !ok {
+			unused = append(unused, name)
+		}
+	}
+	sort.Strings(unused)
+	for _, name := range unused {
+		findings = append(findings, &Finding{
+			Severity:   SeverityInfo,
+			Message:    fmt.Sprintf("变量 %s 已定义但没有使用", name),
+			Suggestion: "删除不再使用的变量定义",
+		})
+	}
+	return findings
+}
+
+// containsAny 判断文本是否包含任一关键词（大小写不敏感）
+func containsAny(text string, keywords []string) bool {
+	lower := strings.ToLower(text)
+	for _, keyword := range keywords {
+		if strings.Contains(lower, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// visibleLength 统计去掉空白后的字符数
+func visibleLength(text string) int {
+	count := 0
+	for _, r := range text {
+		if !unicode.IsSpace(r) {
+			count++
+		}
+	}
+	return count
+}
+
+// excerptContext 片段中命中位置前后保留的字符数
+const excerptContext = 10
+
+// excerpt 截取命中位置（字节区间）前后的原文
+func excerpt(text string, start, end int) string {
+	before := []rune(text[:start])
+	after := []rune(text[end:])
+
+	var b strings.Builder
+	if len(before) > excerptContext {
+		b.WriteString("…")
+		before = before[len(before)-excerptContext:]
+	}
+	b.WriteString(string(before))
+	b.WriteString(text[start:end])
+	if len(after) > excerptContext {
+		b.WriteString(string(after[:excerptContext]))
+		b.WriteString("…")
+	} else {
+		b.WriteString(string(after))
+	}
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,208 @@
+package prompt
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MarkdownDocument 解析后的 Markdown 提示词文档
+type MarkdownDocument struct {
+	FrontMatter map[string]interface{} // 文档开头 --- 包裹的 YAML 元数据
+	Title       string                 // 第一个一级标题
+	Sections    []Section              // 按出现顺序识别出的段落（Title 为原始标题）
+	Unknown     []string               // 未识别的二级标题（其内容并入上一个段落）
+}
+
+// ParseMarkdown 解析按二级标题分段的 Markdown 文档
+//
+// headings 为标题到段落字段名的映射，标题比较前会经过 NormalizeHeading 处理。
+// 只有能识别的二级标题会开始新段落，代码块中的标题行不做处理。
+// 段落内容中以反斜杠转义的二级标题行（如 \## 标题）会去掉一个反斜杠。
+func ParseMarkdown(text string, headings map[string]string) (*MarkdownDocument, error) {
+	text = strings.ReplaceAll(strings.TrimPrefix(text, "\ufeff"), "\r\n", "\n")
+
+	doc := &MarkdownDocument{}
+	body, frontMatter, err := splitFrontMatter(text)
+	if err != nil {
+		return nil, err
+	}
+	doc.FrontMatter = frontMatter
+
+	lookup := make(map[string]string, len(headings))
+	for heading, key := range headings {
+		lookup[NormalizeHeading(heading)] = key
+	}
+
+	var current *Section
+	var content []string
+	flush := func() {
+		if current != nil {
+			current.Content = strings.TrimSpace(strings.Join(content, "\n"))
+			doc.Sections = append(doc.Sections, *current)
+		}
+		content = nil
+	}
+
+	fence := ""
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if marker := fenceMarker(trimmed); marker != "" {
+			switch {
+			case fence == "":
+				fence = marker
+			case strings.HasPrefix(trimmed, fence):
+				fence = ""
+			}
+		}
+
+		if fence == "" {
+			if heading, ok := headingText(trimmed, 1); ok && current == nil && doc.Title == "" {
+				doc.Title = heading
+				continue
+			}
+			if heading, ok := headingText(trimmed, 2); ok {
+				if key, known := lookup[NormalizeHeading(heading)]; known {
+					flush()
+					current = &Section{Key: key, Title: heading}
+					continue
+				}
+				doc.Unknown = append(doc.Unknown, heading)
+			}
+		}
+
+		if current != nil {
+			if fence == "" && isEscapedHeading(trimmed) {
+				line = strings.Replace(line, `\`, "", 1)
+			}
+			content = append(content, line)
+		}
+	}
+	flush()
+
+	return doc, nil
+}
+
+// MarshalMarkdown 生成带 YAML 元数据的 Markdown 文档，meta 为 nil 时省略元数据
+//
+// 段落内容中代码块以外的二级标题行会加反斜杠转义，ParseMarkdown 解析时还原，
+// 避免内容被识别为新的段落。
+func MarshalMarkdown(meta interface{}, sections []Section) (string, error) {
+	var b strings.Builder
+	if meta != nil {
+		data, err := yaml.Marshal(meta)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString("---\n")
+		b.Write(data)
+		b.WriteString("---\n\n")
+	}
+	escaped := make([]Section, len(sections))
+	for i, section := range sections {
+		section.Content = escapeHeadings(section.Content)
+		escaped[i] = section
+	}
+	b.WriteString(renderMarkdown(escaped))
+	return b.String(), nil
+}
+
+// NormalizeHeading 规范化标题：忽略大小写、空白、下划线、连字符和末尾冒号
+func NormalizeHeading(heading string) string {
+	heading = strings.TrimRight(strings.TrimSpace(heading), ":：")
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '_' || r == '-' {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, heading)
+}
+
+// splitFrontMatter 拆分文档开头的 YAML 元数据
+func splitFrontMatter(text string) (string, map[string]interface{}, error) {
+	if !strings.HasPrefix(text, "---\n") {
+		return text, nil, nil
+	}
+
+	rest := text[len("---"):]
+	end := strings.Index(rest, "\n---")
+	if end < 0 {
+		return text, nil, nil
+	}
+	after := rest[end+len("\n---"):]
+	if after != "" && after[0] != '\n' {
+		return text, nil, nil
+	}
+
+	frontMatter := make(map[string]interface{})
+	if err := yaml.Unmarshal([]byte(rest[:end]), &frontMatter); err != nil {
+		return "", nil, errors.New("文档元数据格式错误: " + err.Error())
+	}
+	return strings.TrimPrefix(after, "\n"), frontMatter, nil
+}
+
+// headingText 判断是否为指定级别的 ATX 标题并返回标题文本
+func headingText(line string, level int) (string, bool) {
+	prefix := strings.Repeat("#", level)
+	if !strings.HasPrefix(line, prefix) {
+		return "", false
+	}
+	rest := line[level:]
+	if rest == "" || (rest[0] != ' ' && rest[0] != '\t') {
+		return "", false
+	}
+	heading := strings.TrimSpace(rest)
+	// 去掉可选的结尾 # 序列（前面须有空白）
+	if stripped := strings.TrimRight(heading, "#"); stripped != heading {
+		if stripped == "" || strings.HasSuffix(stripped, " ") || strings.HasSuffix(stripped, "\t") {
+			heading = strings.TrimSpace(stripped)
+		}
+	}
+	return heading, heading != ""
+}
+
+// escapeHeadings 在代码块以外的二级标题行（包括已转义的）前加一个反斜杠
+func escapeHeadings(content string) string {
+	lines := strings.Split(content, "\n")
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if marker := fenceMarker(trimmed); marker != "" {
+			switch {
+			case fence == "":
+				fence = marker
+			case strings.HasPrefix(trimmed, fence):
+				fence = ""
+			}
+			continue
+		}
+		if fence == "" && (isEscapedHeading(trimmed) || isHeading(trimmed)) {
+			indent := len(line) - len(strings.TrimLeft(line, " \t"))
+			lines[i] = line[:indent] + `\` + line[indent:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isHeading 判断是否为二级标题行
+func isHeading(line string) bool {
+	_, ok := headingText(line, 2)
+	return ok
+}
+
+// isEscapedHeading 判断去掉开头的反斜杠后是否为二级标题行
+func isEscapedHeading(line string) bool {
+	return strings.HasPrefix(line, `\`) && isHeading(strings.TrimLeft(line, `\`))
+}
+
+// fenceMarker 判断是否为代码块围栏行并返回围栏标记
+func fenceMarker(line string) string {
+	for _, marker := range []string{"```", "~~~"} {
+		if strings.HasPrefix(line, marker) {
+			return marker
+		}
+	}
+	return ""
+}
@@ -0,0 +1,91 @@
+package prompt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testHeadings = map[string]string{
+	"任务目标":      "task_goal",
+	"Task Goal": "task_goal",
+	"AI的角色":     "ai_role",
+	"AI Role":   "ai_role",
+	"交付格式":      "delivery_format",
+}
+
+func TestParseMarkdown(t *testing.T) {
+	text := "---\nsubject: 周报生成\ntags: [办公, 周报]\n---\n" +
+		"# 周报模板\n\n" +
+		"前言不属于任何段落\n\n" +
+		"## 任务目标\n\n整理本周工作\n\n### 细节\n- 按项目分组\n\n" +
+		"## ai_role：\r\n资深项目助理\n\n" +
+		"## 备注\n补充说明\n\n" +
+		"## Delivery Format ##\n" +
+		"```markdown\n## 任务目标\n代码块中的标题\n```\n"
+
+	doc, err := ParseMarkdown(text, testHeadings)
+	require.NoError(t, err)
+
+	assert.Equal(t, "周报生成", doc.FrontMatter["subject"])
+	assert.Equal(t, []interface{}{"办公", "周报"}, doc.FrontMatter["tags"])
+	assert.Equal(t, "周报模板", doc.Title)
+	assert.Equal(t, []string{"备注", "Delivery Format"}, doc.Unknown)
+
+	require.Len(t, doc.Sections, 2)
+	assert.Equal(t, Section{Key: "task_goal", Title: "任务目标", Content: "整理本周工作\n\n### 细节\n- 按项目分组"}, doc.Sections[0])
+	assert.Equal(t, "ai_role", doc.Sections[1].Key)
+	assert.Equal(t, "资深项目助理\n\n## 备注\n补充说明\n\n## Delivery Format ##\n```markdown\n## 任务目标\n代码块中的标题\n```", doc.Sections[1].Content)
+}
+
+func TestParseMarkdownEnglishAlias(t *testing.T) {
+	doc, err := ParseMarkdown("## task-goal\nWrite release notes\n## 交付格式 ##\nBullet list\n", testHeadings)
+	require.NoError(t, err)
+	require.Len(t, doc.Sections, 2)
+	assert.Equal(t, "task_goal", doc.Sections[0].Key)
+	assert.Equal(t, "delivery_format", doc.Sections[1].Key)
+	assert.Equal(t, "Bullet list", doc.Sections[1].Content)
+	assert.Nil(t, doc.FrontMatter)
+}
+
+func TestParseMarkdownInvalidFrontMatter(t *testing.T) {
+	_, err := ParseMarkdown("---\nsubject: [未闭合\n---\n## 任务目标\n", testHeadings)
+	assert.Error(t, err)
+}
+
+func TestMarshalMarkdownRoundTrip(t *testing.T) {
+	meta := struct {
+		Subject string   `yaml:"subject"`
+		Tags    []string `yaml:"tags"`
+	}{Subject: "周报生成", Tags: []string{"办公"}}
+	sections := []Section{
+		{Key: "task_goal", Title: "任务目标", Content: "整理本周工作"},
+		{Key: "ai_role", Title: "AI的角色", Content: ""},
+	}
+
+	text, err := MarshalMarkdown(meta, sections)
+	require.NoError(t, err)
+	assert.Equal(t, "---\nsubject: 周报生成\ntags:\n    - 办公\n---\n\n## 任务目标\n\n整理本周工作\n\n## AI的角色\n\n", text)
+
+	doc, err := ParseMarkdown(text, testHeadings)
+	require.NoError(t, err)
+	assert.Equal(t, "周报生成", doc.FrontMatter["subject"])
+	assert.Equal(t, sections, doc.Sections)
+}
+
+func TestMarshalMarkdownEscapesHeadings(t *testing.T) {
+	sections := []Section{
+		{Key: "task_goal", Title: "任务目标", Content: "整理本周工作\n## 行为规则\n  ## 交付格式\n\\## 已转义\n```\n## 代码块\n```"},
+		{Key: "delivery_format", Title: "交付格式", Content: "Markdown 表格"},
+	}
+
+	text, err := MarshalMarkdown(nil, sections)
+	require.NoError(t, err)
+	assert.Contains(t, text, "\n\\## 行为规则\n  \\## 交付格式\n\\\\## 已转义\n```\n## 代码块\n```\n")
+
+	doc, err := ParseMarkdown(text, testHeadings)
+	require.NoError(t, err)
+	assert.Equal(t, sections, doc.Sections)
+	assert.Empty(t, doc.Unknown)
+}
@@ -0,0 +1,201 @@
+package prompt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// 输出格式
+const (
+	FormatMarkdown = "markdown"
+	FormatText     = "text"
+	FormatXML      = "xml"
+	FormatJSON     = "json"
+)
+
+// Section 提示词段落
+type Section struct {
+	Key     string // 字段名，如 task_goal
+	Title   string // 段落标题，如 任务目标
+	Content string // 段落内容
+}
+
+// Options 渲染选项
+type Options struct {
+	Format    string   // 输出格式，默认 markdown
+	Order     []string // 段落顺序（字段名），未列出的段落按默认顺序追加在后
+	OmitEmpty bool     // 是否省略内容为空的段落
+}
+
+// ContentType 获取输出格式对应的 Content-Type
+func ContentType(format string) string {
+	switch format {
+	case FormatJSON:
+		return "application/json; charset=utf-8"
+	case FormatXML:
+		return "application/xml; charset=utf-8"
+	case FormatText:
+		return "text/plain; charset=utf-8"
+	default:
+		return "text/markdown; charset=utf-8"
+	}
+}
+
+// Render 将段落渲染为指定格式的提示词
+func Render(sections []Section, opts Options) (string, error) {
+	ordered, err := orderSections(sections, opts.Order)
+	if err != nil {
+		return "", err
+	}
+
+	if opts.OmitEmpty {
+		filtered := make([]Section, 0, len(ordered))
+		for _, section := range ordered {
+			if strings.TrimSpace(section.Content) != "" {
+				filtered = append(filtered, section)
+			}
+		}
+		ordered = filtered
+	}
+
+	switch opts.Format {
+	case "", FormatMarkdown:
+		return renderMarkdown(ordered), nil
+	case FormatText:
+		return renderText(ordered), nil
+	case FormatXML:
+		return renderXML(ordered), nil
+	case FormatJSON:
+		return renderJSON(ordered)
+	default:
+		return "", fmt.Errorf("不支持的输出格式: %s", opts.Format)
+	}
+}
+
+// orderSections 按指定顺序排列段落
+func orderSections(sections []Section, order []string) ([]Section, error) {
+	if len(order) == 0 {
+		return sections, nil
+	}
+
+	index := make(map[string]int, len(sections))
+	for i, section := range sections {
+		index[section.Key] = i
+	}
+
+	used := make(map[string]bool, len(sections))
+	ordered := make([]Section, 0, len(sections))
+	for _, key := range order {
+		i, ok := index[key]
+		if !ok {
+			return nil, fmt.Errorf("未知的段落: %s", key)
+		}
+		if used[key] {
+			continue
+		}
+		used[key] = true
+		ordered = append(ordered, sections[i])
+	}
+
+	for _, section := range sections {
+		if !used[section.Key] {
+			ordered = append(ordered, section)
+		}
+	}
+
+	return ordered, nil
+}
+
+// renderMarkdown 渲染为 Markdown（与六要素模板的二级标题结构一致）
+func renderMarkdown(sections []Section) string {
+	var b strings.Builder
+	for i, section := range sections {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("## ")
+		b.WriteString(section.Title)
+		b.WriteString("\n\n")
+		if content := strings.TrimSpace(section.Content); content != "" {
+			b.WriteString(content)
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// renderText 渲染为纯文本
+func renderText(sections []Section) string {
+	var b strings.Builder
+	for i, section := range sections {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(section.Title)
+		b.WriteString("：\n")
+		if content := strings.TrimSpace(section.Content); content != "" {
+			b.WriteString(content)
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// renderXML 渲染为 XML 标签分段（内容保持原文，便于直接作为提示词使用）
+func renderXML(sections []Section) string {
+	var b strings.Builder
+	for _, section := range sections {
+		b.WriteString("<")
+		b.WriteString(section.Key)
+		b.WriteString(">\n")
+		if content := strings.TrimSpace(section.Content); content != "" {
+			b.WriteString(content)
+			b.WriteString("\n")
+		}
+		b.WriteString("</")
+		b.WriteString(section.Key)
+		b.WriteString(">\n")
+	}
+	return b.String()
+}
+
+// renderJSON 渲染为 JSON 对象（保持段落顺序）
+func renderJSON(sections []Section) (string, error) {
+	var b bytes.Buffer
+	b.WriteString("{")
+	for i, section := range sections {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		key, err := marshalString(section.Key)
+		if err != nil {
+			return "", err
+		}
+		value, err := marshalString(strings.TrimSpace(section.Content))
+		if err != nil {
+			return "", err
+		}
+		b.WriteString("\n  ")
+		b.Write(key)
+		b.WriteString(": ")
+		b.Write(value)
+	}
+	if len(sections) > 0 {
+		b.WriteString("\n")
+	}
+	b.WriteString("}")
+	return b.String(), nil
+}
+
+// marshalString 将字符串编码为 JSON（不转义 HTML 字符）
+func marshalString(value string) ([]byte, error) {
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(b.Bytes(), "\n"), nil
+}
@@ -0,0 +1,73 @@
+package prompt
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSections() []Section {
+	return []Section{
+		{Key: "task_goal", Title: "任务目标", Content: "撰写产品发布文案"},
+		{Key: "ai_role", Title: "AI的角色", Content: "资深文案策划"},
+		{Key: "key_info", Title: "关键信息", Content: ""},
+		{Key: "delivery_format", Title: "交付格式", Content: "Markdown 列表 <3条>"},
+	}
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{
+			name: "默认Markdown",
+			opts: Options{},
+			want: "## 任务目标\n\n撰写产品发布文案\n\n## AI的角色\n\n资深文案策划\n\n## 关键信息\n\n\n## 交付格式\n\nMarkdown 列表 <3条>\n",
+		},
+		{
+			name: "省略空段落并调整顺序",
+			opts: Options{Format: FormatMarkdown, Order: []string{"delivery_format", "task_goal"}, OmitEmpty: true},
+			want: "## 交付格式\n\nMarkdown 列表 <3条>\n\n## 任务目标\n\n撰写产品发布文案\n\n## AI的角色\n\n资深文案策划\n",
+		},
+		{
+			name: "纯文本",
+			opts: Options{Format: FormatText, OmitEmpty: true},
+			want: "任务目标：\n撰写产品发布文案\n\nAI的角色：\n资深文案策划\n\n交付格式：\nMarkdown 列表 <3条>\n",
+		},
+		{
+			name: "XML标签",
+			opts: Options{Format: FormatXML, OmitEmpty: true, Order: []string{"ai_role"}},
+			want: "<ai_role>\n资深文案策划\n</ai_role>\n<task_goal>\n撰写产品发布文案\n</task_goal>\n<delivery_format>\nMarkdown 列表 <3条>\n</delivery_format>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(testSections(), tt.opts)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRenderJSONKeepsOrder(t *testing.T) {
+	got, err := Render(testSections(), Options{Format: FormatJSON, OmitEmpty: true, Order: []string{"delivery_format"}})
+	require.NoError(t, err)
+	assert.Equal(t, "{\n  \"delivery_format\": \"Markdown 列表 <3条>\",\n  \"task_goal\": \"撰写产品发布文案\",\n  \"ai_role\": \"资深文案策划\"\n}", got)
+
+	var decoded map[string]string
+	require.NoError(t, json.Unmarshal([]byte(got), &decoded))
+	assert.Equal(t, "资深文案策划", decoded["ai_role"])
+}
+
+func TestRenderErrors(t *testing.T) {
+	_, err := Render(testSections(), Options{Format: "yaml"})
+	assert.Error(t, err)
+
+	_, err = Render(testSections(), Options{Order: []string{"unknown"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,202 @@
+package prompt
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 变量类型
+const (
+	VarTypeString = "string"
+	VarTypeNumber = "number"
+	VarTypeEnum   = "enum"
+	VarTypeBool   = "bool"
+)
+
+// variablePattern 匹配 {{name}} 形式的变量占位符，名称支持中英文、数字和下划线
+var variablePattern = regexp.MustCompile(`\{\{\s*([\p{L}_][\p{L}\p{N}_]*)\s*\}\}`)
+
+// variableNamePattern 校验变量名
+var variableNamePattern = regexp.MustCompile(`^[\p{L}_][\p{L}\p{N}_]*$`)
+
+// Variable 变量定义
+type Variable struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Default     string   `json:"default"`
+	Required    bool     `json:"required"`
+	Description string   `json:"description"`
+	Options     []string `json:"options,omitempty"` // 枚举可选值
+}
+
+// VariableError 变量校验错误
+type VariableError struct {
+	Name    string `json:"name"`
+	Message string `json:"message"`
+}
+
+// DetectVariables 检测文本中出现的变量名（按首次出现顺序去重）
+func DetectVariables(texts ...string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, text := range texts {
+		for _, match := range variablePattern.FindAllStringSubmatch(text, -1) {
+			name := match[1]
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// Substitute 将文本中的变量占位符替换为对应的值，未提供值的占位符保持原样
+func Substitute(text string, values map[string]string) string {
+	return variablePattern.ReplaceAllStringFunc(text, func(placeholder string) string {
+		name := variablePattern.FindStringSubmatch(placeholder)[1]
+		if value, ok := values[name]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
+
+// ValidateSchema 校验变量定义是否合法
+func ValidateSchema(schema []Variable) []*VariableError {
+	var errs []*VariableError
+	seen := make(map[string]bool, len(schema))
+	for _, v := range schema {
+		if !variableNamePattern.MatchString(v.Name) {
+			errs = append(errs, &VariableError{Name: v.Name, Message: "变量名只能包含字母、数字和下划线，且不能以数字开头"})
+			continue
+		}
+		if seen[v.Name] {
+			errs = append(errs, &VariableError{Name: v.Name, Message: "变量名重复"})
+			continue
+		}
+		seen[v.Name] = true
+
+		switch v.Type {
+		case VarTypeString, VarTypeNumber, VarTypeBool:
+		case VarTypeEnum:
+			if len(v.Options) == 0 {
+				errs = append(errs, &VariableError{Name: v.Name, Message: "枚举类型必须提供可选值"})
+				continue
+			}
+		default:
+			errs = append(errs, &VariableError{Name: v.Name, Message: fmt.Sprintf("不支持的变量类型: %s", v.Type)})
+			continue
+		}
+
+		if v.Default != "" {
+			if _, err := normalizeValue(v, v.Default); err != nil {
+				errs = append(errs, &VariableError{Name: v.Name, Message: "默认值" + err.Error()})
+			}
+		}
+	}
+	return errs
+}
+
+// ResolveValues 按变量定义校验传入的变量值并补齐默认值
+//
+// names 为模板中实际出现的变量；未在 schema 中声明的变量按必填字符串处理。
+// 返回可直接用于 Substitute 的字符串值以及逐个变量的校验错误。
+func ResolveValues(names []string, schema []Variable, values map[string]interface{}) (map[string]string, []*VariableError) {
+	definitions := make(map[string]Variable, len(schema))
+	for _, v := range schema {
+		definitions[v.Name] = v
+	}
+
+	resolved := make(map[string]string, len(names))
+	var errs []*VariableError
+
+	check := func(v Variable) {
+		raw, provided := values[v.Name]
+		if !provided || raw == nil || raw == "" {
+			if v.Default != "" {
+				resolved[v.Name] = v.Default
+				return
+			}
+			if v.Required {
+				errs = append(errs, &VariableError{Name: v.Name, Message: "缺少必填变量"})
+			} else {
+				resolved[v.Name] = ""
+			}
+			return
+		}
+
+		value, err := normalizeValue(v, raw)
+		if err != nil {
+			errs = append(errs, &VariableError{Name: v.Name, Message: err.Error()})
+			return
+		}
+		resolved[v.Name] = value
+	}
+
+	// 先按 schema 顺序校验已声明变量，再处理模板中未声明的变量
+	for _, v := range schema {
+		check(v)
+	}
+	for _, name := range names {
+		if _, declared := definitions[name]; !declared {
+			check(Variable{Name: name, Type: VarTypeString, Required: true})
+		}
+	}
+
+	return resolved, errs
+}
+
+// normalizeValue 按变量类型校验并转换为字符串
+func normalizeValue(v Variable, raw interface{}) (string, error) {
+	switch v.Type {
+	case VarTypeNumber:
+		switch value := raw.(type) {
+		case float64:
+			return strconv.FormatFloat(value, 'f', -1, 64), nil
+		case int:
+			return strconv.Itoa(value), nil
+		case string:
+			if _, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err != nil {
+				return "", fmt.Errorf("必须是数字")
+			}
+			return strings.TrimSpace(value), nil
+		default:
+			return "", fmt.Errorf("必须是数字")
+		}
+	case VarTypeBool:
+		switch value := raw.(type) {
+		case bool:
+			return strconv.FormatBool(value), nil
+		case string:
+			b, err := strconv.ParseBool(strings.TrimSpace(value))
+			if err != nil {
+				return "", fmt.Errorf("必须是布尔值")
+			}
+			return strconv.FormatBool(b), nil
+		default:
+			return "", fmt.Errorf("必须是布尔值")
+		}
+	case VarTypeEnum:
+		value := fmt.Sprint(raw)
+		for _, option := range v.Options {
+			if option == value {
+				return value, nil
+			}
+		}
+		return "", fmt.Errorf("必须是以下值之一: %s", strings.Join(v.Options, ", "))
+	default:
+		switch value := raw.(type) {
+		case string:
+			return value, nil
+		case float64:
+			return strconv.FormatFloat(value, 'f', -1, 64), nil
+		case bool:
+			return strconv.FormatBool(value), nil
+		default:
+			return "", fmt.Errorf("必须是字符串")
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package prompt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectVariables(t *testing.T) {
+	names := DetectVariables("为{{product}}撰写{{ count }}条文案", "语气：{{语气}}，产品：{{product}}", "{{1abc}} 不是变量")
+	assert.Equal(t, []string{"product", "count", "语气"}, names)
+}
+
+func TestSubstitute(t *testing.T) {
+	got := Substitute("为{{product}}撰写{{ count }}条{{style}}文案", map[string]string{
+		"product": "智能手表",
+		"count":   "3",
+	})
+	assert.Equal(t, "为智能手表撰写3条{{style}}文案", got)
+}
+
+func TestValidateSchema(t *testing.T) {
+	errs := ValidateSchema([]Variable{
+		{Name: "product", Type: VarTypeString},
+		{Name: "product", Type: VarTypeString},
+		{Name: "9count", Type: VarTypeNumber},
+		{Name: "tone", Type: VarTypeEnum},
+		{Name: "count", Type: VarTypeNumber, Default: "三"},
+		{Name: "flag", Type: "date"},
+	})
+
+	names := make([]string, len(errs))
+	for i, err := range errs {
+		names[i] = err.Name
+	}
+	assert.Equal(t, []string{"product", "9count", "tone", "count", "flag"}, names)
+}
+
+func TestResolveValues(t *testing.T) {
+	schema := []Variable{
+		{Name: "product", Type: VarTypeString, Required: true},
+		{Name: "count", Type: VarTypeNumber, Default: "3"},
+		{Name: "tone", Type: VarTypeEnum, Options: []string{"正式", "活泼"}, Required: true},
+		{Name: "emoji", Type: VarTypeBool},
+	}
+
+	t.Run("校验通过", func(t *testing.T) {
+		resolved, errs := ResolveValues([]string{"product", "count", "tone", "emoji"}, schema, map[string]interface{}{
+			"product": "智能手表",
+			"tone":    "活泼",
+			"emoji":   true,
+		})
+		assert.Empty(t, errs)
+		assert.Equal(t, map[string]string{
+			"product": "智能手表",
+			"count":   "3",
+			"tone":    "活泼",
+			"emoji":   "true",
+		}, resolved)
+	})
+
+	t.Run("逐个变量报错", func(t *testing.T) {
+		_, errs := ResolveValues([]string{"product", "count", "tone", "audience"}, schema, map[string]interface{}{
+			"count": "很多",
+			"tone":  "严肃",
+		})
+		assert.Equal(t, []*VariableError{
+			{Name: "product", Message: "缺少必填变量"},
+			{Name: "count", Message: "必须是数字"},
+			{Name: "tone", Message: "必须是以下值之一: 正式, 活泼"},
+			{Name: "audience", Message: "缺少必填变量"},
+		}, errs)
+	})
+}
@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBody 读取错误响应体的最大字节数
+const maxErrorBody = 4096
+
+// maxStreamLine 流式响应中单行的最大字节数
+const maxStreamLine = 1024 * 1024
+
+// post 发送 JSON 请求，状态码不是 2xx 时返回 *Error
+func post(ctx context.Context, client *http.Client, url string, headers map[string]string, body interface{}) (*http.Response, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("序列化请求失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return nil, &Error{StatusCode: resp.StatusCode, Message: errorMessage(data)}
+	}
+	return resp, nil
+}
+
+// postJSON 发送 JSON 请求并解析 JSON 响应
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body, out interface{}) error {
+	resp, err := post(ctx, client, url, headers, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("解析响应失败: %w", err)
+	}
+	return nil
+}
+
+// errorMessage 从错误响应体中提取错误信息，兼容 {"error": {"message": ...}} 和 {"error": "..."} 两种格式
+func errorMessage(data []byte) string {
+	var body struct {
+		Error   json.RawMessage `json:"error"`
+		Message string          `json:"message"`
+	}
+	if json.Unmarshal(data, &body) == nil {
+		var detail struct {
+			Message string `json:"message"`
+		}
+		var text string
+		switch {
+		case json.Unmarshal(body.Error, &detail) == nil && detail.Message != "":
+			return detail.Message
+		case json.Unmarshal(body.Error, &text) == nil && text != "":
+			return text
+		case body.Message != "":
+			return body.Message
+		}
+	}
+
+	message := strings.ToValidUTF8(strings.TrimSpace(string(data)), "")
+	if message == "" {
+		return "无错误信息"
+	}
+	return message
+}
+
+// streamError 读取流式响应出错时，请求已被取消或超时则返回 context 的错误
+func streamError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return fmt.Errorf("读取响应失败: %w", err)
+}
@@ -0,0 +1,135 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ollama Ollama 原生的 /chat 接口
+type ollama struct {
+	baseURL string
+	client  *http.Client
+}
+
+// ollamaOptions 采样参数
+type ollamaOptions struct {
+	Temperature      *float64 `json:"temperature,omitempty"`
+	NumPredict       int      `json:"num_predict,omitempty"`
+	TopP             *float64 `json:"top_p,omitempty"`
+	FrequencyPenalty *float64 `json:"frequency_penalty,omitempty"`
+	PresencePenalty  *float64 `json:"presence_penalty,omitempty"`
+}
+
+// ollamaRequest /chat 请求
+type ollamaRequest struct {
+	Model    string        `json:"model"`
+	Messages []Message     `json:"messages"`
+	Stream   bool          `json:"stream"` // Ollama 默认为 true，需要显式传递
+	Options  ollamaOptions `json:"options"`
+}
+
+// ollamaResponse /chat 响应（流式输出时为其中一行）
+type ollamaResponse struct {
+	Model           string  `json:"model"`
+	Message         Message `json:"message"`
+	Done            bool    `json:"done"`
+	DoneReason      string  `json:"done_reason"`
+	PromptEvalCount int     `json:"prompt_eval_count"`
+	EvalCount       int     `json:"eval_count"`
+	Error           string  `json:"error"`
+}
+
+// newOllamaRequest 转换为 /chat 请求
+func newOllamaRequest(req *Request, stream bool) *ollamaRequest {
+	return &ollamaRequest{
+		Model:    req.Model,
+		Messages: req.Messages,
+		Stream:   stream,
+		Options: ollamaOptions{
+			Temperature:      req.Temperature,
+			NumPredict:       req.MaxTokens,
+			TopP:             req.TopP,
+			FrequencyPenalty: req.FrequencyPenalty,
+			PresencePenalty:  req.PresencePenalty,
+		},
+	}
+}
+
+// usage 令牌用量
+func (r *ollamaResponse) usage() Usage {
+	return Usage{
+		PromptTokens:     r.PromptEvalCount,
+		CompletionTokens: r.EvalCount,
+		TotalTokens:      r.PromptEvalCount + r.EvalCount,
+	}
+}
+
+// Generate 调用 /chat 接口生成内容
+func (p *ollama) Generate(ctx context.Context, req *Request) (*Response, error) {
+	var resp ollamaResponse
+	if err := postJSON(ctx, p.client, p.baseURL+"/chat", nil, newOllamaRequest(req, false), &resp); err != nil {
+		return nil, err
+	}
+	if resp.Error != "" {
+		return nil, &Error{StatusCode: http.StatusOK, Message: resp.Error}
+	}
+
+	model := resp.Model
+	if model == "" {
+		model = req.Model
+	}
+	return &Response{
+		Model:        model,
+		Content:      resp.Message.Content,
+		FinishReason: resp.DoneReason,
+		Usage:        resp.usage(),
+	}, nil
+}
+
+// Stream 以流式方式调用 /chat 接口，响应为每行一个 JSON 对象，最后一行包含令牌用量
+func (p *ollama) Stream(ctx context.Context, req *Request, onDelta DeltaFunc) (*Response, error) {
+	resp, err := post(ctx, p.client, p.baseURL+"/chat", nil, newOllamaRequest(req, true))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	result := &Response{Model: req.Model}
+	var content strings.Builder
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var chunk ollamaResponse
+		if err := decoder.Decode(&chunk); err == io.EOF {
+			break
+		} else if err != nil {
+			if ctx.Err() != nil {
+				return result.finish(&content), ctx.Err()
+			}
+			return result.finish(&content), fmt.Errorf("解析响应失败: %w", err)
+		}
+		if chunk.Error != "" {
+			return result.finish(&content), &Error{StatusCode: resp.StatusCode, Message: chunk.Error}
+		}
+		if chunk.Model != "" {
+			result.Model = chunk.Model
+		}
+
+		if chunk.Message.Content != "" {
+			content.WriteString(chunk.Message.Content)
+			if err := onDelta(chunk.Message.Content); err != nil {
+				return result.finish(&content), err
+			}
+		}
+		if chunk.Done {
+			result.FinishReason = chunk.DoneReason
+			result.Usage = chunk.usage()
+			break
+		}
+	}
+
+	return result.finish(&content), nil
+}
@@ -0,0 +1,161 @@
+package provider
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// openAI OpenAI 兼容的 Chat Completions 接口
+type openAI struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+// openAIRequest Chat Completions 请求
+type openAIRequest struct {
+	Model            string               `json:"model"`
+	Messages         []Message            `json:"messages"`
+	Temperature      *float64             `json:"temperature,omitempty"`
+	MaxTokens        int                  `json:"max_tokens,omitempty"`
+	TopP             *float64             `json:"top_p,omitempty"`
+	FrequencyPenalty *float64             `json:"frequency_penalty,omitempty"`
+	PresencePenalty  *float64             `json:"presence_penalty,omitempty"`
+	Stream           bool                 `json:"stream,omitempty"`
+	StreamOptions    *openAIStreamOptions `json:"stream_options,omitempty"`
+}
+
+// openAIStreamOptions 流式输出选项
+type openAIStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"` // 在最后一个数据块中返回令牌用量
+}
+
+// openAIResponse Chat Completions 响应
+type openAIResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message      Message `json:"message"`
+		FinishReason string  `json:"finish_reason"`
+	} `json:"choices"`
+	Usage Usage `json:"usage"`
+}
+
+// openAIStreamChunk 流式输出的数据块
+type openAIStreamChunk struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Delta        Message `json:"delta"`
+		FinishReason string  `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *Usage `json:"usage"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// newOpenAIRequest 转换为 Chat Completions 请求
+func newOpenAIRequest(req *Request) *openAIRequest {
+	return &openAIRequest{
+		Model:            req.Model,
+		Messages:         req.Messages,
+		Temperature:      req.Temperature,
+		MaxTokens:        req.MaxTokens,
+		TopP:             req.TopP,
+		FrequencyPenalty: req.FrequencyPenalty,
+		PresencePenalty:  req.PresencePenalty,
+	}
+}
+
+// headers 请求头，未配置 API Key 时不发送认证信息（如本地部署的兼容服务）
+func (p *openAI) headers() map[string]string {
+	if p.apiKey == "" {
+		return nil
+	}
+	return map[string]string{"Authorization": "Bearer " + p.apiKey}
+}
+
+// Generate 调用 Chat Completions 接口生成内容
+func (p *openAI) Generate(ctx context.Context, req *Request) (*Response, error) {
+	var resp openAIResponse
+	if err := postJSON(ctx, p.client, p.baseURL+"/chat/completions", p.headers(), newOpenAIRequest(req), &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Choices) == 0 {
+		return nil, errors.New("模型服务没有返回内容")
+	}
+
+	model := resp.Model
+	if model == "" {
+		model = req.Model
+	}
+	return &Response{
+		Model:        model,
+		Content:      resp.Choices[0].Message.Content,
+		FinishReason: resp.Choices[0].FinishReason,
+		Usage:        resp.Usage,
+	}, nil
+}
+
+// Stream 以流式方式调用 Chat Completions 接口，并请求在最后一个数据块中返回令牌用量
+func (p *openAI) Stream(ctx context.Context, req *Request, onDelta DeltaFunc) (*Response, error) {
+	body := newOpenAIRequest(req)
+	body.Stream = true
+	body.StreamOptions = &openAIStreamOptions{IncludeUsage: true}
+
+	resp, err := post(ctx, p.client, p.baseURL+"/chat/completions", p.headers(), body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	result := &Response{Model: req.Model}
+	var content strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), maxStreamLine)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk openAIStreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return result.finish(&content), fmt.Errorf("解析响应失败: %w", err)
+		}
+		if chunk.Error != nil {
+			return result.finish(&content), &Error{StatusCode: resp.StatusCode, Message: chunk.Error.Message}
+		}
+		if chunk.Model != "" {
+			result.Model = chunk.Model
+		}
+		if chunk.Usage != nil {
+			result.Usage = *chunk.Usage
+		}
+		for _, choice := range chunk.Choices {
+			if choice.FinishReason != "" {
+				result.FinishReason = choice.FinishReason
+			}
+			if choice.Delta.Content == "" {
+				continue
+			}
+			content.WriteString(choice.Delta.Content)
+			if err := onDelta(choice.Delta.Content); err != nil {
+				return result.finish(&content), err
+			}
+		}
+	}
+	if err := streamError(ctx, scanner.Err()); err != nil {
+		return result.finish(&content), err
+	}
+
+	return result.finish(&content), nil
+}
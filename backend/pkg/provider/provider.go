@@ -0,0 +1,131 @@
+// Package provider 通过统一的接口调用各家大模型服务生成内容
+//
+// openai、baidu、alibaba、tencent、bytedance 使用 OpenAI 兼容的 Chat Completions 接口，
+// ollama 使用 Ollama 原生的 /chat 接口。
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// 提供商类型
+const (
+	TypeOpenAI    = "openai"
+	TypeBaidu     = "baidu"
+	TypeAlibaba   = "alibaba"
+	TypeTencent   = "tencent"
+	TypeBytedance = "bytedance"
+	TypeOllama    = "ollama"
+)
+
+// Types 支持的提供商类型
+var Types = []string{TypeOpenAI, TypeBaidu, TypeAlibaba, TypeTencent, TypeBytedance, TypeOllama}
+
+// 消息角色
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// Message 对话消息
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Request 生成请求，采样参数为空时使用模型服务的默认值
+type Request struct {
+	Model            string
+	Messages         []Message
+	Temperature      *float64
+	MaxTokens        int
+	TopP             *float64
+	FrequencyPenalty *float64
+	PresencePenalty  *float64
+}
+
+// Usage 令牌用量
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// Response 生成结果
+type Response struct {
+	Model        string `json:"model"`
+	Content      string `json:"content"`
+	FinishReason string `json:"finish_reason"`
+	Usage        Usage  `json:"usage"`
+}
+
+// finish 填入流式输出累积的内容
+func (r *Response) finish(content *strings.Builder) *Response {
+	r.Content = content.String()
+	return r
+}
+
+// DeltaFunc 流式输出时接收每段新生成的内容，返回错误时停止生成
+type DeltaFunc func(delta string) error
+
+// Provider 大模型服务
+type Provider interface {
+	// Generate 生成完整内容后返回
+	Generate(ctx context.Context, req *Request) (*Response, error)
+	// Stream 以流式方式生成，每收到一段内容调用 onDelta；出错或被取消时同时返回已生成的部分内容
+	Stream(ctx context.Context, req *Request, onDelta DeltaFunc) (*Response, error)
+}
+
+// Config 模型服务的连接配置
+type Config struct {
+	Type    string
+	BaseURL string
+	APIKey  string
+	Client  *http.Client // 为空时使用默认客户端，超时由调用方通过 context 控制
+}
+
+// Error 模型服务返回的错误
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+// Error 实现 error 接口
+func (e *Error) Error() string {
+	return fmt.Sprintf("模型服务返回错误(%d): %s", e.StatusCode, e.Message)
+}
+
+// IsType 判断是否为支持的提供商类型
+func IsType(providerType string) bool {
+	for _, t := range Types {
+		if t == providerType {
+			return true
+		}
+	}
+	return false
+}
+
+// New 根据配置创建模型服务
+func New(cfg Config) (Provider, error) {
+	if cfg.BaseURL == "" {
+		return nil, fmt.Errorf("模型服务地址不能为空")
+	}
+	client := cfg.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	baseURL := strings.TrimRight(cfg.BaseURL, "/")
+
+	switch cfg.Type {
+	case TypeOllama:
+		return &ollama{baseURL: baseURL, client: client}, nil
+	case TypeOpenAI, TypeBaidu, TypeAlibaba, TypeTencent, TypeBytedance:
+		return &openAI{baseURL: baseURL, apiKey: cfg.APIKey, client: client}, nil
+	default:
+		return nil, fmt.Errorf("不支持的提供商类型: %s", cfg.Type)
+	}
+}
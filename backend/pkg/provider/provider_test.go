@@ -0,0 +1,251 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func float64Ptr(v float64) *float64 {
+	return &v
+}
+
+func testRequest() *Request {
+	return &Request{
+		Model:       "test-model",
+		Messages:    []Message{{Role: RoleUser, Content: "你好"}},
+		Temperature: float64Ptr(0.7),
+		MaxTokens:   100,
+		TopP:        float64Ptr(0.9),
+	}
+}
+
+func TestOpenAI_Generate(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer sk-test", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"model": "test-model-0613",
+			"choices": [{"message": {"role": "assistant", "content": "你好！"}, "finish_reason": "stop"}],
+			"usage": {"prompt_tokens": 5, "completion_tokens": 3, "total_tokens": 8}
+		}`))
+	}))
+	defer server.Close()
+
+	p, err := New(Config{Type: TypeOpenAI, BaseURL: server.URL + "/v1/", APIKey: "sk-test"})
+	require.NoError(t, err)
+
+	resp, err := p.Generate(context.Background(), testRequest())
+	require.NoError(t, err)
+	assert.Equal(t, &Response{
+		Model:        "test-model-0613",
+		Content:      "你好！",
+		FinishReason: "stop",
+		Usage:        Usage{PromptTokens: 5, CompletionTokens: 3, TotalTokens: 8},
+	}, resp)
+
+	assert.Equal(t, "test-model", received["model"])
+	assert.Equal(t, 0.7, received["temperature"])
+	assert.Equal(t, float64(100), received["max_tokens"])
+	assert.Equal(t, 0.9, received["top_p"])
+	assert.NotContains(t, received, "frequency_penalty")
+}
+
+func TestOpenAI_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error": {"message": "Incorrect API key provided", "type": "invalid_request_error"}}`))
+	}))
+	defer server.Close()
+
+	p, err := New(Config{Type: TypeAlibaba, BaseURL: server.URL})
+	require.NoError(t, err)
+
+	_, err = p.Generate(context.Background(), testRequest())
+	var providerErr *Error
+	require.ErrorAs(t, err, &providerErr)
+	assert.Equal(t, http.StatusUnauthorized, providerErr.StatusCode)
+	assert.Equal(t, "Incorrect API key provided", providerErr.Message)
+}
+
+func TestOllama_Generate(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/chat", r.URL.Path)
+		assert.Empty(t, r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		_, _ = w.Write([]byte(`{
+			"model": "llama3",
+			"message": {"role": "assistant", "content": "Hello"},
+			"done": true,
+			"done_reason": "stop",
+			"prompt_eval_count": 10,
+			"eval_count": 2
+		}`))
+	}))
+	defer server.Close()
+
+	p, err := New(Config{Type: TypeOllama, BaseURL: server.URL + "/api"})
+	require.NoError(t, err)
+
+	resp, err := p.Generate(context.Background(), testRequest())
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", resp.Content)
+	assert.Equal(t, "stop", resp.FinishReason)
+	assert.Equal(t, Usage{PromptTokens: 10, CompletionTokens: 2, TotalTokens: 12}, resp.Usage)
+
+	assert.Equal(t, false, received["stream"])
+	options := received["options"].(map[string]interface{})
+	assert.Equal(t, float64(100), options["num_predict"])
+	assert.Equal(t, 0.7, options["temperature"])
+}
+
+func TestOllama_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error": "model \"llama9\" not found"}`))
+	}))
+	defer server.Close()
+
+	p, err := New(Config{Type: TypeOllama, BaseURL: server.URL})
+	require.NoError(t, err)
+
+	_, err = p.Generate(context.Background(), testRequest())
+	assert.EqualError(t, err, `模型服务返回错误(404): model "llama9" not found`)
+}
+
+func TestGenerate_ContextCanceled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	p, err := New(Config{Type: TypeOpenAI, BaseURL: server.URL})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = p.Generate(ctx, testRequest())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(Config{Type: "unknown", BaseURL: "http://localhost"})
+	assert.EqualError(t, err, "不支持的提供商类型: unknown")
+
+	_, err = New(Config{Type: TypeOpenAI})
+	assert.EqualError(t, err, "模型服务地址不能为空")
+
+	assert.True(t, IsType(TypeBytedance))
+	assert.False(t, IsType("unknown"))
+}
+
+func TestOpenAI_Stream(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, line := range []string{
+			`data: {"model":"test-model-0613","choices":[{"delta":{"role":"assistant","content":""}}]}`,
+			`data: {"choices":[{"delta":{"content":"你好"}}]}`,
+			`data: {"choices":[{"delta":{"content":"！"},"finish_reason":"stop"}]}`,
+			`data: {"choices":[],"usage":{"prompt_tokens":5,"completion_tokens":2,"total_tokens":7}}`,
+			`data: [DONE]`,
+		} {
+			_, _ = w.Write([]byte(line + "\n\n"))
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer server.Close()
+
+	p, err := New(Config{Type: TypeOpenAI, BaseURL: server.URL})
+	require.NoError(t, err)
+
+	var deltas []string
+	resp, err := p.Stream(context.Background(), testRequest(), func(delta string) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"你好", "！"}, deltas)
+	assert.Equal(t, &Response{
+		Model:        "test-model-0613",
+		Content:      "你好！",
+		FinishReason: "stop",
+		Usage:        Usage{PromptTokens: 5, CompletionTokens: 2, TotalTokens: 7},
+	}, resp)
+	assert.Equal(t, true, received["stream"])
+	assert.Equal(t, map[string]interface{}{"include_usage": true}, received["stream_options"])
+}
+
+func TestOpenAI_StreamStopped(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`data: {"choices":[{"delta":{"content":"第一段"}}]}` + "\n\n"))
+		w.(http.Flusher).Flush()
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	p, err := New(Config{Type: TypeOpenAI, BaseURL: server.URL})
+	require.NoError(t, err)
+
+	// 接收方返回错误时停止生成，并返回已生成的内容
+	stop := errors.New("客户端已断开")
+	resp, err := p.Stream(context.Background(), testRequest(), func(delta string) error {
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, "第一段", resp.Content)
+
+	// 上游没有响应时随请求超时结束
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	resp, err = p.Stream(ctx, testRequest(), func(delta string) error { return nil })
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, "第一段", resp.Content)
+}
+
+func TestOllama_Stream(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		for _, line := range []string{
+			`{"model":"llama3","message":{"role":"assistant","content":"Hel"},"done":false}`,
+			`{"model":"llama3","message":{"role":"assistant","content":"lo"},"done":false}`,
+			`{"model":"llama3","message":{"role":"assistant","content":""},"done":true,"done_reason":"stop","prompt_eval_count":10,"eval_count":2}`,
+		} {
+			_, _ = w.Write([]byte(line + "\n"))
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer server.Close()
+
+	p, err := New(Config{Type: TypeOllama, BaseURL: server.URL})
+	require.NoError(t, err)
+
+	var deltas []string
+	resp, err := p.Stream(context.Background(), testRequest(), func(delta string) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Hel", "lo"}, deltas)
+	assert.Equal(t, "Hello", resp.Content)
+	assert.Equal(t, "stop", resp.FinishReason)
+	assert.Equal(t, 12, resp.Usage.TotalTokens)
+	assert.Equal(t, true, received["stream"])
+}
@@ -23,6 +23,17 @@ type PageResponse struct {
 	Size    int         `json:"size"`
 }
 
+// CursorPageResponse 游标分页响应结构
+type CursorPageResponse struct {
+	Code       int         `json:"code"`
+	Message    string      `json:"message"`
+	Data       interface{} `json:"data"`
+	NextCursor string      `json:"next_cursor"`     // 下一页游标，为空表示没有更多数据
+	PrevCursor string      `json:"prev_cursor"`     // 上一页游标，为空表示已是第一页
+	Size       int         `json:"size"`            // 每页数量
+	Total      *int64      `json:"total,omitempty"` // 总数，仅在请求时返回
+}
+
 // 错误码定义
 const (
 	// 通用错误码
@@ -41,14 +52,36 @@ const (
 	CodeInvalidPhone    = 1005 // 手机号格式错误
 
 	// 六要素相关错误码
-	CodeElementNotFound = 2001 // 六要素不存在
-	CodeElementExists   = 2002 // 六要素已存在
-	CodeInvalidElement  = 2003 // 六要素参数错误
+	CodeElementNotFound     = 2001 // 六要素不存在
+	CodeElementExists       = 2002 // 六要素已存在
+	CodeInvalidElement      = 2003 // 六要素参数错误
+	CodeVersionNotFound     = 2004 // 历史版本不存在
+	CodeInvalidVariable     = 2005 // 模板变量校验失败
+	CodeTagNotFound         = 2006 // 标签不存在
+	CodeTagExists           = 2007 // 标签已存在
+	CodeFolderNotFound      = 2008 // 文件夹不存在
+	CodeFolderExists        = 2009 // 文件夹已存在
+	CodeTemplateNotFound    = 2010 // 模板不存在
+	CodeVersionConflict     = 2011 // 记录已被修改（If-Match不匹配）
+	CodeIfMatchRequired     = 2012 // 缺少If-Match请求头
+	CodeGenerationNotFound  = 2013 // 生成记录不存在
+	CodeComparisonNotFound  = 2014 // 对比运行不存在
+	CodeEvalDatasetNotFound = 2015 // 评估数据集不存在
+	CodeEvalRunNotFound     = 2016 // 评估运行不存在
+	CodeCatalogItemNotFound = 2017 // 目录项不存在
+	CodeCatalogItemExists   = 2018 // 目录项已存在
 
 	// JWT相关错误码
 	CodeInvalidToken = 3001 // Token无效
 	CodeTokenExpired = 3002 // Token过期
 	CodeTokenMissing = 3003 // Token缺失
+
+	// 大模型生成相关错误码
+	CodeProviderNotFound       = 4001 // 模型服务不存在或未启用
+	CodeProviderError          = 4002 // 模型服务调用失败
+	CodeProviderTimeout        = 4003 // 模型服务响应超时
+	CodeProviderConfigNotFound = 4004 // 模型服务配置不存在
+	CodeProviderConfigExists   = 4005 // 模型服务配置已存在
 )
 
 // 错误消息映射
@@ -66,13 +99,34 @@ var codeMessages = map[int]string{
 	CodeWeakPassword:    "密码强度不够",
 	CodeInvalidPhone:    "手机号格式错误",
 
-	CodeElementNotFound: "六要素不存在",
-	CodeElementExists:   "六要素已存在",
-	CodeInvalidElement:  "六要素参数错误",
+	CodeElementNotFound:     "六要素不存在",
+	CodeElementExists:       "六要素已存在",
+	CodeInvalidElement:      "六要素参数错误",
+	CodeVersionNotFound:     "历史版本不存在",
+	CodeInvalidVariable:     "模板变量校验失败",
+	CodeTagNotFound:         "标签不存在",
+	CodeTagExists:           "标签已存在",
+	CodeFolderNotFound:      "文件夹不存在",
+	CodeFolderExists:        "文件夹已存在",
+	CodeTemplateNotFound:    "模板不存在",
+	CodeVersionConflict:     "记录已被修改，请基于最新内容重试",
+	CodeIfMatchRequired:     "缺少If-Match请求头",
+	CodeGenerationNotFound:  "生成记录不存在",
+	CodeComparisonNotFound:  "对比运行不存在",
+	CodeEvalDatasetNotFound: "评估数据集不存在",
+	CodeEvalRunNotFound:     "评估运行不存在",
+	CodeCatalogItemNotFound: "目录项不存在",
+	CodeCatalogItemExists:   "目录项已存在",
 
 	CodeInvalidToken: "Token无效",
 	CodeTokenExpired: "Token过期",
 	CodeTokenMissing: "Token缺失",
+
+	CodeProviderNotFound:       "模型服务不存在或未启用",
+	CodeProviderError:          "模型服务调用失败",
+	CodeProviderTimeout:        "模型服务响应超时",
+	CodeProviderConfigNotFound: "模型服务配置不存在",
+	CodeProviderConfigExists:   "模型服务配置已存在",
 }
 
 // GetMessage 根据错误码获取错误消息
@@ -153,6 +207,19 @@ func PageSuccessWithMessage(c *app.RequestContext, message string, data interfac
 	})
 }
 
+// CursorPageSuccessWithMessage 游标分页成功响应（自定义消息）
+func CursorPageSuccessWithMessage(c *app.RequestContext, message string, data interface{}, nextCursor, prevCursor string, size int, total *int64) {
+	c.JSON(http.StatusOK, CursorPageResponse{
+		Code:       CodeSuccess,
+		Message:    message,
+		Data:       data,
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+		Size:       size,
+		Total:      total,
+	})
+}
+
 // getHTTPStatus 根据业务错误码获取HTTP状态码
 func getHTTPStatus(code int) int {
 	switch {
@@ -162,10 +229,26 @@ func getHTTPStatus(code int) int {
 		return code
 	case code >= 1000 && code < 2000:
 		return http.StatusBadRequest
+	case code == CodeInvalidVariable || code == CodeTagExists || code == CodeFolderExists || code == CodeCatalogItemExists:
+		return http.StatusBadRequest
+	case code == CodeVersionConflict:
+		return http.StatusPreconditionFailed
+	case code == CodeIfMatchRequired:
+		return http.StatusPreconditionRequired
 	case code >= 2000 && code < 3000:
 		return http.StatusNotFound
 	case code >= 3000 && code < 4000:
 		return http.StatusUnauthorized
+	case code == CodeProviderNotFound:
+		return http.StatusBadRequest
+	case code == CodeProviderError:
+		return http.StatusBadGateway
+	case code == CodeProviderTimeout:
+		return http.StatusGatewayTimeout
+	case code == CodeProviderConfigNotFound:
+		return http.StatusNotFound
+	case code == CodeProviderConfigExists:
+		return http.StatusBadRequest
 	default:
 		return http.StatusInternalServerError
 	}
@@ -0,0 +1,150 @@
+package search
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// 高亮标签
+const (
+	HighlightPre  = "<em>"
+	HighlightPost = "</em>"
+)
+
+// HighlightOptions 高亮选项
+type HighlightOptions struct {
+	Context     int // 命中位置前后保留的字符数
+	MaxSnippets int // 每个字段最多返回的片段数
+}
+
+// span 命中区间（按 rune 计）
+type span struct {
+	start, end int
+}
+
+// Highlight 在文本中查找关键词并返回带高亮的片段
+//
+// 匹配大小写不敏感；片段中的原文会做 HTML 转义，命中部分以 <em></em> 包裹，
+// 片段被截断时以省略号标记。未命中时返回 nil。
+func Highlight(text string, words []string, opts HighlightOptions) []string {
+	if opts.Context <= 0 {
+		opts.Context = 30
+	}
+	if opts.MaxSnippets <= 0 {
+		opts.MaxSnippets = 3
+	}
+
+	runes := []rune(text)
+	matches := findMatches(runes, words)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	// 以命中位置为中心扩展上下文，并合并重叠的窗口
+	var windows []span
+	for _, m := range matches {
+		window := span{start: max(0, m.start-opts.Context), end: min(len(runes), m.end+opts.Context)}
+		if n := len(windows); n > 0 && window.start <= windows[n-1].end {
+			windows[n-1].end = max(windows[n-1].end, window.end)
+			continue
+		}
+		windows = append(windows, window)
+	}
+	if len(windows) > opts.MaxSnippets {
+		windows = windows[:opts.MaxSnippets]
+	}
+
+	snippets := make([]string, 0, len(windows))
+	for _, window := range windows {
+		var b strings.Builder
+		if window.start > 0 {
+			b.WriteString("…")
+		}
+		pos := window.start
+		for _, m := range matches {
+			if m.end <= window.start || m.start >= window.end {
+				continue
+			}
+			b.WriteString(html.EscapeString(strings.TrimLeftFunc(string(runes[pos:m.start]), func(r rune) bool {
+				return pos == window.start && unicode.IsSpace(r)
+			})))
+			b.WriteString(HighlightPre)
+			b.WriteString(html.EscapeString(string(runes[m.start:m.end])))
+			b.WriteString(HighlightPost)
+			pos = m.end
+		}
+		b.WriteString(html.EscapeString(string(runes[pos:window.end])))
+		if window.end < len(runes) {
+			b.WriteString("…")
+		}
+		snippets = append(snippets, strings.TrimSpace(b.String()))
+	}
+	return snippets
+}
+
+// findMatches 查找全部命中区间（按起始位置排序并合并重叠区间）
+func findMatches(runes []rune, words []string) []span {
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	var matches []span
+	for _, word := range words {
+		pattern := []rune(strings.ToLower(strings.TrimSpace(word)))
+		if len(pattern) == 0 {
+			continue
+		}
+		for i := 0; i+len(pattern) <= len(lower); i++ {
+			if equalRunes(lower[i:i+len(pattern)], pattern) {
+				matches = append(matches, span{start: i, end: i + len(pattern)})
+			}
+		}
+	}
+	if len(matches) == 0 {
+		return nil
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].start != matches[j].start {
+			return matches[i].start < matches[j].start
+		}
+		return matches[i].end > matches[j].end
+	})
+	merged := []span{matches[0]}
+	for _, m := range matches[1:] {
+		last := &merged[len(merged)-1]
+		if m.start <= last.end {
+			last.end = max(last.end, m.end)
+			continue
+		}
+		merged = append(merged, m)
+	}
+	return merged
+}
+
+// equalRunes 比较两个 rune 切片是否相同
+func equalRunes(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,37 @@
+package search
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHighlight(t *testing.T) {
+	snippets := Highlight("编写 Go 代码审查清单，关注 go 语言惯用法", []string{"go", "代码审查"}, HighlightOptions{})
+	assert.Equal(t, []string{"编写 <em>Go</em> <em>代码审查</em>清单，关注 <em>go</em> 语言惯用法"}, snippets)
+
+	assert.Nil(t, Highlight("没有命中", []string{"代码"}, HighlightOptions{}))
+	assert.Nil(t, Highlight("任意内容", nil, HighlightOptions{}))
+}
+
+func TestHighlightEscapesHTML(t *testing.T) {
+	snippets := Highlight("<b>输出</b> & 格式", []string{"输出"}, HighlightOptions{})
+	assert.Equal(t, []string{"&lt;b&gt;<em>输出</em>&lt;/b&gt; &amp; 格式"}, snippets)
+}
+
+func TestHighlightSnippets(t *testing.T) {
+	filler := strings.Repeat("无关内容", 10)
+	text := "开头命中" + filler + "中间命中" + filler + "结尾命中" + filler + "再次命中"
+
+	snippets := Highlight(text, []string{"命中"}, HighlightOptions{Context: 4, MaxSnippets: 2})
+	assert.Equal(t, []string{
+		"开头<em>命中</em>无关内容…",
+		"…内容中间<em>命中</em>无关内容…",
+	}, snippets)
+}
+
+func TestHighlightMergesOverlappingMatches(t *testing.T) {
+	snippets := Highlight("ABCD", []string{"abc", "bcd"}, HighlightOptions{})
+	assert.Equal(t, []string{"<em>ABCD</em>"}, snippets)
+}
@@ -0,0 +1,139 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Query 解析后的搜索表达式
+type Query struct {
+	Terms    []string // 普通关键词（需全部命中）
+	Phrases  []string // 双引号包裹的短语（需完整命中）
+	Excludes []string // 以 - 开头的排除词或短语
+}
+
+// ParseQuery 解析搜索表达式
+//
+// 支持以空白分隔的关键词、"双引号短语"（也支持中文引号“”）以及 -排除词 / -"排除短语"。
+// 关键词会去重，大小写不敏感。
+func ParseQuery(input string) Query {
+	var q Query
+	seen := make(map[string]bool)
+	add := func(list *[]string, kind, value string) {
+		value = strings.TrimSpace(strings.Join(strings.Fields(value), " "))
+		if value == "" {
+			return
+		}
+		key := kind + ":" + strings.ToLower(value)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		*list = append(*list, value)
+	}
+
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		exclude := false
+		if runes[i] == '-' {
+			exclude = true
+			i++
+			if i >= len(runes) || unicode.IsSpace(runes[i]) {
+				continue
+			}
+		}
+
+		if closing, ok := closingQuote(runes[i]); ok {
+			end := i + 1
+			for end < len(runes) && runes[end] != closing {
+				end++
+			}
+			phrase := string(runes[i+1 : end])
+			if exclude {
+				add(&q.Excludes, "exclude", phrase)
+			} else {
+				add(&q.Phrases, "phrase", phrase)
+			}
+			i = end + 1
+			continue
+		}
+
+		end := i
+		for end < len(runes) && !unicode.IsSpace(runes[end]) {
+			if _, ok := closingQuote(runes[end]); ok {
+				break
+			}
+			end++
+		}
+		term := string(runes[i:end])
+		if exclude {
+			add(&q.Excludes, "exclude", term)
+		} else {
+			add(&q.Terms, "term", term)
+		}
+		i = end
+	}
+
+	return q
+}
+
+// Positives 获取需要命中的关键词和短语
+func (q Query) Positives() []string {
+	positives := make([]string, 0, len(q.Terms)+len(q.Phrases))
+	positives = append(positives, q.Terms...)
+	return append(positives, q.Phrases...)
+}
+
+// IsEmpty 是否不包含任何条件
+func (q Query) IsEmpty() bool {
+	return len(q.Terms) == 0 && len(q.Phrases) == 0 && len(q.Excludes) == 0
+}
+
+// BooleanRequired 生成 MySQL BOOLEAN MODE 表达式：全部关键词和短语必须命中
+func BooleanRequired(words []string) string {
+	return booleanExpression(words, "+")
+}
+
+// BooleanAny 生成 MySQL BOOLEAN MODE 表达式：命中任意关键词或短语
+func BooleanAny(words []string) string {
+	return booleanExpression(words, "")
+}
+
+// booleanExpression 将每个词作为带引号的短语，避免用户输入被当作运算符
+func booleanExpression(words []string, operator string) string {
+	parts := make([]string, 0, len(words))
+	for _, word := range words {
+		word = strings.TrimSpace(strings.Map(func(r rune) rune {
+			if r == '"' {
+				return ' '
+			}
+			return r
+		}, word))
+		if word != "" {
+			parts = append(parts, operator+`"`+word+`"`)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// EscapeLike 转义 LIKE 模式中的通配符
+func EscapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+// closingQuote 判断是否为起始引号并返回对应的结束引号
+func closingQuote(r rune) (rune, bool) {
+	switch r {
+	case '"':
+		return '"', true
+	case '“':
+		return '”', true
+	default:
+		return 0, false
+	}
+}
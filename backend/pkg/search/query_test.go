@@ -0,0 +1,45 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQuery(t *testing.T) {
+	q := ParseQuery(`代码审查  "单元 测试" -Java -"遗留 系统" “中文短语” 代码审查 - `)
+
+	assert.Equal(t, []string{"代码审查"}, q.Terms)
+	assert.Equal(t, []string{"单元 测试", "中文短语"}, q.Phrases)
+	assert.Equal(t, []string{"Java", "遗留 系统"}, q.Excludes)
+	assert.Equal(t, []string{"代码审查", "单元 测试", "中文短语"}, q.Positives())
+	assert.False(t, q.IsEmpty())
+}
+
+func TestParseQueryEdgeCases(t *testing.T) {
+	assert.True(t, ParseQuery("   ").IsEmpty())
+
+	// 未闭合的引号视为短语直到末尾
+	q := ParseQuery(`"未闭合 短语`)
+	assert.Equal(t, []string{"未闭合 短语"}, q.Phrases)
+
+	// 关键词中间的连字符保持原样，引号会切分关键词
+	q = ParseQuery(`e-mail abc"def"`)
+	assert.Equal(t, []string{"e-mail", "abc"}, q.Terms)
+	assert.Equal(t, []string{"def"}, q.Phrases)
+
+	// 只有排除词时没有需要命中的关键词
+	q = ParseQuery("-草稿")
+	assert.Empty(t, q.Positives())
+	assert.False(t, q.IsEmpty())
+}
+
+func TestBooleanExpression(t *testing.T) {
+	assert.Equal(t, `+"代码" +"单元 测试"`, BooleanRequired([]string{"代码", "单元 测试"}))
+	assert.Equal(t, `"a b" "c"`, BooleanAny([]string{`a"b`, "c", " "}))
+	assert.Equal(t, "", BooleanRequired(nil))
+}
+
+func TestEscapeLike(t *testing.T) {
+	assert.Equal(t, `100\%\_a\\b`, EscapeLike(`100%_a\b`))
+}
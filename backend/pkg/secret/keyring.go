@@ -0,0 +1,144 @@
+// Package secret 使用 AES-256-GCM 加密保存在数据库中的敏感信息（如模型服务的 API Key）
+//
+// 密钥为 Base64 编码的 32 字节随机数。每段密文同时记录加密所用密钥的 ID，
+// 轮换主密钥时把旧密钥保留在 previous 中用于解密，再用当前密钥重新加密即可。
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// KeySize 密钥长度（字节）
+const KeySize = 32
+
+// ErrUnknownKey 密文使用的密钥不在密钥环中
+var ErrUnknownKey = errors.New("未知的加密密钥")
+
+// Keyring 加密密钥环：当前密钥用于加密，当前密钥和旧密钥都可用于解密
+type Keyring struct {
+	currentID string
+	keys      map[string]cipher.AEAD
+}
+
+// NewKeyring 创建密钥环，current 为当前密钥，previous 为轮换前使用的旧密钥
+func NewKeyring(current string, previous ...string) (*Keyring, error) {
+	k := &Keyring{keys: make(map[string]cipher.AEAD, len(previous)+1)}
+
+	id, err := k.add(current)
+	if err != nil {
+		return nil, fmt.Errorf("当前密钥错误: %w", err)
+	}
+	k.currentID = id
+
+	for i, key := range previous {
+		if _, err := k.add(key); err != nil {
+			return nil, fmt.Errorf("第%d个旧密钥错误: %w", i+1, err)
+		}
+	}
+	return k, nil
+}
+
+// ParseKey 解析 Base64 编码的密钥
+func ParseKey(key string) ([]byte, error) {
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, errors.New("密钥必须是 Base64 编码")
+	}
+	if len(raw) != KeySize {
+		return nil, fmt.Errorf("密钥长度必须为%d字节，实际为%d字节", KeySize, len(raw))
+	}
+	return raw, nil
+}
+
+// GenerateKey 生成 Base64 编码的随机密钥
+func GenerateKey() (string, error) {
+	raw := make([]byte, KeySize)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(raw), nil
+}
+
+// add 加入一个密钥，返回密钥 ID
+func (k *Keyring) add(key string) (string, error) {
+	raw, err := ParseKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return "", err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+
+	id := keyID(raw)
+	k.keys[id] = aead
+	return id, nil
+}
+
+// keyID 密钥 ID：密钥 SHA-256 摘要的前 4 字节
+func keyID(raw []byte) string {
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:4])
+}
+
+// CurrentKeyID 当前密钥的 ID
+func (k *Keyring) CurrentKeyID() string {
+	return k.currentID
+}
+
+// Encrypt 使用当前密钥加密，返回 Base64 编码的密文（随机 nonce 在前）和密钥 ID
+//
+// associated 为附加认证数据（如所属用户），解密时必须一致，防止密文被挪用到其他记录。
+func (k *Keyring) Encrypt(plaintext string, associated []byte) (ciphertext, keyID string, err error) {
+	aead := k.keys[k.currentID]
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", "", err
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), associated)
+	return base64.StdEncoding.EncodeToString(sealed), k.currentID, nil
+}
+
+// Decrypt 使用 keyID 对应的密钥解密
+func (k *Keyring) Decrypt(ciphertext, keyID string, associated []byte) (string, error) {
+	aead, ok := k.keys[keyID]
+	if !ok {
+		return "", ErrUnknownKey
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", errors.New("密文格式错误")
+	}
+
+	nonce, data := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, data, associated)
+	if err != nil {
+		return "", errors.New("解密失败")
+	}
+	return string(plaintext), nil
+}
+
+// Mask 只保留末尾几个字符的掩码，如 ****c3d4；过短时全部隐藏
+func Mask(value string) string {
+	const visible = 4
+	runes := []rune(value)
+	if len(runes) < visible*2 {
+		return "****"
+	}
+	return "****" + string(runes[len(runes)-visible:])
+}
@@ -0,0 +1,88 @@
+package secret
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(t *testing.T) string {
+	key, err := GenerateKey()
+	require.NoError(t, err)
+	return key
+}
+
+func TestKeyring_EncryptDecrypt(t *testing.T) {
+	k, err := NewKeyring(testKey(t))
+	require.NoError(t, err)
+
+	ciphertext, keyID, err := k.Encrypt("sk-test-1234567890", []byte("user:1"))
+	require.NoError(t, err)
+	assert.Equal(t, k.CurrentKeyID(), keyID)
+	assert.NotContains(t, ciphertext, "sk-test")
+
+	plaintext, err := k.Decrypt(ciphertext, keyID, []byte("user:1"))
+	require.NoError(t, err)
+	assert.Equal(t, "sk-test-1234567890", plaintext)
+
+	// 每次加密使用随机 nonce
+	again, _, err := k.Encrypt("sk-test-1234567890", []byte("user:1"))
+	require.NoError(t, err)
+	assert.NotEqual(t, ciphertext, again)
+
+	// 附加认证数据不一致或密文被篡改时解密失败
+	_, err = k.Decrypt(ciphertext, keyID, []byte("user:2"))
+	assert.EqualError(t, err, "解密失败")
+	sealed, _ := base64.StdEncoding.DecodeString(ciphertext)
+	sealed[len(sealed)-1] ^= 1
+	_, err = k.Decrypt(base64.StdEncoding.EncodeToString(sealed), keyID, []byte("user:1"))
+	assert.EqualError(t, err, "解密失败")
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	oldKey, newKey := testKey(t), testKey(t)
+
+	old, err := NewKeyring(oldKey)
+	require.NoError(t, err)
+	ciphertext, oldID, err := old.Encrypt("sk-old", nil)
+	require.NoError(t, err)
+
+	// 新密钥环用旧密钥解密，用新密钥重新加密
+	rotated, err := NewKeyring(newKey, oldKey)
+	require.NoError(t, err)
+	assert.NotEqual(t, oldID, rotated.CurrentKeyID())
+
+	plaintext, err := rotated.Decrypt(ciphertext, oldID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "sk-old", plaintext)
+
+	_, newID, err := rotated.Encrypt(plaintext, nil)
+	require.NoError(t, err)
+	assert.Equal(t, rotated.CurrentKeyID(), newID)
+
+	// 移除旧密钥后无法解密旧密文
+	current, err := NewKeyring(newKey)
+	require.NoError(t, err)
+	_, err = current.Decrypt(ciphertext, oldID, nil)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestNewKeyring_InvalidKey(t *testing.T) {
+	_, err := NewKeyring("not-base64!")
+	assert.EqualError(t, err, "当前密钥错误: 密钥必须是 Base64 编码")
+
+	_, err = NewKeyring(base64.StdEncoding.EncodeToString([]byte("short")))
+	assert.EqualError(t, err, "当前密钥错误: 密钥长度必须为32字节，实际为5字节")
+
+	_, err = NewKeyring(testKey(t), "")
+	assert.Error(t, err)
+}
+
+func TestMask(t *testing.T) {
+	assert.Equal(t, "****c3d4", Mask("sk-proj-a1b2c3d4"))
+	assert.Equal(t, "****", Mask("short"))
+	assert.False(t, strings.Contains(Mask("sk-proj-a1b2c3d4"), "proj"))
+}
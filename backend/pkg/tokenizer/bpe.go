@@ -0,0 +1,159 @@
+package tokenizer
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// BPE 字节级 BPE 编码器，词表为 tiktoken 格式
+type BPE struct {
+	ranks   map[string]int
+	pattern *regexp.Regexp
+}
+
+// LoadBPE 读取 tiktoken 格式的词表（每行为 base64 编码的字节序列和序号）
+func LoadBPE(r io.Reader, pattern *regexp.Regexp) (*BPE, error) {
+	ranks := make(map[string]int)
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("词表第%d行格式错误", line)
+		}
+		token, err := base64.StdEncoding.DecodeString(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("词表第%d行格式错误: %w", line, err)
+		}
+		rank, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("词表第%d行格式错误: %w", line, err)
+		}
+		ranks[string(token)] = rank
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取词表失败: %w", err)
+	}
+
+	// 每个单字节都必须在词表中，否则无法编码任意文本
+	for b := 0; b < 256; b++ {
+		if _, ok := ranks[string([]byte{byte(b)})]; !ok {
+			return nil, fmt.Errorf("词表缺少单字节 0x%02x", b)
+		}
+	}
+
+	return &BPE{ranks: ranks, pattern: pattern}, nil
+}
+
+// Encode 将文本编码为令牌序号
+func (b *BPE) Encode(text string) []int {
+	var tokens []int
+	for _, piece := range split(b.pattern, text) {
+		if rank, ok := b.ranks[piece]; ok {
+			tokens = append(tokens, rank)
+			continue
+		}
+		tokens = append(tokens, b.encodePiece(piece)...)
+	}
+	return tokens
+}
+
+// Count 统计文本的令牌数
+func (b *BPE) Count(text string) int {
+	return len(b.Encode(text))
+}
+
+// Exact 词表编码的结果是精确值
+func (b *BPE) Exact() bool {
+	return true
+}
+
+// encodePiece 按 tiktoken 的 byte_pair_merge 编码单个片段：以字节偏移表示各部分，
+// 记录每对相邻部分合并后的序号，每次合并序号最小（相同时取最左）的一对，只重新计算两侧的相邻对
+func (b *BPE) encodePiece(piece string) []int {
+	n := len(piece)
+	// 以双向链表维护各部分的起始偏移，部分 i 为 piece[i:next[i]]
+	next := make([]int, n)
+	prev := make([]int, n)
+	merged := make([]bool, n)
+	for i := range next {
+		next[i] = i + 1
+		prev[i] = i - 1
+	}
+
+	pairs := make(pairHeap, 0, n)
+	push := func(i int) {
+		if i < 0 || next[i] >= n {
+			return
+		}
+		end := next[next[i]]
+		if rank, ok := b.ranks[piece[i:end]]; ok {
+			heap.Push(&pairs, pair{rank: rank, start: i, end: end})
+		}
+	}
+	for i := 0; i < n-1; i++ {
+		push(i)
+	}
+
+	for pairs.Len() > 0 {
+		p := heap.Pop(&pairs).(pair)
+		// 相邻部分已变化的记录作废
+		if merged[p.start] || next[p.start] >= n || next[next[p.start]] != p.end {
+			continue
+		}
+		right := next[p.start]
+		merged[right] = true
+		next[p.start] = next[right]
+		if next[right] < n {
+			prev[next[right]] = p.start
+		}
+		push(p.start)
+		push(prev[p.start])
+	}
+
+	var tokens []int
+	for i := 0; i < n; i = next[i] {
+		tokens = append(tokens, b.ranks[piece[i:next[i]]])
+	}
+	return tokens
+}
+
+// pair 相邻两部分合并后的序号，end 为合并后的结束偏移，用于判断记录是否过期
+type pair struct {
+	rank, start, end int
+}
+
+// pairHeap 按序号、起始偏移排序的最小堆
+type pairHeap []pair
+
+func (h pairHeap) Len() int { return len(h) }
+
+func (h pairHeap) Less(i, j int) bool {
+	if h[i].rank != h[j].rank {
+		return h[i].rank < h[j].rank
+	}
+	return h[i].start < h[j].start
+}
+
+func (h pairHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *pairHeap) Push(x interface{}) { *h = append(*h, x.(pair)) }
+
+func (h *pairHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,78 @@
+package tokenizer
+
+import (
+	"math"
+	"regexp"
+	"unicode"
+)
+
+// profile 近似估算的参数，按各编码方式的实测结果校准
+type profile struct {
+	cjkPerRune      float64 // 每个中日韩字符的令牌数
+	lettersPerToken float64 // 其他文字平均每个令牌的字符数
+	symbolsPerToken float64 // 标点符号平均每个令牌的字符数
+	digitsPerToken  float64 // 数字平均每个令牌的字符数
+}
+
+// 各编码方式的估算参数
+var profiles = map[string]profile{
+	EncodingCL100K: {cjkPerRune: 1.2, lettersPerToken: 5, symbolsPerToken: 2, digitsPerToken: 3},
+	EncodingO200K:  {cjkPerRune: 0.8, lettersPerToken: 5.5, symbolsPerToken: 2, digitsPerToken: 3},
+	EncodingCJK:    {cjkPerRune: 0.65, lettersPerToken: 4, symbolsPerToken: 2, digitsPerToken: 1},
+}
+
+// Estimator 没有词表时按字符类别估算令牌数
+type Estimator struct {
+	pattern *regexp.Regexp
+	profile profile
+}
+
+// newEstimator 创建编码方式对应的估算器
+func newEstimator(encoding string) *Estimator {
+	return &Estimator{pattern: patternFor(encoding), profile: profiles[encoding]}
+}
+
+// Count 估算文本的令牌数
+//
+// 先按编码方式的规则预分词，再根据每个片段中中日韩字符、其他文字、数字和符号的数量估算，
+// 每个片段至少计为 1 个令牌。
+func (e *Estimator) Count(text string) int {
+	total := 0
+	for _, piece := range split(e.pattern, text) {
+		var cjk, letters, digits, symbols int
+		for _, r := range piece {
+			switch {
+			case isCJK(r):
+				cjk++
+			case unicode.IsLetter(r) || unicode.IsMark(r):
+				letters++
+			case unicode.IsDigit(r):
+				digits++
+			case !unicode.IsSpace(r):
+				symbols++
+			}
+		}
+
+		estimate := float64(cjk)*e.profile.cjkPerRune +
+			math.Ceil(float64(letters)/e.profile.lettersPerToken) +
+			math.Ceil(float64(digits)/e.profile.digitsPerToken) +
+			math.Ceil(float64(symbols)/e.profile.symbolsPerToken)
+		if tokens := int(math.Ceil(estimate)); tokens > 1 {
+			total += tokens
+		} else {
+			total++
+		}
+	}
+	return total
+}
+
+// Exact 估算的结果不是精确值
+func (e *Estimator) Exact() bool {
+	return false
+}
+
+// isCJK 判断是否为中日韩字符
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) ||
+		(r >= 0x3000 && r <= 0x303f) || (r >= 0xff00 && r <= 0xffef)
+}
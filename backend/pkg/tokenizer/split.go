@@ -0,0 +1,79 @@
+package tokenizer
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 预分词规则，与 tiktoken 的 cl100k_base、o200k_base 一致
+//
+// 原规则中的 `\s+(?!\S)` 使用了 Go 不支持的否定前瞻，这里改为 `\s+` 并在 split 中回退最后一个空白字符；
+// Go 的 `\s` 只匹配 ASCII 空白，编译前替换为 Unicode 空白。
+var (
+	cl100kPattern = compilePattern(`\A(?:` +
+		`(?i:'s|'t|'re|'ve|'m|'ll|'d)` +
+		`|[^\r\n\p{L}\p{N}]?\p{L}+` +
+		`|\p{N}{1,3}` +
+		`| ?[^\s\p{L}\p{N}]+[\r\n]*` +
+		`|[\s]*[\r\n]+` +
+		`|[\s]+)`)
+
+	o200kPattern = compilePattern(`\A(?:` +
+		`[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]*[\p{Ll}\p{Lm}\p{Lo}\p{M}]+(?i:'s|'t|'re|'ve|'m|'ll|'d)?` +
+		`|[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]+[\p{Ll}\p{Lm}\p{Lo}\p{M}]*(?i:'s|'t|'re|'ve|'m|'ll|'d)?` +
+		`|\p{N}{1,3}` +
+		`| ?[^\s\p{L}\p{N}]+[\r\n/]*` +
+		`|[\s]*[\r\n]+` +
+		`|[\s]+)`)
+)
+
+// compilePattern 将规则字符类中的 `\s` 替换为 Unicode 空白后编译
+func compilePattern(pattern string) *regexp.Regexp {
+	return regexp.MustCompile(strings.ReplaceAll(pattern, `\s`, `\s\v\p{Z}\x{85}`))
+}
+
+// patternFor 获取编码方式的预分词规则，近似估算的编码方式沿用 cl100k_base 的规则
+func patternFor(encoding string) *regexp.Regexp {
+	if encoding == EncodingO200K {
+		return o200kPattern
+	}
+	return cl100kPattern
+}
+
+// split 按预分词规则切分文本，BPE 合并只在每个片段内部进行
+func split(pattern *regexp.Regexp, text string) []string {
+	var pieces []string
+	for len(text) > 0 {
+		loc := pattern.FindStringIndex(text)
+		end := len(text)
+		if loc != nil && loc[1] > 0 {
+			end = loc[1]
+		} else {
+			_, end = utf8.DecodeRuneInString(text)
+		}
+
+		// 不含换行的空白串后面紧跟非空白字符时，最后一个空白字符留给下一个片段
+		if piece := text[:end]; end < len(text) && isBlank(piece) {
+			_, size := utf8.DecodeLastRuneInString(piece)
+			if next, _ := utf8.DecodeRuneInString(text[end:]); size < len(piece) && !unicode.IsSpace(next) {
+				end -= size
+			}
+		}
+
+		pieces = append(pieces, text[:end])
+		text = text[end:]
+	}
+	return pieces
+}
+
+// isBlank 判断片段是否为不含换行的空白串
+func isBlank(piece string) bool {
+	for _, r := range piece {
+		if !unicode.IsSpace(r) || r == '\r' || r == '\n' {
+			return false
+		}
+	}
+	return true
+}
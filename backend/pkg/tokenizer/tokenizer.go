@@ -0,0 +1,111 @@
+// Package tokenizer 统计提示词的令牌数
+//
+// cl100k_base、o200k_base 使用字节级 BPE 精确计数，词表优先从词表目录读取，否则使用内置的 tiktoken 词表；
+// cjk_approx 面向中文优化的其他模型，按预分词结果和字符类别近似估算。
+package tokenizer
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// 编码方式
+const (
+	EncodingCL100K = "cl100k_base" // GPT-4、GPT-3.5
+	EncodingO200K  = "o200k_base"  // GPT-4o 及之后的模型
+	EncodingCJK    = "cjk_approx"  // 面向中文优化的其他模型（近似估算）
+)
+
+// Encodings 支持的编码方式
+var Encodings = []string{EncodingCL100K, EncodingO200K, EncodingCJK}
+
+// vocabEncodings 可以加载 tiktoken 词表的编码方式
+var vocabEncodings = map[string]bool{EncodingCL100K: true, EncodingO200K: true}
+
+// builtinVocabs 内置的 tiktoken 词表，与 https://openaipublic.blob.core.windows.net/encodings/ 发布的文件相同
+//
+//go:embed vocab/*.tiktoken
+var builtinVocabs embed.FS
+
+// Tokenizer 令牌计数器
+type Tokenizer interface {
+	Count(text string) int
+	Exact() bool // 是否为精确计数
+}
+
+// Registry 按编码方式提供令牌计数器，词表在首次使用时加载
+type Registry struct {
+	vocabDir string
+
+	mu         sync.Mutex
+	tokenizers map[string]Tokenizer
+}
+
+// NewRegistry 创建令牌计数器集合，vocabDir 为 tiktoken 词表文件所在目录，为空时使用内置词表
+func NewRegistry(vocabDir string) *Registry {
+	return &Registry{vocabDir: vocabDir, tokenizers: make(map[string]Tokenizer)}
+}
+
+// IsEncoding 判断是否为支持的编码方式
+func IsEncoding(encoding string) bool {
+	_, ok := profiles[encoding]
+	return ok
+}
+
+// Get 获取编码方式对应的令牌计数器
+//
+// 词表目录中存在 <编码方式>.tiktoken 文件时使用该文件，否则使用内置词表。
+func (r *Registry) Get(encoding string) (Tokenizer, error) {
+	if !IsEncoding(encoding) {
+		return nil, fmt.Errorf("不支持的编码方式: %s", encoding)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if tokenizer, ok := r.tokenizers[encoding]; ok {
+		return tokenizer, nil
+	}
+
+	tokenizer, err := r.load(encoding)
+	if err != nil {
+		return nil, err
+	}
+	r.tokenizers[encoding] = tokenizer
+	return tokenizer, nil
+}
+
+// load 加载编码方式的词表，近似估算的编码方式返回估算器
+func (r *Registry) load(encoding string) (Tokenizer, error) {
+	if !vocabEncodings[encoding] {
+		return newEstimator(encoding), nil
+	}
+
+	file, err := r.openVocab(encoding)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return LoadBPE(file, patternFor(encoding))
+}
+
+// openVocab 打开词表目录中的词表文件，不存在时打开内置词表
+func (r *Registry) openVocab(encoding string) (io.ReadCloser, error) {
+	name := encoding + ".tiktoken"
+	if r.vocabDir != "" {
+		file, err := os.Open(filepath.Join(r.vocabDir, name))
+		if err == nil {
+			return file, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("打开词表文件失败: %w", err)
+		}
+	}
+	return builtinVocabs.Open("vocab/" + name)
+}
@@ -0,0 +1,228 @@
+package tokenizer
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testVocab 生成测试用的 tiktoken 词表：全部单字节加上给定的合并结果（按顺序编号）
+func testVocab(merges ...string) string {
+	var b strings.Builder
+	rank := 0
+	for i := 0; i < 256; i++ {
+		fmt.Fprintf(&b, "%s %d\n", base64.StdEncoding.EncodeToString([]byte{byte(i)}), rank)
+		rank++
+	}
+	for _, merge := range merges {
+		fmt.Fprintf(&b, "%s %d\n", base64.StdEncoding.EncodeToString([]byte(merge)), rank)
+		rank++
+	}
+	return b.String()
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"单词和空格", "hello world", []string{"hello", " world"}},
+		{"多个空格", "a   b", []string{"a", "  ", " b"}},
+		{"缩写", "I'm here", []string{"I", "'m", " here"}},
+		{"数字每三位一组", "1234567", []string{"123", "456", "7"}},
+		{"换行", "a\n\nb", []string{"a", "\n\n", "b"}},
+		{"中文标点并入后面的文字", "你好，世界", []string{"你好", "，世界"}},
+		{"符号", "x += 1;", []string{"x", " +=", " ", "1", ";"}},
+		{"结尾空格", "end  ", []string{"end", "  "}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, split(cl100kPattern, tt.text))
+		})
+	}
+
+	// o200k_base 按大小写切分单词
+	assert.Equal(t, []string{"Hello", "World"}, split(o200kPattern, "HelloWorld"))
+	assert.Equal(t, []string{"HelloWorld"}, split(cl100kPattern, "HelloWorld"))
+}
+
+func TestBPE(t *testing.T) {
+	bpe, err := LoadBPE(strings.NewReader(testVocab("ab", "abc", " a", " ab")), cl100kPattern)
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{257}, bpe.Encode("abc"))
+	assert.Equal(t, []int{257, 259}, bpe.Encode("abc ab"))
+	assert.Equal(t, []int{256, 'd'}, bpe.Encode("abd"))
+	assert.Equal(t, 0, bpe.Count(""))
+	assert.Equal(t, len("中"), bpe.Count("中"))
+	assert.True(t, bpe.Exact())
+}
+
+// naiveEncodePiece 逐轮扫描全部相邻对的合并实现，作为对照
+func naiveEncodePiece(b *BPE, piece string) []int {
+	parts := make([]string, len(piece))
+	for i := range parts {
+		parts[i] = piece[i : i+1]
+	}
+	for len(parts) > 1 {
+		best, bestRank := -1, 0
+		for i := 0; i < len(parts)-1; i++ {
+			if rank, ok := b.ranks[parts[i]+parts[i+1]]; ok && (best < 0 || rank < bestRank) {
+				best, bestRank = i, rank
+			}
+		}
+		if best < 0 {
+			break
+		}
+		parts[best] += parts[best+1]
+		parts = append(parts[:best+1], parts[best+2:]...)
+	}
+	tokens := make([]int, len(parts))
+	for i, part := range parts {
+		tokens[i] = b.ranks[part]
+	}
+	return tokens
+}
+
+// longCJK 生成不含空格的长中文文本，预切分后为单个片段
+func longCJK(chars int) string {
+	const sample = "上下文工程六要素包括任务目标人工智能角色我的角色关键信息行为规则和交付格式每个字段都会影响模型的输出质量"
+	runes := []rune(sample)
+	var b strings.Builder
+	for i := 0; i < chars; i++ {
+		b.WriteRune(runes[(i*7+i/len(runes))%len(runes)])
+	}
+	return b.String()
+}
+
+func TestBPE_LongCJK(t *testing.T) {
+	registry := NewRegistry("")
+	for _, encoding := range []string{EncodingCL100K, EncodingO200K} {
+		t.Run(encoding, func(t *testing.T) {
+			tokenizer, err := registry.Get(encoding)
+			require.NoError(t, err)
+			bpe := tokenizer.(*BPE)
+
+			// 与逐轮扫描的结果一致
+			text := longCJK(600)
+			require.Len(t, split(bpe.pattern, text), 1)
+			assert.Equal(t, naiveEncodePiece(bpe, text), bpe.encodePiece(text))
+
+			// 超长文本也能快速完成
+			assert.Positive(t, bpe.Count(longCJK(100000)))
+		})
+	}
+}
+
+func BenchmarkBPE_LongCJK(b *testing.B) {
+	tokenizer, err := NewRegistry("").Get(EncodingCL100K)
+	require.NoError(b, err)
+	text := longCJK(4800)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tokenizer.Count(text)
+	}
+}
+
+func TestLoadBPE_Invalid(t *testing.T) {
+	_, err := LoadBPE(strings.NewReader("YQ== 0\n"), cl100kPattern)
+	assert.EqualError(t, err, "词表缺少单字节 0x00")
+
+	_, err = LoadBPE(strings.NewReader("YQ==\n"), cl100kPattern)
+	assert.EqualError(t, err, "词表第1行格式错误")
+}
+
+func TestEstimator(t *testing.T) {
+	cl100k := newEstimator(EncodingCL100K)
+	o200k := newEstimator(EncodingO200K)
+	cjk := newEstimator(EncodingCJK)
+
+	assert.False(t, cl100k.Exact())
+	assert.Equal(t, 0, cl100k.Count(""))
+	assert.Equal(t, 2, cl100k.Count("hello world"))
+
+	chinese := "请根据以下销售数据，整理一份本周的销售周报，重点说明同比变化的原因。"
+	assert.Greater(t, cl100k.Count(chinese), o200k.Count(chinese))
+	assert.Greater(t, o200k.Count(chinese), cjk.Count(chinese))
+	assert.Greater(t, cjk.Count(chinese), 0)
+}
+
+func TestRegistry(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, EncodingCL100K+".tiktoken"), []byte(testVocab("ab")), 0o644))
+
+	registry := NewRegistry(dir)
+	tokenizer, err := registry.Get(EncodingCL100K)
+	require.NoError(t, err)
+	assert.True(t, tokenizer.Exact())
+	assert.Equal(t, 1, tokenizer.Count("ab"))
+
+	// 词表目录中没有的词表使用内置词表
+	tokenizer, err = registry.Get(EncodingO200K)
+	require.NoError(t, err)
+	assert.True(t, tokenizer.Exact())
+	assert.Equal(t, 2, tokenizer.Count("hello world"))
+
+	// 面向中文优化的其他模型始终近似估算
+	tokenizer, err = NewRegistry("").Get(EncodingCJK)
+	require.NoError(t, err)
+	assert.False(t, tokenizer.Exact())
+
+	_, err = registry.Get("p50k_base")
+	assert.EqualError(t, err, "不支持的编码方式: p50k_base")
+
+	// 词表格式错误
+	require.NoError(t, os.WriteFile(filepath.Join(dir, EncodingO200K+".tiktoken"), []byte("bad"), 0o644))
+	_, err = NewRegistry(dir).Get(EncodingO200K)
+	assert.Error(t, err)
+}
+
+func TestBuiltinVocabs(t *testing.T) {
+	// tiktoken 发布的词表文件校验值
+	checksums := map[string]string{
+		EncodingCL100K: "223921b76ee99bde995b7ff738513eef100fb51d18c93597a113bcffe865b2a7",
+		EncodingO200K:  "446a9538cb6c348e3516120d7c08b09f57c36495e2acfffe59a5bf8b0cfb1a2d",
+	}
+	for encoding, checksum := range checksums {
+		data, err := builtinVocabs.ReadFile("vocab/" + encoding + ".tiktoken")
+		require.NoError(t, err)
+		assert.Equal(t, checksum, fmt.Sprintf("%x", sha256.Sum256(data)), encoding)
+	}
+
+	// 与 tiktoken 的编码结果一致
+	tests := []struct {
+		encoding string
+		text     string
+		want     []int
+	}{
+		{EncodingCL100K, "hello world", []int{15339, 1917}},
+		{EncodingCL100K, "tiktoken is great!", []int{83, 1609, 5963, 374, 2294, 0}},
+		{EncodingCL100K, "antidisestablishmentarianism", []int{519, 85342, 34500, 479, 8997, 2191}},
+		{EncodingCL100K, "2 + 2 = 4", []int{17, 489, 220, 17, 284, 220, 19}},
+		{EncodingCL100K, "お誕生日おめでとう", []int{33334, 45918, 243, 21990, 9080, 33334, 62004, 16556, 78699}},
+		{EncodingO200K, "hello world", []int{24912, 2375}},
+		{EncodingO200K, "tiktoken is great!", []int{83, 8251, 2488, 382, 2212, 0}},
+		{EncodingO200K, "antidisestablishmentarianism", []int{493, 129901, 376, 160388, 21203, 2367}},
+		{EncodingO200K, "2 + 2 = 4", []int{17, 659, 220, 17, 314, 220, 19}},
+		{EncodingO200K, "お誕生日おめでとう", []int{8930, 9697, 243, 128225, 8930, 17693, 4344, 48669}},
+	}
+	registry := NewRegistry("")
+	for _, tt := range tests {
+		t.Run(tt.encoding+"/"+tt.text, func(t *testing.T) {
+			tokenizer, err := registry.Get(tt.encoding)
+			require.NoError(t, err)
+			require.True(t, tokenizer.Exact())
+			assert.Equal(t, tt.want, tokenizer.(*BPE).Encode(tt.text))
+			assert.Equal(t, len(tt.want), tokenizer.Count(tt.text))
+		})
+	}
+}
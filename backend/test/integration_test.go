@@ -13,6 +13,7 @@ import (
 	"cese-backend/internal/model"
 	"cese-backend/internal/repository"
 	"cese-backend/internal/service"
+	"cese-backend/pkg/eval"
 	"cese-backend/pkg/response"
 
 	"github.com/cloudwego/hertz/pkg/app/server"
@@ -69,15 +70,32 @@ func (suite *IntegrationTestSuite) SetupSuite() {
 
 	// 创建Repository实例
 	userRepo := repository.NewUserRepository(repository.GetDB())
-	elementRepo := repository.NewContextElementRepository(repository.GetDB())
+	elementRepo := repository.NewContextElementRepository(repository.GetDB(), cfg.Search)
+	versionRepo := repository.NewContextElementVersionRepository(repository.GetDB())
+	variableRepo := repository.NewContextElementVariableRepository(repository.GetDB())
+	usageRepo := repository.NewContextElementUsageRepository(repository.GetDB())
+	tagRepo := repository.NewTagRepository(repository.GetDB())
+	folderRepo := repository.NewFolderRepository(repository.GetDB())
+	templateRepo := repository.NewTemplateRepository(repository.GetDB())
+	generationRecordRepo := repository.NewGenerationRecordRepository(repository.GetDB())
+	providerConfigRepo := repository.NewProviderConfigRepository(repository.GetDB())
+	comparisonRepo := repository.NewComparisonRepository(repository.GetDB())
+	evalRepo := repository.NewEvalRepository(repository.GetDB())
+	catalogRepo := repository.NewCatalogRepository(repository.GetDB())
 
 	// 创建Service实例
 	userService := service.NewUserService(userRepo, cfg)
-	elementService := service.NewContextElementService(elementRepo, cfg)
+	elementService := service.NewContextElementService(elementRepo, versionRepo, variableRepo, folderRepo, usageRepo, cfg)
+	tagService := service.NewTagService(tagRepo)
+	folderService := service.NewFolderService(folderRepo)
+	templateService := service.NewTemplateService(templateRepo, elementRepo, folderRepo, variableRepo, cfg)
+	generationService := service.NewGenerationService(elementService, generationRecordRepo, comparisonRepo, evalRepo, cfg)
+	providerConfigService := service.NewProviderConfigService(providerConfigRepo, cfg)
+	catalogService := service.NewCatalogService(catalogRepo)
 
 	// 创建Hertz服务器
-	h := server.Default(server.WithHostPorts(cfg.GetServerAddr()))
-	handler.SetupRoutes(h, cfg, userService, elementService)
+	h := server.Default(server.WithHostPorts(cfg.GetServerAddr()), server.WithMaxRequestBodySize(handler.MaxRequestBodySize))
+	handler.SetupRoutes(h, cfg, userService, elementService, tagService, folderService, templateService, generationService, providerConfigService, catalogService)
 	suite.server = h
 
 	// 启动服务器
@@ -93,6 +111,20 @@ func (suite *IntegrationTestSuite) SetupSuite() {
 func (suite *IntegrationTestSuite) TearDownSuite() {
 	// 清理测试数据
 	db := repository.GetDB()
+	db.Exec("DELETE FROM cese_generation_record")
+	db.Exec("DELETE FROM cese_eval_result")
+	db.Exec("DELETE FROM cese_eval_run")
+	db.Exec("DELETE FROM cese_eval_case")
+	db.Exec("DELETE FROM cese_eval_dataset")
+	db.Exec("DELETE FROM cese_comparison_output")
+	db.Exec("DELETE FROM cese_comparison_variant")
+	db.Exec("DELETE FROM cese_comparison_run")
+	db.Exec("DELETE FROM cese_context_element_version")
+	db.Exec("DELETE FROM cese_context_element_variable")
+	db.Exec("DELETE FROM cese_context_element_tag")
+	db.Exec("DELETE FROM cese_tag")
+	db.Exec("DELETE FROM cese_folder")
+	db.Exec("DELETE FROM cese_template")
 	db.Exec("DELETE FROM cese_context_element")
 	db.Exec("DELETE FROM cese_user")
 
@@ -348,6 +380,142 @@ func (suite *IntegrationTestSuite) testDeleteContextElement(elementID uint64) {
 	assert.Equal(suite.T(), "删除成功", result.Message)
 }
 
+// TestPurgeDeletedCascade 测试彻底删除六要素时一并删除依赖它的数据
+func (suite *IntegrationTestSuite) TestPurgeDeletedCascade() {
+	db := repository.GetDB()
+	elementRepo := repository.NewContextElementRepository(db, suite.cfg.Search)
+	const userID uint64 = 990001
+
+	purged := &model.ContextElement{UserID: userID, Subject: "待彻底删除", TaskGoal: "目标"}
+	kept := &model.ContextElement{UserID: userID, Subject: "保留", TaskGoal: "目标"}
+	suite.Require().NoError(elementRepo.Create(purged))
+	suite.Require().NoError(elementRepo.Create(kept))
+
+	suite.Require().NoError(db.Create(&model.GenerationRecord{
+		UserID: userID, ElementID: purged.ID, Provider: "openai", Model: "gpt-4o", Format: "markdown", Status: model.GenerationStatusCompleted,
+	}).Error)
+	dataset := &model.EvalDataset{UserID: userID, ElementID: purged.ID, Name: "回归", Cases: []*model.EvalCase{{Position: 1, Name: "用例"}}}
+	suite.Require().NoError(db.Create(dataset).Error)
+	suite.Require().NoError(db.Create(&model.EvalRun{
+		UserID: userID, DatasetID: dataset.ID, ElementID: purged.ID, Provider: "openai", Model: "gpt-4o", Format: "markdown",
+		Status: model.EvalRunStatusCompleted, Results: []*model.EvalResult{{CaseID: dataset.Cases[0].ID, Status: eval.StatusPassed}},
+	}).Error)
+
+	// 一个对比运行同时包含两个六要素，另一个只包含待删除的六要素
+	shared := &model.ComparisonRun{UserID: userID, Provider: "openai", Model: "gpt-4o", Format: "markdown", Repeat: 1, Variants: []*model.ComparisonVariant{
+		{Position: 1, ElementID: purged.ID, Outputs: []*model.ComparisonOutput{{Attempt: 1, Status: model.GenerationStatusCompleted}}},
+		{Position: 2, ElementID: kept.ID},
+	}}
+	own := &model.ComparisonRun{UserID: userID, Provider: "openai", Model: "gpt-4o", Format: "markdown", Repeat: 1, Variants: []*model.ComparisonVariant{
+		{Position: 1, ElementID: purged.ID},
+	}}
+	suite.Require().NoError(db.Create(shared).Error)
+	suite.Require().NoError(db.Create(own).Error)
+	suite.Require().NoError(db.Model(shared).Update("winner_variant_id", shared.Variants[0].ID).Error)
+
+	template := &model.Template{UserID: userID, ElementID: purged.ID, Subject: purged.Subject, Category: "办公"}
+	suite.Require().NoError(db.Create(template).Error)
+	suite.Require().NoError(db.Delete(template).Error)
+
+	suite.Require().NoError(elementRepo.Delete(purged.ID))
+	count, err := elementRepo.PurgeDeleted(userID, []uint64{purged.ID})
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), int64(1), count)
+
+	remaining := func(value interface{}, query string, args ...interface{}) int64 {
+		var total int64
+		suite.Require().NoError(db.Unscoped().Model(value).Where(query, args...).Count(&total).Error)
+		return total
+	}
+	assert.Zero(suite.T(), remaining(&model.GenerationRecord{}, "element_id = ?", purged.ID))
+	assert.Zero(suite.T(), remaining(&model.EvalDataset{}, "element_id = ?", purged.ID))
+	assert.Zero(suite.T(), remaining(&model.EvalCase{}, "dataset_id = ?", dataset.ID))
+	assert.Zero(suite.T(), remaining(&model.EvalRun{}, "element_id = ?", purged.ID))
+	assert.Zero(suite.T(), remaining(&model.ComparisonVariant{}, "element_id = ?", purged.ID))
+	assert.Zero(suite.T(), remaining(&model.ComparisonOutput{}, "variant_id = ?", shared.Variants[0].ID))
+	assert.Zero(suite.T(), remaining(&model.ComparisonRun{}, "id = ?", own.ID))
+	assert.Zero(suite.T(), remaining(&model.Template{}, "element_id = ?", purged.ID))
+
+	var run model.ComparisonRun
+	suite.Require().NoError(db.First(&run, shared.ID).Error)
+	assert.Nil(suite.T(), run.WinnerVariantID)
+	assert.Equal(suite.T(), int64(1), remaining(&model.ComparisonVariant{}, "run_id = ?", shared.ID))
+}
+
+// TestSearchSingleCharacter 测试全文搜索中单个字符的关键词改用 LIKE 匹配
+func (suite *IntegrationTestSuite) TestSearchSingleCharacter() {
+	db := repository.GetDB()
+	elementRepo := repository.NewContextElementRepository(db, suite.cfg.Search)
+	const userID uint64 = 990002
+
+	weekly := &model.ContextElement{UserID: userID, Subject: "周报模板", TaskGoal: "总结本周工作"}
+	daily := &model.ContextElement{UserID: userID, Subject: "日报模板", TaskGoal: "总结当天工作"}
+	suite.Require().NoError(elementRepo.Create(weekly))
+	suite.Require().NoError(elementRepo.Create(daily))
+
+	hits, total, err := elementRepo.Search(userID, &model.ContextElementQueryRequest{Keyword: "周 模板", Page: 1, Size: 10})
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), int64(1), total)
+	suite.Require().Len(hits, 1)
+	assert.Equal(suite.T(), weekly.ID, hits[0].Element.ID)
+	assert.Greater(suite.T(), hits[0].Score, float64(0))
+
+	hits, _, err = elementRepo.Search(userID, &model.ContextElementQueryRequest{Keyword: "模板 -周", Page: 1, Size: 10})
+	suite.Require().NoError(err)
+	suite.Require().Len(hits, 1)
+	assert.Equal(suite.T(), daily.ID, hits[0].Element.ID)
+}
+
+// TestCatalogNameUnique 测试目录项名称唯一：同名创建被唯一索引拦截，删除后可重新创建
+func (suite *IntegrationTestSuite) TestCatalogNameUnique() {
+	db := repository.GetDB()
+	catalogRepo := repository.NewCatalogRepository(db)
+	const name = "集成测试角色"
+	defer db.Unscoped().Where("field = ? AND name = ?", model.CatalogFieldAIRole, name).Delete(&model.CatalogItem{})
+
+	item := &model.CatalogItem{Field: model.CatalogFieldAIRole, Name: name, Example: "你是一位测试专家"}
+	suite.Require().NoError(catalogRepo.Create(item))
+	err := catalogRepo.Create(&model.CatalogItem{Field: model.CatalogFieldAIRole, Name: name, Example: "重复"})
+	assert.ErrorIs(suite.T(), err, repository.ErrCatalogItemExists)
+
+	// 删除后重新创建时恢复原记录
+	suite.Require().NoError(catalogRepo.Delete(item.ID))
+	recreated := &model.CatalogItem{Field: model.CatalogFieldAIRole, Name: name, Example: "你是一位资深测试专家"}
+	suite.Require().NoError(catalogRepo.Create(recreated))
+	assert.Equal(suite.T(), item.ID, recreated.ID)
+
+	found, err := catalogRepo.GetByName(model.CatalogFieldAIRole, name)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(found)
+	assert.Equal(suite.T(), "你是一位资深测试专家", found.Example)
+
+	// 前缀中的通配符按字面匹配
+	items, err := catalogRepo.GetList(model.CatalogFieldAIRole, "", "集成%", 0)
+	suite.Require().NoError(err)
+	assert.Empty(suite.T(), items)
+}
+
+// TestCatalogSeedCaseInsensitive 测试内置目录项与已有目录项仅大小写不同时跳过，不影响其他内置项导入
+func (suite *IntegrationTestSuite) TestCatalogSeedCaseInsensitive() {
+	db := repository.GetDB()
+	catalogRepo := repository.NewCatalogRepository(db)
+	defer db.Unscoped().Where("name IN ?", []string{"seo Expert", "SEO Expert", "集成测试内置角色"}).Delete(&model.CatalogItem{})
+
+	suite.Require().NoError(catalogRepo.Create(&model.CatalogItem{Field: model.CatalogFieldAIRole, Name: "seo Expert", Example: "管理员添加"}))
+
+	first, second := "integration-seo", "integration-role"
+	count, err := catalogRepo.Seed([]*model.CatalogItem{
+		{Field: model.CatalogFieldAIRole, Name: "SEO Expert", Example: "内置", SeedKey: &first},
+		{Field: model.CatalogFieldAIRole, Name: "集成测试内置角色", Example: "内置", SeedKey: &second},
+	})
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 1, count)
+
+	found, err := catalogRepo.GetByName(model.CatalogFieldAIRole, "集成测试内置角色")
+	suite.Require().NoError(err)
+	assert.NotNil(suite.T(), found)
+}
+
 // TestIntegrationSuite 运行集成测试套件
 func TestIntegrationSuite(t *testing.T) {
 	suite.Run(t, new(IntegrationTestSuite))